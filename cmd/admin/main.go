@@ -0,0 +1,18 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/aintsashqa/go-simple-blog/internal/admin"
+)
+
+func main() {
+	opt := admin.Options{}
+
+	flag.StringVar(&opt.Email, "email", "", "Email of user to grant role")
+	flag.StringVar(&opt.Role, "role", "admin", "Granted role: admin, editor, author or reader")
+	flag.BoolVar(&opt.Force, "force", false, "Grant admin role even if another admin exists")
+	flag.Parse()
+
+	admin.Run(opt)
+}
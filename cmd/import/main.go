@@ -0,0 +1,18 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/aintsashqa/go-simple-blog/internal/importer"
+)
+
+func main() {
+	opt := importer.Options{}
+
+	flag.StringVar(&opt.Directory, "dir", ".", "Directory with markdown files")
+	flag.StringVar(&opt.Email, "email", "", "Email of posts author")
+	flag.BoolVar(&opt.DryRun, "dry-run", false, "Validate files without saving posts")
+	flag.Parse()
+
+	importer.Run(opt)
+}
@@ -0,0 +1,82 @@
+package admin
+
+import (
+	"context"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/config"
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	"github.com/aintsashqa/go-simple-blog/pkg/database/mysql"
+	standart "github.com/aintsashqa/go-simple-blog/pkg/logger/standard"
+)
+
+type Options struct {
+	Email string
+	Role  string
+	Force bool
+}
+
+// Run grants role to existing user, it is used to bootstrap first admin,
+// who could manage roles of other users afterwards
+func Run(opt Options) {
+	ctx := context.Background()
+	logger := standart.NewStandartLoggerProvider()
+
+	role := domain.Role(opt.Role)
+	if !role.IsValid() {
+		logger.Critical(domain.ErrUserRoleInvalidValue)
+	}
+
+	logger.Info("Initialize config")
+	cfg, err := config.Init("config")
+	if err != nil {
+		logger.Critical(err)
+	}
+
+	logger.Info("Initialize database connection")
+	database, err := mysql.NewMySQLProvider(mysql.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		Username: cfg.Database.Username,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.DatabaseName,
+		Charset:  cfg.Database.Charset,
+	})
+	if err != nil {
+		logger.Critical(err)
+	}
+	defer database.Close()
+
+	repos := repository.NewRepository(database)
+
+	if role == domain.AdminRole && !opt.Force {
+		count, err := repos.User.CountWithRole(ctx, domain.AdminRole)
+		if err != nil {
+			logger.Critical(err)
+		}
+		if count != 0 {
+			logger.Criticalf("Found %d admins, use -force to grant another admin", count)
+		}
+	}
+
+	user, err := repos.User.GetByEmail(ctx, opt.Email)
+	if err != nil {
+		logger.Criticalf("Find user with email %s error: %s", opt.Email, err)
+	}
+
+	if user.Roles.HasAny(role) {
+		logger.Infof("User %s already has role %s", opt.Email, role)
+		return
+	}
+
+	user.Roles = append(user.Roles, role)
+	user.UpdatedAt = time.Now()
+
+	if err := repos.User.UpdateRoles(ctx, user); err != nil {
+		logger.Critical(err)
+	}
+
+	// Cached user expires by itself, role is applied on next token refresh
+	logger.Infof("Granted role %s to user %s", role, opt.Email)
+}
@@ -2,9 +2,11 @@ package app
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/aintsashqa/go-simple-blog/internal/config"
 	"github.com/aintsashqa/go-simple-blog/internal/delivery/http"
@@ -16,11 +18,37 @@ import (
 	"github.com/aintsashqa/go-simple-blog/pkg/auth/jwt"
 	"github.com/aintsashqa/go-simple-blog/pkg/cache/redis"
 	"github.com/aintsashqa/go-simple-blog/pkg/database/mysql"
+	"github.com/aintsashqa/go-simple-blog/pkg/hash"
+	"github.com/aintsashqa/go-simple-blog/pkg/hash/argon2"
 	"github.com/aintsashqa/go-simple-blog/pkg/hash/bcrypt"
+	"github.com/aintsashqa/go-simple-blog/pkg/hash/multi"
 	standart "github.com/aintsashqa/go-simple-blog/pkg/logger/standard"
+	"github.com/aintsashqa/go-simple-blog/pkg/mailer"
+	"github.com/aintsashqa/go-simple-blog/pkg/mailer/outbox"
+	"github.com/aintsashqa/go-simple-blog/pkg/mailer/smtp"
+	"github.com/aintsashqa/go-simple-blog/pkg/oidc"
+	"github.com/aintsashqa/go-simple-blog/pkg/oidc/client"
+	"github.com/aintsashqa/go-simple-blog/pkg/ratelimit"
+	"github.com/aintsashqa/go-simple-blog/pkg/ratelimit/memory"
+	redislimiter "github.com/aintsashqa/go-simple-blog/pkg/ratelimit/redis"
+	"github.com/aintsashqa/go-simple-blog/pkg/signature/hmac"
+	webhookclient "github.com/aintsashqa/go-simple-blog/pkg/webhook/client"
 	"github.com/aintsashqa/go-simple-blog/seeds"
 )
 
+const (
+	smtpMailDriver      string = "smtp"
+	bcryptHashAlgorithm string = "bcrypt"
+	// Default keys were shipped in example config, so anyone could sign tokens with them
+	defaultJWTSigningKey          string = "jwt-signing-key"
+	defaultVerificationSigningKey string = "verification-signing-key"
+)
+
+var (
+	ErrInsecureSigningKey             error = errors.New("JWT signing key is empty or default one, set `auth.jwt_signing_key` to random secret")
+	ErrInsecureVerificationSigningKey error = errors.New("Verification signing key is empty or default one, set `auth.verification_signing_key` to random secret")
+)
+
 // @title Go Simple Blog API
 // @version 1.0.0
 // @BasePath /api/v1
@@ -72,12 +100,86 @@ func Run() {
 		logger.Critical(err)
 	}
 
+	logger.Info("Initialize rate limiter")
+	// Limiter falls back to memory of instance when redis is unavailable,
+	// so failed connection is not critical
+	limiterClient, err := redis.NewRedisClient(ctx, redis.Config{
+		Host:     cfg.Cache.Host,
+		Port:     cfg.Cache.Port,
+		Username: cfg.Cache.Username,
+		Password: cfg.Cache.Password,
+		Database: cfg.Cache.Database,
+	})
+	if err != nil {
+		logger.Error(err)
+	}
+
+	rateLimits := make(map[string]ratelimit.Limit, len(cfg.RateLimit.Groups))
+	for group, limit := range cfg.RateLimit.Groups {
+		rateLimits[group] = ratelimit.Limit{Requests: limit.Requests, Period: limit.Period}
+	}
+
+	logger.Info("Initialize mailer")
+	var mail mailer.MailerProvider
+	if cfg.Mail.Driver == smtpMailDriver {
+		mail = smtp.NewSMTPMailerProvider(smtp.Config{
+			Host:     cfg.Mail.Host,
+			Port:     cfg.Mail.Port,
+			Username: cfg.Mail.Username,
+			Password: cfg.Mail.Password,
+			From:     cfg.Mail.From,
+		})
+	} else {
+		mail, err = outbox.NewOutboxMailerProvider(cfg.Mail.OutboxDirectory)
+		if err != nil {
+			logger.Critical(err)
+		}
+	}
+
 	logger.Info("Initialize dependecies")
 	repos := repository.NewRepository(database)
 	serializer := serializer.NewSerializer()
 	store := store.NewCacheStore(repos, cache, serializer)
-	hasher := bcrypt.NewBcryptProvider()
-	auth := jwt.NewJWTAuthorizationProvider(cfg.Auth.JWTSigningKey)
+	bcryptHasher := bcrypt.NewBcryptProvider(cfg.Hash.BcryptCost)
+	argon2Hasher := argon2.NewArgon2Provider(argon2.Config{
+		Time:       cfg.Hash.Argon2Time,
+		Memory:     cfg.Hash.Argon2Memory,
+		Threads:    cfg.Hash.Argon2Threads,
+		KeyLength:  argon2.DefaultConfig.KeyLength,
+		SaltLength: argon2.DefaultConfig.SaltLength,
+	})
+
+	var currentHasher hash.HashProvider = argon2Hasher
+	if cfg.Hash.Algorithm == bcryptHashAlgorithm {
+		currentHasher = bcryptHasher
+	}
+
+	hasher := multi.NewMultiProvider(currentHasher, map[string]hash.HashProvider{
+		"2a":             bcryptHasher,
+		"2b":             bcryptHasher,
+		"2y":             bcryptHasher,
+		argon2.Algorithm: argon2Hasher,
+	})
+	auth, err := newAuthorizationProvider(cfg.Auth)
+	if err != nil {
+		logger.Critical(err)
+	}
+
+	signer, err := newSignatureProvider(cfg.Auth)
+	if err != nil {
+		logger.Critical(err)
+	}
+
+	var identityProvider oidc.Provider
+	if cfg.Auth.OIDCIssuerURL != "" {
+		identityProvider = client.NewOIDCProvider(client.Config{
+			Name:         cfg.Auth.OIDCProviderName,
+			IssuerURL:    cfg.Auth.OIDCIssuerURL,
+			ClientID:     cfg.Auth.OIDCClientID,
+			ClientSecret: cfg.Auth.OIDCClientSecret,
+			RedirectURL:  cfg.Auth.OIDCRedirectURL,
+		})
+	}
 
 	services := service.NewService(service.ServiceDependencies{
 		Logger:                        logger,
@@ -85,6 +187,40 @@ func Run() {
 		Hasher:                        hasher,
 		Authorization:                 auth,
 		AuthorizationTokenExpiresTime: cfg.Auth.JWTExpiresTime,
+		RefreshTokenExpiresTime:       cfg.Auth.RefreshTokenExpiresTime,
+		Mailer:                        mail,
+		Signer:                        signer,
+		OIDC:                          identityProvider,
+		TOTPIssuer:                    cfg.Auth.TOTPIssuer,
+		SignInThrottle: service.SignInThrottleConfig{
+			AccountLimit:   cfg.Auth.SignInAccountLimit,
+			IPLimit:        cfg.Auth.SignInIPLimit,
+			LockoutTime:    cfg.Auth.SignInLockoutTime,
+			MaxLockoutTime: cfg.Auth.SignInMaxLockoutTime,
+			FailuresWindow: cfg.Auth.SignInFailuresWindow,
+		},
+		RateLimiter:                redislimiter.NewRedisLimiterProvider(limiterClient),
+		FallbackRateLimiter:        memory.NewMemoryLimiterProvider(),
+		RateLimits:                 rateLimits,
+		VerificationURL:            cfg.Auth.VerificationURL,
+		EmailChangeURL:             cfg.Auth.EmailChangeURL,
+		VerificationExpiresTime:    cfg.Auth.VerificationExpiresTime,
+		RequireVerifiedEmail:       cfg.Auth.RequireVerifiedEmail,
+		PasswordResetURL:           cfg.Auth.PasswordResetURL,
+		PasswordResetExpiresTime:   cfg.Auth.PasswordResetExpiresTime,
+		AccountDeletionGracePeriod: cfg.Account.DeletionGracePeriod,
+		ExportDirectory:            cfg.Export.Directory,
+		ExportPostsLimit:           cfg.Export.PostsLimit,
+		ExportWorkers:              cfg.Export.Workers,
+		ExportRetention:            cfg.Export.Retention,
+		Webhooks:                   webhookclient.NewWebhookProvider(webhookclient.Config{Timeout: cfg.Webhook.Timeout}),
+		WebhookConfig: service.WebhookConfig{
+			MaxAttempts: cfg.Webhook.MaxAttempts,
+			BackoffBase: cfg.Webhook.BackoffBase,
+			BackoffMax:  cfg.Webhook.BackoffMax,
+			Lease:       cfg.Webhook.LeaseTime,
+			BatchSize:   cfg.Webhook.BatchSize,
+		},
 	})
 
 	handler := http.NewHandler(services)
@@ -99,6 +235,54 @@ func Run() {
 
 	logger.Info("Server started")
 
+	go func() {
+		ticker := time.NewTicker(cfg.Account.PurgeInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			purged, err := services.Account.Purge(ctx)
+			if err != nil {
+				logger.Errorf("app.Purge error: %s", err)
+				continue
+			}
+			if purged > 0 {
+				logger.Infof("Purged %d deleted accounts", purged)
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(cfg.Export.PurgeInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			purged, err := services.Export.Purge(ctx)
+			if err != nil {
+				logger.Errorf("app.Purge error: %s", err)
+				continue
+			}
+			if purged > 0 {
+				logger.Infof("Purged %d expired exports", purged)
+			}
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(cfg.Webhook.DeliveryInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			delivered, err := services.Webhook.Deliver(ctx)
+			if err != nil {
+				logger.Errorf("app.Deliver error: %s", err)
+				continue
+			}
+			if delivered > 0 {
+				logger.Infof("Delivered %d webhooks", delivered)
+			}
+		}
+	}()
+
 	// Graceful Shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
@@ -109,3 +293,51 @@ func Run() {
 		logger.Critical(err)
 	}
 }
+
+// newSignatureProvider signs verification, email change, MFA challenge and
+// OIDC session tokens, services bind tokens to their purposes
+func newSignatureProvider(cfg config.AuthorizationConfig) (*hmac.HMACSignatureProvider, error) {
+	if len(cfg.VerificationSigningKey) == 0 || cfg.VerificationSigningKey == defaultVerificationSigningKey {
+		return nil, ErrInsecureVerificationSigningKey
+	}
+
+	return hmac.NewHMACSignatureProvider(cfg.VerificationSigningKey), nil
+}
+
+// newAuthorizationProvider signs tokens with private key when it is configured,
+// signing key then verifies tokens issued before switching only when legacy
+// verification is enabled explicitly
+func newAuthorizationProvider(cfg config.AuthorizationConfig) (*jwt.JWTAuthorizationProvider, error) {
+	usesSigningKey := len(cfg.JWTPrivateKeyFile) == 0 || cfg.JWTLegacyVerification
+	if usesSigningKey && (len(cfg.JWTSigningKey) == 0 || cfg.JWTSigningKey == defaultJWTSigningKey) {
+		return nil, ErrInsecureSigningKey
+	}
+
+	jwtConfig := jwt.Config{
+		Issuer:     cfg.JWTIssuer,
+		Audience:   cfg.JWTAudience,
+		SigningKey: jwt.NewHMACKey("", cfg.JWTSigningKey),
+	}
+
+	if len(cfg.JWTPrivateKeyFile) != 0 {
+		signingKey, err := jwt.LoadKey(cfg.JWTKeyID, cfg.JWTPrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		if cfg.JWTLegacyVerification {
+			jwtConfig.VerificationKeys = append(jwtConfig.VerificationKeys, jwtConfig.SigningKey)
+		}
+		jwtConfig.SigningKey = signingKey
+	}
+
+	for _, keyFile := range cfg.JWTVerificationKeys {
+		key, err := jwt.LoadKey(keyFile.ID, keyFile.File)
+		if err != nil {
+			return nil, err
+		}
+		jwtConfig.VerificationKeys = append(jwtConfig.VerificationKeys, key)
+	}
+
+	return jwt.NewJWTAuthorizationProvider(jwtConfig)
+}
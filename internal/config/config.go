@@ -13,7 +13,13 @@ type (
 		App         AppConfig           `mapstructure:"app"`
 		Database    MySQLDatabaseConfig `mapstructure:"db"`
 		Auth        AuthorizationConfig `mapstructure:"auth"`
+		Hash        HashConfig          `mapstructure:"hash"`
 		Cache       CacheConfig         `mapstructure:"cache"`
+		Export      ExportConfig        `mapstructure:"export"`
+		Mail        MailConfig          `mapstructure:"mail"`
+		Account     AccountConfig       `mapstructure:"account"`
+		RateLimit   RateLimitConfig     `mapstructure:"rate_limit"`
+		Webhook     WebhookConfig       `mapstructure:"webhook"`
 	}
 
 	AppConfig struct {
@@ -34,8 +40,50 @@ type (
 	}
 
 	AuthorizationConfig struct {
-		JWTSigningKey  string        `mapstructure:"jwt_signing_key"`
-		JWTExpiresTime time.Duration `mapstructure:"jwt_expires_time"`
+		JWTSigningKey            string        `mapstructure:"jwt_signing_key"`
+		JWTExpiresTime           time.Duration `mapstructure:"jwt_expires_time"`
+		RefreshTokenExpiresTime  time.Duration `mapstructure:"refresh_token_expires_time"`
+		VerificationSigningKey   string        `mapstructure:"verification_signing_key"`
+		VerificationExpiresTime  time.Duration `mapstructure:"verification_expires_time"`
+		VerificationURL          string        `mapstructure:"verification_url"`
+		EmailChangeURL           string        `mapstructure:"email_change_url"`
+		RequireVerifiedEmail     bool          `mapstructure:"require_verified_email"`
+		PasswordResetURL         string        `mapstructure:"password_reset_url"`
+		PasswordResetExpiresTime time.Duration `mapstructure:"password_reset_expires_time"`
+		OIDCProviderName         string        `mapstructure:"oidc_provider_name"`
+		OIDCIssuerURL            string        `mapstructure:"oidc_issuer_url"`
+		OIDCClientID             string        `mapstructure:"oidc_client_id"`
+		OIDCClientSecret         string        `mapstructure:"oidc_client_secret"`
+		OIDCRedirectURL          string        `mapstructure:"oidc_redirect_url"`
+		TOTPIssuer               string        `mapstructure:"totp_issuer"`
+		SignInAccountLimit       int64         `mapstructure:"sign_in_account_limit"`
+		SignInIPLimit            int64         `mapstructure:"sign_in_ip_limit"`
+		SignInLockoutTime        time.Duration `mapstructure:"sign_in_lockout_time"`
+		SignInMaxLockoutTime     time.Duration `mapstructure:"sign_in_max_lockout_time"`
+		SignInFailuresWindow     time.Duration `mapstructure:"sign_in_failures_window"`
+
+		// Tokens are signed with private key instead of signing key when it is configured
+		JWTKeyID            string          `mapstructure:"jwt_key_id"`
+		JWTPrivateKeyFile   string          `mapstructure:"jwt_private_key_file"`
+		JWTVerificationKeys []KeyFileConfig `mapstructure:"jwt_verification_keys"`
+		JWTIssuer           string          `mapstructure:"jwt_issuer"`
+		JWTAudience         string          `mapstructure:"jwt_audience"`
+
+		// Signing key verifies tokens after switching to private key only when it is enabled
+		JWTLegacyVerification bool `mapstructure:"jwt_legacy_verification"`
+	}
+
+	KeyFileConfig struct {
+		ID   string `mapstructure:"id"`
+		File string `mapstructure:"file"`
+	}
+
+	HashConfig struct {
+		Algorithm     string `mapstructure:"algorithm"`
+		BcryptCost    int    `mapstructure:"bcrypt_cost"`
+		Argon2Time    uint32 `mapstructure:"argon2_time"`
+		Argon2Memory  uint32 `mapstructure:"argon2_memory"`
+		Argon2Threads uint8  `mapstructure:"argon2_threads"`
 	}
 
 	CacheConfig struct {
@@ -46,6 +94,48 @@ type (
 		Database int           `mapstructure:"database"`
 		Expires  time.Duration `mapstructure:"expires"`
 	}
+
+	MailConfig struct {
+		Driver          string `mapstructure:"driver"`
+		Host            string `mapstructure:"host"`
+		Port            int    `mapstructure:"port"`
+		Username        string `mapstructure:"username"`
+		Password        string `mapstructure:"password"`
+		From            string `mapstructure:"from"`
+		OutboxDirectory string `mapstructure:"outbox_directory"`
+	}
+
+	ExportConfig struct {
+		Directory     string        `mapstructure:"directory"`
+		PostsLimit    int           `mapstructure:"posts_limit"`
+		Workers       int           `mapstructure:"workers"`
+		Retention     time.Duration `mapstructure:"retention"`
+		PurgeInterval time.Duration `mapstructure:"purge_interval"`
+	}
+
+	RateLimitConfig struct {
+		Groups map[string]RateLimitGroupConfig `mapstructure:"groups"`
+	}
+
+	RateLimitGroupConfig struct {
+		Requests int64         `mapstructure:"requests"`
+		Period   time.Duration `mapstructure:"period"`
+	}
+
+	AccountConfig struct {
+		DeletionGracePeriod time.Duration `mapstructure:"deletion_grace_period"`
+		PurgeInterval       time.Duration `mapstructure:"purge_interval"`
+	}
+
+	WebhookConfig struct {
+		Timeout          time.Duration `mapstructure:"timeout"`
+		MaxAttempts      int           `mapstructure:"max_attempts"`
+		BackoffBase      time.Duration `mapstructure:"backoff_base"`
+		BackoffMax       time.Duration `mapstructure:"backoff_max"`
+		LeaseTime        time.Duration `mapstructure:"lease_time"`
+		BatchSize        int           `mapstructure:"batch_size"`
+		DeliveryInterval time.Duration `mapstructure:"delivery_interval"`
+	}
 )
 
 func Init(filename string) (Config, error) {
@@ -51,4 +51,6 @@ func (h *Handler) api(r chi.Router) {
 	r.Route("/api", func(r chi.Router) {
 		version1.Init(r)
 	})
+
+	r.Get("/.well-known/jwks.json", version1.GetJWKS)
 }
@@ -0,0 +1,139 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	requestdto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/request"
+	responsedto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+)
+
+// @Summary Get personal access tokens
+// @Description Get personal access tokens of self user, values of tokens are not shown
+// @ID user-get-tokens
+// @Tags Token
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.PersonalAccessTokenListResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/self/tokens [get]
+func (h *Handler) GetAllPersonalAccessTokens(w http.ResponseWriter, r *http.Request) {
+	response := responsedto.PersonalAccessTokenListResponseDto{}
+
+	userID, casted := requestdto.UserIDFromContext(r.Context())
+	if !casted {
+
+		h.Service.Logger.Errorf("v1.GetAllPersonalAccessTokens error: %s", errors.ErrInvalidTokenUserId)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	tokens, err := h.Service.PersonalAccessToken.GetAll(r.Context(), userID)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.GetAllPersonalAccessTokens error: %s", err)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(tokens)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Create personal access token
+// @Description Create personal access token with scopes posts:read, posts:write or profile:write, value of token is shown only once
+// @ID user-create-token
+// @Tags Token
+// @Accept json
+// @Produce json
+// @Param payload body request.CreatePersonalAccessTokenRequestDto true "Name and scopes of token"
+// @Success 201 {object} response.CreatedPersonalAccessTokenResponseDto
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/self/tokens [post]
+func (h *Handler) CreatePersonalAccessToken(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.CreatePersonalAccessTokenRequestDto{}
+	response := responsedto.CreatedPersonalAccessTokenResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.CreatePersonalAccessToken error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	token, value, err := h.Service.PersonalAccessToken.Create(r.Context(), input)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.CreatePersonalAccessToken error: %s", err)
+
+		if errorResp, isValidation := ValidationErrorsHandler(err); isValidation {
+			errorRespond(w, r, errorResp)
+			return
+		}
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(token, value)
+	respond(w, r, http.StatusCreated, response)
+}
+
+// @Summary Revoke personal access token
+// @Description Revoke personal access token of self user with id
+// @ID user-revoke-token
+// @Tags Token
+// @Accept json
+// @Produce json
+// @Param id path string true "Personal access token id"
+// @Success 204 "No content"
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/self/tokens/{id} [delete]
+func (h *Handler) RevokePersonalAccessToken(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.RevokePersonalAccessTokenRequestDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.RevokePersonalAccessToken error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	if err := h.Service.PersonalAccessToken.Revoke(r.Context(), input); err != nil {
+
+		h.Service.Logger.Errorf("v1.RevokePersonalAccessToken error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case repoerrors.ErrPersonalAccessTokenNotFound:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	respond(w, r, http.StatusNoContent, nil)
+}
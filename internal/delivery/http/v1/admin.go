@@ -0,0 +1,270 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	requestdto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/request"
+	responsedto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+)
+
+// @Summary Search users
+// @Description Search users by part of email or username with pagination, admin role is required
+// @ID admin-search-users
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param query query string false "Part of email or username"
+// @Param include_deleted query bool false "Include deleted users"
+// @Param current_page query int false "Number of current page"
+// @Param count_per_page query int false "Number of users count"
+// @Success 200 {object} response.AdminUserPaginationResponseDto
+// @Failure 401 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /admin/users [get]
+func (h *Handler) SearchUsers(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.SearchUsersRequestDto{}
+	response := responsedto.AdminUserPaginationResponseDto{}
+
+	request.FromRequest(r)
+
+	opt := request.TransformToObject()
+	pagination, err := h.Service.Admin.SearchUsers(r.Context(), opt)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.SearchUsers error: %s", err)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(pagination)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Suspend user
+// @Description Suspend user with id and sign user out from all sessions, admin role is required
+// @ID admin-suspend-user
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User id"
+// @Success 200 {object} response.AdminUserResponseDto
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 401 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /admin/users/{id}/suspend [put]
+func (h *Handler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.ModerateUserRequestDto{}
+	response := responsedto.AdminUserResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.SuspendUser error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	user, err := h.Service.Admin.Suspend(r.Context(), input)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.SuspendUser error: %s", err)
+
+		errorRespond(w, r, moderateUserErrorResponse(err))
+		return
+	}
+
+	response.TransformFromObject(user)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Unsuspend user
+// @Description Allow suspended user with id to sign in again, admin role is required
+// @ID admin-unsuspend-user
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "User id"
+// @Success 200 {object} response.AdminUserResponseDto
+// @Failure 401 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /admin/users/{id}/suspend [delete]
+func (h *Handler) UnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.ModerateUserRequestDto{}
+	response := responsedto.AdminUserResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.UnsuspendUser error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	user, err := h.Service.Admin.Unsuspend(r.Context(), input)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.UnsuspendUser error: %s", err)
+
+		errorRespond(w, r, moderateUserErrorResponse(err))
+		return
+	}
+
+	response.TransformFromObject(user)
+	respond(w, r, http.StatusOK, response)
+}
+
+func moderateUserErrorResponse(err error) responsedto.ErrorResponseDto {
+	switch err {
+
+	case serviceerrors.ErrSuspendSelf:
+		return responsedto.NewErrorResponseDto(http.StatusBadRequest, err.Error())
+
+	case repoerrors.ErrUserNotFound:
+		return responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+
+	default:
+		return responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+	}
+}
+
+// @Summary Unpublish any post
+// @Description Unpublish post with id of any user, admin role is required
+// @ID admin-unpublish-post
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Post id"
+// @Success 200 {object} response.PostResponseDto
+// @Failure 401 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /admin/posts/{id}/unpublish [put]
+func (h *Handler) AdminUnpublishPost(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.ModeratePostRequestDto{}
+	response := responsedto.PostResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.AdminUnpublishPost error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	post, err := h.Service.Admin.UnpublishPost(r.Context(), input)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.AdminUnpublishPost error: %s", err)
+
+		errorRespond(w, r, moderatePostErrorResponse(err))
+		return
+	}
+
+	response.TransformFromObject(post)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Delete any post
+// @Description Soft delete post with id of any user, admin role is required
+// @ID admin-delete-post
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Post id"
+// @Success 204 "No content"
+// @Failure 401 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 409 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /admin/posts/{id} [delete]
+func (h *Handler) AdminDeletePost(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.ModeratePostRequestDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.AdminDeletePost error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	if err := h.Service.Admin.DeletePost(r.Context(), input); err != nil {
+
+		h.Service.Logger.Errorf("v1.AdminDeletePost error: %s", err)
+
+		errorRespond(w, r, moderatePostErrorResponse(err))
+		return
+	}
+
+	respond(w, r, http.StatusNoContent, nil)
+}
+
+func moderatePostErrorResponse(err error) responsedto.ErrorResponseDto {
+	switch err {
+
+	case repoerrors.ErrPostNotFound:
+		return responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+
+	case serviceerrors.ErrPostAlreadyDeleted:
+		return responsedto.NewErrorResponseDto(http.StatusConflict, err.Error())
+
+	default:
+		return responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+	}
+}
+
+// @Summary Get deleted posts
+// @Description Get soft deleted posts of all users with pagination, admin role is required
+// @ID admin-get-deleted-posts
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param current_page query int false "Number of current page"
+// @Param count_per_page query int false "Number of posts count"
+// @Success 200 {object} response.PostPaginationResponseDto
+// @Failure 401 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /admin/posts/deleted [get]
+func (h *Handler) GetAllDeletedPosts(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.PostPaginationRequestDto{}
+	response := responsedto.PostPaginationResponseDto{}
+
+	request.FromRequest(r)
+
+	opt := request.TransformToObject()
+	pagination, err := h.Service.Admin.DeletedPosts(r.Context(), opt)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.GetAllDeletedPosts error: %s", err)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(pagination)
+	respond(w, r, http.StatusOK, response)
+}
@@ -1,11 +1,14 @@
 package v1
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
 	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
 	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
 )
 
 func ValidationErrorsHandler(err error) (response.ErrorResponseDto, bool) {
@@ -18,18 +21,70 @@ func ValidationErrorsHandler(err error) (response.ErrorResponseDto, bool) {
 		domain.ErrUserEmailInvalidValue,
 		domain.ErrUserUsernameEmptyValue,
 		domain.ErrUserUsernameInvalidLength,
+		domain.ErrUserUsernameInvalidValue,
+		domain.ErrUserUsernameReserved,
 		domain.ErrUserPasswordEmptyValue,
 		domain.ErrUserPasswordInvalidLength,
+		domain.ErrUserDisplayNameInvalidLength,
+		domain.ErrUserBioInvalidLength,
+		domain.ErrUserAvatarURLInvalidValue,
+		domain.ErrUserLocationInvalidLength,
+		domain.ErrUserWebsiteInvalidValue,
+		domain.ErrUserSocialLinksInvalidSize,
+		domain.ErrUserSocialLinksInvalidValue,
 
 		// Post errors
 		domain.ErrPostTitleEmptyValue,
 		domain.ErrPostTitleInvalidLength,
 		domain.ErrPostSlugInvalidLength,
 		domain.ErrPostContentEmptyValue,
-		domain.ErrPostContentInvalidLength:
+		domain.ErrPostContentInvalidLength,
+		domain.ErrPostTagsInvalidLength,
+
+		// Personal access token errors
+		domain.ErrPersonalAccessTokenNameEmptyValue,
+		domain.ErrPersonalAccessTokenNameInvalidLength,
+		domain.ErrPersonalAccessTokenScopesEmptyValue,
+		domain.ErrPersonalAccessTokenScopesInvalidValue,
+
+		// Webhook errors
+		domain.ErrWebhookURLInvalidValue,
+		domain.ErrWebhookEventsEmptyValue,
+		domain.ErrWebhookEventsInvalidValue,
+
+		// Bulk post errors
+		serviceerrors.ErrBulkPostActionInvalid,
+		serviceerrors.ErrBulkPostIDsInvalidSize:
 
 		return response.NewErrorResponseDto(http.StatusBadRequest, errors.ErrInvalidRequestBody.Error(), err.Error()), true
 	}
 
 	return response.ErrorResponseDto{}, false
 }
+
+// VersionErrorsHandler responds to failed optimistic concurrency checks
+// with current version of model
+func VersionErrorsHandler(w http.ResponseWriter, err error, model domain.Model) (response.ErrorResponseDto, bool) {
+	var code int
+
+	switch err {
+
+	case serviceerrors.ErrVersionRequired:
+		code = http.StatusPreconditionRequired
+
+	case serviceerrors.ErrETagMismatch:
+		code = http.StatusPreconditionFailed
+
+	case
+		serviceerrors.ErrVersionMismatch,
+		repoerrors.ErrUserVersionConflict,
+		repoerrors.ErrPostVersionConflict:
+		code = http.StatusConflict
+
+	default:
+		return response.ErrorResponseDto{}, false
+	}
+
+	w.Header().Set("ETag", model.ETag())
+	return response.NewErrorResponseDto(code, err.Error(), fmt.Sprintf("Current version is %d", model.Version)), true
+}
@@ -15,4 +15,9 @@ var (
 	ErrEmptyAuthorizationHeader   error = errors.New("Header `Authorization` could not be empty")
 	ErrInvalidAuthorizationHeader error = errors.New("Invalid `Authorization` header")
 	ErrAuthenticationFailed       error = errors.New("Authentication failed")
+	ErrInsufficientRole           error = errors.New("User role does not allow this action")
+	ErrInsufficientScope          error = errors.New("Personal access token scopes do not allow this action")
+	ErrSessionRequired            error = errors.New("Personal access token could not be used for this action")
+	ErrOIDCAuthorizationDenied    error = errors.New("Identity provider denied authorization")
+	ErrRateLimitExceeded          error = errors.New("Too many requests, try again later")
 )
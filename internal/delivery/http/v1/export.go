@@ -0,0 +1,166 @@
+package v1
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	requestdto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/request"
+	responsedto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+)
+
+const (
+	exportLocation string = "/api/v1/user/self/export/%s"
+	exportFilename string = "attachment; filename=\"export-%s.zip\""
+)
+
+// @Summary Export self user
+// @Description Export profile and posts as zip archive, large accounts are exported in background
+// @ID user-export-self
+// @Tags User
+// @Produce application/zip
+// @Produce json
+// @Param include_deleted query bool false "Include deleted posts"
+// @Success 200 {file} binary
+// @Success 202 {object} response.ExportResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Failure 503 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/self/export [get]
+func (h *Handler) ExportSelfUser(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.ExportUserRequestDto{}
+	response := responsedto.ExportResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.ExportSelfUser error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+
+	// User is checked before archive is streamed, error could not be
+	// responded after headers are written
+	if _, err := h.Service.User.Self(r.Context(), opt.UserID); err != nil {
+
+		h.Service.Logger.Errorf("v1.ExportSelfUser error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		if err == repoerrors.ErrUserNotFound {
+			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+		} else {
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	isLarge, err := h.Service.Export.IsLarge(r.Context(), opt)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.ExportSelfUser error: %s", err)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	if isLarge {
+		id, err := h.Service.Export.Schedule(r.Context(), opt)
+		if err != nil {
+
+			h.Service.Logger.Errorf("v1.ExportSelfUser error: %s", err)
+
+			var errorResp responsedto.ErrorResponseDto
+			if err == serviceerrors.ErrExportBusy {
+				errorResp = responsedto.NewErrorResponseDto(http.StatusServiceUnavailable, err.Error())
+			} else {
+				errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+			}
+
+			errorRespond(w, r, errorResp)
+			return
+		}
+
+		location := fmt.Sprintf(exportLocation, id)
+		w.Header().Set("Location", location)
+		response.TransformFromObject(id, location)
+		respond(w, r, http.StatusAccepted, response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(exportFilename, opt.UserID))
+	if err := h.Service.Export.Write(r.Context(), w, opt); err != nil {
+
+		h.Service.Logger.Errorf("v1.ExportSelfUser error: %s", err)
+
+		// Archive is partially written already, connection is aborted so
+		// client does not receive truncated archive as complete one
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// @Summary Download export
+// @Description Download export archive scheduled for large account
+// @ID user-export-download
+// @Tags User
+// @Produce application/zip
+// @Produce json
+// @Param id path string true "Export with id"
+// @Success 200 {file} binary
+// @Success 202 {object} response.ExportResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/self/export/{id} [get]
+func (h *Handler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.DownloadExportRequestDto{}
+	response := responsedto.ExportResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.DownloadExport error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	archive, err := h.Service.Export.Open(r.Context(), request.UserID, request.ExportID)
+	if err != nil {
+
+		if err == serviceerrors.ErrExportNotReady {
+			response.TransformFromObject(request.ExportID, fmt.Sprintf(exportLocation, request.ExportID))
+			respond(w, r, http.StatusAccepted, response)
+			return
+		}
+
+		h.Service.Logger.Errorf("v1.DownloadExport error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		if err == serviceerrors.ErrExportNotFound {
+			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+		} else {
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+	defer archive.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(exportFilename, request.ExportID))
+	if _, err := io.Copy(w, archive); err != nil {
+		h.Service.Logger.Errorf("v1.DownloadExport error: %s", err)
+	}
+}
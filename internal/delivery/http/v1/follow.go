@@ -0,0 +1,219 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	requestdto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/request"
+	responsedto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+)
+
+// @Summary Follow user
+// @Description Follow user with id, posts of followed users are shown in feed
+// @ID user-follow
+// @Tags Follow
+// @Accept json
+// @Produce json
+// @Param id path string true "User id"
+// @Success 204 "No content"
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/{id}/follow [put]
+func (h *Handler) FollowUser(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.FollowRequestDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.FollowUser error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+	if err := h.Service.Follow.Follow(r.Context(), opt); err != nil {
+
+		h.Service.Logger.Errorf("v1.FollowUser error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case serviceerrors.ErrFollowSelf:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusBadRequest, err.Error())
+
+		case repoerrors.ErrUserNotFound:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	respond(w, r, http.StatusNoContent, nil)
+}
+
+// @Summary Unfollow user
+// @Description Stop following user with id
+// @ID user-unfollow
+// @Tags Follow
+// @Accept json
+// @Produce json
+// @Param id path string true "User id"
+// @Success 204 "No content"
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/{id}/follow [delete]
+func (h *Handler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.FollowRequestDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.UnfollowUser error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+	if err := h.Service.Follow.Unfollow(r.Context(), opt); err != nil {
+
+		h.Service.Logger.Errorf("v1.UnfollowUser error: %s", err)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	respond(w, r, http.StatusNoContent, nil)
+}
+
+// @Summary Get followers
+// @Description Get users following user with id with pagination
+// @ID user-get-followers
+// @Tags Follow
+// @Accept json
+// @Produce json
+// @Param id path string true "User id"
+// @Param current_page query int false "Number of current page"
+// @Param count_per_page query int false "Number of users count"
+// @Success 200 {object} response.UserPaginationResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Router /user/{id}/followers [get]
+func (h *Handler) GetUserFollowers(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.FollowPaginationRequestDto{}
+	response := responsedto.UserPaginationResponseDto{}
+
+	request.FromRequest(r)
+
+	opt := request.TransformToObject()
+	pagination, err := h.Service.Follow.Followers(r.Context(), opt)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.GetUserFollowers error: %s", err)
+
+		errorRespond(w, r, followListErrorResponse(err))
+		return
+	}
+
+	response.TransformFromObject(pagination)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Get following
+// @Description Get users followed by user with id with pagination
+// @ID user-get-following
+// @Tags Follow
+// @Accept json
+// @Produce json
+// @Param id path string true "User id"
+// @Param current_page query int false "Number of current page"
+// @Param count_per_page query int false "Number of users count"
+// @Success 200 {object} response.UserPaginationResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Router /user/{id}/following [get]
+func (h *Handler) GetUserFollowing(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.FollowPaginationRequestDto{}
+	response := responsedto.UserPaginationResponseDto{}
+
+	request.FromRequest(r)
+
+	opt := request.TransformToObject()
+	pagination, err := h.Service.Follow.Following(r.Context(), opt)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.GetUserFollowing error: %s", err)
+
+		errorRespond(w, r, followListErrorResponse(err))
+		return
+	}
+
+	response.TransformFromObject(pagination)
+	respond(w, r, http.StatusOK, response)
+}
+
+func followListErrorResponse(err error) responsedto.ErrorResponseDto {
+	if err == repoerrors.ErrUserNotFound {
+		return responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+	}
+	return responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+}
+
+// @Summary Get feed
+// @Description Get published posts of followed users, newest first, next page is requested with cursor from previous page
+// @ID feed-get
+// @Tags Follow
+// @Accept json
+// @Produce json
+// @Param cursor query string false "Cursor of next page"
+// @Param count_per_page query int false "Number of posts count"
+// @Success 200 {object} response.FeedResponseDto
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /feed [get]
+func (h *Handler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.FeedRequestDto{}
+	response := responsedto.FeedResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.GetFeed error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+	feed, err := h.Service.Follow.Feed(r.Context(), opt)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.GetFeed error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		if err == serviceerrors.ErrFeedCursorInvalid {
+			errorResp = responsedto.NewErrorResponseDto(http.StatusBadRequest, err.Error())
+		} else {
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(feed)
+	respond(w, r, http.StatusOK, response)
+}
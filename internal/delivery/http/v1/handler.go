@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
 	"github.com/aintsashqa/go-simple-blog/internal/service"
 	"github.com/go-chi/chi"
 )
@@ -19,29 +20,120 @@ func (h *Handler) Init(r chi.Router) {
 	r.Route("/v1", func(r chi.Router) {
 
 		r.Route("/user", func(r chi.Router) {
-			r.Post("/sign-up", h.SignUp)
-			r.Post("/sign-in", h.SignIn)
+			r.Group(func(r chi.Router) {
+				r.Use(h.rateLimit(service.AuthRateLimitGroup))
+				r.Post("/sign-up", h.SignUp)
+				r.Post("/sign-in", h.SignIn)
+				r.Post("/refresh", h.RefreshToken)
+				r.Post("/verify-email", h.VerifyEmail)
+				r.Post("/password/forgot", h.ForgotPassword)
+				r.Post("/password/reset", h.ResetPassword)
+				r.Post("/confirm-email", h.ConfirmEmailChange)
+				r.Post("/restore", h.RestoreUser)
+				r.Post("/mfa/verify", h.VerifyMFA)
+				r.Post("/mfa/enroll", h.EnrollMFAChallenge)
+				r.Get("/oidc/login", h.OIDCLogin)
+				r.Get("/oidc/callback", h.OIDCCallback)
+			})
+
+			r.Get("/by-username/{username}", h.GetUserByUsername)
 			r.Get("/{id}", h.GetSingleUser)
+			r.Get("/{id}/followers", h.GetUserFollowers)
+			r.Get("/{id}/following", h.GetUserFollowing)
 
 			r.Group(func(r chi.Router) {
 				r.Use(h.authenticateMiddleware)
 				r.Get("/self", h.GetSelfUser)
-				r.Put("/{id}", h.UpdateUser)
+
+				r.Group(func(r chi.Router) {
+					r.Use(h.requireScope(domain.ProfileWriteScope))
+					r.Use(h.rateLimit(service.WriteRateLimitGroup))
+					r.Put("/{id}", h.UpdateUser)
+					r.Patch("/{id}", h.UpdateUser)
+					r.Put("/{id}/follow", h.FollowUser)
+					r.Delete("/{id}/follow", h.UnfollowUser)
+				})
+
+				r.Group(func(r chi.Router) {
+					r.Use(h.requireSession)
+					r.Post("/sign-out", h.SignOut)
+					r.Put("/self/password", h.ChangePassword)
+					r.Put("/self/email", h.ChangeEmail)
+					r.Get("/self/export", h.ExportSelfUser)
+					r.Get("/self/export/{id}", h.DownloadExport)
+					r.Get("/self/tokens", h.GetAllPersonalAccessTokens)
+					r.Post("/self/tokens", h.CreatePersonalAccessToken)
+					r.Delete("/self/tokens/{id}", h.RevokePersonalAccessToken)
+					r.Get("/self/sessions", h.GetAllSessions)
+					r.Delete("/self/sessions", h.RevokeAllSessions)
+					r.Delete("/self/sessions/{id}", h.RevokeSession)
+					r.Get("/self/mfa", h.GetMFAStatus)
+					r.Post("/self/mfa/totp", h.EnrollTOTP)
+					r.Post("/self/mfa/totp/confirm", h.ConfirmTOTP)
+					r.Delete("/self/mfa/totp", h.DisableTOTP)
+					r.Post("/self/mfa/recovery-codes", h.RegenerateRecoveryCodes)
+					r.Delete("/{id}", h.DeleteUser)
+				})
 			})
 		})
 
+		r.Route("/feed", func(r chi.Router) {
+			r.Use(h.authenticateMiddleware)
+			r.Use(h.requireScope(domain.PostsReadScope))
+			r.Get("/", h.GetFeed)
+		})
+
+		r.Route("/notifications", func(r chi.Router) {
+			r.Use(h.authenticateMiddleware)
+			r.Use(h.requireSession)
+			r.Get("/", h.GetAllNotifications)
+			r.Put("/read", h.MarkAllNotificationsRead)
+			r.Put("/{id}/read", h.MarkNotificationRead)
+		})
+
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(h.authenticateMiddleware)
+			r.Use(h.requireSession)
+			r.Get("/", h.GetAllWebhooks)
+			r.Post("/", h.CreateWebhook)
+			r.Delete("/{id}", h.DeleteWebhook)
+			r.Get("/{id}/deliveries", h.GetAllWebhookDeliveries)
+			r.Post("/{id}/deliveries/{delivery_id}/redeliver", h.RedeliverWebhook)
+		})
+
 		r.Route("/post", func(r chi.Router) {
 			r.Get("/", h.GetAllPublishedPosts)
 			r.Get("/{id}", h.GetSinglePost)
 
 			r.Group(func(r chi.Router) {
 				r.Use(h.authenticateMiddleware)
-				r.Post("/", h.CreatePost)
-				r.Get("/self", h.GetAllSelfPosts)
-				r.Put("/{id}", h.UpdatePost)
-				r.Get("/{id}/publish", h.PublishPost)
-				r.Delete("/{id}", h.DeletePost)
+				r.Use(h.requireRole(domain.AuthorRole, domain.EditorRole, domain.AdminRole))
+				r.With(h.requireScope(domain.PostsReadScope)).Get("/self", h.GetAllSelfPosts)
+
+				r.Group(func(r chi.Router) {
+					r.Use(h.requireScope(domain.PostsWriteScope))
+					r.Use(h.rateLimit(service.WriteRateLimitGroup))
+					r.Post("/", h.CreatePost)
+					r.Post("/bulk", h.BulkPosts)
+					r.Put("/{id}", h.UpdatePost)
+					r.Get("/{id}/publish", h.PublishPost)
+					r.Delete("/{id}", h.DeletePost)
+				})
 			})
 		})
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(h.authenticateMiddleware)
+			r.Use(h.requireSession)
+			r.Use(h.requireRole(domain.AdminRole))
+			r.Get("/users", h.SearchUsers)
+			r.Put("/users/{id}/suspend", h.SuspendUser)
+			r.Delete("/users/{id}/suspend", h.UnsuspendUser)
+			r.Get("/posts/deleted", h.GetAllDeletedPosts)
+			r.Put("/posts/{id}/unpublish", h.AdminUnpublishPost)
+			r.Delete("/posts/{id}", h.AdminDeletePost)
+			r.Get("/mfa/roles", h.GetMFARoles)
+			r.Put("/mfa/roles", h.SetMFARoles)
+		})
 	})
 }
@@ -0,0 +1,20 @@
+package v1
+
+import (
+	"net/http"
+
+	responsedto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+)
+
+// GetJWKS publishes public keys verifying access tokens. It is served at
+// `/.well-known/jwks.json` outside of API base path, so it is not documented
+// in swagger
+func (h *Handler) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	var resp responsedto.JSONWebKeySetResponseDto
+	resp.TransformFromObject(h.Service.Token.PublicKeys(r.Context()))
+
+	// Verifiers cache keys, so new signing key must be published
+	// as verification key for longer than that before it is used
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	respond(w, r, http.StatusOK, resp)
+}
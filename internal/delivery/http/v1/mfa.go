@@ -0,0 +1,412 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	requestdto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/request"
+	responsedto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+)
+
+// signInRespond sends tokens, or challenge with status accepted when
+// second factor is required
+func signInRespond(w http.ResponseWriter, r *http.Request, result service.SignInResult) {
+	if result.Challenge.Token != "" {
+		response := responsedto.MFAChallengeResponseDto{}
+		response.TransformFromObject(result.Challenge)
+		respond(w, r, http.StatusAccepted, response)
+		return
+	}
+
+	response := responsedto.TokenResponseDto{}
+	response.TransformFromObject(result.Tokens)
+	respond(w, r, http.StatusOK, response)
+}
+
+// mfaErrorResponse maps errors shared by second factor handlers
+func mfaErrorResponse(err error) responsedto.ErrorResponseDto {
+	switch err {
+
+	case
+		serviceerrors.ErrMFAChallengeInvalid,
+		serviceerrors.ErrMFACodeInvalid:
+		return responsedto.NewErrorResponseDto(http.StatusUnauthorized, err.Error())
+
+	case
+		serviceerrors.ErrMFARequired,
+		serviceerrors.ErrUserSuspended:
+		return responsedto.NewErrorResponseDto(http.StatusForbidden, err.Error())
+
+	case repoerrors.ErrUserNotFound:
+		return responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+
+	case
+		serviceerrors.ErrMFAAlreadyEnabled,
+		serviceerrors.ErrMFANotEnabled:
+		return responsedto.NewErrorResponseDto(http.StatusConflict, err.Error())
+
+	case serviceerrors.ErrCurrentPasswordMismatch:
+		return responsedto.NewErrorResponseDto(http.StatusBadRequest, err.Error())
+
+	case serviceerrors.ErrSignInLocked:
+		return responsedto.NewErrorResponseDto(http.StatusTooManyRequests, err.Error())
+
+	default:
+		return responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+	}
+}
+
+// @Summary Verify second factor
+// @Description Finish sign in with code of authenticator app or recovery code, recovery codes are returned when factor was enrolled by challenge
+// @ID user-mfa-verify
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param payload body request.VerifyMFARequestDto true "Challenge token and code"
+// @Success 200 {object} response.MFATokenResponseDto
+// @Failure 401 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 409 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Router /user/mfa/verify [post]
+func (h *Handler) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.VerifyMFARequestDto{}
+	response := responsedto.MFATokenResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.VerifyMFA error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	verification, err := h.Service.MFA.Verify(r.Context(), input)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.VerifyMFA error: %s", err)
+
+		errorRespond(w, r, mfaErrorResponse(err))
+		return
+	}
+
+	response.TransformFromObject(verification)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Enroll second factor by challenge
+// @Description Start enrollment of authenticator app before sign in, when role of user requires second factor
+// @ID user-mfa-enroll
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param payload body request.MFAChallengeRequestDto true "Challenge token"
+// @Success 200 {object} response.TOTPEnrollmentResponseDto
+// @Failure 401 {object} response.ErrorResponseDto
+// @Failure 409 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Router /user/mfa/enroll [post]
+func (h *Handler) EnrollMFAChallenge(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.MFAChallengeRequestDto{}
+	response := responsedto.TOTPEnrollmentResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.EnrollMFAChallenge error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	enrollment, err := h.Service.MFA.EnrollChallenge(r.Context(), input)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.EnrollMFAChallenge error: %s", err)
+
+		errorRespond(w, r, mfaErrorResponse(err))
+		return
+	}
+
+	response.TransformFromObject(enrollment)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Get second factor status
+// @Description Get whether second factor of self user is enabled or required and count of unused recovery codes
+// @ID user-mfa-status
+// @Tags User
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.MFAStatusResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/self/mfa [get]
+func (h *Handler) GetMFAStatus(w http.ResponseWriter, r *http.Request) {
+	response := responsedto.MFAStatusResponseDto{}
+
+	userID, casted := requestdto.UserIDFromContext(r.Context())
+	if !casted {
+
+		h.Service.Logger.Errorf("v1.GetMFAStatus error: %s", errors.ErrInvalidTokenUserId)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	status, err := h.Service.MFA.Status(r.Context(), userID)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.GetMFAStatus error: %s", err)
+
+		errorRespond(w, r, mfaErrorResponse(err))
+		return
+	}
+
+	response.TransformFromObject(status)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Enroll authenticator app
+// @Description Generate secret of authenticator app, second factor is enabled after first code is confirmed
+// @ID user-totp-enroll
+// @Tags User
+// @Accept json
+// @Produce json
+// @Success 201 {object} response.TOTPEnrollmentResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 409 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/self/mfa/totp [post]
+func (h *Handler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	response := responsedto.TOTPEnrollmentResponseDto{}
+
+	userID, casted := requestdto.UserIDFromContext(r.Context())
+	if !casted {
+
+		h.Service.Logger.Errorf("v1.EnrollTOTP error: %s", errors.ErrInvalidTokenUserId)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	enrollment, err := h.Service.MFA.Enroll(r.Context(), userID)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.EnrollTOTP error: %s", err)
+
+		errorRespond(w, r, mfaErrorResponse(err))
+		return
+	}
+
+	response.TransformFromObject(enrollment)
+	respond(w, r, http.StatusCreated, response)
+}
+
+// @Summary Confirm authenticator app
+// @Description Enable second factor with first code of authenticator app, recovery codes are shown only once
+// @ID user-totp-confirm
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param payload body request.ConfirmTOTPRequestDto true "Code of authenticator app"
+// @Success 200 {object} response.RecoveryCodesResponseDto
+// @Failure 401 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 409 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/self/mfa/totp/confirm [post]
+func (h *Handler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.ConfirmTOTPRequestDto{}
+	response := responsedto.RecoveryCodesResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.ConfirmTOTP error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	codes, err := h.Service.MFA.Confirm(r.Context(), input)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.ConfirmTOTP error: %s", err)
+
+		errorRespond(w, r, mfaErrorResponse(err))
+		return
+	}
+
+	response.TransformFromObject(codes)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Disable authenticator app
+// @Description Disable second factor and remove recovery codes, second factor required by role could not be disabled
+// @ID user-totp-disable
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param payload body request.AuthorizeMFARequestDto true "Current password"
+// @Success 204 "No content"
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 409 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/self/mfa/totp [delete]
+func (h *Handler) DisableTOTP(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.AuthorizeMFARequestDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.DisableTOTP error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	if err := h.Service.MFA.Disable(r.Context(), input); err != nil {
+
+		h.Service.Logger.Errorf("v1.DisableTOTP error: %s", err)
+
+		errorRespond(w, r, mfaErrorResponse(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Regenerate recovery codes
+// @Description Replace recovery codes of self user, previous codes could not be used anymore
+// @ID user-recovery-codes
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param payload body request.AuthorizeMFARequestDto true "Current password"
+// @Success 200 {object} response.RecoveryCodesResponseDto
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 409 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/self/mfa/recovery-codes [post]
+func (h *Handler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.AuthorizeMFARequestDto{}
+	response := responsedto.RecoveryCodesResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.RegenerateRecoveryCodes error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	codes, err := h.Service.MFA.RegenerateRecoveryCodes(r.Context(), input)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.RegenerateRecoveryCodes error: %s", err)
+
+		errorRespond(w, r, mfaErrorResponse(err))
+		return
+	}
+
+	response.TransformFromObject(codes)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Get roles requiring second factor
+// @Description Get roles which users must enable second factor, admin role is required
+// @ID admin-get-mfa-roles
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.MFARolesResponseDto
+// @Failure 401 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /admin/mfa/roles [get]
+func (h *Handler) GetMFARoles(w http.ResponseWriter, r *http.Request) {
+	response := responsedto.MFARolesResponseDto{}
+
+	roles, err := h.Service.Admin.MFARoles(r.Context())
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.GetMFARoles error: %s", err)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(roles)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Set roles requiring second factor
+// @Description Replace roles which users must enable second factor, users of these roles enroll factor on next sign in, admin role is required
+// @ID admin-set-mfa-roles
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param payload body request.SetMFARolesRequestDto true "Roles"
+// @Success 200 {object} response.MFARolesResponseDto
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 401 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /admin/mfa/roles [put]
+func (h *Handler) SetMFARoles(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.SetMFARolesRequestDto{}
+	response := responsedto.MFARolesResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.SetMFARoles error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	roles, err := h.Service.Admin.SetMFARoles(r.Context(), input)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.SetMFARoles error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case serviceerrors.ErrMFARolesInvalid:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusBadRequest, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(roles)
+	respond(w, r, http.StatusOK, response)
+}
@@ -0,0 +1,133 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	requestdto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/request"
+	responsedto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+)
+
+// @Summary Get notifications
+// @Description Get notifications of self user with pagination, recent notifications first. Count of unread notifications is returned
+// @ID notification-get-all
+// @Tags Notification
+// @Accept json
+// @Produce json
+// @Param current_page query int false "Number of current page"
+// @Param count_per_page query int false "Number of notifications count"
+// @Success 200 {object} response.NotificationPaginationResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /notifications [get]
+func (h *Handler) GetAllNotifications(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.NotificationPaginationRequestDto{}
+	response := responsedto.NotificationPaginationResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.GetAllNotifications error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+	pagination, err := h.Service.Notification.GetAll(r.Context(), opt)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.GetAllNotifications error: %s", err)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(pagination)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Mark notification read
+// @Description Mark notification of self user with id as read, notification already read keeps time of first read
+// @ID notification-mark-read
+// @Tags Notification
+// @Accept json
+// @Produce json
+// @Param id path string true "Notification id"
+// @Success 200 {object} response.NotificationResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /notifications/{id}/read [put]
+func (h *Handler) MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.MarkNotificationReadRequestDto{}
+	response := responsedto.NotificationResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.MarkNotificationRead error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	notification, err := h.Service.Notification.MarkRead(r.Context(), input)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.MarkNotificationRead error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case repoerrors.ErrNotificationNotFound:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(notification)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Mark all notifications read
+// @Description Mark all unread notifications of self user as read
+// @ID notification-mark-all-read
+// @Tags Notification
+// @Accept json
+// @Produce json
+// @Success 204 "No content"
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /notifications/read [put]
+func (h *Handler) MarkAllNotificationsRead(w http.ResponseWriter, r *http.Request) {
+	userID, casted := requestdto.UserIDFromContext(r.Context())
+	if !casted {
+
+		h.Service.Logger.Errorf("v1.MarkAllNotificationsRead error: %s", errors.ErrInvalidTokenUserId)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	if err := h.Service.Notification.MarkAllRead(r.Context(), userID); err != nil {
+
+		h.Service.Logger.Errorf("v1.MarkAllNotificationsRead error: %s", err)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	respond(w, r, http.StatusNoContent, nil)
+}
@@ -0,0 +1,133 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	requestdto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/request"
+	responsedto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/oidc"
+)
+
+// @Summary Sign in with identity provider
+// @Description Redirect to OpenID Connect identity provider, login session is kept in cookie until callback
+// @ID user-oidc-login
+// @Tags User
+// @Success 302 {string} string "Redirect to identity provider"
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Router /user/oidc/login [get]
+func (h *Handler) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	login, err := h.Service.OIDC.Login(r.Context())
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.OIDCLogin error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case serviceerrors.ErrOIDCDisabled:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     requestdto.OIDCSessionCookie,
+		Value:    login.Session,
+		Path:     "/",
+		Expires:  login.ExpiresAt,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, login.URL, http.StatusFound)
+}
+
+// @Summary Identity provider callback
+// @Description Finish OpenID Connect login, external identity is linked to user with same verified email or new user is created
+// @ID user-oidc-callback
+// @Tags User
+// @Produce json
+// @Param code query string true "Authorization code"
+// @Param state query string true "Login state"
+// @Success 200 {object} response.TokenResponseDto
+// @Success 202 {object} response.MFAChallengeResponseDto
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 401 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 409 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Router /user/oidc/callback [get]
+func (h *Handler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.OIDCCallbackRequestDto{}
+
+	// Session could be used once
+	http.SetCookie(w, &http.Cookie{
+		Name:     requestdto.OIDCSessionCookie,
+		Path:     "/",
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.OIDCCallback error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	result, err := h.Service.OIDC.Callback(r.Context(), input)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.OIDCCallback error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case serviceerrors.ErrOIDCDisabled:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+
+		case serviceerrors.ErrOIDCStateInvalid:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusBadRequest, err.Error())
+
+		case
+			oidc.ErrExchangeFailed,
+			oidc.ErrIDTokenInvalid,
+			oidc.ErrIDTokenNonce,
+			oidc.ErrSigningKeyUnknown:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusUnauthorized, err.Error())
+
+		case
+			serviceerrors.ErrOIDCEmailNotVerified,
+			serviceerrors.ErrOIDCAccountNotVerified,
+			serviceerrors.ErrUserSuspended:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusForbidden, err.Error())
+
+		case repoerrors.ErrUserAlreadyExists:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusConflict, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	signInRespond(w, r, result)
+}
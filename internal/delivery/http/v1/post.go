@@ -7,6 +7,7 @@ import (
 	requsetdto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/request"
 	responsedto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
 	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
 	"github.com/go-chi/chi"
 	uuid "github.com/satori/go.uuid"
 )
@@ -20,7 +21,10 @@ import (
 // @Param current_page query int false "Number of current page"
 // @Param count_per_page query int false "Number of posts count"
 // @Param user_id query string false "Posts with user id"
+// @Param If-None-Match header string false "Entity tag of cached representation"
+// @Param If-Modified-Since header string false "Modification date of cached representation"
 // @Success 200 {object} response.PostPaginationResponseDto
+// @Success 304 "Not modified"
 // @Failure 500 {object} response.ErrorResponseDto
 // @Router /post [get]
 func (h *Handler) GetAllPublishedPosts(w http.ResponseWriter, r *http.Request) {
@@ -40,6 +44,13 @@ func (h *Handler) GetAllPublishedPosts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	validator := pagination.Validator()
+	if isNotModified(r, validator) {
+		notModifiedRespond(w, validator)
+		return
+	}
+
+	writeValidator(w, validator)
 	response.TransformFromObject(pagination)
 	respond(w, r, http.StatusOK, response)
 }
@@ -52,7 +63,10 @@ func (h *Handler) GetAllPublishedPosts(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param current_page query int false "Number of current page"
 // @Param count_per_page query int false "Number of posts count"
+// @Param If-None-Match header string false "Entity tag of cached representation"
+// @Param If-Modified-Since header string false "Modification date of cached representation"
 // @Success 200 {object} response.PostPaginationResponseDto
+// @Success 304 "Not modified"
 // @Failure 403 {object} response.ErrorResponseDto
 // @Failure 500 {object} response.ErrorResponseDto
 // @Security ApiKeyAuth
@@ -80,6 +94,13 @@ func (h *Handler) GetAllSelfPosts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	validator := pagination.Validator()
+	if isNotModified(r, validator) {
+		notModifiedRespond(w, validator)
+		return
+	}
+
+	writeValidator(w, validator)
 	response.TransformFromObject(pagination)
 	respond(w, r, http.StatusOK, response)
 }
@@ -91,7 +112,10 @@ func (h *Handler) GetAllSelfPosts(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path string string "Post with id"
+// @Param If-None-Match header string false "Entity tag of cached representation"
+// @Param If-Modified-Since header string false "Modification date of cached representation"
 // @Success 200 {object} response.PostResponseDto
+// @Success 304 "Not modified"
 // @Failure 404 {object} response.ErrorResponseDto
 // @Failure 500 {object} response.ErrorResponseDto
 // @Router /post/{id} [get]
@@ -100,6 +124,14 @@ func (h *Handler) GetSinglePost(w http.ResponseWriter, r *http.Request) {
 
 	id := uuid.FromStringOrNil(chi.URLParam(r, "id"))
 
+	if isConditional(r) {
+		// Validator is cheaper than whole post, so unchanged post is never loaded
+		if validator, err := h.Service.Post.Validator(r.Context(), id); err == nil && isNotModified(r, validator) {
+			notModifiedRespond(w, validator)
+			return
+		}
+	}
+
 	post, err := h.Service.Post.Find(r.Context(), id)
 	if err != nil {
 
@@ -116,6 +148,7 @@ func (h *Handler) GetSinglePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	writeValidator(w, post.Validator())
 	response.TransformFromObject(post)
 	respond(w, r, http.StatusOK, response)
 }
@@ -131,6 +164,7 @@ func (h *Handler) GetSinglePost(w http.ResponseWriter, r *http.Request) {
 // @Failure 400 {object} response.ErrorResponseDto
 // @Failure 401 {object} response.ErrorResponseDto
 // @Failure 403 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
 // @Failure 500 {object} response.ErrorResponseDto
 // @Security ApiKeyAuth
 // @Router /post [post]
@@ -157,6 +191,12 @@ func (h *Handler) CreatePost(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if err == serviceerrors.ErrEmailNotVerified {
+			errorResp := responsedto.NewErrorResponseDto(http.StatusForbidden, err.Error())
+			errorRespond(w, r, errorResp)
+			return
+		}
+
 		errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
 		errorRespond(w, r, errorResp)
 		return
@@ -173,11 +213,16 @@ func (h *Handler) CreatePost(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Post with id"
+// @Param If-Match header string false "Entity tag of post version"
 // @Param payload body request.UpdatePostRequestDto true "Post details"
 // @Success 202 {object} response.PostResponseDto
 // @Failure 400 {object} response.ErrorResponseDto
 // @Failure 401 {object} response.ErrorResponseDto
 // @Failure 404 {object} response.ErrorResponseDto
+// @Failure 409 {object} response.ErrorResponseDto
+// @Failure 412 {object} response.ErrorResponseDto
+// @Failure 428 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
 // @Failure 500 {object} response.ErrorResponseDto
 // @Security ApiKeyAuth
 // @Router /post/{id} [put]
@@ -204,6 +249,11 @@ func (h *Handler) UpdatePost(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errorResp, isVersion := VersionErrorsHandler(w, err, post.Model); isVersion {
+			errorRespond(w, r, errorResp)
+			return
+		}
+
 		var errorResp responsedto.ErrorResponseDto
 		if err == repoerrors.ErrPostNotFound {
 			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
@@ -215,6 +265,7 @@ func (h *Handler) UpdatePost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	writeValidator(w, post.Validator())
 	response.TransformFromObject(post)
 	respond(w, r, http.StatusAccepted, response)
 }
@@ -229,6 +280,7 @@ func (h *Handler) UpdatePost(w http.ResponseWriter, r *http.Request) {
 // @Success 202 {object} response.PostResponseDto
 // @Failure 401 {object} response.ErrorResponseDto
 // @Failure 404 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
 // @Failure 500 {object} response.ErrorResponseDto
 // @Security ApiKeyAuth
 // @Router /post/{id}/publish [get]
@@ -267,6 +319,7 @@ func (h *Handler) PublishPost(w http.ResponseWriter, r *http.Request) {
 // @Failure 401 {object} response.ErrorResponseDto
 // @Failure 403 {object} response.ErrorResponseDto
 // @Failure 404 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
 // @Failure 500 {object} response.ErrorResponseDto
 // @Security ApiKeyAuth
 // @Router /post/{id} [delete]
@@ -299,3 +352,60 @@ func (h *Handler) DeletePost(w http.ResponseWriter, r *http.Request) {
 
 	respond(w, r, http.StatusNoContent, nil)
 }
+
+// @Summary Bulk post operation
+// @Description Apply one action to many self posts in single transaction
+// @ID post-bulk
+// @Tags Post
+// @Accept json
+// @Produce json
+// @Param payload body request.BulkPostRequestDto true "Posts ids and action"
+// @Success 200 {object} response.BulkPostResponseDto
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 401 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 409 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /post/bulk [post]
+func (h *Handler) BulkPosts(w http.ResponseWriter, r *http.Request) {
+	request := requsetdto.BulkPostRequestDto{}
+	response := responsedto.BulkPostResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.BulkPosts error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+	results, err := h.Service.Post.Bulk(r.Context(), opt)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.BulkPosts error: %s", err)
+
+		if errorResp, isValidation := ValidationErrorsHandler(err); isValidation {
+			errorRespond(w, r, errorResp)
+			return
+		}
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case repoerrors.ErrPostVersionConflict:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusConflict, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(results)
+	respond(w, r, http.StatusOK, response)
+}
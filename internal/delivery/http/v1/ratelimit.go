@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	requestdto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/request"
+	responsedto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	"github.com/aintsashqa/go-simple-blog/pkg/ratelimit"
+)
+
+// rateLimit limits requests of group per authenticated user, or per address
+// of anonymous client, so it must be used after authenticateMiddleware to
+// limit users
+func (h *Handler) rateLimit(group string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			input := service.RateLimitInput{Group: group, IP: requestdto.ClientIP(r)}
+			if identity, casted := requestdto.IdentityFromContext(r.Context()); casted {
+				input.UserID = identity.UserID
+			}
+
+			result, err := h.Service.RateLimit.Allow(r.Context(), input)
+			if err != nil {
+
+				h.Service.Logger.Errorf("v1.rateLimit error: %s", err)
+
+				errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+				errorRespond(w, r, errorResp)
+				return
+			}
+
+			writeRateLimit(w, result)
+
+			if !result.Allowed {
+
+				h.Service.Logger.Errorf("v1.rateLimit error: %s", errors.ErrRateLimitExceeded)
+
+				w.Header().Set("Retry-After", strconv.FormatInt(seconds(result.RetryAfter), 10))
+				errorResp := responsedto.NewErrorResponseDto(http.StatusTooManyRequests, errors.ErrRateLimitExceeded.Error())
+				errorRespond(w, r, errorResp)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeRateLimit writes `RateLimit-*` headers, unlimited groups have none
+func writeRateLimit(w http.ResponseWriter, result ratelimit.Result) {
+	if result.Limit == 0 {
+		return
+	}
+
+	w.Header().Set("RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+	w.Header().Set("RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
+	w.Header().Set("RateLimit-Reset", strconv.FormatInt(seconds(result.ResetAfter), 10))
+}
+
+// seconds rounds duration up, so client never retries too early
+func seconds(d time.Duration) int64 {
+	value := int64(math.Ceil(d.Seconds()))
+	if value < 1 {
+		return 1
+	}
+	return value
+}
@@ -0,0 +1,74 @@
+package request
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	"github.com/go-chi/chi"
+	uuid "github.com/satori/go.uuid"
+)
+
+type CreatePersonalAccessTokenRequestDto struct {
+	UserID uuid.UUID `json:"-"`
+	Name   string    `json:"name"`
+	Scopes []string  `json:"scopes"`
+}
+
+func (dto *CreatePersonalAccessTokenRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.UserID = userID
+
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *CreatePersonalAccessTokenRequestDto) TransformToObject() service.CreatePersonalAccessTokenInput {
+	scopes := domain.Scopes{}
+	for _, scope := range dto.Scopes {
+		scopes = append(scopes, domain.Scope(scope))
+	}
+
+	return service.CreatePersonalAccessTokenInput{
+		UserID: dto.UserID,
+		Name:   dto.Name,
+		Scopes: scopes,
+	}
+}
+
+type RevokePersonalAccessTokenRequestDto struct {
+	ID     uuid.UUID `json:"-"`
+	UserID uuid.UUID `json:"-"`
+}
+
+func (dto *RevokePersonalAccessTokenRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.UserID = userID
+	dto.ID = uuid.FromStringOrNil(chi.URLParam(r, "id"))
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *RevokePersonalAccessTokenRequestDto) TransformToObject() service.RevokePersonalAccessTokenInput {
+	return service.RevokePersonalAccessTokenInput{
+		ID:     dto.ID,
+		UserID: dto.UserID,
+	}
+}
@@ -0,0 +1,97 @@
+package request
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	"github.com/go-chi/chi"
+	uuid "github.com/satori/go.uuid"
+)
+
+type SearchUsersRequestDto struct {
+	Query          string `json:"-"`
+	IncludeDeleted bool   `json:"-"`
+	CurrentPage    int    `json:"-"`
+	CountPerPage   int    `json:"-"`
+}
+
+func (dto *SearchUsersRequestDto) FromRequest(r *http.Request) {
+	currentPage, err := strconv.Atoi(r.URL.Query().Get("current_page"))
+	if err != nil {
+		currentPage = DefaultCurrentPage
+	}
+
+	countPerPage, err := strconv.Atoi(r.URL.Query().Get("count_per_page"))
+	if err != nil {
+		countPerPage = DefaultCountPerPage
+	}
+
+	includeDeleted, _ := strconv.ParseBool(r.URL.Query().Get("include_deleted"))
+
+	dto.Query = r.URL.Query().Get("query")
+	dto.IncludeDeleted = includeDeleted
+	dto.CurrentPage = currentPage
+	dto.CountPerPage = countPerPage
+}
+
+func (dto *SearchUsersRequestDto) TransformToObject() service.SearchUsersOptions {
+	return service.SearchUsersOptions{
+		Query:          dto.Query,
+		IncludeDeleted: dto.IncludeDeleted,
+		CurrentPage:    dto.CurrentPage,
+		UsersPerPage:   dto.CountPerPage,
+	}
+}
+
+type ModerateUserRequestDto struct {
+	ActorID uuid.UUID `json:"-"`
+	UserID  uuid.UUID `json:"-"`
+}
+
+func (dto *ModerateUserRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	actorID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.ActorID = actorID
+	dto.UserID = uuid.FromStringOrNil(chi.URLParam(r, "id"))
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *ModerateUserRequestDto) TransformToObject() service.ModerateUserInput {
+	return service.ModerateUserInput{
+		ActorID: dto.ActorID,
+		UserID:  dto.UserID,
+	}
+}
+
+type ModeratePostRequestDto struct {
+	ActorID uuid.UUID `json:"-"`
+	PostID  uuid.UUID `json:"-"`
+}
+
+func (dto *ModeratePostRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	actorID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.ActorID = actorID
+	dto.PostID = uuid.FromStringOrNil(chi.URLParam(r, "id"))
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *ModeratePostRequestDto) TransformToObject() service.ModeratePostInput {
+	return service.ModeratePostInput{
+		ActorID: dto.ActorID,
+		PostID:  dto.PostID,
+	}
+}
@@ -0,0 +1,28 @@
+package request
+
+import (
+	"context"
+
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+// contextKey is unexported, so values could not be overwritten by other packages
+type contextKey int
+
+const identityContextKey contextKey = iota
+
+func WithIdentity(ctx context.Context, identity service.Identity) context.Context {
+	return context.WithValue(ctx, identityContextKey, identity)
+}
+
+// IdentityFromContext returns user authenticated by middleware
+func IdentityFromContext(ctx context.Context) (service.Identity, bool) {
+	identity, casted := ctx.Value(identityContextKey).(service.Identity)
+	return identity, casted
+}
+
+func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	identity, casted := IdentityFromContext(ctx)
+	return identity.UserID, casted
+}
@@ -0,0 +1,60 @@
+package request
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	"github.com/go-chi/chi"
+	uuid "github.com/satori/go.uuid"
+)
+
+type ExportUserRequestDto struct {
+	UserID         uuid.UUID `json:"-"`
+	IncludeDeleted bool      `json:"-"`
+}
+
+func (dto *ExportUserRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	includeDeleted, err := strconv.ParseBool(r.URL.Query().Get("include_deleted"))
+	if err != nil {
+		includeDeleted = false
+	}
+
+	dto.UserID = userID
+	dto.IncludeDeleted = includeDeleted
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *ExportUserRequestDto) TransformToObject() service.ExportInput {
+	return service.ExportInput{
+		UserID:         dto.UserID,
+		IncludeDeleted: dto.IncludeDeleted,
+	}
+}
+
+type DownloadExportRequestDto struct {
+	UserID   uuid.UUID `json:"-"`
+	ExportID string    `json:"-"`
+}
+
+func (dto *DownloadExportRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.UserID = userID
+	dto.ExportID = chi.URLParam(r, "id")
+
+	return response.ErrorResponseDto{}, nil
+}
@@ -0,0 +1,100 @@
+package request
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	"github.com/go-chi/chi"
+	uuid "github.com/satori/go.uuid"
+)
+
+type FollowRequestDto struct {
+	FollowerID uuid.UUID `json:"-"`
+	FolloweeID uuid.UUID `json:"-"`
+}
+
+func (dto *FollowRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.FollowerID = userID
+	dto.FolloweeID = uuid.FromStringOrNil(chi.URLParam(r, "id"))
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *FollowRequestDto) TransformToObject() service.FollowInput {
+	return service.FollowInput{
+		FollowerID: dto.FollowerID,
+		FolloweeID: dto.FolloweeID,
+	}
+}
+
+type FollowPaginationRequestDto struct {
+	CurrentPage  int       `json:"-"`
+	CountPerPage int       `json:"-"`
+	UserID       uuid.UUID `json:"-"`
+}
+
+func (dto *FollowPaginationRequestDto) FromRequest(r *http.Request) {
+	currentPage, err := strconv.Atoi(r.URL.Query().Get("current_page"))
+	if err != nil {
+		currentPage = DefaultCurrentPage
+	}
+
+	countPerPage, err := strconv.Atoi(r.URL.Query().Get("count_per_page"))
+	if err != nil {
+		countPerPage = DefaultCountPerPage
+	}
+
+	dto.CurrentPage = currentPage
+	dto.CountPerPage = countPerPage
+	dto.UserID = uuid.FromStringOrNil(chi.URLParam(r, "id"))
+}
+
+func (dto *FollowPaginationRequestDto) TransformToObject() service.PaginateFollowOptions {
+	return service.PaginateFollowOptions{
+		UserID:       dto.UserID,
+		CurrentPage:  dto.CurrentPage,
+		UsersPerPage: dto.CountPerPage,
+	}
+}
+
+type FeedRequestDto struct {
+	Cursor       string    `json:"-"`
+	CountPerPage int       `json:"-"`
+	UserID       uuid.UUID `json:"-"`
+}
+
+func (dto *FeedRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	countPerPage, err := strconv.Atoi(r.URL.Query().Get("count_per_page"))
+	if err != nil {
+		countPerPage = DefaultCountPerPage
+	}
+
+	dto.Cursor = r.URL.Query().Get("cursor")
+	dto.CountPerPage = countPerPage
+	dto.UserID = userID
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *FeedRequestDto) TransformToObject() service.FeedOptions {
+	return service.FeedOptions{
+		UserID:       dto.UserID,
+		Cursor:       dto.Cursor,
+		PostsPerPage: dto.CountPerPage,
+	}
+}
@@ -0,0 +1,150 @@
+package request
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+type MFAChallengeRequestDto struct {
+	MFAToken string `json:"mfa_token"`
+}
+
+func (dto *MFAChallengeRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *MFAChallengeRequestDto) TransformToObject() service.MFAChallengeInput {
+	return service.MFAChallengeInput{
+		Token: dto.MFAToken,
+	}
+}
+
+// VerifyMFARequestDto contains either code or recovery code
+type VerifyMFARequestDto struct {
+	MFAToken     string         `json:"mfa_token"`
+	Code         string         `json:"code"`
+	RecoveryCode string         `json:"recovery_code"`
+	Client       service.Client `json:"-"`
+}
+
+func (dto *VerifyMFARequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	dto.Client = ClientFromRequest(r)
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *VerifyMFARequestDto) TransformToObject() service.VerifyMFAInput {
+	return service.VerifyMFAInput{
+		Token:        dto.MFAToken,
+		Code:         dto.Code,
+		RecoveryCode: dto.RecoveryCode,
+		Client:       dto.Client,
+	}
+}
+
+type ConfirmTOTPRequestDto struct {
+	UserID uuid.UUID `json:"-"`
+	Code   string    `json:"code"`
+}
+
+func (dto *ConfirmTOTPRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.UserID = userID
+
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *ConfirmTOTPRequestDto) TransformToObject() service.ConfirmTOTPInput {
+	return service.ConfirmTOTPInput{
+		UserID: dto.UserID,
+		Code:   dto.Code,
+	}
+}
+
+type AuthorizeMFARequestDto struct {
+	UserID   uuid.UUID `json:"-"`
+	Password string    `json:"password"`
+}
+
+func (dto *AuthorizeMFARequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.UserID = userID
+
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *AuthorizeMFARequestDto) TransformToObject() service.AuthorizeMFAInput {
+	return service.AuthorizeMFAInput{
+		UserID:   dto.UserID,
+		Password: dto.Password,
+	}
+}
+
+type SetMFARolesRequestDto struct {
+	ActorID uuid.UUID `json:"-"`
+	Roles   []string  `json:"roles"`
+}
+
+func (dto *SetMFARolesRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	actorID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.ActorID = actorID
+
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *SetMFARolesRequestDto) TransformToObject() service.SetMFARolesInput {
+	roles := domain.Roles{}
+	for _, role := range dto.Roles {
+		roles = append(roles, domain.Role(role))
+	}
+
+	return service.SetMFARolesInput{
+		ActorID: dto.ActorID,
+		Roles:   roles,
+	}
+}
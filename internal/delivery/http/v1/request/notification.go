@@ -0,0 +1,75 @@
+package request
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	"github.com/go-chi/chi"
+	uuid "github.com/satori/go.uuid"
+)
+
+type NotificationPaginationRequestDto struct {
+	CurrentPage  int       `json:"-"`
+	CountPerPage int       `json:"-"`
+	UserID       uuid.UUID `json:"-"`
+}
+
+func (dto *NotificationPaginationRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	currentPage, err := strconv.Atoi(r.URL.Query().Get("current_page"))
+	if err != nil {
+		currentPage = DefaultCurrentPage
+	}
+
+	countPerPage, err := strconv.Atoi(r.URL.Query().Get("count_per_page"))
+	if err != nil {
+		countPerPage = DefaultCountPerPage
+	}
+
+	dto.CurrentPage = currentPage
+	dto.CountPerPage = countPerPage
+	dto.UserID = userID
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *NotificationPaginationRequestDto) TransformToObject() service.PaginateNotificationOptions {
+	return service.PaginateNotificationOptions{
+		UserID:               dto.UserID,
+		CurrentPage:          dto.CurrentPage,
+		NotificationsPerPage: dto.CountPerPage,
+	}
+}
+
+type MarkNotificationReadRequestDto struct {
+	ID     uuid.UUID `json:"-"`
+	UserID uuid.UUID `json:"-"`
+}
+
+func (dto *MarkNotificationReadRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.UserID = userID
+	dto.ID = uuid.FromStringOrNil(chi.URLParam(r, "id"))
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *MarkNotificationReadRequestDto) TransformToObject() service.MarkNotificationReadInput {
+	return service.MarkNotificationReadInput{
+		ID:     dto.ID,
+		UserID: dto.UserID,
+	}
+}
@@ -0,0 +1,47 @@
+package request
+
+import (
+	"net/http"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+)
+
+// OIDCSessionCookie keeps signed login session between redirects
+const OIDCSessionCookie string = "oidc_session"
+
+type OIDCCallbackRequestDto struct {
+	Code    string         `json:"-"`
+	State   string         `json:"-"`
+	Session string         `json:"-"`
+	Client  service.Client `json:"-"`
+}
+
+func (dto *OIDCCallbackRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	// Identity provider returns error instead of code, when user denied access
+	if value := r.URL.Query().Get("error"); value != "" {
+		response := response.NewErrorResponseDto(http.StatusUnauthorized, errors.ErrOIDCAuthorizationDenied.Error(), value)
+		return response, errors.ErrOIDCAuthorizationDenied
+	}
+
+	dto.Code = r.URL.Query().Get("code")
+	dto.State = r.URL.Query().Get("state")
+
+	if cookie, err := r.Cookie(OIDCSessionCookie); err == nil {
+		dto.Session = cookie.Value
+	}
+
+	dto.Client = ClientFromRequest(r)
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *OIDCCallbackRequestDto) TransformToObject() service.OIDCCallbackInput {
+	return service.OIDCCallbackInput{
+		Code:    dto.Code,
+		State:   dto.State,
+		Session: dto.Session,
+		Client:  dto.Client,
+	}
+}
@@ -56,7 +56,7 @@ type SelfPostPaginationRequestDto struct {
 }
 
 func (dto *SelfPostPaginationRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
-	userID, casted := r.Context().Value("user_id").(uuid.UUID)
+	userID, casted := UserIDFromContext(r.Context())
 	if !casted {
 		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
 		return response, errors.ErrInvalidTokenUserId
@@ -91,12 +91,13 @@ type CreatePostRequestDto struct {
 	Title       string    `json:"title"`
 	Slug        string    `json:"slug"`
 	Content     string    `json:"content"`
+	Tags        []string  `json:"tags"`
 	UserID      uuid.UUID `json:"-"`
 	IsPublished bool      `json:"is_published"`
 }
 
 func (dto *CreatePostRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
-	id, casted := r.Context().Value("user_id").(uuid.UUID)
+	id, casted := UserIDFromContext(r.Context())
 	if !casted {
 		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
 		return response, errors.ErrInvalidTokenUserId
@@ -117,6 +118,7 @@ func (dto *CreatePostRequestDto) TransformToObject() service.CreatePostInput {
 		Title:       dto.Title,
 		Slug:        dto.Slug,
 		Content:     dto.Content,
+		Tags:        dto.Tags,
 		UserID:      dto.UserID,
 		IsPublished: dto.IsPublished,
 	}
@@ -127,11 +129,15 @@ type UpdatePostRequestDto struct {
 	Title       string    `json:"title"`
 	Slug        string    `json:"slug"`
 	Content     string    `json:"content"`
+	Tags        []string  `json:"tags"`
 	IsPublished bool      `json:"is_published"`
+	Version     int       `json:"version"`
+	ETag        string    `json:"-"`
 }
 
 func (dto *UpdatePostRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
 	dto.ID = uuid.FromStringOrNil(chi.URLParam(r, "id"))
+	dto.ETag = r.Header.Get("If-Match")
 
 	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
 		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
@@ -147,7 +153,10 @@ func (dto *UpdatePostRequestDto) TransformToObject() service.UpdatePostInput {
 		Title:       dto.Title,
 		Slug:        dto.Slug,
 		Content:     dto.Content,
+		Tags:        dto.Tags,
 		IsPublished: dto.IsPublished,
+		Version:     dto.Version,
+		ETag:        dto.ETag,
 	}
 }
 
@@ -157,7 +166,7 @@ type DeletePostRequestDto struct {
 }
 
 func (dto *DeletePostRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
-	userID, casted := r.Context().Value("user_id").(uuid.UUID)
+	userID, casted := UserIDFromContext(r.Context())
 	if !casted {
 		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
 		return response, errors.ErrInvalidTokenUserId
@@ -177,3 +186,36 @@ func (dto *DeletePostRequestDto) TransformToObject() service.SoftDeletePostInput
 		PostID: dto.PostID,
 	}
 }
+
+type BulkPostRequestDto struct {
+	UserID uuid.UUID   `json:"-"`
+	IDs    []uuid.UUID `json:"ids"`
+	Action string      `json:"action" enums:"publish,unpublish,delete,restore,retag"`
+	Tags   []string    `json:"tags"`
+}
+
+func (dto *BulkPostRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.UserID = userID
+
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *BulkPostRequestDto) TransformToObject() service.BulkPostInput {
+	return service.BulkPostInput{
+		UserID: dto.UserID,
+		IDs:    dto.IDs,
+		Action: service.BulkPostAction(dto.Action),
+		Tags:   dto.Tags,
+	}
+}
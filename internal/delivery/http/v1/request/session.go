@@ -0,0 +1,36 @@
+package request
+
+import (
+	"net/http"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	"github.com/go-chi/chi"
+	uuid "github.com/satori/go.uuid"
+)
+
+type RevokeSessionRequestDto struct {
+	ID     uuid.UUID `json:"-"`
+	UserID uuid.UUID `json:"-"`
+}
+
+func (dto *RevokeSessionRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.UserID = userID
+	dto.ID = uuid.FromStringOrNil(chi.URLParam(r, "id"))
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *RevokeSessionRequestDto) TransformToObject() service.RevokeSessionInput {
+	return service.RevokeSessionInput{
+		ID:     dto.ID,
+		UserID: dto.UserID,
+	}
+}
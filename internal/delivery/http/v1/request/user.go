@@ -2,10 +2,14 @@ package request
 
 import (
 	"encoding/json"
+	"io"
+	"net"
 	"net/http"
+	"strings"
 
 	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
 	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
 	"github.com/aintsashqa/go-simple-blog/internal/service"
 	"github.com/go-chi/chi"
 	uuid "github.com/satori/go.uuid"
@@ -13,6 +17,7 @@ import (
 
 type SignUpUserRequestDto struct {
 	Email    string `json:"email"`
+	Username string `json:"username"`
 	Password string `json:"password"`
 }
 
@@ -28,13 +33,15 @@ func (dto *SignUpUserRequestDto) FromRequest(r *http.Request) (response.ErrorRes
 func (dto *SignUpUserRequestDto) TransformToObject() service.SignUpUserInput {
 	return service.SignUpUserInput{
 		Email:    dto.Email,
+		Username: dto.Username,
 		Password: dto.Password,
 	}
 }
 
 type SignInUserRequestDto struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string         `json:"email"`
+	Password string         `json:"password"`
+	Client   service.Client `json:"-"`
 }
 
 func (dto *SignInUserRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
@@ -43,6 +50,7 @@ func (dto *SignInUserRequestDto) FromRequest(r *http.Request) (response.ErrorRes
 		return response, errors.ErrUnavailableRequestBody
 	}
 
+	dto.Client = ClientFromRequest(r)
 	return response.ErrorResponseDto{}, nil
 }
 
@@ -50,6 +58,199 @@ func (dto *SignInUserRequestDto) TransformToObject() service.SignInUserInput {
 	return service.SignInUserInput{
 		Email:    dto.Email,
 		Password: dto.Password,
+		Client:   dto.Client,
+	}
+}
+
+type RefreshTokenRequestDto struct {
+	RefreshToken string         `json:"refresh_token"`
+	Client       service.Client `json:"-"`
+}
+
+func (dto *RefreshTokenRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	dto.Client = ClientFromRequest(r)
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *RefreshTokenRequestDto) TransformToObject() service.RefreshTokenInput {
+	return service.RefreshTokenInput{
+		RefreshToken: dto.RefreshToken,
+		Client:       dto.Client,
+	}
+}
+
+type SignOutRequestDto struct {
+	AccessToken  string `json:"-"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (dto *SignOutRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	headerPieces := strings.Split(r.Header.Get("Authorization"), " ")
+	if len(headerPieces) != 2 {
+		response := response.NewErrorResponseDto(http.StatusUnauthorized, errors.ErrInvalidAuthorizationHeader.Error())
+		return response, errors.ErrInvalidAuthorizationHeader
+	}
+
+	dto.AccessToken = headerPieces[1]
+
+	// Refresh token is optional, so empty body is allowed
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil && err != io.EOF {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *SignOutRequestDto) TransformToObject() service.SignOutInput {
+	return service.SignOutInput{
+		AccessToken:  dto.AccessToken,
+		RefreshToken: dto.RefreshToken,
+	}
+}
+
+type VerifyEmailRequestDto struct {
+	Token string `json:"token"`
+}
+
+func (dto *VerifyEmailRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *VerifyEmailRequestDto) TransformToObject() service.VerifyEmailInput {
+	return service.VerifyEmailInput{
+		Token: dto.Token,
+	}
+}
+
+type ForgotPasswordRequestDto struct {
+	Email string `json:"email"`
+}
+
+func (dto *ForgotPasswordRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *ForgotPasswordRequestDto) TransformToObject() service.ForgotPasswordInput {
+	return service.ForgotPasswordInput{
+		Email: dto.Email,
+	}
+}
+
+type ResetPasswordRequestDto struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+func (dto *ResetPasswordRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *ResetPasswordRequestDto) TransformToObject() service.ResetPasswordInput {
+	return service.ResetPasswordInput{
+		Token:    dto.Token,
+		Password: dto.Password,
+	}
+}
+
+type ChangePasswordRequestDto struct {
+	ID              uuid.UUID `json:"-"`
+	CurrentPassword string    `json:"current_password"`
+	NewPassword     string    `json:"new_password"`
+}
+
+func (dto *ChangePasswordRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.ID = userID
+
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *ChangePasswordRequestDto) TransformToObject() service.ChangePasswordInput {
+	return service.ChangePasswordInput{
+		ID:              dto.ID,
+		CurrentPassword: dto.CurrentPassword,
+		NewPassword:     dto.NewPassword,
+	}
+}
+
+type ChangeEmailRequestDto struct {
+	ID       uuid.UUID `json:"-"`
+	Email    string    `json:"email"`
+	Password string    `json:"password"`
+}
+
+func (dto *ChangeEmailRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.ID = userID
+
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *ChangeEmailRequestDto) TransformToObject() service.ChangeEmailInput {
+	return service.ChangeEmailInput{
+		ID:       dto.ID,
+		Email:    dto.Email,
+		Password: dto.Password,
+	}
+}
+
+type ConfirmEmailChangeRequestDto struct {
+	Token string `json:"token"`
+}
+
+func (dto *ConfirmEmailChangeRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *ConfirmEmailChangeRequestDto) TransformToObject() service.ConfirmEmailChangeInput {
+	return service.ConfirmEmailChangeInput{
+		Token: dto.Token,
 	}
 }
 
@@ -58,7 +259,7 @@ type SelfUserRequestDto struct {
 }
 
 func (dto *SelfUserRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
-	userID, casted := r.Context().Value("user_id").(uuid.UUID)
+	userID, casted := UserIDFromContext(r.Context())
 	if !casted {
 		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
 		return response, errors.ErrInvalidTokenUserId
@@ -68,13 +269,22 @@ func (dto *SelfUserRequestDto) FromRequest(r *http.Request) (response.ErrorRespo
 	return response.ErrorResponseDto{}, nil
 }
 
+// UpdateUserRequestDto is partial, omitted fields are not changed
 type UpdateUserRequestDto struct {
-	ID       uuid.UUID `json:"-"`
-	Username string    `json:"username"`
+	ID          uuid.UUID `json:"-"`
+	Username    *string   `json:"username,omitempty"`
+	DisplayName *string   `json:"display_name,omitempty"`
+	Bio         *string   `json:"bio,omitempty"`
+	AvatarURL   *string   `json:"avatar_url,omitempty"`
+	Location    *string   `json:"location,omitempty"`
+	Website     *string   `json:"website,omitempty"`
+	SocialLinks *[]string `json:"social_links,omitempty"`
+	Version     int       `json:"version"`
+	ETag        string    `json:"-"`
 }
 
 func (dto *UpdateUserRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
-	authorizeUserID, casted := r.Context().Value("user_id").(uuid.UUID)
+	authorizeUserID, casted := UserIDFromContext(r.Context())
 	if !casted {
 		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
 		return response, errors.ErrInvalidTokenUserId
@@ -88,6 +298,7 @@ func (dto *UpdateUserRequestDto) FromRequest(r *http.Request) (response.ErrorRes
 	}
 
 	dto.ID = urlUserID
+	dto.ETag = r.Header.Get("If-Match")
 
 	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
 		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
@@ -99,7 +310,94 @@ func (dto *UpdateUserRequestDto) FromRequest(r *http.Request) (response.ErrorRes
 
 func (dto *UpdateUserRequestDto) TransformToObject() service.UpdateUserInput {
 	return service.UpdateUserInput{
-		ID:       dto.ID,
-		Username: dto.Username,
+		ID:          dto.ID,
+		Username:    dto.Username,
+		DisplayName: dto.DisplayName,
+		Bio:         dto.Bio,
+		AvatarURL:   dto.AvatarURL,
+		Location:    dto.Location,
+		Website:     dto.Website,
+		SocialLinks: dto.SocialLinks,
+		Version:     dto.Version,
+		ETag:        dto.ETag,
+	}
+}
+
+type DeleteUserRequestDto struct {
+	ID         uuid.UUID `json:"-"`
+	Password   string    `json:"password"`
+	Posts      string    `json:"posts"`
+	TransferTo uuid.UUID `json:"transfer_to"`
+}
+
+func (dto *DeleteUserRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	authorizeUserID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	urlUserID := uuid.FromStringOrNil(chi.URLParam(r, "id"))
+
+	if authorizeUserID != urlUserID {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidAuthorizedUserID.Error())
+		return response, errors.ErrInvalidAuthorizedUserID
+	}
+
+	dto.ID = urlUserID
+
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *DeleteUserRequestDto) TransformToObject() service.DeleteAccountInput {
+	return service.DeleteAccountInput{
+		ID:          dto.ID,
+		Password:    dto.Password,
+		PostsAction: domain.PostsAction(dto.Posts),
+		TransferTo:  dto.TransferTo,
+	}
+}
+
+type RestoreUserRequestDto struct {
+	Email    string         `json:"email"`
+	Password string         `json:"password"`
+	Client   service.Client `json:"-"`
+}
+
+func (dto *RestoreUserRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	dto.Client = ClientFromRequest(r)
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *RestoreUserRequestDto) TransformToObject() service.RestoreAccountInput {
+	return service.RestoreAccountInput{
+		Email:    dto.Email,
+		Password: dto.Password,
+		Client:   dto.Client,
+	}
+}
+
+// ClientFromRequest describes device which sent request
+func ClientFromRequest(r *http.Request) service.Client {
+	return service.Client{UserAgent: r.UserAgent(), IP: ClientIP(r)}
+}
+
+// ClientIP is address of connection, forwarded headers are not trusted,
+// because they could be set by client
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
 	}
+	return host
 }
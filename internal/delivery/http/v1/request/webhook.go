@@ -0,0 +1,152 @@
+package request
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	"github.com/go-chi/chi"
+	uuid "github.com/satori/go.uuid"
+)
+
+type CreateWebhookRequestDto struct {
+	UserID uuid.UUID `json:"-"`
+	URL    string    `json:"url"`
+	Events []string  `json:"events"`
+	Global bool      `json:"global"`
+}
+
+// FromRequest allows global webhooks only for admins
+func (dto *CreateWebhookRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	identity, casted := IdentityFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.UserID = identity.UserID
+
+	if err := json.NewDecoder(r.Body).Decode(&dto); err != nil {
+		response := response.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrUnavailableRequestBody.Error())
+		return response, errors.ErrUnavailableRequestBody
+	}
+
+	if dto.Global && !identity.HasRole(domain.AdminRole) {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInsufficientRole.Error())
+		return response, errors.ErrInsufficientRole
+	}
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *CreateWebhookRequestDto) TransformToObject() service.CreateWebhookInput {
+	events := domain.WebhookEvents{}
+	for _, event := range dto.Events {
+		events = append(events, domain.WebhookEvent(event))
+	}
+
+	return service.CreateWebhookInput{
+		UserID: dto.UserID,
+		URL:    dto.URL,
+		Events: events,
+		Global: dto.Global,
+	}
+}
+
+type DeleteWebhookRequestDto struct {
+	ID     uuid.UUID `json:"-"`
+	UserID uuid.UUID `json:"-"`
+}
+
+func (dto *DeleteWebhookRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.UserID = userID
+	dto.ID = uuid.FromStringOrNil(chi.URLParam(r, "id"))
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *DeleteWebhookRequestDto) TransformToObject() service.DeleteWebhookInput {
+	return service.DeleteWebhookInput{
+		ID:     dto.ID,
+		UserID: dto.UserID,
+	}
+}
+
+type WebhookDeliveryPaginationRequestDto struct {
+	WebhookID    uuid.UUID `json:"-"`
+	UserID       uuid.UUID `json:"-"`
+	CurrentPage  int       `json:"-"`
+	CountPerPage int       `json:"-"`
+}
+
+func (dto *WebhookDeliveryPaginationRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	currentPage, err := strconv.Atoi(r.URL.Query().Get("current_page"))
+	if err != nil {
+		currentPage = DefaultCurrentPage
+	}
+
+	countPerPage, err := strconv.Atoi(r.URL.Query().Get("count_per_page"))
+	if err != nil {
+		countPerPage = DefaultCountPerPage
+	}
+
+	dto.WebhookID = uuid.FromStringOrNil(chi.URLParam(r, "id"))
+	dto.UserID = userID
+	dto.CurrentPage = currentPage
+	dto.CountPerPage = countPerPage
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *WebhookDeliveryPaginationRequestDto) TransformToObject() service.PaginateWebhookDeliveryOptions {
+	return service.PaginateWebhookDeliveryOptions{
+		WebhookID:         dto.WebhookID,
+		UserID:            dto.UserID,
+		CurrentPage:       dto.CurrentPage,
+		DeliveriesPerPage: dto.CountPerPage,
+	}
+}
+
+type RedeliverWebhookRequestDto struct {
+	ID        uuid.UUID `json:"-"`
+	WebhookID uuid.UUID `json:"-"`
+	UserID    uuid.UUID `json:"-"`
+}
+
+func (dto *RedeliverWebhookRequestDto) FromRequest(r *http.Request) (response.ErrorResponseDto, error) {
+	userID, casted := UserIDFromContext(r.Context())
+	if !casted {
+		response := response.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		return response, errors.ErrInvalidTokenUserId
+	}
+
+	dto.UserID = userID
+	dto.WebhookID = uuid.FromStringOrNil(chi.URLParam(r, "id"))
+	dto.ID = uuid.FromStringOrNil(chi.URLParam(r, "delivery_id"))
+
+	return response.ErrorResponseDto{}, nil
+}
+
+func (dto *RedeliverWebhookRequestDto) TransformToObject() service.RedeliverWebhookInput {
+	return service.RedeliverWebhookInput{
+		ID:        dto.ID,
+		WebhookID: dto.WebhookID,
+		UserID:    dto.UserID,
+	}
+}
@@ -2,8 +2,11 @@ package v1
 
 import (
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
 	"github.com/go-chi/render"
 )
 
@@ -15,3 +18,40 @@ func respond(w http.ResponseWriter, r *http.Request, status int, payload interfa
 func errorRespond(w http.ResponseWriter, r *http.Request, err response.ErrorResponseDto) {
 	respond(w, r, err.Code, err)
 }
+
+func isConditional(r *http.Request) bool {
+	return len(r.Header.Get("If-None-Match")) != 0 || len(r.Header.Get("If-Modified-Since")) != 0
+}
+
+// isNotModified evaluates conditional headers against current validator,
+// `If-Modified-Since` is ignored when `If-None-Match` is present
+func isNotModified(r *http.Request, validator domain.Validator) bool {
+	if match := r.Header.Get("If-None-Match"); len(match) != 0 {
+		for _, value := range strings.Split(match, ",") {
+			value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+			if value == "*" || value == validator.ETag {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || validator.LastModified.IsZero() {
+		return false
+	}
+
+	return !validator.LastModified.Truncate(time.Second).After(since)
+}
+
+func writeValidator(w http.ResponseWriter, validator domain.Validator) {
+	w.Header().Set("ETag", validator.ETag)
+	if !validator.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", validator.LastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+func notModifiedRespond(w http.ResponseWriter, validator domain.Validator) {
+	writeValidator(w, validator)
+	w.WriteHeader(http.StatusNotModified)
+}
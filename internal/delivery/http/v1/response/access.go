@@ -0,0 +1,56 @@
+package response
+
+import (
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+type PersonalAccessTokenResponseDto struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Prefix     string    `json:"prefix"`
+	Scopes     []string  `json:"scopes"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt null.Time `json:"last_used_at"`
+}
+
+func (dto *PersonalAccessTokenResponseDto) TransformFromObject(token domain.PersonalAccessToken) {
+	dto.ID = token.ID
+	dto.Name = token.Name
+	dto.Prefix = token.Prefix
+	dto.Scopes = []string{}
+	for _, scope := range token.Scopes {
+		dto.Scopes = append(dto.Scopes, string(scope))
+	}
+	dto.CreatedAt = token.CreatedAt
+	dto.LastUsedAt = token.LastUsedAt
+}
+
+// CreatedPersonalAccessTokenResponseDto holds value of token, value is
+// shown only once and could not be read later
+type CreatedPersonalAccessTokenResponseDto struct {
+	PersonalAccessTokenResponseDto
+	Token string `json:"token"`
+}
+
+func (dto *CreatedPersonalAccessTokenResponseDto) TransformFromObject(token domain.PersonalAccessToken, value string) {
+	dto.PersonalAccessTokenResponseDto.TransformFromObject(token)
+	dto.Token = value
+}
+
+type PersonalAccessTokenListResponseDto struct {
+	Tokens []PersonalAccessTokenResponseDto `json:"tokens"`
+}
+
+func (dto *PersonalAccessTokenListResponseDto) TransformFromObject(tokens []domain.PersonalAccessToken) {
+	dto.Tokens = []PersonalAccessTokenResponseDto{}
+
+	for _, token := range tokens {
+		temp := PersonalAccessTokenResponseDto{}
+		temp.TransformFromObject(token)
+		dto.Tokens = append(dto.Tokens, temp)
+	}
+}
@@ -0,0 +1,53 @@
+package response
+
+import (
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	"gopkg.in/guregu/null.v4"
+)
+
+// AdminUserResponseDto shows private fields of user to admins
+type AdminUserResponseDto struct {
+	UserResponseDto
+	IsSuspended bool      `json:"is_suspended"`
+	IsDeleted   bool      `json:"is_deleted"`
+	SuspendedAt null.Time `json:"suspended_at"`
+	DeletedAt   null.Time `json:"deleted_at"`
+}
+
+func (dto *AdminUserResponseDto) TransformFromObject(user domain.User) {
+	dto.UserResponseDto.TransformFromObject(user)
+
+	if user.SuspendedAt.Valid {
+		dto.IsSuspended = user.SuspendedAt.Valid
+		dto.SuspendedAt = user.SuspendedAt
+	}
+
+	if user.DeletedAt.Valid {
+		dto.IsDeleted = user.DeletedAt.Valid
+		dto.DeletedAt = user.DeletedAt
+	}
+}
+
+type AdminUserPaginationResponseDto struct {
+	Users      []AdminUserResponseDto `json:"users"`
+	Pagination PaginationResponseDto  `json:"pagination"`
+}
+
+func (dto *AdminUserPaginationResponseDto) TransformFromObject(pagination service.UserPagination) {
+	dto.Users = []AdminUserResponseDto{}
+
+	for _, user := range pagination.Users {
+		temp := AdminUserResponseDto{}
+		temp.TransformFromObject(user)
+		dto.Users = append(dto.Users, temp)
+	}
+
+	dto.Pagination = PaginationResponseDto{
+		Total:        pagination.UsersCount,
+		PreviousPage: pagination.PreviousPage,
+		CurrentPage:  pagination.CurrentPage,
+		NextPage:     pagination.NextPage,
+		CountPerPage: pagination.UsersPerPage,
+	}
+}
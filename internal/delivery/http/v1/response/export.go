@@ -0,0 +1,17 @@
+package response
+
+const (
+	ExportPendingStatus string = "pending"
+)
+
+type ExportResponseDto struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Location string `json:"location"`
+}
+
+func (dto *ExportResponseDto) TransformFromObject(id string, location string) {
+	dto.ID = id
+	dto.Status = ExportPendingStatus
+	dto.Location = location
+}
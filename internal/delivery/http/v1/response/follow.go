@@ -0,0 +1,45 @@
+package response
+
+import (
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+)
+
+type UserPaginationResponseDto struct {
+	Users      []UserResponseDto     `json:"users"`
+	Pagination PaginationResponseDto `json:"pagination"`
+}
+
+func (dto *UserPaginationResponseDto) TransformFromObject(pagination service.UserPagination) {
+	dto.Users = []UserResponseDto{}
+
+	for _, user := range pagination.Users {
+		temp := UserResponseDto{}
+		temp.TransformFromObject(user)
+		dto.Users = append(dto.Users, temp)
+	}
+
+	dto.Pagination = PaginationResponseDto{
+		Total:        pagination.UsersCount,
+		PreviousPage: pagination.PreviousPage,
+		CurrentPage:  pagination.CurrentPage,
+		NextPage:     pagination.NextPage,
+		CountPerPage: pagination.UsersPerPage,
+	}
+}
+
+type FeedResponseDto struct {
+	Posts      []PostResponseDto `json:"posts"`
+	NextCursor string            `json:"next_cursor"`
+}
+
+func (dto *FeedResponseDto) TransformFromObject(feed service.Feed) {
+	dto.Posts = []PostResponseDto{}
+
+	for _, post := range feed.Posts {
+		temp := PostResponseDto{}
+		temp.TransformFromObject(post)
+		dto.Posts = append(dto.Posts, temp)
+	}
+
+	dto.NextCursor = feed.NextCursor
+}
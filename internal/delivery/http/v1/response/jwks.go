@@ -0,0 +1,54 @@
+package response
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/auth"
+)
+
+// JSONWebKeyResponseDto describes public key of RFC 7517, parameters
+// are filled depending on type of key
+type JSONWebKeyResponseDto struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid,omitempty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+func (dto *JSONWebKeyResponseDto) TransformFromObject(key auth.PublicKey) {
+	dto.KeyID = key.ID
+	dto.Use = "sig"
+	dto.Algorithm = key.Algorithm
+
+	switch publicKey := key.Key.(type) {
+	case *rsa.PublicKey:
+		dto.KeyType = "RSA"
+		dto.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		dto.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case ed25519.PublicKey:
+		dto.KeyType = "OKP"
+		dto.Curve = "Ed25519"
+		dto.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	}
+}
+
+type JSONWebKeySetResponseDto struct {
+	Keys []JSONWebKeyResponseDto `json:"keys"`
+}
+
+func (dto *JSONWebKeySetResponseDto) TransformFromObject(keys []auth.PublicKey) {
+	dto.Keys = []JSONWebKeyResponseDto{}
+
+	for _, key := range keys {
+		temp := JSONWebKeyResponseDto{}
+		temp.TransformFromObject(key)
+		dto.Keys = append(dto.Keys, temp)
+	}
+}
@@ -0,0 +1,74 @@
+package response
+
+import (
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+)
+
+// MFAChallengeResponseDto is returned by sign in instead of tokens, when
+// second factor is required
+type MFAChallengeResponseDto struct {
+	MFAToken           string    `json:"mfa_token"`
+	EnrollmentRequired bool      `json:"enrollment_required"`
+	ExpiresAt          time.Time `json:"expires_at"`
+}
+
+func (dto *MFAChallengeResponseDto) TransformFromObject(challenge service.MFAChallenge) {
+	dto.MFAToken = challenge.Token
+	dto.EnrollmentRequired = challenge.EnrollmentRequired
+	dto.ExpiresAt = challenge.ExpiresAt
+}
+
+// MFATokenResponseDto contains recovery codes only when factor was enrolled
+type MFATokenResponseDto struct {
+	TokenResponseDto
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+func (dto *MFATokenResponseDto) TransformFromObject(verification service.MFAVerification) {
+	dto.TokenResponseDto.TransformFromObject(verification.Tokens)
+	dto.RecoveryCodes = verification.RecoveryCodes
+}
+
+type TOTPEnrollmentResponseDto struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+func (dto *TOTPEnrollmentResponseDto) TransformFromObject(enrollment service.TOTPEnrollment) {
+	dto.Secret = enrollment.Secret
+	dto.URI = enrollment.URI
+}
+
+type RecoveryCodesResponseDto struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func (dto *RecoveryCodesResponseDto) TransformFromObject(codes []string) {
+	dto.RecoveryCodes = codes
+}
+
+type MFAStatusResponseDto struct {
+	Enabled           bool `json:"enabled"`
+	Required          bool `json:"required"`
+	RecoveryCodesLeft int  `json:"recovery_codes_left"`
+}
+
+func (dto *MFAStatusResponseDto) TransformFromObject(status service.MFAStatus) {
+	dto.Enabled = status.Enabled
+	dto.Required = status.Required
+	dto.RecoveryCodesLeft = status.RecoveryCodes
+}
+
+type MFARolesResponseDto struct {
+	Roles []string `json:"roles"`
+}
+
+func (dto *MFARolesResponseDto) TransformFromObject(roles domain.Roles) {
+	dto.Roles = []string{}
+	for _, role := range roles {
+		dto.Roles = append(dto.Roles, string(role))
+	}
+}
@@ -0,0 +1,59 @@
+package response
+
+import (
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+type NotificationResponseDto struct {
+	ID        uuid.UUID  `json:"id"`
+	Type      string     `json:"type"`
+	ActorID   *uuid.UUID `json:"actor_id,omitempty"`
+	PostID    *uuid.UUID `json:"post_id,omitempty"`
+	Event     string     `json:"event,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    null.Time  `json:"read_at"`
+}
+
+func (dto *NotificationResponseDto) TransformFromObject(notification domain.Notification) {
+	dto.ID = notification.ID
+	dto.Type = string(notification.Type)
+	if notification.ActorID.Valid {
+		dto.ActorID = &notification.ActorID.UUID
+	}
+	if notification.PostID.Valid {
+		dto.PostID = &notification.PostID.UUID
+	}
+	dto.Event = string(notification.Event)
+	dto.CreatedAt = notification.CreatedAt
+	dto.ReadAt = notification.ReadAt
+}
+
+type NotificationPaginationResponseDto struct {
+	Notifications []NotificationResponseDto `json:"notifications"`
+	UnreadCount   int                       `json:"unread_count"`
+	Pagination    PaginationResponseDto     `json:"pagination"`
+}
+
+func (dto *NotificationPaginationResponseDto) TransformFromObject(pagination service.NotificationPagination) {
+	dto.Notifications = []NotificationResponseDto{}
+
+	for _, notification := range pagination.Notifications {
+		temp := NotificationResponseDto{}
+		temp.TransformFromObject(notification)
+		dto.Notifications = append(dto.Notifications, temp)
+	}
+
+	dto.UnreadCount = pagination.UnreadCount
+	dto.Pagination = PaginationResponseDto{
+		Total:        pagination.NotificationsCount,
+		PreviousPage: pagination.PreviousPage,
+		CurrentPage:  pagination.CurrentPage,
+		NextPage:     pagination.NextPage,
+		CountPerPage: pagination.NotificationsPerPage,
+	}
+}
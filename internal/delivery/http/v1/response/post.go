@@ -45,6 +45,7 @@ type PostResponseDto struct {
 	Title   string    `json:"title"`
 	Slug    string    `json:"slug"`
 	Content string    `json:"content"`
+	Tags    []string  `json:"tags"`
 	UserID  uuid.UUID `json:"user_id"`
 	// User        *UserResponseDto `json:"user,omitempty"`
 	IsPublished bool      `json:"is_published"`
@@ -53,6 +54,7 @@ type PostResponseDto struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 	PublishedAt null.Time `json:"published_at"`
 	DeletedAt   null.Time `json:"deleted_at"`
+	Version     int       `json:"version"`
 }
 
 func (dto *PostResponseDto) TransformFromObject(post domain.Post) {
@@ -60,9 +62,11 @@ func (dto *PostResponseDto) TransformFromObject(post domain.Post) {
 	dto.Title = post.Title
 	dto.Slug = post.Slug
 	dto.Content = post.Content
+	dto.Tags = post.Tags
 	dto.UserID = post.UserID
 	dto.CreatedAt = post.CreatedAt
 	dto.UpdatedAt = post.UpdatedAt
+	dto.Version = post.Version
 
 	if post.PublishedAt.Valid {
 		dto.IsPublished = post.PublishedAt.Valid
@@ -74,3 +78,32 @@ func (dto *PostResponseDto) TransformFromObject(post domain.Post) {
 		dto.DeletedAt = post.DeletedAt
 	}
 }
+
+type BulkPostResultResponseDto struct {
+	ID      uuid.UUID        `json:"id"`
+	Success bool             `json:"success"`
+	Error   string           `json:"error,omitempty"`
+	Post    *PostResponseDto `json:"post,omitempty"`
+}
+
+type BulkPostResponseDto struct {
+	Results []BulkPostResultResponseDto `json:"results"`
+}
+
+func (dto *BulkPostResponseDto) TransformFromObject(results []service.BulkPostResult) {
+	dto.Results = []BulkPostResultResponseDto{}
+
+	for _, result := range results {
+		temp := BulkPostResultResponseDto{ID: result.ID}
+
+		if result.Err != nil {
+			temp.Error = result.Err.Error()
+		} else {
+			temp.Success = true
+			temp.Post = &PostResponseDto{}
+			temp.Post.TransformFromObject(result.Post)
+		}
+
+		dto.Results = append(dto.Results, temp)
+	}
+}
@@ -0,0 +1,41 @@
+package response
+
+import (
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	uuid "github.com/satori/go.uuid"
+)
+
+type SessionResponseDto struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
+
+func (dto *SessionResponseDto) TransformFromObject(session domain.Session, currentID uuid.UUID) {
+	dto.ID = session.ID
+	dto.UserAgent = session.UserAgent
+	dto.IP = session.IP
+	dto.CreatedAt = session.CreatedAt
+	dto.LastSeenAt = session.LastSeenAt
+	dto.Current = uuid.Equal(session.ID, currentID)
+}
+
+type SessionListResponseDto struct {
+	Sessions []SessionResponseDto `json:"sessions"`
+}
+
+// TransformFromObject marks session of current access token
+func (dto *SessionListResponseDto) TransformFromObject(sessions []domain.Session, currentID uuid.UUID) {
+	dto.Sessions = []SessionResponseDto{}
+
+	for _, session := range sessions {
+		temp := SessionResponseDto{}
+		temp.TransformFromObject(session, currentID)
+		dto.Sessions = append(dto.Sessions, temp)
+	}
+}
@@ -9,25 +9,68 @@ import (
 )
 
 type TokenResponseDto struct {
-	AccessToken string `json:"access_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
 }
 
 func (dto *TokenResponseDto) TransformFromObject(tokens service.Tokens) {
 	dto.AccessToken = tokens.AccessToken
+	dto.RefreshToken = tokens.RefreshToken
+	dto.ExpiresIn = int(tokens.ExpiresIn.Seconds())
 }
 
 type UserResponseDto struct {
-	ID        uuid.UUID `json:"id"`
-	Email     string    `json:"email,omitempty"`
-	Username  string    `json:"username"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID            uuid.UUID `json:"id"`
+	Email         string    `json:"email,omitempty"`
+	Username      string    `json:"username"`
+	EmailVerified bool      `json:"email_verified,omitempty"`
+	DisplayName   string    `json:"display_name"`
+	Bio           string    `json:"bio"`
+	AvatarURL     string    `json:"avatar_url"`
+	Location      string    `json:"location"`
+	Website       string    `json:"website"`
+	SocialLinks   []string  `json:"social_links"`
+	Roles         []string  `json:"roles,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Version       int       `json:"version"`
 }
 
 func (dto *UserResponseDto) TransformFromObject(user domain.User) {
 	dto.ID = user.ID
 	dto.Email = user.Email
 	dto.Username = user.Username
+	dto.EmailVerified = user.IsEmailVerified()
+	dto.DisplayName = user.DisplayName
+	dto.Bio = user.Bio
+	dto.AvatarURL = user.AvatarURL
+	dto.Location = user.Location
+	dto.Website = user.Website
+	dto.SocialLinks = []string(user.SocialLinks)
+	if dto.SocialLinks == nil {
+		dto.SocialLinks = []string{}
+	}
+	for _, role := range user.Roles {
+		dto.Roles = append(dto.Roles, string(role))
+	}
 	dto.CreatedAt = user.CreatedAt
 	dto.UpdatedAt = user.UpdatedAt
+	dto.Version = user.Version
+}
+
+type AccountDeletionResponseDto struct {
+	Posts       string     `json:"posts"`
+	TransferTo  *uuid.UUID `json:"transfer_to,omitempty"`
+	RequestedAt time.Time  `json:"requested_at"`
+	PurgeAt     time.Time  `json:"purge_at"`
+}
+
+func (dto *AccountDeletionResponseDto) TransformFromObject(deletion domain.AccountDeletion) {
+	dto.Posts = string(deletion.PostsAction)
+	if deletion.TransferTo.Valid {
+		dto.TransferTo = &deletion.TransferTo.UUID
+	}
+	dto.RequestedAt = deletion.RequestedAt
+	dto.PurgeAt = deletion.PurgeAt
 }
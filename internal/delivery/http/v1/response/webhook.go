@@ -0,0 +1,102 @@
+package response
+
+import (
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+type WebhookResponseDto struct {
+	ID        uuid.UUID `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Global    bool      `json:"global"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (dto *WebhookResponseDto) TransformFromObject(webhook domain.Webhook) {
+	dto.ID = webhook.ID
+	dto.URL = webhook.URL
+	dto.Events = []string{}
+	for _, event := range webhook.Events {
+		dto.Events = append(dto.Events, string(event))
+	}
+	dto.Global = webhook.Global
+	dto.CreatedAt = webhook.CreatedAt
+}
+
+// CreatedWebhookResponseDto holds secret of webhook, secret is shown only
+// once and is used by receiver to verify signatures
+type CreatedWebhookResponseDto struct {
+	WebhookResponseDto
+	Secret string `json:"secret"`
+}
+
+func (dto *CreatedWebhookResponseDto) TransformFromObject(webhook domain.Webhook) {
+	dto.WebhookResponseDto.TransformFromObject(webhook)
+	dto.Secret = webhook.Secret
+}
+
+type WebhookListResponseDto struct {
+	Webhooks []WebhookResponseDto `json:"webhooks"`
+}
+
+func (dto *WebhookListResponseDto) TransformFromObject(webhooks []domain.Webhook) {
+	dto.Webhooks = []WebhookResponseDto{}
+
+	for _, webhook := range webhooks {
+		temp := WebhookResponseDto{}
+		temp.TransformFromObject(webhook)
+		dto.Webhooks = append(dto.Webhooks, temp)
+	}
+}
+
+type WebhookDeliveryResponseDto struct {
+	ID            uuid.UUID `json:"id"`
+	Event         string    `json:"event"`
+	Status        string    `json:"status"`
+	Attempts      int       `json:"attempts"`
+	StatusCode    int       `json:"status_code"`
+	Error         string    `json:"error,omitempty"`
+	NextAttemptAt null.Time `json:"next_attempt_at"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (dto *WebhookDeliveryResponseDto) TransformFromObject(delivery domain.WebhookDelivery) {
+	dto.ID = delivery.ID
+	dto.Event = string(delivery.Event)
+	dto.Status = string(delivery.Status)
+	dto.Attempts = delivery.Attempts
+	dto.StatusCode = delivery.StatusCode
+	dto.Error = delivery.Error
+	dto.NextAttemptAt = delivery.NextAttemptAt
+	dto.CreatedAt = delivery.CreatedAt
+	dto.UpdatedAt = delivery.UpdatedAt
+}
+
+type WebhookDeliveryPaginationResponseDto struct {
+	Deliveries []WebhookDeliveryResponseDto `json:"deliveries"`
+	Pagination PaginationResponseDto        `json:"pagination"`
+}
+
+func (dto *WebhookDeliveryPaginationResponseDto) TransformFromObject(pagination service.WebhookDeliveryPagination) {
+	dto.Deliveries = []WebhookDeliveryResponseDto{}
+
+	for _, delivery := range pagination.Deliveries {
+		temp := WebhookDeliveryResponseDto{}
+		temp.TransformFromObject(delivery)
+		dto.Deliveries = append(dto.Deliveries, temp)
+	}
+
+	dto.Pagination = PaginationResponseDto{
+		Total:        pagination.DeliveriesCount,
+		PreviousPage: pagination.PreviousPage,
+		CurrentPage:  pagination.CurrentPage,
+		NextPage:     pagination.NextPage,
+		CountPerPage: pagination.DeliveriesPerPage,
+	}
+}
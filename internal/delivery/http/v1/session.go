@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	requestdto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/request"
+	responsedto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+)
+
+// @Summary Get sessions
+// @Description Get active sessions of self user, session of current access token is marked
+// @ID user-get-sessions
+// @Tags Session
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.SessionListResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/self/sessions [get]
+func (h *Handler) GetAllSessions(w http.ResponseWriter, r *http.Request) {
+	response := responsedto.SessionListResponseDto{}
+
+	identity, casted := requestdto.IdentityFromContext(r.Context())
+	if !casted {
+
+		h.Service.Logger.Errorf("v1.GetAllSessions error: %s", errors.ErrInvalidTokenUserId)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	sessions, err := h.Service.Session.GetAll(r.Context(), identity.UserID)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.GetAllSessions error: %s", err)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(sessions, identity.SessionID)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Revoke session
+// @Description Sign self user out from session with id, access tokens of session are rejected at once
+// @ID user-revoke-session
+// @Tags Session
+// @Accept json
+// @Produce json
+// @Param id path string true "Session id"
+// @Success 204 "No content"
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/self/sessions/{id} [delete]
+func (h *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.RevokeSessionRequestDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.RevokeSession error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	if err := h.Service.Session.Revoke(r.Context(), input); err != nil {
+
+		h.Service.Logger.Errorf("v1.RevokeSession error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case repoerrors.ErrSessionNotFound:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	respond(w, r, http.StatusNoContent, nil)
+}
+
+// @Summary Revoke all sessions
+// @Description Sign self user out everywhere, including current session
+// @ID user-revoke-sessions
+// @Tags Session
+// @Accept json
+// @Produce json
+// @Success 204 "No content"
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/self/sessions [delete]
+func (h *Handler) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	userID, casted := requestdto.UserIDFromContext(r.Context())
+	if !casted {
+
+		h.Service.Logger.Errorf("v1.RevokeAllSessions error: %s", errors.ErrInvalidTokenUserId)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	if err := h.Service.Session.RevokeAll(r.Context(), userID); err != nil {
+
+		h.Service.Logger.Errorf("v1.RevokeAllSessions error: %s", err)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	respond(w, r, http.StatusNoContent, nil)
+}
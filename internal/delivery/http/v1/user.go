@@ -1,15 +1,16 @@
 package v1
 
 import (
-	"context"
 	"net/http"
 	"strings"
 
 	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
 	requestdto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/request"
 	responsedto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
 	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
 	"github.com/aintsashqa/go-simple-blog/internal/service"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
 	"github.com/go-chi/chi"
 	uuid "github.com/satori/go.uuid"
 )
@@ -23,6 +24,8 @@ import (
 // @Param payload body request.SignUpUserRequestDto true "Sign up with account details"
 // @Success 201 {object} response.UserResponseDto
 // @Failure 400 {object} response.ErrorResponseDto
+// @Failure 409 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
 // @Failure 500 {object} response.ErrorResponseDto
 // @Router /user/sign-up [post]
 func (h *Handler) SignUp(w http.ResponseWriter, r *http.Request) {
@@ -48,7 +51,18 @@ func (h *Handler) SignUp(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case
+			serviceerrors.ErrUsernameAlreadyTaken,
+			repoerrors.ErrUserAlreadyExists:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusConflict, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
 		errorRespond(w, r, errorResp)
 		return
 	}
@@ -65,12 +79,14 @@ func (h *Handler) SignUp(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param payload body request.SignInUserRequestDto true "Sign in with account details"
 // @Success 200 {object} response.TokenResponseDto
-// @Failure 404 {object} response.ErrorResponseDto
+// @Success 202 {object} response.MFAChallengeResponseDto
+// @Failure 401 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
 // @Failure 500 {object} response.ErrorResponseDto
 // @Router /user/sign-in [post]
 func (h *Handler) SignIn(w http.ResponseWriter, r *http.Request) {
 	request := requestdto.SignInUserRequestDto{}
-	response := responsedto.TokenResponseDto{}
 
 	if response, err := request.FromRequest(r); err != nil {
 
@@ -81,18 +97,79 @@ func (h *Handler) SignIn(w http.ResponseWriter, r *http.Request) {
 	}
 
 	opt := request.TransformToObject()
-	tokens, err := h.Service.User.SignIn(r.Context(), opt)
+	result, err := h.Service.User.SignIn(r.Context(), opt)
 	if err != nil {
 
 		h.Service.Logger.Errorf("v1.SignIn error: %s", err)
 
-		// TODO: check passwords not equals to send StatusBadRequest
 		var errorResp responsedto.ErrorResponseDto
-		if err == repoerrors.ErrUserNotFound {
-			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
-		} else {
+		switch err {
+
+		case serviceerrors.ErrInvalidCredentials:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusUnauthorized, err.Error())
+
+		case serviceerrors.ErrSignInLocked:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusTooManyRequests, err.Error())
+
+		case serviceerrors.ErrUserSuspended:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusForbidden, err.Error())
+
+		default:
 			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
 
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	signInRespond(w, r, result)
+}
+
+// @Summary Refresh tokens
+// @Description Exchange refresh token for new pair of tokens
+// @ID user-refresh
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param payload body request.RefreshTokenRequestDto true "Refresh token"
+// @Success 200 {object} response.TokenResponseDto
+// @Failure 401 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Router /user/refresh [post]
+func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.RefreshTokenRequestDto{}
+	response := responsedto.TokenResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.RefreshToken error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+	tokens, err := h.Service.Token.Refresh(r.Context(), opt)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.RefreshToken error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case
+			serviceerrors.ErrRefreshTokenInvalid,
+			serviceerrors.ErrRefreshTokenExpired,
+			serviceerrors.ErrRefreshTokenReused:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusUnauthorized, err.Error())
+
+		case serviceerrors.ErrUserSuspended:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusForbidden, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
 		}
 
 		errorRespond(w, r, errorResp)
@@ -103,6 +180,353 @@ func (h *Handler) SignIn(w http.ResponseWriter, r *http.Request) {
 	respond(w, r, http.StatusOK, response)
 }
 
+// @Summary Sign out
+// @Description Revoke current access token, its session and session of refresh token
+// @ID user-sign-out
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param payload body request.SignOutRequestDto false "Refresh token"
+// @Success 204
+// @Failure 401 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/sign-out [post]
+func (h *Handler) SignOut(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.SignOutRequestDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.SignOut error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+	if err := h.Service.Token.SignOut(r.Context(), opt); err != nil {
+
+		h.Service.Logger.Errorf("v1.SignOut error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		if err == serviceerrors.ErrRefreshTokenInvalid {
+			errorResp = responsedto.NewErrorResponseDto(http.StatusUnauthorized, err.Error())
+		} else {
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	respond(w, r, http.StatusNoContent, nil)
+}
+
+// @Summary Verify email
+// @Description Verify email address with token from verification link
+// @ID user-verify-email
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param payload body request.VerifyEmailRequestDto true "Verification token"
+// @Success 200 {object} response.UserResponseDto
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Router /user/verify-email [post]
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.VerifyEmailRequestDto{}
+	response := responsedto.UserResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.VerifyEmail error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+	user, err := h.Service.Verification.Verify(r.Context(), opt)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.VerifyEmail error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case
+			serviceerrors.ErrVerificationTokenInvalid,
+			serviceerrors.ErrVerificationTokenExpired:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusBadRequest, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(user)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Forgot password
+// @Description Send password reset link to email, response does not depend on whether email is registered
+// @ID user-forgot-password
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param payload body request.ForgotPasswordRequestDto true "Account email"
+// @Success 202
+// @Failure 429 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Router /user/password/forgot [post]
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.ForgotPasswordRequestDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.ForgotPassword error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+	if err := h.Service.Password.Forgot(r.Context(), opt); err != nil {
+
+		h.Service.Logger.Errorf("v1.ForgotPassword error: %s", err)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	respond(w, r, http.StatusAccepted, nil)
+}
+
+// @Summary Reset password
+// @Description Set new password with token from reset link and revoke all sessions
+// @ID user-reset-password
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param payload body request.ResetPasswordRequestDto true "Reset token and new password"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Router /user/password/reset [post]
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.ResetPasswordRequestDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.ResetPassword error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+	if err := h.Service.Password.Reset(r.Context(), opt); err != nil {
+
+		h.Service.Logger.Errorf("v1.ResetPassword error: %s", err)
+
+		if errorResp, isValidation := ValidationErrorsHandler(err); isValidation {
+			errorRespond(w, r, errorResp)
+			return
+		}
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case
+			serviceerrors.ErrPasswordResetTokenInvalid,
+			serviceerrors.ErrPasswordResetTokenExpired:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusBadRequest, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	respond(w, r, http.StatusNoContent, nil)
+}
+
+// @Summary Change password
+// @Description Change password of self user, current password is required
+// @ID user-change-password
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param payload body request.ChangePasswordRequestDto true "Current and new password"
+// @Success 204
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/self/password [put]
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.ChangePasswordRequestDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.ChangePassword error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+	if err := h.Service.User.ChangePassword(r.Context(), opt); err != nil {
+
+		h.Service.Logger.Errorf("v1.ChangePassword error: %s", err)
+
+		if errorResp, isValidation := ValidationErrorsHandler(err); isValidation {
+			errorRespond(w, r, errorResp)
+			return
+		}
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case serviceerrors.ErrCurrentPasswordMismatch:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusBadRequest, err.Error())
+
+		case repoerrors.ErrUserNotFound:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	respond(w, r, http.StatusNoContent, nil)
+}
+
+// @Summary Change email
+// @Description Send confirmation link to new email of self user, email is changed after confirmation
+// @ID user-change-email
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param payload body request.ChangeEmailRequestDto true "New email and current password"
+// @Success 202
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 409 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/self/email [put]
+func (h *Handler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.ChangeEmailRequestDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.ChangeEmail error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+	if err := h.Service.User.ChangeEmail(r.Context(), opt); err != nil {
+
+		h.Service.Logger.Errorf("v1.ChangeEmail error: %s", err)
+
+		if errorResp, isValidation := ValidationErrorsHandler(err); isValidation {
+			errorRespond(w, r, errorResp)
+			return
+		}
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case serviceerrors.ErrCurrentPasswordMismatch:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusBadRequest, err.Error())
+
+		case serviceerrors.ErrEmailAlreadyTaken:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusConflict, err.Error())
+
+		case repoerrors.ErrUserNotFound:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	respond(w, r, http.StatusAccepted, nil)
+}
+
+// @Summary Confirm email change
+// @Description Replace email with new one using token from confirmation link
+// @ID user-confirm-email
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param payload body request.ConfirmEmailChangeRequestDto true "Confirmation token"
+// @Success 200 {object} response.UserResponseDto
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 409 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Router /user/confirm-email [post]
+func (h *Handler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.ConfirmEmailChangeRequestDto{}
+	response := responsedto.UserResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.ConfirmEmailChange error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+	user, err := h.Service.Verification.ConfirmEmailChange(r.Context(), opt)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.ConfirmEmailChange error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case
+			serviceerrors.ErrVerificationTokenInvalid,
+			serviceerrors.ErrVerificationTokenExpired:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusBadRequest, err.Error())
+
+		case serviceerrors.ErrEmailAlreadyTaken:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusConflict, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(user)
+	respond(w, r, http.StatusOK, response)
+}
+
 // @Summary Get self user
 // @Description Get self user by authorized information
 // @ID user-get-self
@@ -154,7 +578,10 @@ func (h *Handler) GetSelfUser(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path string string "User with id"
+// @Param If-None-Match header string false "Entity tag of cached representation"
+// @Param If-Modified-Since header string false "Modification date of cached representation"
 // @Success 200 {object} response.UserResponseDto
+// @Success 304 "Not modified"
 // @Failure 404 {object} response.ErrorResponseDto
 // @Failure 500 {object} response.ErrorResponseDto
 // @Router /user/{id} [get]
@@ -162,6 +589,15 @@ func (h *Handler) GetSingleUser(w http.ResponseWriter, r *http.Request) {
 	response := responsedto.UserResponseDto{}
 
 	id := uuid.FromStringOrNil(chi.URLParam(r, "id"))
+
+	if isConditional(r) {
+		// Validator is cheaper than whole user, so unchanged user is never loaded
+		if validator, err := h.Service.User.Validator(r.Context(), id); err == nil && isNotModified(r, validator) {
+			notModifiedRespond(w, validator)
+			return
+		}
+	}
+
 	user, err := h.Service.User.Find(r.Context(), id)
 	if err != nil {
 
@@ -178,26 +614,67 @@ func (h *Handler) GetSingleUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	writeValidator(w, user.Validator())
+	response.TransformFromObject(user)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Get user by username
+// @Description Get single user by unique case insensitive username
+// @ID user-get-by-username
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param username path string true "User with username"
+// @Success 200 {object} response.UserResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Router /user/by-username/{username} [get]
+func (h *Handler) GetUserByUsername(w http.ResponseWriter, r *http.Request) {
+	response := responsedto.UserResponseDto{}
+
+	user, err := h.Service.User.FindByUsername(r.Context(), chi.URLParam(r, "username"))
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.GetUserByUsername error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		if err == repoerrors.ErrUserNotFound {
+			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+		} else {
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	writeValidator(w, user.Validator())
 	response.TransformFromObject(user)
 	respond(w, r, http.StatusOK, response)
 }
 
 // @Summary Update user
-// @Description Update user with id
+// @Description Partially update user with id, omitted fields are not changed
 // @ID user-update
 // @Tags User
 // @Accept json
 // @Produce json
 // @Param id path string true "User with id"
+// @Param If-Match header string false "Entity tag of user version"
 // @Param payload body request.UpdateUserRequestDto true "User details"
 // @Success 202 {object} response.UserResponseDto
 // @Failure 400 {object} response.ErrorResponseDto
 // @Failure 401 {object} response.ErrorResponseDto
 // @Failure 403 {object} response.ErrorResponseDto
 // @Failure 404 {object} response.ErrorResponseDto
+// @Failure 409 {object} response.ErrorResponseDto
+// @Failure 412 {object} response.ErrorResponseDto
+// @Failure 428 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
 // @Failure 500 {object} response.ErrorResponseDto
 // @Security ApiKeyAuth
-// @Router /user/{id} [put]
+// @Router /user/{id} [patch]
 func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	request := requestdto.UpdateUserRequestDto{}
 	response := responsedto.UserResponseDto{}
@@ -221,10 +698,23 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errorResp, isVersion := VersionErrorsHandler(w, err, user.Model); isVersion {
+			errorRespond(w, r, errorResp)
+			return
+		}
+
 		var errorResp responsedto.ErrorResponseDto
-		if err == repoerrors.ErrUserNotFound {
+		switch err {
+
+		case repoerrors.ErrUserNotFound:
 			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
-		} else {
+
+		case
+			serviceerrors.ErrUsernameAlreadyTaken,
+			repoerrors.ErrUserAlreadyExists:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusConflict, err.Error())
+
+		default:
 			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
 		}
 
@@ -232,6 +722,7 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	writeValidator(w, user.Validator())
 	response.TransformFromObject(user)
 	respond(w, r, http.StatusAccepted, response)
 }
@@ -258,17 +749,26 @@ func (h *Handler) authenticateMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		id, err := h.Service.User.Authenticate(r.Context(), service.AuthenticateUserInput{Token: headerPieces[1]})
+		// Personal access tokens are accepted alongside access tokens
+		authenticate := h.Service.Token.Authenticate
+		if strings.HasPrefix(headerPieces[1], service.PersonalAccessTokenPrefix) {
+			authenticate = h.Service.PersonalAccessToken.Authenticate
+		}
+
+		identity, err := authenticate(r.Context(), service.AuthenticateUserInput{Token: headerPieces[1], IP: requestdto.ClientIP(r)})
 		if err != nil {
 
 			h.Service.Logger.Errorf("v1.authenticateMiddleware error: %s", err)
 
 			errorResp := responsedto.NewErrorResponseDto(http.StatusUnauthorized, errors.ErrAuthenticationFailed.Error())
+			if err == serviceerrors.ErrUserSuspended {
+				errorResp = responsedto.NewErrorResponseDto(http.StatusForbidden, err.Error())
+			}
 			errorRespond(w, r, errorResp)
 			return
 		}
 
-		if id == uuid.Nil {
+		if identity.UserID == uuid.Nil {
 
 			h.Service.Logger.Errorf("v1.authenticateMiddleware error: %s", errors.ErrInvalidTokenUserId)
 
@@ -277,7 +777,204 @@ func (h *Handler) authenticateMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), "user_id", id)
+		ctx := requestdto.WithIdentity(r.Context(), identity)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// requireRole allows requests of users with at least one of roles,
+// it must be used after authenticateMiddleware
+func (h *Handler) requireRole(roles ...domain.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, casted := requestdto.IdentityFromContext(r.Context())
+			if !casted {
+
+				h.Service.Logger.Errorf("v1.requireRole error: %s", errors.ErrInvalidTokenUserId)
+
+				errorResp := responsedto.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+				errorRespond(w, r, errorResp)
+				return
+			}
+
+			if !identity.HasRole(roles...) {
+
+				h.Service.Logger.Errorf("v1.requireRole error: %s", errors.ErrInsufficientRole)
+
+				errorResp := responsedto.NewErrorResponseDto(http.StatusForbidden, errors.ErrInsufficientRole.Error())
+				errorRespond(w, r, errorResp)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requireScope allows requests of personal access tokens granted scope,
+// access tokens of session are granted all scopes
+func (h *Handler) requireScope(scope domain.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, casted := requestdto.IdentityFromContext(r.Context())
+			if !casted {
+
+				h.Service.Logger.Errorf("v1.requireScope error: %s", errors.ErrInvalidTokenUserId)
+
+				errorResp := responsedto.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+				errorRespond(w, r, errorResp)
+				return
+			}
+
+			if !identity.HasScope(scope) {
+
+				h.Service.Logger.Errorf("v1.requireScope error: %s", errors.ErrInsufficientScope)
+
+				errorResp := responsedto.NewErrorResponseDto(http.StatusForbidden, errors.ErrInsufficientScope.Error())
+				errorRespond(w, r, errorResp)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// requireSession rejects personal access tokens, it is used for account
+// management which must not be available for automation
+func (h *Handler) requireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, casted := requestdto.IdentityFromContext(r.Context())
+		if !casted {
+
+			h.Service.Logger.Errorf("v1.requireSession error: %s", errors.ErrInvalidTokenUserId)
+
+			errorResp := responsedto.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+			errorRespond(w, r, errorResp)
+			return
+		}
+
+		if identity.IsPersonalAccessToken() {
+
+			h.Service.Logger.Errorf("v1.requireSession error: %s", errors.ErrSessionRequired)
+
+			errorResp := responsedto.NewErrorResponseDto(http.StatusForbidden, errors.ErrSessionRequired.Error())
+			errorRespond(w, r, errorResp)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// @Summary Delete user
+// @Description Delete self user, account is hidden at once and removed after grace period together with chosen handling of posts
+// @ID user-delete
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param id path string true "User id"
+// @Param payload body request.DeleteUserRequestDto true "Password and handling of posts: delete, anonymize or transfer"
+// @Success 202 {object} response.AccountDeletionResponseDto
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /user/{id} [delete]
+func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.DeleteUserRequestDto{}
+	response := responsedto.AccountDeletionResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.DeleteUser error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+	deletion, err := h.Service.Account.Delete(r.Context(), opt)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.DeleteUser error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case
+			serviceerrors.ErrCurrentPasswordMismatch,
+			serviceerrors.ErrPostsActionInvalid,
+			serviceerrors.ErrTransferUserInvalid:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusBadRequest, err.Error())
+
+		case repoerrors.ErrUserNotFound:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(deletion)
+	respond(w, r, http.StatusAccepted, response)
+}
+
+// @Summary Restore user
+// @Description Cancel deletion of account during grace period and sign in
+// @ID user-restore
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param payload body request.RestoreUserRequestDto true "Account details"
+// @Success 200 {object} response.TokenResponseDto
+// @Success 202 {object} response.MFAChallengeResponseDto
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 429 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Router /user/restore [post]
+func (h *Handler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.RestoreUserRequestDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.RestoreUser error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+	result, err := h.Service.Account.Restore(r.Context(), opt)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.RestoreUser error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case serviceerrors.ErrCurrentPasswordMismatch:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusBadRequest, err.Error())
+
+		case serviceerrors.ErrUserSuspended:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusForbidden, err.Error())
+
+		case repoerrors.ErrUserNotFound:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	signInRespond(w, r, result)
+}
@@ -3,6 +3,7 @@ package v1_test
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
 	"errors"
 	"fmt"
 	"net/http"
@@ -14,10 +15,13 @@ import (
 	rerr "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
 	"github.com/aintsashqa/go-simple-blog/internal/domain"
 	derr "github.com/aintsashqa/go-simple-blog/internal/domain"
-	repoerr "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
 	"github.com/aintsashqa/go-simple-blog/internal/service"
+	serr "github.com/aintsashqa/go-simple-blog/internal/service/errors"
 	mock_service "github.com/aintsashqa/go-simple-blog/internal/service/mocks"
+	"github.com/aintsashqa/go-simple-blog/pkg/auth"
 	mock_logger "github.com/aintsashqa/go-simple-blog/pkg/logger/mocks"
+	"github.com/aintsashqa/go-simple-blog/pkg/ratelimit"
+	"github.com/go-chi/chi"
 	"github.com/golang/mock/gomock"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/require"
@@ -27,8 +31,9 @@ import (
 const (
 	ErrorResponseBodyInformationNull string = `{"code":%d,"message":"%s","information":null}`
 	ErrorResponseBody                string = `{"code":%d,"message":"%s","information":["%s"]}`
-	SignUpResponseBody               string = `{"id":"%s","email":"%s","username":"new username","created_at":"%v","updated_at":"%v"}`
-	SignInResponseBody               string = `{"access_token":"%s"}`
+	SignUpResponseBody               string = `{"id":"%s","email":"%s","username":"new username","display_name":"","bio":"","avatar_url":"","location":"","website":"","social_links":[],"created_at":"%v","updated_at":"%v","version":0}`
+	SignInResponseBody               string = `{"access_token":"%s","refresh_token":"%s","expires_in":%d}`
+	MFAChallengeResponseBody         string = `{"mfa_token":"%s","enrollment_required":%t,"expires_at":"%s"}`
 )
 
 type UserHTTPHandlerSuite struct {
@@ -37,8 +42,11 @@ type UserHTTPHandlerSuite struct {
 
 	Controller *gomock.Controller
 
-	MockUserService   *mock_service.MockUser
-	MockLoggerService *mock_logger.MockLogger
+	MockUserService                *mock_service.MockUser
+	MockTokenService               *mock_service.MockToken
+	MockPersonalAccessTokenService *mock_service.MockPersonalAccessToken
+	MockRateLimitService           *mock_service.MockRateLimit
+	MockLoggerService              *mock_logger.MockLogger
 
 	CurrentHTTPHandler *v1.Handler
 }
@@ -51,11 +59,21 @@ func (s *UserHTTPHandlerSuite) SetupTest() {
 	s.Assertions = require.New(s.T())
 	s.Controller = gomock.NewController(s.T())
 	s.MockUserService = mock_service.NewMockUser(s.Controller)
+	s.MockTokenService = mock_service.NewMockToken(s.Controller)
+	s.MockPersonalAccessTokenService = mock_service.NewMockPersonalAccessToken(s.Controller)
+	s.MockRateLimitService = mock_service.NewMockRateLimit(s.Controller)
 	s.MockLoggerService = mock_logger.NewMockLogger(s.Controller)
 
 	s.MockLoggerService.EXPECT().Errorf(gomock.Any(), gomock.Any()).AnyTimes()
+	s.MockLoggerService.EXPECT().Info(gomock.Any()).AnyTimes()
 
-	service := service.Service{User: s.MockUserService, Logger: s.MockLoggerService}
+	service := service.Service{
+		User:                s.MockUserService,
+		Token:               s.MockTokenService,
+		PersonalAccessToken: s.MockPersonalAccessTokenService,
+		RateLimit:           s.MockRateLimitService,
+		Logger:              s.MockLoggerService,
+	}
 	s.CurrentHTTPHandler = v1.NewHandler(&service)
 }
 
@@ -164,12 +182,12 @@ func (s *UserHTTPHandlerSuite) TestSignUpMethod() {
 }
 
 func (s *UserHTTPHandlerSuite) TestSignInMethod() {
-	type MockUserServiceSignInMethodBehavior func(*mock_service.MockUser, context.Context, service.SignInUserInput, service.Tokens, error)
+	type MockUserServiceSignInMethodBehavior func(*mock_service.MockUser, context.Context, service.SignInUserInput, service.SignInResult, error)
 
-	mockUserServiceSignInMethodBehavior := func(m *mock_service.MockUser, ctx context.Context, input service.SignInUserInput, returnsTokens service.Tokens, returnsError error) {
+	mockUserServiceSignInMethodBehavior := func(m *mock_service.MockUser, ctx context.Context, input service.SignInUserInput, returnsResult service.SignInResult, returnsError error) {
 		m.EXPECT().
 			SignIn(context.Background(), input).
-			Return(returnsTokens, returnsError).
+			Return(returnsResult, returnsError).
 			Times(1)
 	}
 
@@ -179,7 +197,7 @@ func (s *UserHTTPHandlerSuite) TestSignInMethod() {
 		Name                                string
 		RequestBody                         string
 		ServiceInput                        service.SignInUserInput
-		ServiceResult                       service.Tokens
+		ServiceResult                       service.SignInResult
 		ServiceResultError                  error
 		MockUserServiceSignInMethodBehavior MockUserServiceSignInMethodBehavior
 		ResponseBody                        string
@@ -191,37 +209,76 @@ func (s *UserHTTPHandlerSuite) TestSignInMethod() {
 			ServiceInput: service.SignInUserInput{
 				Email:    "root@example.com",
 				Password: "secret",
+				Client:   service.Client{IP: "192.0.2.1"},
 			},
-			ServiceResult: service.Tokens{
-				AccessToken: "VALID_ACCESS_TOKEN",
+			ServiceResult: service.SignInResult{
+				Tokens: service.Tokens{
+					AccessToken:  "VALID_ACCESS_TOKEN",
+					RefreshToken: "VALID_REFRESH_TOKEN",
+					ExpiresIn:    time.Minute * 15,
+				},
 			},
 			ServiceResultError:                  nil,
 			MockUserServiceSignInMethodBehavior: mockUserServiceSignInMethodBehavior,
-			ResponseBody:                        fmt.Sprintf(SignInResponseBody, "VALID_ACCESS_TOKEN"),
+			ResponseBody:                        fmt.Sprintf(SignInResponseBody, "VALID_ACCESS_TOKEN", "VALID_REFRESH_TOKEN", 900),
 			ResponseStatusCode:                  http.StatusOK,
 		},
+		{
+			Name:        "MFAChallenge",
+			RequestBody: fmt.Sprintf(`{"email":"root@example.com","password":"secret"}`),
+			ServiceInput: service.SignInUserInput{
+				Email:    "root@example.com",
+				Password: "secret",
+				Client:   service.Client{IP: "192.0.2.1"},
+			},
+			ServiceResult: service.SignInResult{
+				Challenge: service.MFAChallenge{
+					Token:     "VALID_MFA_TOKEN",
+					ExpiresAt: time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC),
+				},
+			},
+			ServiceResultError:                  nil,
+			MockUserServiceSignInMethodBehavior: mockUserServiceSignInMethodBehavior,
+			ResponseBody:                        fmt.Sprintf(MFAChallengeResponseBody, "VALID_MFA_TOKEN", false, "2021-03-01T12:00:00Z"),
+			ResponseStatusCode:                  http.StatusAccepted,
+		},
 		{
 			Name:                                "RequestFailure - Unavailable request body",
 			RequestBody:                         fmt.Sprintf(`{"email":"root@example.com","password":"secret"`),
 			ServiceInput:                        service.SignInUserInput{},
-			ServiceResult:                       service.Tokens{},
+			ServiceResult:                       service.SignInResult{},
 			ServiceResultError:                  nil,
 			MockUserServiceSignInMethodBehavior: nil,
 			ResponseBody:                        fmt.Sprintf(ErrorResponseBodyInformationNull, http.StatusInternalServerError, rerr.ErrUnavailableRequestBody.Error()),
 			ResponseStatusCode:                  http.StatusInternalServerError,
 		},
 		{
-			Name:        "RepositoryFailure",
+			Name:        "InvalidCredentials",
 			RequestBody: fmt.Sprintf(`{"email":"notfound@example.com","password":"secret"}`),
 			ServiceInput: service.SignInUserInput{
 				Email:    "notfound@example.com",
 				Password: "secret",
+				Client:   service.Client{IP: "192.0.2.1"},
+			},
+			ServiceResult:                       service.SignInResult{},
+			ServiceResultError:                  serr.ErrInvalidCredentials,
+			MockUserServiceSignInMethodBehavior: mockUserServiceSignInMethodBehavior,
+			ResponseBody:                        fmt.Sprintf(ErrorResponseBodyInformationNull, http.StatusUnauthorized, serr.ErrInvalidCredentials.Error()),
+			ResponseStatusCode:                  http.StatusUnauthorized,
+		},
+		{
+			Name:        "Locked",
+			RequestBody: fmt.Sprintf(`{"email":"root@example.com","password":"secret"}`),
+			ServiceInput: service.SignInUserInput{
+				Email:    "root@example.com",
+				Password: "secret",
+				Client:   service.Client{IP: "192.0.2.1"},
 			},
-			ServiceResult:                       service.Tokens{},
-			ServiceResultError:                  repoerr.ErrUserNotFound,
+			ServiceResult:                       service.SignInResult{},
+			ServiceResultError:                  serr.ErrSignInLocked,
 			MockUserServiceSignInMethodBehavior: mockUserServiceSignInMethodBehavior,
-			ResponseBody:                        fmt.Sprintf(ErrorResponseBodyInformationNull, http.StatusNotFound, repoerr.ErrUserNotFound.Error()),
-			ResponseStatusCode:                  http.StatusNotFound,
+			ResponseBody:                        fmt.Sprintf(ErrorResponseBodyInformationNull, http.StatusTooManyRequests, serr.ErrSignInLocked.Error()),
+			ResponseStatusCode:                  http.StatusTooManyRequests,
 		},
 		{
 			Name:        "InternalFailure",
@@ -229,8 +286,9 @@ func (s *UserHTTPHandlerSuite) TestSignInMethod() {
 			ServiceInput: service.SignInUserInput{
 				Email:    "notfound@example.com",
 				Password: "secret",
+				Client:   service.Client{IP: "192.0.2.1"},
 			},
-			ServiceResult:                       service.Tokens{},
+			ServiceResult:                       service.SignInResult{},
 			ServiceResultError:                  someInternalError,
 			MockUserServiceSignInMethodBehavior: mockUserServiceSignInMethodBehavior,
 			ResponseBody:                        fmt.Sprintf(ErrorResponseBodyInformationNull, http.StatusInternalServerError, rerr.ErrInternal.Error()),
@@ -253,3 +311,195 @@ func (s *UserHTTPHandlerSuite) TestSignInMethod() {
 		})
 	}
 }
+
+func (s *UserHTTPHandlerSuite) TestGetSingleUserMethod() {
+	type MockUserServiceBehavior func(*mock_service.MockUser, domain.User)
+
+	user := domain.User{
+		Model:    domain.Model{ID: uuid.NewV4(), UpdatedAt: time.Date(2021, time.March, 1, 12, 0, 0, 0, time.UTC), Version: 2},
+		Username: "username",
+	}
+
+	mockValidatorBehavior := func(m *mock_service.MockUser, user domain.User) {
+		m.EXPECT().
+			Validator(gomock.Any(), user.ID).
+			Return(user.Validator(), nil).
+			Times(1)
+	}
+
+	mockFindBehavior := func(m *mock_service.MockUser, user domain.User) {
+		m.EXPECT().
+			Find(gomock.Any(), user.ID).
+			Return(user, nil).
+			Times(1)
+	}
+
+	methodCases := []struct {
+		Name                    string
+		RequestHeaders          map[string]string
+		MockUserServiceBehavior []MockUserServiceBehavior
+		ResponseStatusCode      int
+	}{
+		{
+			Name:                    "Success",
+			RequestHeaders:          map[string]string{},
+			MockUserServiceBehavior: []MockUserServiceBehavior{mockFindBehavior},
+			ResponseStatusCode:      http.StatusOK,
+		},
+		{
+			Name:                    "NotModified - Matching entity tag",
+			RequestHeaders:          map[string]string{"If-None-Match": `"other", ` + user.ETag()},
+			MockUserServiceBehavior: []MockUserServiceBehavior{mockValidatorBehavior},
+			ResponseStatusCode:      http.StatusNotModified,
+		},
+		{
+			Name:                    "NotModified - Modification date",
+			RequestHeaders:          map[string]string{"If-Modified-Since": user.UpdatedAt.Format(http.TimeFormat)},
+			MockUserServiceBehavior: []MockUserServiceBehavior{mockValidatorBehavior},
+			ResponseStatusCode:      http.StatusNotModified,
+		},
+		{
+			Name: "Modified - Entity tag takes precedence",
+			RequestHeaders: map[string]string{
+				"If-None-Match":     `"other"`,
+				"If-Modified-Since": user.UpdatedAt.Format(http.TimeFormat),
+			},
+			MockUserServiceBehavior: []MockUserServiceBehavior{mockValidatorBehavior, mockFindBehavior},
+			ResponseStatusCode:      http.StatusOK,
+		},
+		{
+			Name:                    "Modified - Outdated modification date",
+			RequestHeaders:          map[string]string{"If-Modified-Since": user.UpdatedAt.Add(-time.Hour).Format(http.TimeFormat)},
+			MockUserServiceBehavior: []MockUserServiceBehavior{mockValidatorBehavior, mockFindBehavior},
+			ResponseStatusCode:      http.StatusOK,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			router := chi.NewRouter()
+			router.Get("/v1/user/{id}", s.CurrentHTTPHandler.GetSingleUser)
+			for _, behavior := range currentCase.MockUserServiceBehavior {
+				behavior(s.MockUserService, user)
+			}
+			responseRecorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/user/%s", user.ID), nil)
+			for key, value := range currentCase.RequestHeaders {
+				request.Header.Set(key, value)
+			}
+			router.ServeHTTP(responseRecorder, request)
+			s.Assertions.Equal(currentCase.ResponseStatusCode, responseRecorder.Code)
+			s.Assertions.Equal(user.ETag(), responseRecorder.Header().Get("ETag"))
+			s.Assertions.Equal(user.UpdatedAt.Format(http.TimeFormat), responseRecorder.Header().Get("Last-Modified"))
+		})
+	}
+}
+
+func (s *UserHTTPHandlerSuite) TestRequireRoleMiddleware() {
+	methodCases := []struct {
+		Name         string
+		Roles        domain.Roles
+		ResponseBody string
+	}{
+		{
+			Name:         "Reader",
+			Roles:        domain.Roles{domain.ReaderRole},
+			ResponseBody: fmt.Sprintf(ErrorResponseBodyInformationNull, http.StatusForbidden, rerr.ErrInsufficientRole),
+		},
+		{
+			Name:         "WithoutRoles",
+			Roles:        domain.Roles{},
+			ResponseBody: fmt.Sprintf(ErrorResponseBodyInformationNull, http.StatusForbidden, rerr.ErrInsufficientRole),
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			router := chi.NewRouter()
+			s.CurrentHTTPHandler.Init(router)
+			s.MockTokenService.EXPECT().
+				Authenticate(gomock.Any(), service.AuthenticateUserInput{Token: "access-token", IP: "192.0.2.1"}).
+				Return(service.Identity{UserID: uuid.NewV4(), Roles: currentCase.Roles}, nil).
+				Times(1)
+			responseRecorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/v1/post", bytes.NewBufferString(`{}`))
+			request.Header.Set("Authorization", "Bearer access-token")
+			router.ServeHTTP(responseRecorder, request)
+			s.Assertions.Equal(http.StatusForbidden, responseRecorder.Code)
+			s.Assertions.Equal(currentCase.ResponseBody+"\n", responseRecorder.Body.String())
+		})
+	}
+}
+
+func (s *UserHTTPHandlerSuite) TestPersonalAccessTokenMiddleware() {
+	methodCases := []struct {
+		Name         string
+		Method       string
+		Target       string
+		Scopes       domain.Scopes
+		ResponseBody string
+	}{
+		{
+			Name:         "InsufficientScope",
+			Method:       http.MethodPost,
+			Target:       "/v1/post",
+			Scopes:       domain.Scopes{domain.PostsReadScope},
+			ResponseBody: fmt.Sprintf(ErrorResponseBodyInformationNull, http.StatusForbidden, rerr.ErrInsufficientScope),
+		},
+		{
+			Name:         "SessionRequired",
+			Method:       http.MethodPut,
+			Target:       "/v1/user/self/password",
+			Scopes:       domain.Scopes{domain.PostsReadScope, domain.PostsWriteScope, domain.ProfileWriteScope},
+			ResponseBody: fmt.Sprintf(ErrorResponseBodyInformationNull, http.StatusForbidden, rerr.ErrSessionRequired),
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			router := chi.NewRouter()
+			s.CurrentHTTPHandler.Init(router)
+			s.MockPersonalAccessTokenService.EXPECT().
+				Authenticate(gomock.Any(), service.AuthenticateUserInput{Token: "sbp_token", IP: "192.0.2.1"}).
+				Return(service.Identity{UserID: uuid.NewV4(), Roles: domain.Roles{domain.AuthorRole}, Scopes: currentCase.Scopes, PersonalAccessTokenID: uuid.NewV4()}, nil).
+				Times(1)
+			responseRecorder := httptest.NewRecorder()
+			request := httptest.NewRequest(currentCase.Method, currentCase.Target, bytes.NewBufferString(`{}`))
+			request.Header.Set("Authorization", "Bearer sbp_token")
+			router.ServeHTTP(responseRecorder, request)
+			s.Assertions.Equal(http.StatusForbidden, responseRecorder.Code)
+			s.Assertions.Equal(currentCase.ResponseBody+"\n", responseRecorder.Body.String())
+		})
+	}
+}
+
+func (s *UserHTTPHandlerSuite) TestRateLimitMiddleware() {
+	router := chi.NewRouter()
+	s.CurrentHTTPHandler.Init(router)
+	s.MockRateLimitService.EXPECT().
+		Allow(gomock.Any(), service.RateLimitInput{Group: service.AuthRateLimitGroup, IP: "192.0.2.1"}).
+		Return(ratelimit.Result{Limit: 20, ResetAfter: 30 * time.Second, RetryAfter: 1500 * time.Millisecond}, nil).
+		Times(1)
+	responseRecorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodPost, "/v1/user/sign-up", bytes.NewBufferString(`{}`))
+	router.ServeHTTP(responseRecorder, request)
+	s.Assertions.Equal(http.StatusTooManyRequests, responseRecorder.Code)
+	s.Assertions.Equal("2", responseRecorder.Header().Get("Retry-After"))
+	s.Assertions.Equal("20", responseRecorder.Header().Get("RateLimit-Limit"))
+	s.Assertions.Equal("0", responseRecorder.Header().Get("RateLimit-Remaining"))
+	s.Assertions.Equal("30", responseRecorder.Header().Get("RateLimit-Reset"))
+	s.Assertions.Equal(fmt.Sprintf(ErrorResponseBodyInformationNull, http.StatusTooManyRequests, rerr.ErrRateLimitExceeded)+"\n", responseRecorder.Body.String())
+}
+
+func (s *UserHTTPHandlerSuite) TestGetJWKS() {
+	publicKey := ed25519.PublicKey(bytes.Repeat([]byte{1}, ed25519.PublicKeySize))
+	s.MockTokenService.EXPECT().
+		PublicKeys(gomock.Any()).
+		Return([]auth.PublicKey{{ID: "key-1", Algorithm: "EdDSA", Key: publicKey}}).
+		Times(1)
+	responseRecorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	s.CurrentHTTPHandler.GetJWKS(responseRecorder, request)
+	s.Assertions.Equal(http.StatusOK, responseRecorder.Code)
+	s.Assertions.Equal(`{"keys":[{"kty":"OKP","kid":"key-1","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE"}]}`+"\n", responseRecorder.Body.String())
+}
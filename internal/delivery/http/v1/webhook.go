@@ -0,0 +1,242 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/errors"
+	requestdto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/request"
+	responsedto "github.com/aintsashqa/go-simple-blog/internal/delivery/http/v1/response"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+)
+
+// @Summary Get webhooks
+// @Description Get webhooks of self user, secrets of webhooks are not shown
+// @ID webhook-get-all
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.WebhookListResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /webhooks [get]
+func (h *Handler) GetAllWebhooks(w http.ResponseWriter, r *http.Request) {
+	response := responsedto.WebhookListResponseDto{}
+
+	userID, casted := requestdto.UserIDFromContext(r.Context())
+	if !casted {
+
+		h.Service.Logger.Errorf("v1.GetAllWebhooks error: %s", errors.ErrInvalidTokenUserId)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusForbidden, errors.ErrInvalidTokenUserId.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	webhooks, err := h.Service.Webhook.GetAll(r.Context(), userID)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.GetAllWebhooks error: %s", err)
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(webhooks)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Create webhook
+// @Description Create webhook subscribed to post.created, post.published, post.deleted or user.updated events. Payloads are signed with HMAC-SHA256 in X-Webhook-Signature header, secret is shown only once. Global webhooks receive events of all users and are created only by admins
+// @ID webhook-create
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param payload body request.CreateWebhookRequestDto true "Url and events of webhook"
+// @Success 201 {object} response.CreatedWebhookResponseDto
+// @Failure 400 {object} response.ErrorResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /webhooks [post]
+func (h *Handler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.CreateWebhookRequestDto{}
+	response := responsedto.CreatedWebhookResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.CreateWebhook error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	webhook, err := h.Service.Webhook.Create(r.Context(), input)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.CreateWebhook error: %s", err)
+
+		if errorResp, isValidation := ValidationErrorsHandler(err); isValidation {
+			errorRespond(w, r, errorResp)
+			return
+		}
+
+		errorResp := responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(webhook)
+	respond(w, r, http.StatusCreated, response)
+}
+
+// @Summary Delete webhook
+// @Description Delete webhook of self user with id, delivery log of webhook is deleted as well
+// @ID webhook-delete
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook id"
+// @Success 204 "No content"
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /webhooks/{id} [delete]
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.DeleteWebhookRequestDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.DeleteWebhook error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	if err := h.Service.Webhook.Delete(r.Context(), input); err != nil {
+
+		h.Service.Logger.Errorf("v1.DeleteWebhook error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case repoerrors.ErrWebhookNotFound:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	respond(w, r, http.StatusNoContent, nil)
+}
+
+// @Summary Get webhook deliveries
+// @Description Get delivery log of webhook with pagination, recent deliveries first. Status code and error of last attempt are shown
+// @ID webhook-get-deliveries
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook id"
+// @Param current_page query int false "Number of current page"
+// @Param count_per_page query int false "Number of deliveries count"
+// @Success 200 {object} response.WebhookDeliveryPaginationResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /webhooks/{id}/deliveries [get]
+func (h *Handler) GetAllWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.WebhookDeliveryPaginationRequestDto{}
+	response := responsedto.WebhookDeliveryPaginationResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.GetAllWebhookDeliveries error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	opt := request.TransformToObject()
+	pagination, err := h.Service.Webhook.Deliveries(r.Context(), opt)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.GetAllWebhookDeliveries error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case repoerrors.ErrWebhookNotFound:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(pagination)
+	respond(w, r, http.StatusOK, response)
+}
+
+// @Summary Redeliver webhook delivery
+// @Description Queue new delivery with payload of delivery with id, payload is signed again when it is sent
+// @ID webhook-redeliver
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook id"
+// @Param delivery_id path string true "Delivery id"
+// @Success 202 {object} response.WebhookDeliveryResponseDto
+// @Failure 403 {object} response.ErrorResponseDto
+// @Failure 404 {object} response.ErrorResponseDto
+// @Failure 500 {object} response.ErrorResponseDto
+// @Security ApiKeyAuth
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *Handler) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	request := requestdto.RedeliverWebhookRequestDto{}
+	response := responsedto.WebhookDeliveryResponseDto{}
+
+	if response, err := request.FromRequest(r); err != nil {
+
+		h.Service.Logger.Errorf("v1.RedeliverWebhook error: %s", err)
+
+		errorRespond(w, r, response)
+		return
+	}
+
+	input := request.TransformToObject()
+	delivery, err := h.Service.Webhook.Redeliver(r.Context(), input)
+	if err != nil {
+
+		h.Service.Logger.Errorf("v1.RedeliverWebhook error: %s", err)
+
+		var errorResp responsedto.ErrorResponseDto
+		switch err {
+
+		case
+			repoerrors.ErrWebhookNotFound,
+			repoerrors.ErrWebhookDeliveryNotFound:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusNotFound, err.Error())
+
+		default:
+			errorResp = responsedto.NewErrorResponseDto(http.StatusInternalServerError, errors.ErrInternal.Error())
+		}
+
+		errorRespond(w, r, errorResp)
+		return
+	}
+
+	response.TransformFromObject(delivery)
+	respond(w, r, http.StatusAccepted, response)
+}
@@ -1,9 +1,17 @@
 package domain
 
 import (
+	"crypto/sha1"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/aintsashqa/go-simple-blog/pkg/webhook"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	uuid "github.com/satori/go.uuid"
@@ -13,38 +21,197 @@ import (
 const (
 	CreateUserValidationAction UserValidationAction = iota
 	UpdateUserValidationAction
+	UpdatePasswordUserValidationAction
+	UpdateEmailUserValidationAction
+	UpdateRolesUserValidationAction
+	UpdateProfileUserValidationAction
 
 	CreatePostValidationAction PostValidationAction = iota
 	UpdatePostValidationAction
 )
 
+const (
+	PasswordChangedSecurityEvent      SecurityEventType = "password_changed"
+	EmailChangeRequestedSecurityEvent SecurityEventType = "email_change_requested"
+	EmailChangedSecurityEvent         SecurityEventType = "email_changed"
+	AccountDeletedSecurityEvent       SecurityEventType = "account_deleted"
+	AccountRestoredSecurityEvent      SecurityEventType = "account_restored"
+	MFAEnabledSecurityEvent           SecurityEventType = "mfa_enabled"
+	MFADisabledSecurityEvent          SecurityEventType = "mfa_disabled"
+	RecoveryCodesCreatedSecurityEvent SecurityEventType = "recovery_codes_created"
+	RecoveryCodeUsedSecurityEvent     SecurityEventType = "recovery_code_used"
+	SignInLockedSecurityEvent         SecurityEventType = "sign_in_locked"
+
+	DeletePostsAction    PostsAction = "delete"
+	AnonymizePostsAction PostsAction = "anonymize"
+	TransferPostsAction  PostsAction = "transfer"
+
+	UserSuspendedAuditAction   AuditAction = "user_suspended"
+	UserUnsuspendedAuditAction AuditAction = "user_unsuspended"
+	PostUnpublishedAuditAction AuditAction = "post_unpublished"
+	PostDeletedAuditAction     AuditAction = "post_deleted"
+	MFARolesChangedAuditAction AuditAction = "mfa_roles_changed"
+
+	UserAuditTarget     AuditTarget = "user"
+	PostAuditTarget     AuditTarget = "post"
+	SettingsAuditTarget AuditTarget = "settings"
+
+	AdminRole  Role = "admin"
+	EditorRole Role = "editor"
+	AuthorRole Role = "author"
+	ReaderRole Role = "reader"
+
+	PostPublishedNotification   NotificationType = "post_published"
+	PostUnpublishedNotification NotificationType = "post_unpublished"
+	SecurityEventNotification   NotificationType = "security_event"
+
+	PostCreatedWebhookEvent   WebhookEvent = "post.created"
+	PostPublishedWebhookEvent WebhookEvent = "post.published"
+	PostDeletedWebhookEvent   WebhookEvent = "post.deleted"
+	UserUpdatedWebhookEvent   WebhookEvent = "user.updated"
+
+	PendingWebhookDelivery   WebhookDeliveryStatus = "pending"
+	DeliveredWebhookDelivery WebhookDeliveryStatus = "delivered"
+	FailedWebhookDelivery    WebhookDeliveryStatus = "failed"
+
+	PostsReadScope    Scope = "posts:read"
+	PostsWriteScope   Scope = "posts:write"
+	ProfileWriteScope Scope = "profile:write"
+)
+
+// GhostUserID is author of anonymized posts, user is created by migration
+var GhostUserID uuid.UUID = uuid.FromStringOrNil("00000000-0000-4000-8000-000000000000")
+
 var (
 	// User model errors
 	ErrUserEmailEmptyValue       error = errors.New("Field email is required.")
 	ErrUserEmailInvalidLength    error = errors.New("Field email must be greater than 6 and less 255 characters.")
 	ErrUserEmailInvalidValue     error = errors.New("Field email must be an email.")
 	ErrUserUsernameEmptyValue    error = errors.New("Field username is required.")
-	ErrUserUsernameInvalidLength error = errors.New("Field username must be greater than 3 and less 255 characters.")
+	ErrUserUsernameInvalidLength error = errors.New("Field username must be greater than 3 and less 30 characters.")
+	ErrUserUsernameInvalidValue  error = errors.New("Field username must contain only latin letters, digits and underscores.")
+	ErrUserUsernameReserved      error = errors.New("Field username must not be reserved word.")
 	ErrUserPasswordEmptyValue    error = errors.New("Field password is required.")
 	ErrUserPasswordInvalidLength error = errors.New("Field password must be greater than 5 and less 255 characters.")
 
+	// User profile errors
+	ErrUserDisplayNameInvalidLength error = errors.New("Field display_name must be less 64 characters.")
+	ErrUserBioInvalidLength         error = errors.New("Field bio must be less 2000 characters.")
+	ErrUserAvatarURLInvalidValue    error = errors.New("Field avatar_url must be http or https url less 255 characters.")
+	ErrUserLocationInvalidLength    error = errors.New("Field location must be less 128 characters.")
+	ErrUserWebsiteInvalidValue      error = errors.New("Field website must be http or https url less 255 characters.")
+	ErrUserSocialLinksInvalidSize   error = errors.New("Field social_links must contain less 10 values.")
+	ErrUserSocialLinksInvalidValue  error = errors.New("Field social_links must contain http or https urls less 255 characters.")
+	ErrUserRoleInvalidValue         error = errors.New("Field role must be one of admin, editor, author or reader.")
+
+	// Personal access token model errors
+	ErrPersonalAccessTokenNameEmptyValue     error = errors.New("Field name is required.")
+	ErrPersonalAccessTokenNameInvalidLength  error = errors.New("Field name must be less 100 characters.")
+	ErrPersonalAccessTokenScopesEmptyValue   error = errors.New("Field scopes is required.")
+	ErrPersonalAccessTokenScopesInvalidValue error = errors.New("Field scopes must contain posts:read, posts:write or profile:write.")
+
+	// Webhook model errors
+	ErrWebhookURLInvalidValue    error = errors.New("Field url must be public http or https url less 255 characters.")
+	ErrWebhookEventsEmptyValue   error = errors.New("Field events is required.")
+	ErrWebhookEventsInvalidValue error = errors.New("Field events must contain post.created, post.published, post.deleted or user.updated.")
+
 	// Post model errors
 	ErrPostTitleEmptyValue      error = errors.New("Field title is required.")
 	ErrPostTitleInvalidLength   error = errors.New("Field title must be greater than 8 and less 255 characters.")
 	ErrPostSlugInvalidLength    error = errors.New("Field slug must be greater than 8 and less 255 characters.")
 	ErrPostContentEmptyValue    error = errors.New("Field content is required.")
 	ErrPostContentInvalidLength error = errors.New("Field content must be greater than 500 characters.")
+	ErrPostTagsInvalidLength    error = errors.New("Field tags must contain values greater than 1 and less 64 characters.")
 )
 
+var usernamePattern *regexp.Regexp = regexp.MustCompile("^[A-Za-z0-9_]+$")
+
+// reservedUsernames could be confused with service accounts or routes
+var reservedUsernames map[string]struct{} = map[string]struct{}{
+	"admin":         {},
+	"administrator": {},
+	"anonymous":     {},
+	"api":           {},
+	"by_username":   {},
+	"deleted":       {},
+	"ghost":         {},
+	"help":          {},
+	"me":            {},
+	"moderator":     {},
+	"null":          {},
+	"root":          {},
+	"self":          {},
+	"settings":      {},
+	"staff":         {},
+	"support":       {},
+	"system":        {},
+	"undefined":     {},
+	"user":          {},
+	"www":           {},
+}
+
+func IsReservedUsername(username string) bool {
+	_, reserved := reservedUsernames[strings.ToLower(username)]
+	return reserved
+}
+
+// isProfileURL allows empty value, so profile urls could be removed
+func isProfileURL(value interface{}) error {
+	value, _ = validation.Indirect(value)
+	url, _ := value.(string)
+	if url == "" {
+		return nil
+	}
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return errors.New("url must use http or https scheme")
+	}
+	if err := validation.Validate(url, validation.Length(0, 255), is.URL); err != nil {
+		return err
+	}
+	return nil
+}
+
+// isPublicURL rejects urls of localhost and private networks, so webhooks
+// could not reach internal services
+func isPublicURL(value interface{}) error {
+	value, _ = validation.Indirect(value)
+	raw, _ := value.(string)
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if !webhook.IsPublicHost(parsed.Hostname()) {
+		return errors.New("url host must be public")
+	}
+	return nil
+}
+
 type (
 	UserValidationAction uint8
 	PostValidationAction uint8
+	SecurityEventType    string
+	PostsAction          string
+	Role                 string
+	AuditAction          string
+	AuditTarget          string
+	Scope                string
+	NotificationType     string
+
+	Tags        []string
+	SocialLinks []string
+	Roles       []Role
+	Scopes      []Scope
+
+	WebhookEvent          string
+	WebhookEvents         []WebhookEvent
+	WebhookDeliveryStatus string
 
 	Model struct {
 		ID        uuid.UUID `json:"id"            db:"id"`
 		CreatedAt time.Time `json:"created_at"    db:"created_at"`
 		UpdatedAt time.Time `json:"updated_at"    db:"updated_at"`
 		DeletedAt null.Time `json:"-"             db:"deleted_at"`
+		Version   int       `json:"version"       db:"version"`
 	}
 
 	User struct {
@@ -52,6 +219,21 @@ type (
 		Email    string `json:"email,omitempty"    db:"email"`
 		Username string `json:"username"           db:"username"`
 		Password string `json:"-"                  db:"encrypted_password"`
+
+		EmailVerifiedAt null.Time `json:"email_verified_at" db:"email_verified_at"`
+
+		// Profile fields are public, bio is Markdown
+		DisplayName string      `json:"display_name" db:"display_name"`
+		Bio         string      `json:"bio"          db:"bio"`
+		AvatarURL   string      `json:"avatar_url"   db:"avatar_url"`
+		Location    string      `json:"location"     db:"location"`
+		Website     string      `json:"website"      db:"website"`
+		SocialLinks SocialLinks `json:"social_links" db:"social_links"`
+
+		Roles Roles `json:"roles,omitempty" db:"roles"`
+
+		// SuspendedAt is set by admin, suspended user could not sign in
+		SuspendedAt null.Time `json:"-" db:"suspended_at"`
 	}
 
 	Post struct {
@@ -59,9 +241,175 @@ type (
 		Title       string    `json:"title"           db:"title"`
 		Slug        string    `json:"slug"            db:"slug"`
 		Content     string    `json:"content"         db:"content"`
+		Tags        Tags      `json:"tags"            db:"tags"`
 		UserID      uuid.UUID `json:"user_id"         db:"user_id"`
 		PublishedAt null.Time `json:"published_at"    db:"published_at"`
 	}
+
+	// RefreshToken is stored by hash only, tokens rotated from same sign in
+	// share family, so reuse of revoked token could revoke whole family
+	RefreshToken struct {
+		ID        uuid.UUID `db:"id"`
+		UserID    uuid.UUID `db:"user_id"`
+		FamilyID  uuid.UUID `db:"family_id"`
+		TokenHash string    `db:"token_hash"`
+		CreatedAt time.Time `db:"created_at"`
+		ExpiresAt time.Time `db:"expires_at"`
+		RevokedAt null.Time `db:"revoked_at"`
+	}
+
+	// Session is created on every sign in, refresh tokens of session share
+	// its id as family, so revoked session could not be refreshed
+	Session struct {
+		ID         uuid.UUID `db:"id"`
+		UserID     uuid.UUID `db:"user_id"`
+		UserAgent  string    `db:"user_agent"`
+		IP         string    `db:"ip"`
+		CreatedAt  time.Time `db:"created_at"`
+		LastSeenAt time.Time `db:"last_seen_at"`
+		RevokedAt  null.Time `db:"revoked_at"`
+	}
+
+	// PersonalAccessToken is long lived token for automation, token is stored
+	// by hash only and prefix is kept to identify token in list
+	PersonalAccessToken struct {
+		ID         uuid.UUID `db:"id"`
+		UserID     uuid.UUID `db:"user_id"`
+		Name       string    `db:"name"`
+		Prefix     string    `db:"prefix"`
+		TokenHash  string    `db:"token_hash"`
+		Scopes     Scopes    `db:"scopes"`
+		CreatedAt  time.Time `db:"created_at"`
+		LastUsedAt null.Time `db:"last_used_at"`
+	}
+
+	// UserIdentity links account of external identity provider to user,
+	// subject is unique only within provider
+	UserIdentity struct {
+		Provider  string    `db:"provider"`
+		Subject   string    `db:"subject"`
+		UserID    uuid.UUID `db:"user_id"`
+		Email     string    `db:"email"`
+		CreatedAt time.Time `db:"created_at"`
+	}
+
+	// TOTPFactor is second factor of user, factor is enabled only after first
+	// code was confirmed. Counter of last accepted code is kept, so same code
+	// could not be used twice
+	TOTPFactor struct {
+		UserID      uuid.UUID `db:"user_id"`
+		Secret      string    `db:"secret"`
+		LastCounter int64     `db:"last_counter"`
+		CreatedAt   time.Time `db:"created_at"`
+		ConfirmedAt null.Time `db:"confirmed_at"`
+	}
+
+	// RecoveryCode replaces second factor once, codes are stored by hash only
+	RecoveryCode struct {
+		ID        uuid.UUID `db:"id"`
+		UserID    uuid.UUID `db:"user_id"`
+		CodeHash  string    `db:"code_hash"`
+		CreatedAt time.Time `db:"created_at"`
+		UsedAt    null.Time `db:"used_at"`
+	}
+
+	// PasswordReset is stored by hash only and could be used once
+	PasswordReset struct {
+		ID        uuid.UUID `db:"id"`
+		UserID    uuid.UUID `db:"user_id"`
+		TokenHash string    `db:"token_hash"`
+		CreatedAt time.Time `db:"created_at"`
+		ExpiresAt time.Time `db:"expires_at"`
+		UsedAt    null.Time `db:"used_at"`
+	}
+
+	// SecurityEvent records sensitive change of account, events are never updated
+	SecurityEvent struct {
+		ID        uuid.UUID         `db:"id"`
+		UserID    uuid.UUID         `db:"user_id"`
+		Type      SecurityEventType `db:"type"`
+		CreatedAt time.Time         `db:"created_at"`
+	}
+
+	// AccountDeletion keeps choice of user until account is purged,
+	// posts are handled only on purge, so account could be restored before
+	AccountDeletion struct {
+		UserID      uuid.UUID     `db:"user_id"`
+		PostsAction PostsAction   `db:"posts_action"`
+		TransferTo  uuid.NullUUID `db:"transfer_to"`
+		RequestedAt time.Time     `db:"requested_at"`
+		PurgeAt     time.Time     `db:"purge_at"`
+	}
+
+	// AuditLog records action of admin, logs are never updated or deleted
+	AuditLog struct {
+		ID         uuid.UUID   `db:"id"`
+		ActorID    uuid.UUID   `db:"actor_id"`
+		Action     AuditAction `db:"action"`
+		TargetType AuditTarget `db:"target_type"`
+		TargetID   uuid.UUID   `db:"target_id"`
+		CreatedAt  time.Time   `db:"created_at"`
+	}
+
+	// Follow subscribes follower to posts of followee, see feed of posts
+	Follow struct {
+		FollowerID uuid.UUID `db:"follower_id"`
+		FolloweeID uuid.UUID `db:"followee_id"`
+		CreatedAt  time.Time `db:"created_at"`
+	}
+
+	// Notification informs user about activity, actor and post are set for
+	// notifications about posts, event is set for security notifications
+	Notification struct {
+		ID        uuid.UUID         `db:"id"`
+		UserID    uuid.UUID         `db:"user_id"`
+		Type      NotificationType  `db:"type"`
+		ActorID   uuid.NullUUID     `db:"actor_id"`
+		PostID    uuid.NullUUID     `db:"post_id"`
+		Event     SecurityEventType `db:"event"`
+		CreatedAt time.Time         `db:"created_at"`
+		ReadAt    null.Time         `db:"read_at"`
+	}
+
+	// Webhook subscribes url to events of its user, global webhooks are
+	// created by admins and receive events of all users
+	Webhook struct {
+		ID        uuid.UUID     `db:"id"`
+		UserID    uuid.UUID     `db:"user_id"`
+		URL       string        `db:"url"`
+		Secret    string        `db:"secret"`
+		Events    WebhookEvents `db:"events"`
+		Global    bool          `db:"global"`
+		CreatedAt time.Time     `db:"created_at"`
+	}
+
+	// WebhookDelivery keeps signed payload until it is delivered, status
+	// code and error of last attempt are kept as delivery log
+	WebhookDelivery struct {
+		ID            uuid.UUID             `db:"id"`
+		WebhookID     uuid.UUID             `db:"webhook_id"`
+		Event         WebhookEvent          `db:"event"`
+		Payload       string                `db:"payload"`
+		Status        WebhookDeliveryStatus `db:"status"`
+		Attempts      int                   `db:"attempts"`
+		StatusCode    int                   `db:"status_code"`
+		Error         string                `db:"error"`
+		NextAttemptAt null.Time             `db:"next_attempt_at"`
+		CreatedAt     time.Time             `db:"created_at"`
+		UpdatedAt     time.Time             `db:"updated_at"`
+	}
+
+	// FeedCursor points to last post of feed page, next page starts after it
+	FeedCursor struct {
+		PublishedAt time.Time
+		PostID      uuid.UUID
+	}
+
+	// Validator holds representation metadata used by conditional requests
+	Validator struct {
+		ETag         string    `json:"etag"`
+		LastModified time.Time `json:"last_modified"`
+	}
 )
 
 func (m *Model) Init() {
@@ -69,10 +417,42 @@ func (m *Model) Init() {
 	m.CreatedAt = time.Now()
 	m.UpdatedAt = time.Now()
 	m.DeletedAt = null.NewTime(time.Now(), false)
+	m.Version = 1
 }
 
+// Update must be called once on every write, so version is increased
+// exactly by one and previous version could be checked by repositories
 func (m *Model) Update() {
 	m.UpdatedAt = time.Now()
+	m.Version++
+}
+
+// ETag returns strong entity tag of current model version, every write
+// increases version so tag changes together with content
+func (m *Model) ETag() string {
+	return fmt.Sprintf("\"%x\"", sha1.Sum([]byte(fmt.Sprintf("%s:%d", m.ID, m.Version))))
+}
+
+func (m *Model) Validator() Validator {
+	return Validator{ETag: m.ETag(), LastModified: m.UpdatedAt}
+}
+
+// NewCollectionValidator combines validators of collection items, extra values
+// such as pagination must be passed so different pages never share entity tag
+func NewCollectionValidator(validators []Validator, extra ...interface{}) Validator {
+	hash := sha1.New()
+	collection := Validator{}
+
+	for _, validator := range validators {
+		hash.Write([]byte(validator.ETag))
+		if validator.LastModified.After(collection.LastModified) {
+			collection.LastModified = validator.LastModified
+		}
+	}
+
+	fmt.Fprint(hash, extra...)
+	collection.ETag = fmt.Sprintf("\"%x\"", hash.Sum(nil))
+	return collection
 }
 
 func (m *Model) Delete() {
@@ -80,36 +460,215 @@ func (m *Model) Delete() {
 	m.DeletedAt = null.NewTime(time.Now(), true)
 }
 
-func (u *User) Validate(action UserValidationAction) error {
-	switch action {
+// NewTags trims, lowercases and deduplicates tag values
+func NewTags(values ...string) Tags {
+	tags := Tags{}
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(value, ",", " ")))
+		if len(value) == 0 || tags.Contains(value) {
+			continue
+		}
+		tags = append(tags, value)
+	}
+	return tags
+}
 
-	case CreateUserValidationAction:
-		// Email validations
-		if err := validation.Validate(&u.Email, validation.Required); err != nil {
-			return ErrUserEmailEmptyValue
+func (t Tags) Contains(value string) bool {
+	for _, tag := range t {
+		if tag == value {
+			return true
 		}
-		if err := validation.Validate(&u.Email, validation.Length(6, 255)); err != nil {
-			return ErrUserEmailInvalidLength
+	}
+	return false
+}
+
+// Value stores tags as comma separated string
+func (t Tags) Value() (driver.Value, error) {
+	return strings.Join(t, ","), nil
+}
+
+func (t *Tags) Scan(src interface{}) error {
+	var value string
+	switch src := src.(type) {
+	case []byte:
+		value = string(src)
+	case string:
+		value = src
+	case nil:
+		value = ""
+	default:
+		return errors.New("unsupported tags value type")
+	}
+
+	*t = Tags{}
+	if len(value) != 0 {
+		*t = strings.Split(value, ",")
+	}
+	return nil
+}
+
+func (r Role) IsValid() bool {
+	switch r {
+	case AdminRole, EditorRole, AuthorRole, ReaderRole:
+		return true
+	}
+	return false
+}
+
+// HasAny reports whether at least one of roles is granted
+func (r Roles) HasAny(roles ...Role) bool {
+	for _, granted := range r {
+		for _, role := range roles {
+			if granted == role {
+				return true
+			}
 		}
-		if err := validation.Validate(&u.Email, is.Email); err != nil {
-			return ErrUserEmailInvalidValue
+	}
+	return false
+}
+
+// Value stores roles as comma separated string, same as tags
+func (r Roles) Value() (driver.Value, error) {
+	values := make([]string, 0, len(r))
+	for _, role := range r {
+		values = append(values, string(role))
+	}
+	return strings.Join(values, ","), nil
+}
+
+func (r *Roles) Scan(src interface{}) error {
+	var value string
+	switch src := src.(type) {
+	case []byte:
+		value = string(src)
+	case string:
+		value = src
+	case nil:
+		value = ""
+	default:
+		return errors.New("unsupported roles value type")
+	}
+
+	*r = Roles{}
+	if len(value) != 0 {
+		for _, role := range strings.Split(value, ",") {
+			*r = append(*r, Role(role))
 		}
+	}
+	return nil
+}
 
-		// Password validations
-		if err := validation.Validate(&u.Password, validation.Required); err != nil {
-			return ErrUserPasswordEmptyValue
+func (s Scope) IsValid() bool {
+	switch s {
+	case PostsReadScope, PostsWriteScope, ProfileWriteScope:
+		return true
+	}
+	return false
+}
+
+func (s Scopes) Contains(scope Scope) bool {
+	for _, granted := range s {
+		if granted == scope {
+			return true
 		}
-		if err := validation.Validate(&u.Password, validation.Length(5, 255)); err != nil {
-			return ErrUserPasswordInvalidLength
+	}
+	return false
+}
+
+// Value stores scopes as comma separated string, same as roles
+func (s Scopes) Value() (driver.Value, error) {
+	values := make([]string, 0, len(s))
+	for _, scope := range s {
+		values = append(values, string(scope))
+	}
+	return strings.Join(values, ","), nil
+}
+
+func (s *Scopes) Scan(src interface{}) error {
+	var value string
+	switch src := src.(type) {
+	case []byte:
+		value = string(src)
+	case string:
+		value = src
+	case nil:
+		value = ""
+	default:
+		return errors.New("unsupported scopes value type")
+	}
+
+	*s = Scopes{}
+	if len(value) != 0 {
+		for _, scope := range strings.Split(value, ",") {
+			*s = append(*s, Scope(scope))
 		}
+	}
+	return nil
+}
+
+// Value stores links as json array, because urls could contain commas
+func (l SocialLinks) Value() (driver.Value, error) {
+	if l == nil {
+		l = SocialLinks{}
+	}
+	value, err := json.Marshal([]string(l))
+	return string(value), err
+}
+
+func (l *SocialLinks) Scan(src interface{}) error {
+	var value []byte
+	switch src := src.(type) {
+	case []byte:
+		value = src
+	case string:
+		value = []byte(src)
+	case nil:
+		value = nil
+	default:
+		return errors.New("unsupported social links value type")
+	}
+
+	*l = SocialLinks{}
+	if len(value) != 0 {
+		return json.Unmarshal(value, l)
+	}
+	return nil
+}
+
+func (u *User) Validate(action UserValidationAction) error {
+	switch action {
+
+	case CreateUserValidationAction:
+		if err := u.validateEmail(); err != nil {
+			return err
+		}
+		if err := u.validateUsername(); err != nil {
+			return err
+		}
+
+		return u.validatePassword()
 
 	case UpdateUserValidationAction:
-		// Username validations
-		if err := validation.Validate(&u.Username, validation.Required); err != nil {
-			return ErrUserUsernameEmptyValue
+		if err := u.validateUsername(); err != nil {
+			return err
 		}
-		if err := validation.Validate(&u.Username, validation.Length(3, 255)); err != nil {
-			return ErrUserUsernameInvalidLength
+
+		return u.validateProfile()
+
+	case UpdateProfileUserValidationAction:
+		return u.validateProfile()
+
+	case UpdatePasswordUserValidationAction:
+		return u.validatePassword()
+
+	case UpdateEmailUserValidationAction:
+		return u.validateEmail()
+
+	case UpdateRolesUserValidationAction:
+		for _, role := range u.Roles {
+			if !role.IsValid() {
+				return ErrUserRoleInvalidValue
+			}
 		}
 
 	}
@@ -117,6 +676,78 @@ func (u *User) Validate(action UserValidationAction) error {
 	return nil
 }
 
+// validateUsername does not check uniqueness, usernames are compared
+// case insensitively by database collation
+func (u *User) validateUsername() error {
+	if err := validation.Validate(&u.Username, validation.Required); err != nil {
+		return ErrUserUsernameEmptyValue
+	}
+	if err := validation.Validate(&u.Username, validation.Length(3, 30)); err != nil {
+		return ErrUserUsernameInvalidLength
+	}
+	if err := validation.Validate(&u.Username, validation.Match(usernamePattern)); err != nil {
+		return ErrUserUsernameInvalidValue
+	}
+	if IsReservedUsername(u.Username) {
+		return ErrUserUsernameReserved
+	}
+
+	return nil
+}
+
+func (u *User) validateProfile() error {
+	if err := validation.Validate(&u.DisplayName, validation.Length(0, 64)); err != nil {
+		return ErrUserDisplayNameInvalidLength
+	}
+	if err := validation.Validate(&u.Bio, validation.Length(0, 2000)); err != nil {
+		return ErrUserBioInvalidLength
+	}
+	if err := validation.Validate(&u.AvatarURL, validation.By(isProfileURL)); err != nil {
+		return ErrUserAvatarURLInvalidValue
+	}
+	if err := validation.Validate(&u.Location, validation.Length(0, 128)); err != nil {
+		return ErrUserLocationInvalidLength
+	}
+	if err := validation.Validate(&u.Website, validation.By(isProfileURL)); err != nil {
+		return ErrUserWebsiteInvalidValue
+	}
+	// Links are validated as plain slice, because SocialLinks is driver.Valuer
+	links := []string(u.SocialLinks)
+	if err := validation.Validate(links, validation.Length(0, 10)); err != nil {
+		return ErrUserSocialLinksInvalidSize
+	}
+	if err := validation.Validate(links, validation.Each(validation.Required, validation.By(isProfileURL))); err != nil {
+		return ErrUserSocialLinksInvalidValue
+	}
+
+	return nil
+}
+
+func (u *User) validateEmail() error {
+	if err := validation.Validate(&u.Email, validation.Required); err != nil {
+		return ErrUserEmailEmptyValue
+	}
+	if err := validation.Validate(&u.Email, validation.Length(6, 255)); err != nil {
+		return ErrUserEmailInvalidLength
+	}
+	if err := validation.Validate(&u.Email, is.Email); err != nil {
+		return ErrUserEmailInvalidValue
+	}
+
+	return nil
+}
+
+func (u *User) validatePassword() error {
+	if err := validation.Validate(&u.Password, validation.Required); err != nil {
+		return ErrUserPasswordEmptyValue
+	}
+	if err := validation.Validate(&u.Password, validation.Length(5, 255)); err != nil {
+		return ErrUserPasswordInvalidLength
+	}
+
+	return nil
+}
+
 func (p *Post) Validate(action PostValidationAction) error {
 	switch action {
 
@@ -142,7 +773,260 @@ func (p *Post) Validate(action PostValidationAction) error {
 			return ErrPostContentInvalidLength
 		}
 
+		// Tags validations
+		if err := validation.Validate([]string(p.Tags), validation.Each(validation.Length(1, 64))); err != nil {
+			return ErrPostTagsInvalidLength
+		}
+
+	}
+
+	return nil
+}
+
+func (t *PersonalAccessToken) Validate() error {
+	if err := validation.Validate(&t.Name, validation.Required); err != nil {
+		return ErrPersonalAccessTokenNameEmptyValue
+	}
+	if err := validation.Validate(&t.Name, validation.Length(1, 100)); err != nil {
+		return ErrPersonalAccessTokenNameInvalidLength
+	}
+
+	if len(t.Scopes) == 0 {
+		return ErrPersonalAccessTokenScopesEmptyValue
+	}
+	for _, scope := range t.Scopes {
+		if !scope.IsValid() {
+			return ErrPersonalAccessTokenScopesInvalidValue
+		}
 	}
 
 	return nil
 }
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt.Valid
+}
+
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt.Valid
+}
+
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
+
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt.Valid
+}
+
+func (s *Session) IsRevoked() bool {
+	return s.RevokedAt.Valid
+}
+
+func (n *Notification) IsRead() bool {
+	return n.ReadAt.Valid
+}
+
+func (e WebhookEvent) IsValid() bool {
+	switch e {
+	case PostCreatedWebhookEvent, PostPublishedWebhookEvent, PostDeletedWebhookEvent, UserUpdatedWebhookEvent:
+		return true
+	}
+	return false
+}
+
+func (e WebhookEvents) Contains(event WebhookEvent) bool {
+	for _, subscribed := range e {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Value stores events as comma separated string, same as scopes
+func (e WebhookEvents) Value() (driver.Value, error) {
+	values := make([]string, 0, len(e))
+	for _, event := range e {
+		values = append(values, string(event))
+	}
+	return strings.Join(values, ","), nil
+}
+
+func (e *WebhookEvents) Scan(src interface{}) error {
+	var value string
+	switch src := src.(type) {
+	case []byte:
+		value = string(src)
+	case string:
+		value = src
+	case nil:
+		value = ""
+	default:
+		return errors.New("unsupported webhook events value type")
+	}
+
+	*e = WebhookEvents{}
+	if len(value) != 0 {
+		for _, event := range strings.Split(value, ",") {
+			*e = append(*e, WebhookEvent(event))
+		}
+	}
+	return nil
+}
+
+func (w *Webhook) Validate() error {
+	if err := validation.Validate(&w.URL, validation.Required, validation.Length(1, 255), is.URL, validation.By(isProfileURL), validation.By(isPublicURL)); err != nil {
+		return ErrWebhookURLInvalidValue
+	}
+
+	if len(w.Events) == 0 {
+		return ErrWebhookEventsEmptyValue
+	}
+	for _, event := range w.Events {
+		if !event.IsValid() {
+			return ErrWebhookEventsInvalidValue
+		}
+	}
+
+	return nil
+}
+
+// IsDue reports whether pending delivery should be attempted at time
+func (d *WebhookDelivery) IsDue(now time.Time) bool {
+	return d.Status == PendingWebhookDelivery && d.NextAttemptAt.Valid && !d.NextAttemptAt.Time.After(now)
+}
+
+func (r *PasswordReset) IsExpired() bool {
+	return time.Now().After(r.ExpiresAt)
+}
+
+func (r *PasswordReset) IsUsed() bool {
+	return r.UsedAt.Valid
+}
+
+func (f *TOTPFactor) IsConfirmed() bool {
+	return f.ConfirmedAt.Valid
+}
+
+func (a PostsAction) IsValid() bool {
+	switch a {
+	case DeletePostsAction, AnonymizePostsAction, TransferPostsAction:
+		return true
+	}
+	return false
+}
+
+func NewSecurityEvent(userID uuid.UUID, eventType SecurityEventType) SecurityEvent {
+	return SecurityEvent{
+		ID:        uuid.NewV4(),
+		UserID:    userID,
+		Type:      eventType,
+		CreatedAt: time.Now(),
+	}
+}
+
+func NewAuditLog(actorID uuid.UUID, action AuditAction, targetType AuditTarget, targetID uuid.UUID) AuditLog {
+	return AuditLog{
+		ID:         uuid.NewV4(),
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		CreatedAt:  time.Now(),
+	}
+}
+
+func NewUserIdentity(provider string, subject string, userID uuid.UUID, email string) UserIdentity {
+	return UserIdentity{
+		Provider:  provider,
+		Subject:   subject,
+		UserID:    userID,
+		Email:     email,
+		CreatedAt: time.Now(),
+	}
+}
+
+func NewSession(userID uuid.UUID, userAgent string, ip string) Session {
+	now := time.Now()
+	return Session{
+		ID:         uuid.NewV4(),
+		UserID:     userID,
+		UserAgent:  userAgent,
+		IP:         ip,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		RevokedAt:  null.NewTime(now, false),
+	}
+}
+
+func NewTOTPFactor(userID uuid.UUID, secret string) TOTPFactor {
+	return TOTPFactor{
+		UserID:      userID,
+		Secret:      secret,
+		CreatedAt:   time.Now(),
+		ConfirmedAt: null.NewTime(time.Now(), false),
+	}
+}
+
+func NewRecoveryCode(userID uuid.UUID, hash string) RecoveryCode {
+	return RecoveryCode{
+		ID:        uuid.NewV4(),
+		UserID:    userID,
+		CodeHash:  hash,
+		CreatedAt: time.Now(),
+		UsedAt:    null.NewTime(time.Now(), false),
+	}
+}
+
+func NewFollow(followerID uuid.UUID, followeeID uuid.UUID) Follow {
+	return Follow{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// NewPostNotification is sent to user about post of actor
+func NewPostNotification(userID uuid.UUID, notificationType NotificationType, actorID uuid.UUID, postID uuid.UUID) Notification {
+	return Notification{
+		ID:        uuid.NewV4(),
+		UserID:    userID,
+		Type:      notificationType,
+		ActorID:   uuid.NullUUID{UUID: actorID, Valid: true},
+		PostID:    uuid.NullUUID{UUID: postID, Valid: true},
+		CreatedAt: time.Now(),
+		ReadAt:    null.NewTime(time.Now(), false),
+	}
+}
+
+func NewSecurityEventNotification(event SecurityEvent) Notification {
+	return Notification{
+		ID:        uuid.NewV4(),
+		UserID:    event.UserID,
+		Type:      SecurityEventNotification,
+		Event:     event.Type,
+		CreatedAt: event.CreatedAt,
+		ReadAt:    null.NewTime(time.Now(), false),
+	}
+}
+
+// NewWebhookDelivery is attempted at once, payload is kept as is, so
+// redelivery sends same payload
+func NewWebhookDelivery(webhookID uuid.UUID, event WebhookEvent, payload string) WebhookDelivery {
+	now := time.Now()
+	return WebhookDelivery{
+		ID:            uuid.NewV4(),
+		WebhookID:     webhookID,
+		Event:         event,
+		Payload:       payload,
+		Status:        PendingWebhookDelivery,
+		NextAttemptAt: null.NewTime(now, true),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func (c FeedCursor) IsZero() bool {
+	return c.PublishedAt.IsZero() && c.PostID == uuid.Nil
+}
@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/markdown"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	uuid "github.com/satori/go.uuid"
+)
+
+const markdownExtension string = ".md"
+
+type (
+	Result struct {
+		Filename string
+		Post     domain.Post
+		Err      error
+	}
+
+	Importer struct {
+		posts service.Post
+	}
+
+	// DryRunPostRepos skips writing posts, so service validations
+	// could be checked without changing database
+	DryRunPostRepos struct {
+		repository.Post
+	}
+
+	// SilentNotifier does not notify followers and webhooks, imported
+	// posts are published in the past
+	SilentNotifier struct{}
+)
+
+func NewImporter(posts service.Post) *Importer {
+	return &Importer{posts: posts}
+}
+
+func NewDryRunPostRepos(repo repository.Post) *DryRunPostRepos {
+	return &DryRunPostRepos{Post: repo}
+}
+
+func (r *DryRunPostRepos) Create(ctx context.Context, post domain.Post) error {
+	return nil
+}
+
+func (n SilentNotifier) PostPublished(ctx context.Context, post domain.Post) {}
+
+func (n SilentNotifier) PostUnpublished(ctx context.Context, post domain.Post, actorID uuid.UUID) {}
+
+func (n SilentNotifier) SecurityEvent(ctx context.Context, event domain.SecurityEvent) {}
+
+func (n SilentNotifier) Dispatch(context.Context, domain.WebhookEvent, uuid.UUID, interface{}) {}
+
+// Import creates post for each markdown file in directory, failure of
+// one file does not stop the import and is reported in its result
+func (i *Importer) Import(ctx context.Context, directory string, userID uuid.UUID) ([]Result, error) {
+	files, err := ioutil.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []Result{}
+	for _, file := range files {
+		if file.IsDir() || !strings.EqualFold(filepath.Ext(file.Name()), markdownExtension) {
+			continue
+		}
+
+		filename := filepath.Join(directory, file.Name())
+		post, err := i.importFile(ctx, filename, userID)
+		results = append(results, Result{Filename: filename, Post: post, Err: err})
+	}
+
+	return results, nil
+}
+
+func (i *Importer) importFile(ctx context.Context, filename string, userID uuid.UUID) (domain.Post, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return domain.Post{}, err
+	}
+	defer file.Close()
+
+	document, err := markdown.Parse(file)
+	if err != nil {
+		return domain.Post{}, err
+	}
+
+	return i.posts.Create(ctx, TransformToObject(document, userID))
+}
+
+func TransformToObject(document markdown.Document, userID uuid.UUID) service.CreatePostInput {
+	return service.CreatePostInput{
+		Title:       document.FrontMatter.Title,
+		Slug:        document.FrontMatter.Slug,
+		Content:     document.Content,
+		Tags:        document.FrontMatter.Tags,
+		UserID:      userID,
+		IsPublished: !document.FrontMatter.Draft,
+		CreatedAt:   document.FrontMatter.Date,
+	}
+}
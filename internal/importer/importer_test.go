@@ -0,0 +1,174 @@
+package importer_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/importer"
+	"github.com/aintsashqa/go-simple-blog/internal/markdown"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type ImporterSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockPostRepository *mock_repository.MockPost
+	MockUserRepository *mock_repository.MockUser
+
+	Directory string
+	UserID    uuid.UUID
+}
+
+func TestImporterSuite(t *testing.T) {
+	suite.Run(t, new(ImporterSuite))
+}
+
+func (s *ImporterSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockPostRepository = mock_repository.NewMockPost(s.Controller)
+	s.MockUserRepository = mock_repository.NewMockUser(s.Controller)
+	s.UserID = uuid.NewV4()
+
+	directory, err := ioutil.TempDir("", "importer")
+	s.Assertions.NoError(err)
+	s.Directory = directory
+}
+
+func (s *ImporterSuite) TearDownTest() {
+	s.Controller.Finish()
+	os.RemoveAll(s.Directory)
+}
+
+// newImporter creates posts with repository, which is wrapped in dry
+// run repository when it is requested
+func (s *ImporterSuite) newImporter(dryRun bool) *importer.Importer {
+	var posts repository.Post = s.MockPostRepository
+	if dryRun {
+		posts = importer.NewDryRunPostRepos(s.MockPostRepository)
+	}
+	return importer.NewImporter(service.NewPostService(posts, s.MockUserRepository, importer.SilentNotifier{}, importer.SilentNotifier{}, false))
+}
+
+func (s *ImporterSuite) writeFile(name string, content string) string {
+	filename := filepath.Join(s.Directory, name)
+	s.Assertions.NoError(ioutil.WriteFile(filename, []byte(content), 0644))
+	return filename
+}
+
+func (s *ImporterSuite) TestTransformToObjectMethod() {
+	date := time.Date(2019, time.March, 1, 0, 0, 0, 0, time.UTC)
+	methodCases := []struct {
+		Name              string
+		Document          markdown.Document
+		MethodResultValue service.CreatePostInput
+	}{
+		{
+			Name: "Published",
+			Document: markdown.Document{
+				FrontMatter: markdown.FrontMatter{Title: "Hello world post", Slug: "hello-world-post", Date: date, Tags: []string{"go", "blog"}},
+				Content:     "# Hello",
+			},
+			MethodResultValue: service.CreatePostInput{
+				Title:       "Hello world post",
+				Slug:        "hello-world-post",
+				Content:     "# Hello",
+				Tags:        []string{"go", "blog"},
+				UserID:      s.UserID,
+				IsPublished: true,
+				CreatedAt:   date,
+			},
+		},
+		{
+			Name: "Draft",
+			Document: markdown.Document{
+				FrontMatter: markdown.FrontMatter{Title: "Hello world post", Date: date, Draft: true},
+				Content:     "# Hello",
+			},
+			MethodResultValue: service.CreatePostInput{
+				Title:       "Hello world post",
+				Content:     "# Hello",
+				UserID:      s.UserID,
+				IsPublished: false,
+				CreatedAt:   date,
+			},
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			result := importer.TransformToObject(currentCase.Document, s.UserID)
+			s.Assertions.Equal(currentCase.MethodResultValue, result)
+		})
+	}
+}
+
+func (s *ImporterSuite) TestImportMethod() {
+	content := strings.Repeat("Imported post content. ", 30)
+	valid := s.writeFile("valid.md", "---\ntitle: Hello world post\ndate: 2019-03-01\ntags: [go]\n---\n\n"+content)
+	draft := s.writeFile("draft.md", "---\ntitle: Draft world post\ndate: 2019-04-01\ndraft: true\n---\n\n"+content)
+	invalid := s.writeFile("invalid.md", "---\ntitle: Short\n---\n\n"+content)
+	missing := s.writeFile("missing.md", "# Front matter is missing\n")
+	s.writeFile("notes.txt", "not a post")
+
+	s.MockPostRepository.EXPECT().
+		Create(context.Background(), gomock.AssignableToTypeOf(domain.Post{})).
+		Return(nil).
+		Times(2)
+
+	results, err := s.newImporter(false).Import(context.Background(), s.Directory, s.UserID)
+	s.Assertions.NoError(err)
+	s.Assertions.Len(results, 4)
+
+	byFilename := map[string]importer.Result{}
+	for _, result := range results {
+		byFilename[result.Filename] = result
+	}
+
+	s.Assertions.NoError(byFilename[valid].Err)
+	s.Assertions.Equal(time.Date(2019, time.March, 1, 0, 0, 0, 0, time.UTC), byFilename[valid].Post.CreatedAt)
+	s.Assertions.True(byFilename[valid].Post.PublishedAt.Valid)
+	s.Assertions.Equal(domain.NewTags("go"), byFilename[valid].Post.Tags)
+	s.Assertions.Equal(s.UserID, byFilename[valid].Post.UserID)
+
+	s.Assertions.NoError(byFilename[draft].Err)
+	s.Assertions.False(byFilename[draft].Post.PublishedAt.Valid)
+
+	s.Assertions.Equal(domain.ErrPostTitleInvalidLength, byFilename[invalid].Err)
+	s.Assertions.Equal(markdown.ErrFrontMatterMissing, byFilename[missing].Err)
+}
+
+func (s *ImporterSuite) TestImportMethodDryRun() {
+	content := strings.Repeat("Imported post content. ", 30)
+	valid := s.writeFile("valid.md", "---\ntitle: Hello world post\ndate: 2019-03-01\n---\n\n"+content)
+	invalid := s.writeFile("invalid.md", "---\ntitle: Hello world post\n---\n\nshort")
+
+	// Repository has no expectations, so any write fails the test
+	results, err := s.newImporter(true).Import(context.Background(), s.Directory, s.UserID)
+	s.Assertions.NoError(err)
+	s.Assertions.Len(results, 2)
+
+	for _, result := range results {
+		switch result.Filename {
+		case valid:
+			s.Assertions.NoError(result.Err)
+		case invalid:
+			s.Assertions.Equal(domain.ErrPostContentInvalidLength, result.Err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package importer
+
+import (
+	"context"
+
+	"github.com/aintsashqa/go-simple-blog/internal/config"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	"github.com/aintsashqa/go-simple-blog/pkg/database/mysql"
+	standart "github.com/aintsashqa/go-simple-blog/pkg/logger/standard"
+)
+
+type Options struct {
+	Directory string
+	Email     string
+	DryRun    bool
+}
+
+func Run(opt Options) {
+	ctx := context.Background()
+
+	logger := standart.NewStandartLoggerProvider()
+	logger.Info("Initialize config")
+	cfg, err := config.Init("config")
+	if err != nil {
+		logger.Critical(err)
+	}
+
+	logger.Info("Initialize database connection")
+	database, err := mysql.NewMySQLProvider(mysql.Config{
+		Host:     cfg.Database.Host,
+		Port:     cfg.Database.Port,
+		Username: cfg.Database.Username,
+		Password: cfg.Database.Password,
+		DBName:   cfg.Database.DatabaseName,
+		Charset:  cfg.Database.Charset,
+	})
+	if err != nil {
+		logger.Critical(err)
+	}
+	defer database.Close()
+
+	repos := repository.NewRepository(database)
+
+	user, err := repos.User.GetByEmail(ctx, opt.Email)
+	if err != nil {
+		logger.Criticalf("Find user with email %s error: %s", opt.Email, err)
+	}
+
+	var posts repository.Post = repos.Post
+	if opt.DryRun {
+		logger.Info("Dry run, posts will not be saved")
+		posts = NewDryRunPostRepos(repos.Post)
+	}
+
+	// Imported posts belong to existing user, so verified email is not required
+	importer := NewImporter(service.NewPostService(posts, repos.User, SilentNotifier{}, SilentNotifier{}, false))
+
+	logger.Infof("Import posts from %s", opt.Directory)
+	results, err := importer.Import(ctx, opt.Directory, user.ID)
+	if err != nil {
+		logger.Critical(err)
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+			logger.Errorf("%s: %s", result.Filename, result.Err)
+			continue
+		}
+
+		logger.Infof("%s: %s (%s)", result.Filename, result.Post.Title, result.Post.Slug)
+	}
+
+	action := "Imported"
+	if opt.DryRun {
+		action = "Validated"
+	}
+
+	logger.Infof("%s %d of %d files", action, len(results)-failed, len(results))
+	if failed != 0 {
+		logger.Criticalf("Import finished with %d failed files", failed)
+	}
+}
@@ -0,0 +1,84 @@
+package markdown
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const frontMatterDelimiter string = "---"
+
+var (
+	ErrFrontMatterMissing  error = errors.New("Front matter is missing")
+	ErrFrontMatterNotClose error = errors.New("Front matter is not closed")
+)
+
+type (
+	FrontMatter struct {
+		Title string    `yaml:"title"`
+		Slug  string    `yaml:"slug,omitempty"`
+		Date  time.Time `yaml:"date"`
+		Tags  []string  `yaml:"tags,omitempty"`
+		Draft bool      `yaml:"draft"`
+	}
+
+	Document struct {
+		FrontMatter FrontMatter
+		Content     string
+	}
+)
+
+// Parse reads markdown document which starts with yaml front matter
+// enclosed in `---` lines
+func Parse(r io.Reader) (Document, error) {
+	var document Document
+
+	source, err := ioutil.ReadAll(r)
+	if err != nil {
+		return document, err
+	}
+
+	source = bytes.ReplaceAll(source, []byte("\r\n"), []byte("\n"))
+	lines := bytes.SplitAfter(source, []byte("\n"))
+	if len(lines) == 0 || string(bytes.TrimSpace(lines[0])) != frontMatterDelimiter {
+		return document, ErrFrontMatterMissing
+	}
+
+	for i := 1; i < len(lines); i++ {
+		if string(bytes.TrimSpace(lines[i])) != frontMatterDelimiter {
+			continue
+		}
+
+		header := bytes.Join(lines[1:i], nil)
+		if err := yaml.Unmarshal(header, &document.FrontMatter); err != nil {
+			return document, err
+		}
+
+		document.Content = string(bytes.TrimSpace(bytes.Join(lines[i+1:], nil)))
+		return document, nil
+	}
+
+	return document, ErrFrontMatterNotClose
+}
+
+// Render writes document in the same format Parse reads it
+func Render(w io.Writer, document Document) error {
+	header, err := yaml.Marshal(document.FrontMatter)
+	if err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString(frontMatterDelimiter + "\n")
+	buffer.Write(header)
+	buffer.WriteString(frontMatterDelimiter + "\n\n")
+	buffer.WriteString(document.Content)
+	buffer.WriteString("\n")
+
+	_, err = buffer.WriteTo(w)
+	return err
+}
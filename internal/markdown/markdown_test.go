@@ -0,0 +1,102 @@
+package markdown_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/markdown"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type MarkdownSuite struct {
+	suite.Suite
+	*require.Assertions
+}
+
+func TestMarkdownSuite(t *testing.T) {
+	suite.Run(t, new(MarkdownSuite))
+}
+
+func (s *MarkdownSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+}
+
+func (s *MarkdownSuite) TestParseMethod() {
+	methodCases := []struct {
+		Name              string
+		Input             string
+		MethodResultValue markdown.Document
+		MethodResultError error
+	}{
+		{
+			Name:  "Success",
+			Input: "---\ntitle: Hello world post\nslug: hello-world-post\ndate: 2019-03-01\ntags: [go, blog]\ndraft: true\n---\n\n# Hello\n",
+			MethodResultValue: markdown.Document{
+				FrontMatter: markdown.FrontMatter{
+					Title: "Hello world post",
+					Slug:  "hello-world-post",
+					Date:  time.Date(2019, time.March, 1, 0, 0, 0, 0, time.UTC),
+					Tags:  []string{"go", "blog"},
+					Draft: true,
+				},
+				Content: "# Hello",
+			},
+			MethodResultError: nil,
+		},
+		{
+			Name:  "SuccessWindowsLineEndings",
+			Input: "---\r\ntitle: Hello world post\r\n---\r\nContent\r\n",
+			MethodResultValue: markdown.Document{
+				FrontMatter: markdown.FrontMatter{
+					Title: "Hello world post",
+				},
+				Content: "Content",
+			},
+			MethodResultError: nil,
+		},
+		{
+			Name:              "FrontMatterMissing",
+			Input:             "# Hello\n",
+			MethodResultValue: markdown.Document{},
+			MethodResultError: markdown.ErrFrontMatterMissing,
+		},
+		{
+			Name:  "FrontMatterNotClosed",
+			Input: "---\ntitle: Hello world post\n# Hello\n",
+			MethodResultValue: markdown.Document{
+				FrontMatter: markdown.FrontMatter{},
+			},
+			MethodResultError: markdown.ErrFrontMatterNotClose,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			result, err := markdown.Parse(strings.NewReader(currentCase.Input))
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			s.Assertions.Equal(currentCase.MethodResultValue, result)
+		})
+	}
+}
+
+func (s *MarkdownSuite) TestRenderMethod() {
+	document := markdown.Document{
+		FrontMatter: markdown.FrontMatter{
+			Title: "Hello world post",
+			Slug:  "hello-world-post",
+			Date:  time.Date(2019, time.March, 1, 10, 30, 0, 0, time.UTC),
+			Tags:  []string{"go", "blog"},
+		},
+		Content: "# Hello",
+	}
+
+	var builder strings.Builder
+	err := markdown.Render(&builder, document)
+	s.Assertions.NoError(err)
+
+	result, err := markdown.Parse(strings.NewReader(builder.String()))
+	s.Assertions.NoError(err)
+	s.Assertions.Equal(document, result)
+}
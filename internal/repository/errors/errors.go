@@ -7,4 +7,20 @@ import (
 var (
 	ErrUserNotFound error = errors.New("User not found is database")
 	ErrPostNotFound error = errors.New("Post not found in database")
+
+	ErrRefreshTokenNotFound  error = errors.New("Refresh token not found in database")
+	ErrPasswordResetNotFound error = errors.New("Password reset not found in database")
+	ErrSessionNotFound       error = errors.New("Session not found in database")
+
+	ErrPersonalAccessTokenNotFound error = errors.New("Personal access token not found in database")
+	ErrUserIdentityNotFound        error = errors.New("User identity not found in database")
+	ErrTOTPFactorNotFound          error = errors.New("TOTP factor not found in database")
+	ErrNotificationNotFound        error = errors.New("Notification not found in database")
+	ErrWebhookNotFound             error = errors.New("Webhook not found in database")
+	ErrWebhookDeliveryNotFound     error = errors.New("Webhook delivery not found in database")
+
+	ErrUserAlreadyExists error = errors.New("User with same email or username already exists in database")
+
+	ErrUserVersionConflict error = errors.New("User was changed by another request")
+	ErrPostVersionConflict error = errors.New("Post was changed by another request")
 )
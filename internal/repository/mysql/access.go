@@ -0,0 +1,65 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/database"
+	uuid "github.com/satori/go.uuid"
+)
+
+type PersonalAccessTokenRepos struct {
+	database database.DatabasePrivoder
+}
+
+func NewPersonalAccessTokenRepos(database database.DatabasePrivoder) *PersonalAccessTokenRepos {
+	return &PersonalAccessTokenRepos{database: database}
+}
+
+func (r *PersonalAccessTokenRepos) Create(ctx context.Context, token domain.PersonalAccessToken) error {
+	query := fmt.Sprintf("insert into %s (id, user_id, name, prefix, token_hash, scopes, created_at, last_used_at) values (?, ?, ?, ?, ?, ?, ?, ?)", personalAccessTokensTable)
+	return r.database.Exec(ctx, query, token.ID, token.UserID, token.Name, token.Prefix, token.TokenHash, token.Scopes, token.CreatedAt, token.LastUsedAt)
+}
+
+func (r *PersonalAccessTokenRepos) FindWithHash(ctx context.Context, hash string) (domain.PersonalAccessToken, error) {
+	var token domain.PersonalAccessToken
+	query := fmt.Sprintf("select * from %s where token_hash = ?", personalAccessTokensTable)
+	err := r.database.Get(ctx, &token, query, hash)
+	if err == sql.ErrNoRows {
+		return token, errors.ErrPersonalAccessTokenNotFound
+	}
+	return token, err
+}
+
+func (r *PersonalAccessTokenRepos) GetAllWithUserID(ctx context.Context, userID uuid.UUID) ([]domain.PersonalAccessToken, error) {
+	var tokens []domain.PersonalAccessToken
+	query := fmt.Sprintf("select * from %s where user_id = ? order by created_at desc", personalAccessTokensTable)
+	err := r.database.Select(ctx, &tokens, query, userID)
+	if tokens == nil {
+		tokens = []domain.PersonalAccessToken{}
+	}
+	return tokens, err
+}
+
+func (r *PersonalAccessTokenRepos) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	query := fmt.Sprintf("delete from %s where (id = ? and user_id = ?)", personalAccessTokensTable)
+	affected, err := r.database.ExecAffected(ctx, query, id, userID)
+	if err == nil && affected == 0 {
+		return errors.ErrPersonalAccessTokenNotFound
+	}
+	return err
+}
+
+func (r *PersonalAccessTokenRepos) DeleteAllWithUserID(ctx context.Context, userID uuid.UUID) error {
+	query := fmt.Sprintf("delete from %s where user_id = ?", personalAccessTokensTable)
+	return r.database.Exec(ctx, query, userID)
+}
+
+func (r *PersonalAccessTokenRepos) Touch(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	query := fmt.Sprintf("update %s set last_used_at = ? where id = ?", personalAccessTokensTable)
+	return r.database.Exec(ctx, query, usedAt, id)
+}
@@ -0,0 +1,35 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/pkg/database"
+	uuid "github.com/satori/go.uuid"
+)
+
+type AccountDeletionRepos struct {
+	database database.DatabasePrivoder
+}
+
+func NewAccountDeletionRepos(database database.DatabasePrivoder) *AccountDeletionRepos {
+	return &AccountDeletionRepos{database: database}
+}
+
+func (r *AccountDeletionRepos) Delete(ctx context.Context, userID uuid.UUID) error {
+	query := fmt.Sprintf("delete from %s where user_id = ?", accountDeletionsTable)
+	return r.database.Exec(ctx, query, userID)
+}
+
+// GetAllDue returns deletions which grace period is over at given time
+func (r *AccountDeletionRepos) GetAllDue(ctx context.Context, at time.Time, count int) ([]domain.AccountDeletion, error) {
+	var deletions []domain.AccountDeletion
+	query := fmt.Sprintf("select * from %s where purge_at <= ? order by purge_at limit ?", accountDeletionsTable)
+	err := r.database.Select(ctx, &deletions, query, at, count)
+	if deletions == nil {
+		deletions = []domain.AccountDeletion{}
+	}
+	return deletions, err
+}
@@ -0,0 +1,99 @@
+package mysql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/mysql"
+	mock_database "github.com/aintsashqa/go-simple-blog/pkg/database/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type AccountDeletionRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockDatabasePrivoder *mock_database.MockDatabasePrivoder
+
+	CurrentRepository repository.AccountDeletion
+}
+
+func TestAccountDeletionRepositorySuite(t *testing.T) {
+	suite.Run(t, new(AccountDeletionRepositorySuite))
+}
+
+func (s *AccountDeletionRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockDatabasePrivoder = mock_database.NewMockDatabasePrivoder(s.Controller)
+	s.CurrentRepository = mysql.NewAccountDeletionRepos(s.MockDatabasePrivoder)
+}
+
+func (s *AccountDeletionRepositorySuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *AccountDeletionRepositorySuite) TestGetAllDueMethod() {
+	type MockDatabasePrivoderBehavior func(*mock_database.MockDatabasePrivoder, context.Context, time.Time, int, error)
+
+	mockDatabasePrivoderBehavior := func(m *mock_database.MockDatabasePrivoder, inputContext context.Context, inputAt time.Time, inputCount int, returns error) {
+		m.EXPECT().
+			Select(inputContext, gomock.AssignableToTypeOf(&[]domain.AccountDeletion{}), gomock.Any(), inputAt, inputCount).
+			Return(returns).
+			Times(1).
+			Do(func(_ context.Context, deletions *[]domain.AccountDeletion, _ string, _ time.Time, count int) error {
+				for i := 0; i < count; i++ {
+					*deletions = append(*deletions, domain.AccountDeletion{})
+				}
+				return nil
+			})
+	}
+
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                         string
+		InputCount                   int
+		DatabaseResultError          error
+		MethodResultError            error
+		MockDatabasePrivoderBehavior MockDatabasePrivoderBehavior
+	}{
+		{
+			Name:                         "Success",
+			InputCount:                   10,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+		{
+			Name:                         "SuccessEmpty",
+			InputCount:                   0,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+		{
+			Name:                         "DatabaseFailure",
+			InputCount:                   0,
+			DatabaseResultError:          databaseResultError,
+			MethodResultError:            databaseResultError,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			at := time.Now()
+			currentCase.MockDatabasePrivoderBehavior(s.MockDatabasePrivoder, ctx, at, currentCase.InputCount, currentCase.DatabaseResultError)
+			result, err := s.CurrentRepository.GetAllDue(ctx, at, currentCase.InputCount)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			s.Assertions.NotNil(result)
+			s.Assertions.Len(result, currentCase.InputCount)
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/pkg/database"
+)
+
+type AuditLogRepos struct {
+	database database.DatabasePrivoder
+}
+
+func NewAuditLogRepos(database database.DatabasePrivoder) *AuditLogRepos {
+	return &AuditLogRepos{database: database}
+}
+
+func (r *AuditLogRepos) Create(ctx context.Context, log domain.AuditLog) error {
+	query := fmt.Sprintf("insert into %s (id, actor_id, action, target_type, target_id, created_at) values (?, ?, ?, ?, ?, ?)", auditLogsTable)
+	return r.database.Exec(ctx, query, log.ID, log.ActorID, log.Action, log.TargetType, log.TargetID, log.CreatedAt)
+}
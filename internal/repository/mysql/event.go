@@ -0,0 +1,22 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/pkg/database"
+)
+
+type SecurityEventRepos struct {
+	database database.DatabasePrivoder
+}
+
+func NewSecurityEventRepos(database database.DatabasePrivoder) *SecurityEventRepos {
+	return &SecurityEventRepos{database: database}
+}
+
+func (r *SecurityEventRepos) Create(ctx context.Context, event domain.SecurityEvent) error {
+	query := fmt.Sprintf("insert into %s (id, user_id, type, created_at) values (?, ?, ?, ?)", securityEventsTable)
+	return r.database.Exec(ctx, query, event.ID, event.UserID, event.Type, event.CreatedAt)
+}
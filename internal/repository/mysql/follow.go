@@ -0,0 +1,75 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/pkg/database"
+	uuid "github.com/satori/go.uuid"
+)
+
+type FollowRepos struct {
+	database database.DatabasePrivoder
+}
+
+func NewFollowRepos(database database.DatabasePrivoder) *FollowRepos {
+	return &FollowRepos{database: database}
+}
+
+// Create is idempotent, following same user again keeps original follow date
+func (r *FollowRepos) Create(ctx context.Context, follow domain.Follow) error {
+	query := fmt.Sprintf("insert ignore into %s (follower_id, followee_id, created_at) values (?, ?, ?)", followsTable)
+	return r.database.Exec(ctx, query, follow.FollowerID, follow.FolloweeID, follow.CreatedAt)
+}
+
+func (r *FollowRepos) Delete(ctx context.Context, followerID uuid.UUID, followeeID uuid.UUID) error {
+	query := fmt.Sprintf("delete from %s where (follower_id = ? and followee_id = ?)", followsTable)
+	return r.database.Exec(ctx, query, followerID, followeeID)
+}
+
+// GetAllFollowers returns users following user with id, recent followers first
+func (r *FollowRepos) GetAllFollowers(ctx context.Context, id uuid.UUID, offset, count int) ([]domain.User, error) {
+	return r.users(ctx, "follower_id", "followee_id", id, offset, count)
+}
+
+// GetAllFollowing returns users followed by user with id, recent follows first
+func (r *FollowRepos) GetAllFollowing(ctx context.Context, id uuid.UUID, offset, count int) ([]domain.User, error) {
+	return r.users(ctx, "followee_id", "follower_id", id, offset, count)
+}
+
+func (r *FollowRepos) FollowersCount(ctx context.Context, id uuid.UUID) (int, error) {
+	return r.count(ctx, "follower_id", "followee_id", id)
+}
+
+func (r *FollowRepos) FollowingCount(ctx context.Context, id uuid.UUID) (int, error) {
+	return r.count(ctx, "followee_id", "follower_id", id)
+}
+
+// users joins public columns of users referenced by column, users in grace period are hidden
+func (r *FollowRepos) users(ctx context.Context, column, filter string, id uuid.UUID, offset, count int) ([]domain.User, error) {
+	columns := publicColumns()
+	for i, c := range columns {
+		columns[i] = fmt.Sprintf("%s.%s", usersTable, c)
+	}
+
+	var users []domain.User
+	query := fmt.Sprintf("select %s from %s join %s on %s.id = %s.%s where (%s.%s = ? and %s.deleted_at is null) order by %s.created_at desc limit ?, ?",
+		strings.Join(columns, ", "), followsTable, usersTable, usersTable, followsTable, column, followsTable, filter, usersTable, followsTable,
+	)
+	err := r.database.Select(ctx, &users, query, id, offset, count)
+	if users == nil {
+		users = []domain.User{}
+	}
+	return users, err
+}
+
+func (r *FollowRepos) count(ctx context.Context, column, filter string, id uuid.UUID) (int, error) {
+	var count int
+	query := fmt.Sprintf("select count(*) from %s join %s on %s.id = %s.%s where (%s.%s = ? and %s.deleted_at is null)",
+		followsTable, usersTable, usersTable, followsTable, column, followsTable, filter, usersTable,
+	)
+	err := r.database.QueryRow(ctx, &count, query, id)
+	return count, err
+}
@@ -0,0 +1,99 @@
+package mysql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/mysql"
+	mock_database "github.com/aintsashqa/go-simple-blog/pkg/database/mocks"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type FollowRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockDatabasePrivoder *mock_database.MockDatabasePrivoder
+
+	CurrentRepository repository.Follow
+}
+
+func TestFollowRepositorySuite(t *testing.T) {
+	suite.Run(t, new(FollowRepositorySuite))
+}
+
+func (s *FollowRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockDatabasePrivoder = mock_database.NewMockDatabasePrivoder(s.Controller)
+	s.CurrentRepository = mysql.NewFollowRepos(s.MockDatabasePrivoder)
+}
+
+func (s *FollowRepositorySuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *FollowRepositorySuite) TestGetAllFollowersMethod() {
+	type MockDatabasePrivoderBehavior func(*mock_database.MockDatabasePrivoder, context.Context, uuid.UUID, int, error)
+
+	mockDatabasePrivoderBehavior := func(m *mock_database.MockDatabasePrivoder, inputContext context.Context, inputID uuid.UUID, inputCount int, returns error) {
+		m.EXPECT().
+			Select(inputContext, gomock.AssignableToTypeOf(&[]domain.User{}), gomock.Any(), inputID, 0, inputCount).
+			Return(returns).
+			Times(1).
+			Do(func(_ context.Context, users *[]domain.User, _ string, _ uuid.UUID, _ int, count int) error {
+				for i := 0; i < count; i++ {
+					*users = append(*users, domain.User{})
+				}
+				return nil
+			})
+	}
+
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                         string
+		InputCount                   int
+		DatabaseResultError          error
+		MethodResultError            error
+		MockDatabasePrivoderBehavior MockDatabasePrivoderBehavior
+	}{
+		{
+			Name:                         "Success",
+			InputCount:                   10,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+		{
+			Name:                         "SuccessEmpty",
+			InputCount:                   0,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+		{
+			Name:                         "DatabaseFailure",
+			InputCount:                   0,
+			DatabaseResultError:          databaseResultError,
+			MethodResultError:            databaseResultError,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			id := uuid.NewV4()
+			currentCase.MockDatabasePrivoderBehavior(s.MockDatabasePrivoder, ctx, id, currentCase.InputCount, currentCase.DatabaseResultError)
+			result, err := s.CurrentRepository.GetAllFollowers(ctx, id, 0, currentCase.InputCount)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			s.Assertions.NotNil(result)
+			s.Assertions.Len(result, currentCase.InputCount)
+		})
+	}
+}
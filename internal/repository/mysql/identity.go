@@ -0,0 +1,34 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/database"
+)
+
+type UserIdentityRepos struct {
+	database database.DatabasePrivoder
+}
+
+func NewUserIdentityRepos(database database.DatabasePrivoder) *UserIdentityRepos {
+	return &UserIdentityRepos{database: database}
+}
+
+func (r *UserIdentityRepos) Create(ctx context.Context, identity domain.UserIdentity) error {
+	query := fmt.Sprintf("insert into %s (provider, subject, user_id, email, created_at) values (?, ?, ?, ?, ?)", userIdentitiesTable)
+	return r.database.Exec(ctx, query, identity.Provider, identity.Subject, identity.UserID, identity.Email, identity.CreatedAt)
+}
+
+func (r *UserIdentityRepos) Find(ctx context.Context, provider string, subject string) (domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+	query := fmt.Sprintf("select * from %s where (provider = ? and subject = ?)", userIdentitiesTable)
+	err := r.database.Get(ctx, &identity, query, provider, subject)
+	if err == sql.ErrNoRows {
+		return identity, errors.ErrUserIdentityNotFound
+	}
+	return identity, err
+}
@@ -0,0 +1,158 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/database"
+	uuid "github.com/satori/go.uuid"
+)
+
+type TOTPFactorRepos struct {
+	database database.DatabasePrivoder
+}
+
+func NewTOTPFactorRepos(database database.DatabasePrivoder) *TOTPFactorRepos {
+	return &TOTPFactorRepos{database: database}
+}
+
+// Save replaces factor of user, so enrollment could be started again
+func (r *TOTPFactorRepos) Save(ctx context.Context, factor domain.TOTPFactor) error {
+	query := fmt.Sprintf("insert into %s (user_id, secret, last_counter, created_at, confirmed_at) values (?, ?, ?, ?, ?) on duplicate key update secret = values(secret), last_counter = values(last_counter), created_at = values(created_at), confirmed_at = values(confirmed_at)", totpFactorsTable)
+	return r.database.Exec(ctx, query, factor.UserID, factor.Secret, factor.LastCounter, factor.CreatedAt, factor.ConfirmedAt)
+}
+
+func (r *TOTPFactorRepos) Find(ctx context.Context, userID uuid.UUID) (domain.TOTPFactor, error) {
+	var factor domain.TOTPFactor
+	query := fmt.Sprintf("select * from %s where user_id = ?", totpFactorsTable)
+	err := r.database.Get(ctx, &factor, query, userID)
+	if err == sql.ErrNoRows {
+		return factor, errors.ErrTOTPFactorNotFound
+	}
+	return factor, err
+}
+
+func (r *TOTPFactorRepos) Confirm(ctx context.Context, factor domain.TOTPFactor) error {
+	query := fmt.Sprintf("update %s set last_counter = ?, confirmed_at = ? where (user_id = ? and confirmed_at is null)", totpFactorsTable)
+	affected, err := r.database.ExecAffected(ctx, query, factor.LastCounter, factor.ConfirmedAt, factor.UserID)
+	if err == nil && affected == 0 {
+		return errors.ErrTOTPFactorNotFound
+	}
+	return err
+}
+
+// Use returns false when code of same or later period was already accepted
+func (r *TOTPFactorRepos) Use(ctx context.Context, userID uuid.UUID, counter int64) (bool, error) {
+	query := fmt.Sprintf("update %s set last_counter = ? where (user_id = ? and last_counter < ?)", totpFactorsTable)
+	affected, err := r.database.ExecAffected(ctx, query, counter, userID, counter)
+	return affected != 0, err
+}
+
+func (r *TOTPFactorRepos) Delete(ctx context.Context, userID uuid.UUID) error {
+	query := fmt.Sprintf("delete from %s where user_id = ?", totpFactorsTable)
+	return r.database.Exec(ctx, query, userID)
+}
+
+type RecoveryCodeRepos struct {
+	database database.DatabasePrivoder
+}
+
+func NewRecoveryCodeRepos(database database.DatabasePrivoder) *RecoveryCodeRepos {
+	return &RecoveryCodeRepos{database: database}
+}
+
+// ReplaceAll removes previous codes of user, so only last generated codes are valid
+func (r *RecoveryCodeRepos) ReplaceAll(ctx context.Context, userID uuid.UUID, codes []domain.RecoveryCode) error {
+	tx, err := r.database.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Exec(ctx, fmt.Sprintf("delete from %s where user_id = ?", recoveryCodesTable), userID); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	query := fmt.Sprintf("insert into %s (id, user_id, code_hash, created_at, used_at) values (?, ?, ?, ?, ?)", recoveryCodesTable)
+	for _, code := range codes {
+		if err := tx.Exec(ctx, query, code.ID, code.UserID, code.CodeHash, code.CreatedAt, code.UsedAt); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return err
+			}
+
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *RecoveryCodeRepos) GetAllUnusedWithUserID(ctx context.Context, userID uuid.UUID) ([]domain.RecoveryCode, error) {
+	var codes []domain.RecoveryCode
+	query := fmt.Sprintf("select * from %s where (user_id = ? and used_at is null)", recoveryCodesTable)
+	err := r.database.Select(ctx, &codes, query, userID)
+	return codes, err
+}
+
+// UseWithHash returns false when user has no unused code with hash, code
+// used by concurrent request is not found as well
+func (r *RecoveryCodeRepos) UseWithHash(ctx context.Context, userID uuid.UUID, hash string) (bool, error) {
+	query := fmt.Sprintf("update %s set used_at = ? where (user_id = ? and code_hash = ? and used_at is null)", recoveryCodesTable)
+	affected, err := r.database.ExecAffected(ctx, query, time.Now(), userID, hash)
+	return affected != 0, err
+}
+
+func (r *RecoveryCodeRepos) DeleteAll(ctx context.Context, userID uuid.UUID) error {
+	query := fmt.Sprintf("delete from %s where user_id = ?", recoveryCodesTable)
+	return r.database.Exec(ctx, query, userID)
+}
+
+type MFAPolicyRepos struct {
+	database database.DatabasePrivoder
+}
+
+func NewMFAPolicyRepos(database database.DatabasePrivoder) *MFAPolicyRepos {
+	return &MFAPolicyRepos{database: database}
+}
+
+func (r *MFAPolicyRepos) GetRequiredRoles(ctx context.Context) (domain.Roles, error) {
+	roles := domain.Roles{}
+	query := fmt.Sprintf("select role from %s order by role", mfaRequiredRolesTable)
+	err := r.database.Select(ctx, &roles, query)
+	return roles, err
+}
+
+func (r *MFAPolicyRepos) SetRequiredRoles(ctx context.Context, roles domain.Roles) error {
+	tx, err := r.database.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Exec(ctx, fmt.Sprintf("delete from %s", mfaRequiredRolesTable)); err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	query := fmt.Sprintf("insert into %s (role, created_at) values (?, ?)", mfaRequiredRolesTable)
+	for _, role := range roles {
+		if err := tx.Exec(ctx, query, role, time.Now()); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return err
+			}
+
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
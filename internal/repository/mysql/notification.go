@@ -0,0 +1,87 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/database"
+	uuid "github.com/satori/go.uuid"
+)
+
+type NotificationRepos struct {
+	database database.DatabasePrivoder
+}
+
+func NewNotificationRepos(database database.DatabasePrivoder) *NotificationRepos {
+	return &NotificationRepos{database: database}
+}
+
+func (r *NotificationRepos) Create(ctx context.Context, notification domain.Notification) error {
+	query := fmt.Sprintf("insert into %s (id, user_id, type, actor_id, post_id, event, created_at, read_at) values (?, ?, ?, ?, ?, ?, ?, ?)", notificationsTable)
+	return r.database.Exec(ctx, query,
+		notification.ID, notification.UserID, notification.Type, notification.ActorID, notification.PostID,
+		notification.Event, notification.CreatedAt, notification.ReadAt,
+	)
+}
+
+// CreateForFollowers copies notification to every follower of its actor in
+// single query, id and user of notification are ignored
+func (r *NotificationRepos) CreateForFollowers(ctx context.Context, notification domain.Notification) error {
+	query := fmt.Sprintf("insert into %s (id, user_id, type, actor_id, post_id, event, created_at, read_at) select uuid(), %s.follower_id, ?, ?, ?, ?, ?, ? from %s join %s on %s.id = %s.follower_id where (%s.followee_id = ? and %s.deleted_at is null)",
+		notificationsTable, followsTable, followsTable, usersTable, usersTable, followsTable, followsTable, usersTable,
+	)
+	return r.database.Exec(ctx, query,
+		notification.Type, notification.ActorID, notification.PostID, notification.Event, notification.CreatedAt, notification.ReadAt,
+		notification.ActorID,
+	)
+}
+
+func (r *NotificationRepos) FindWithUserID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (domain.Notification, error) {
+	var notification domain.Notification
+	query := fmt.Sprintf("select * from %s where (id = ? and user_id = ?)", notificationsTable)
+	err := r.database.Get(ctx, &notification, query, id, userID)
+	if err == sql.ErrNoRows {
+		return notification, errors.ErrNotificationNotFound
+	}
+	return notification, err
+}
+
+// GetAllWithUserID returns notifications of user, recent notifications first
+func (r *NotificationRepos) GetAllWithUserID(ctx context.Context, userID uuid.UUID, offset, count int) ([]domain.Notification, error) {
+	var notifications []domain.Notification
+	query := fmt.Sprintf("select * from %s where user_id = ? order by created_at desc limit ?, ?", notificationsTable)
+	err := r.database.Select(ctx, &notifications, query, userID, offset, count)
+	if notifications == nil {
+		notifications = []domain.Notification{}
+	}
+	return notifications, err
+}
+
+func (r *NotificationRepos) CountWithUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	query := fmt.Sprintf("select count(*) from %s where user_id = ?", notificationsTable)
+	err := r.database.QueryRow(ctx, &count, query, userID)
+	return count, err
+}
+
+func (r *NotificationRepos) UnreadCountWithUserID(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	query := fmt.Sprintf("select count(*) from %s where (user_id = ? and read_at is null)", notificationsTable)
+	err := r.database.QueryRow(ctx, &count, query, userID)
+	return count, err
+}
+
+// MarkRead keeps time of first read, notification already read is not changed
+func (r *NotificationRepos) MarkRead(ctx context.Context, id uuid.UUID, readAt time.Time) error {
+	query := fmt.Sprintf("update %s set read_at = ? where (id = ? and read_at is null)", notificationsTable)
+	return r.database.Exec(ctx, query, readAt, id)
+}
+
+func (r *NotificationRepos) MarkAllRead(ctx context.Context, userID uuid.UUID, readAt time.Time) error {
+	query := fmt.Sprintf("update %s set read_at = ? where (user_id = ? and read_at is null)", notificationsTable)
+	return r.database.Exec(ctx, query, readAt, userID)
+}
@@ -0,0 +1,111 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	repoerror "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/mysql"
+	mock_database "github.com/aintsashqa/go-simple-blog/pkg/database/mocks"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotificationRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockDatabasePrivoder *mock_database.MockDatabasePrivoder
+
+	CurrentRepository repository.Notification
+}
+
+func TestNotificationRepositorySuite(t *testing.T) {
+	suite.Run(t, new(NotificationRepositorySuite))
+}
+
+func (s *NotificationRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockDatabasePrivoder = mock_database.NewMockDatabasePrivoder(s.Controller)
+	s.CurrentRepository = mysql.NewNotificationRepos(s.MockDatabasePrivoder)
+}
+
+func (s *NotificationRepositorySuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *NotificationRepositorySuite) TestFindWithUserIDMethod() {
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                string
+		DatabaseResultError error
+		MethodResultError   error
+	}{
+		{
+			Name: "Success",
+		},
+		{
+			Name:                "NotFound",
+			DatabaseResultError: sql.ErrNoRows,
+			MethodResultError:   repoerror.ErrNotificationNotFound,
+		},
+		{
+			Name:                "DatabaseFailure",
+			DatabaseResultError: databaseResultError,
+			MethodResultError:   databaseResultError,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			s.MockDatabasePrivoder.EXPECT().
+				Get(ctx, gomock.AssignableToTypeOf(&domain.Notification{}), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(currentCase.DatabaseResultError).
+				Times(1)
+			_, err := s.CurrentRepository.FindWithUserID(ctx, uuid.NewV4(), uuid.NewV4())
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
+
+func (s *NotificationRepositorySuite) TestGetAllWithUserIDMethod() {
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                string
+		DatabaseResultError error
+	}{
+		{
+			Name: "SuccessEmpty",
+		},
+		{
+			Name:                "DatabaseFailure",
+			DatabaseResultError: databaseResultError,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			id := uuid.NewV4()
+			s.MockDatabasePrivoder.EXPECT().
+				Select(ctx, gomock.AssignableToTypeOf(&[]domain.Notification{}), gomock.Any(), id, 0, 10).
+				Return(currentCase.DatabaseResultError).
+				Times(1)
+			result, err := s.CurrentRepository.GetAllWithUserID(ctx, id, 0, 10)
+			s.Assertions.Equal(currentCase.DatabaseResultError, err)
+			s.Assertions.NotNil(result)
+		})
+	}
+}
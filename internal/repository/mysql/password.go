@@ -0,0 +1,51 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/database"
+	uuid "github.com/satori/go.uuid"
+)
+
+type PasswordResetRepos struct {
+	database database.DatabasePrivoder
+}
+
+func NewPasswordResetRepos(database database.DatabasePrivoder) *PasswordResetRepos {
+	return &PasswordResetRepos{database: database}
+}
+
+func (r *PasswordResetRepos) Create(ctx context.Context, reset domain.PasswordReset) error {
+	query := fmt.Sprintf("insert into %s (id, user_id, token_hash, created_at, expires_at, used_at) values (?, ?, ?, ?, ?, ?)", passwordResetsTable)
+	return r.database.Exec(ctx, query, reset.ID, reset.UserID, reset.TokenHash, reset.CreatedAt, reset.ExpiresAt, reset.UsedAt)
+}
+
+func (r *PasswordResetRepos) FindWithHash(ctx context.Context, hash string) (domain.PasswordReset, error) {
+	var reset domain.PasswordReset
+	query := fmt.Sprintf("select * from %s where token_hash = ?", passwordResetsTable)
+	err := r.database.Get(ctx, &reset, query, hash)
+	if err == sql.ErrNoRows {
+		return reset, errors.ErrPasswordResetNotFound
+	}
+	return reset, err
+}
+
+// UseWithHash claims unused reset, it reports false when reset was already
+// used, so concurrent requests could not use the same token
+func (r *PasswordResetRepos) UseWithHash(ctx context.Context, hash string) (bool, error) {
+	query := fmt.Sprintf("update %s set used_at = ? where (token_hash = ? and used_at is null)", passwordResetsTable)
+	affected, err := r.database.ExecAffected(ctx, query, time.Now(), hash)
+	return affected != 0, err
+}
+
+// UseAll marks every unused reset of user as used, so none of tokens sent
+// before password was changed could be used again
+func (r *PasswordResetRepos) UseAll(ctx context.Context, userID uuid.UUID) error {
+	query := fmt.Sprintf("update %s set used_at = ? where (user_id = ? and used_at is null)", passwordResetsTable)
+	return r.database.Exec(ctx, query, time.Now(), userID)
+}
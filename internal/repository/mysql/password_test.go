@@ -0,0 +1,164 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	repoerror "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/mysql"
+	mock_database "github.com/aintsashqa/go-simple-blog/pkg/database/mocks"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PasswordResetRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockDatabasePrivoder *mock_database.MockDatabasePrivoder
+
+	CurrentRepository repository.PasswordReset
+}
+
+func TestPasswordResetRepositorySuite(t *testing.T) {
+	suite.Run(t, new(PasswordResetRepositorySuite))
+}
+
+func (s *PasswordResetRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockDatabasePrivoder = mock_database.NewMockDatabasePrivoder(s.Controller)
+	s.CurrentRepository = mysql.NewPasswordResetRepos(s.MockDatabasePrivoder)
+}
+
+func (s *PasswordResetRepositorySuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *PasswordResetRepositorySuite) TestFindWithHashMethod() {
+	type MockDatabasePrivoderBehavior func(*mock_database.MockDatabasePrivoder, context.Context, error)
+
+	mockDatabasePrivoderBehavior := func(m *mock_database.MockDatabasePrivoder, input context.Context, returns error) {
+		m.EXPECT().
+			Get(input, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(returns).
+			Times(1)
+	}
+
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                         string
+		DatabaseResultError          error
+		MethodResultError            error
+		MockDatabasePrivoderBehavior MockDatabasePrivoderBehavior
+	}{
+		{
+			Name:                         "Success",
+			DatabaseResultError:          nil,
+			MethodResultError:            nil,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+		{
+			Name:                         "NotFound",
+			DatabaseResultError:          sql.ErrNoRows,
+			MethodResultError:            repoerror.ErrPasswordResetNotFound,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+		{
+			Name:                         "DatabaseFailure",
+			DatabaseResultError:          databaseResultError,
+			MethodResultError:            databaseResultError,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			currentCase.MockDatabasePrivoderBehavior(s.MockDatabasePrivoder, ctx, currentCase.DatabaseResultError)
+			_, err := s.CurrentRepository.FindWithHash(ctx, "token-hash")
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
+
+func (s *PasswordResetRepositorySuite) TestUseWithHashMethod() {
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                string
+		DatabaseAffected    int64
+		DatabaseResultError error
+		MethodResultValue   bool
+		MethodResultError   error
+	}{
+		{
+			Name:              "Success",
+			DatabaseAffected:  1,
+			MethodResultValue: true,
+		},
+		{
+			Name:              "AlreadyUsed",
+			DatabaseAffected:  0,
+			MethodResultValue: false,
+		},
+		{
+			Name:                "DatabaseFailure",
+			DatabaseResultError: databaseResultError,
+			MethodResultError:   databaseResultError,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			s.MockDatabasePrivoder.EXPECT().
+				ExecAffected(ctx, gomock.Any(), gomock.Any(), "token-hash").
+				Return(currentCase.DatabaseAffected, currentCase.DatabaseResultError).
+				Times(1)
+			used, err := s.CurrentRepository.UseWithHash(ctx, "token-hash")
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			s.Assertions.Equal(currentCase.MethodResultValue, used)
+		})
+	}
+}
+
+func (s *PasswordResetRepositorySuite) TestUseAllMethod() {
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                string
+		DatabaseResultError error
+		MethodResultError   error
+	}{
+		{
+			Name: "Success",
+		},
+		{
+			Name:                "DatabaseFailure",
+			DatabaseResultError: databaseResultError,
+			MethodResultError:   databaseResultError,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			userID := uuid.NewV4()
+			s.MockDatabasePrivoder.EXPECT().
+				Exec(ctx, gomock.Any(), gomock.Any(), userID).
+				Return(currentCase.DatabaseResultError).
+				Times(1)
+			err := s.CurrentRepository.UseAll(ctx, userID)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
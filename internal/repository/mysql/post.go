@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/aintsashqa/go-simple-blog/internal/domain"
 	"github.com/aintsashqa/go-simple-blog/internal/repository/errors"
@@ -11,6 +12,9 @@ import (
 	uuid "github.com/satori/go.uuid"
 )
 
+// activeAuthorCondition hides posts of users in grace period after account deletion
+var activeAuthorCondition string = fmt.Sprintf("exists (select 1 from %s where %s.id = %s.user_id and %s.deleted_at is null)", usersTable, usersTable, postsTable, usersTable)
+
 type PostRepos struct {
 	database database.DatabasePrivoder
 }
@@ -21,7 +25,7 @@ func NewPostRepos(database database.DatabasePrivoder) *PostRepos {
 
 func (r *PostRepos) Find(ctx context.Context, id uuid.UUID) (domain.Post, error) {
 	var post domain.Post
-	query := fmt.Sprintf("select * from %s where (id = ? and deleted_at is null)", postsTable)
+	query := fmt.Sprintf("select * from %s where (id = ? and deleted_at is null and %s)", postsTable, activeAuthorCondition)
 	err := r.database.Get(ctx, &post, query, id)
 	if err == sql.ErrNoRows {
 		return post, errors.ErrPostNotFound
@@ -29,6 +33,20 @@ func (r *PostRepos) Find(ctx context.Context, id uuid.UUID) (domain.Post, error)
 	return post, err
 }
 
+// FindValidator selects only columns required by conditional requests
+func (r *PostRepos) FindValidator(ctx context.Context, id uuid.UUID) (domain.Validator, error) {
+	var model domain.Model
+	query := fmt.Sprintf("select id, updated_at, version from %s where (id = ? and deleted_at is null and %s)", postsTable, activeAuthorCondition)
+	err := r.database.Get(ctx, &model, query, id)
+	if err == sql.ErrNoRows {
+		return domain.Validator{}, errors.ErrPostNotFound
+	}
+	if err != nil {
+		return domain.Validator{}, err
+	}
+	return model.Validator(), nil
+}
+
 func (r *PostRepos) FindWithPrimaryAndUserID(ctx context.Context, postID uuid.UUID, userID uuid.UUID) (domain.Post, error) {
 	var post domain.Post
 	query := fmt.Sprintf("select * from %s where (id = ? and user_id = ? and deleted_at is null)", postsTable)
@@ -39,9 +57,28 @@ func (r *PostRepos) FindWithPrimaryAndUserID(ctx context.Context, postID uuid.UU
 	return post, err
 }
 
+// GetAllWithPrimariesAndUserID returns user posts with ids including deleted ones
+func (r *PostRepos) GetAllWithPrimariesAndUserID(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) ([]domain.Post, error) {
+	posts := []domain.Post{}
+	if len(ids) == 0 {
+		return posts, nil
+	}
+
+	args := []interface{}{userID}
+	placeholders := make([]string, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+		placeholders = append(placeholders, "?")
+	}
+
+	query := fmt.Sprintf("select * from %s where (user_id = ? and id in (%s))", postsTable, strings.Join(placeholders, ", "))
+	err := r.database.Select(ctx, &posts, query, args...)
+	return posts, err
+}
+
 func (r *PostRepos) GetAllPublished(ctx context.Context, offset, count int) ([]domain.Post, error) {
 	var posts []domain.Post
-	query := fmt.Sprintf("select * from %s where (published_at is not null and deleted_at is null) limit ?, ?", postsTable)
+	query := fmt.Sprintf("select * from %s where (published_at is not null and deleted_at is null and %s) limit ?, ?", postsTable, activeAuthorCondition)
 	err := r.database.Select(ctx, &posts, query, offset, count)
 	if posts == nil {
 		posts = []domain.Post{}
@@ -51,7 +88,7 @@ func (r *PostRepos) GetAllPublished(ctx context.Context, offset, count int) ([]d
 
 func (r *PostRepos) GetAllPublishedWithUserID(ctx context.Context, id uuid.UUID, offset, count int) ([]domain.Post, error) {
 	var posts []domain.Post
-	query := fmt.Sprintf("select * from %s where (user_id = ? and published_at is not null and deleted_at is null) limit ?, ?", postsTable)
+	query := fmt.Sprintf("select * from %s where (user_id = ? and published_at is not null and deleted_at is null and %s) limit ?, ?", postsTable, activeAuthorCondition)
 	err := r.database.Select(ctx, &posts, query, id, offset, count)
 	if posts == nil {
 		posts = []domain.Post{}
@@ -59,9 +96,11 @@ func (r *PostRepos) GetAllPublishedWithUserID(ctx context.Context, id uuid.UUID,
 	return posts, err
 }
 
+// GetAllWithUserID returns posts of user, recent posts first. Order is
+// stable, so pages do not skip or repeat posts
 func (r *PostRepos) GetAllWithUserID(ctx context.Context, id uuid.UUID, offset int, count int) ([]domain.Post, error) {
 	var posts []domain.Post
-	query := fmt.Sprintf("select * from %s where user_id = ? limit ?, ?", postsTable)
+	query := fmt.Sprintf("select * from %s where user_id = ? order by created_at desc, id desc limit ?, ?", postsTable)
 	err := r.database.Select(ctx, &posts, query, id, offset, count)
 	if posts == nil {
 		posts = []domain.Post{}
@@ -69,16 +108,70 @@ func (r *PostRepos) GetAllWithUserID(ctx context.Context, id uuid.UUID, offset i
 	return posts, err
 }
 
+// GetAllFeed returns published posts of users followed by user with id,
+// posts are ordered by publication date, so cursor could be used instead of offset
+func (r *PostRepos) GetAllFeed(ctx context.Context, id uuid.UUID, cursor domain.FeedCursor, count int) ([]domain.Post, error) {
+	conditions := []string{
+		fmt.Sprintf("user_id in (select followee_id from %s where follower_id = ?)", followsTable),
+		"published_at is not null",
+		"deleted_at is null",
+		activeAuthorCondition,
+	}
+	args := []interface{}{id}
+
+	if !cursor.IsZero() {
+		conditions = append(conditions, "(published_at < ? or (published_at = ? and id < ?))")
+		args = append(args, cursor.PublishedAt, cursor.PublishedAt, cursor.PostID)
+	}
+	args = append(args, count)
+
+	var posts []domain.Post
+	query := fmt.Sprintf("select * from %s where (%s) order by published_at desc, id desc limit ?", postsTable, strings.Join(conditions, " and "))
+	err := r.database.Select(ctx, &posts, query, args...)
+	if posts == nil {
+		posts = []domain.Post{}
+	}
+	return posts, err
+}
+
+// FindWithDeleted returns post regardless of its state and state of author
+func (r *PostRepos) FindWithDeleted(ctx context.Context, id uuid.UUID) (domain.Post, error) {
+	var post domain.Post
+	query := fmt.Sprintf("select * from %s where id = ?", postsTable)
+	err := r.database.Get(ctx, &post, query, id)
+	if err == sql.ErrNoRows {
+		return post, errors.ErrPostNotFound
+	}
+	return post, err
+}
+
+func (r *PostRepos) GetAllDeleted(ctx context.Context, offset, count int) ([]domain.Post, error) {
+	var posts []domain.Post
+	query := fmt.Sprintf("select * from %s where deleted_at is not null order by deleted_at desc limit ?, ?", postsTable)
+	err := r.database.Select(ctx, &posts, query, offset, count)
+	if posts == nil {
+		posts = []domain.Post{}
+	}
+	return posts, err
+}
+
+func (r *PostRepos) AllDeletedCount(ctx context.Context) (int, error) {
+	var count int
+	query := fmt.Sprintf("select count(*) from %s where deleted_at is not null", postsTable)
+	err := r.database.QueryRow(ctx, &count, query)
+	return count, err
+}
+
 func (r *PostRepos) AllPublishedCount(ctx context.Context) (int, error) {
 	var count int
-	query := fmt.Sprintf("select count(*) from %s where (published_at is not null and deleted_at is null)", postsTable)
+	query := fmt.Sprintf("select count(*) from %s where (published_at is not null and deleted_at is null and %s)", postsTable, activeAuthorCondition)
 	err := r.database.QueryRow(ctx, &count, query)
 	return count, err
 }
 
 func (r *PostRepos) AllPublishedCountWithUserID(ctx context.Context, id uuid.UUID) (int, error) {
 	var count int
-	query := fmt.Sprintf("select count(*) from %s where (user_id = ? and published_at is not null and deleted_at is null)", postsTable)
+	query := fmt.Sprintf("select count(*) from %s where (user_id = ? and published_at is not null and deleted_at is null and %s)", postsTable, activeAuthorCondition)
 	err := r.database.QueryRow(ctx, &count, query, id)
 	return count, err
 }
@@ -90,22 +183,36 @@ func (r *PostRepos) TotalCountWithUserID(ctx context.Context, id uuid.UUID) (int
 	return count, err
 }
 
+// GetAllIDsWithUserID returns ids of all user posts including deleted ones
+func (r *PostRepos) GetAllIDsWithUserID(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	query := fmt.Sprintf("select id from %s where user_id = ?", postsTable)
+	err := r.database.Select(ctx, &ids, query, id)
+	return ids, err
+}
+
 func (r *PostRepos) Create(ctx context.Context, post domain.Post) error {
-	query := fmt.Sprintf("insert into %s (id, title, slug, content, user_id, created_at, updated_at, published_at, deleted_at) values (?, ?, ?, ?, ?, ?, ?, ?, ?)", postsTable)
-	return r.database.Exec(ctx, query, post.ID, post.Title, post.Slug, post.Content, post.UserID, post.CreatedAt, post.UpdatedAt, post.PublishedAt, post.DeletedAt)
+	query := fmt.Sprintf("insert into %s (id, title, slug, content, tags, user_id, created_at, updated_at, published_at, deleted_at) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", postsTable)
+	return r.database.Exec(ctx, query, post.ID, post.Title, post.Slug, post.Content, post.Tags, post.UserID, post.CreatedAt, post.UpdatedAt, post.PublishedAt, post.DeletedAt)
 }
 
 func (r *PostRepos) Update(ctx context.Context, post domain.Post) error {
-	query := fmt.Sprintf("update %s set title = ?, slug = ?, content = ?, updated_at = ?, published_at = ? where (id = ? and deleted_at is null)", postsTable)
-	err := r.database.Exec(ctx, query, post.Title, post.Slug, post.Content, post.UpdatedAt, post.PublishedAt, post.ID)
+	query := fmt.Sprintf("update %s set title = ?, slug = ?, content = ?, tags = ?, updated_at = ?, published_at = ?, version = ? where (id = ? and version = ? and deleted_at is null)", postsTable)
+	affected, err := r.database.ExecAffected(ctx, query, post.Title, post.Slug, post.Content, post.Tags, post.UpdatedAt, post.PublishedAt, post.Version, post.ID, post.Version-1)
 	if err == sql.ErrNoRows {
 		return errors.ErrPostNotFound
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.ErrPostVersionConflict
+	}
+	return nil
 }
 
 func (r *PostRepos) Publish(ctx context.Context, post domain.Post) error {
-	query := fmt.Sprintf("update %s set published_at = ?, updated_at = ? where (id = ? and deleted_at is null)", postsTable)
+	query := fmt.Sprintf("update %s set published_at = ?, updated_at = ?, version = version + 1 where (id = ? and deleted_at is null)", postsTable)
 	err := r.database.Exec(ctx, query, post.PublishedAt, post.UpdatedAt, post.ID)
 	if err == sql.ErrNoRows {
 		return errors.ErrPostNotFound
@@ -113,11 +220,56 @@ func (r *PostRepos) Publish(ctx context.Context, post domain.Post) error {
 	return err
 }
 
+// Unpublish returns false when post was not published or was deleted,
+// only published_at is changed, so concurrent edits of post are kept
+func (r *PostRepos) Unpublish(ctx context.Context, post domain.Post) (bool, error) {
+	query := fmt.Sprintf("update %s set published_at = null, updated_at = ?, version = version + 1 where (id = ? and published_at is not null and deleted_at is null)", postsTable)
+	affected, err := r.database.ExecAffected(ctx, query, post.UpdatedAt, post.ID)
+	return affected != 0, err
+}
+
+// SoftDelete returns not found when post was already deleted
 func (r *PostRepos) SoftDelete(ctx context.Context, post domain.Post) error {
-	query := fmt.Sprintf("update %s set updated_at = ?, deleted_at = ? where (id = ? and deleted_at is null)", postsTable)
-	err := r.database.Exec(ctx, query, post.UpdatedAt, post.DeletedAt, post.ID)
-	if err == sql.ErrNoRows {
+	query := fmt.Sprintf("update %s set updated_at = ?, deleted_at = ?, version = version + 1 where (id = ? and deleted_at is null)", postsTable)
+	affected, err := r.database.ExecAffected(ctx, query, post.UpdatedAt, post.DeletedAt, post.ID)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
 		return errors.ErrPostNotFound
 	}
-	return err
+	return nil
+}
+
+// TransferAll changes author of all posts including deleted ones
+func (r *PostRepos) TransferAll(ctx context.Context, fromUserID uuid.UUID, toUserID uuid.UUID) error {
+	query := fmt.Sprintf("update %s set user_id = ?, version = version + 1 where user_id = ?", postsTable)
+	return r.database.Exec(ctx, query, toUserID, fromUserID)
+}
+
+// SaveMany updates all posts in single transaction, posts are checked
+// against their previous version like in Update, so nothing is saved when
+// any of posts was changed by another request
+func (r *PostRepos) SaveMany(ctx context.Context, posts []domain.Post) error {
+	tx, err := r.database.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("update %s set title = ?, slug = ?, content = ?, tags = ?, updated_at = ?, published_at = ?, deleted_at = ?, version = ? where (id = ? and version = ?)", postsTable)
+	for _, post := range posts {
+		affected, err := tx.ExecAffected(ctx, query, post.Title, post.Slug, post.Content, post.Tags, post.UpdatedAt, post.PublishedAt, post.DeletedAt, post.Version, post.ID, post.Version-1)
+		if err == nil && affected == 0 {
+			err = errors.ErrPostVersionConflict
+		}
+		if err != nil {
+			if err := tx.Rollback(); err != nil {
+				return err
+			}
+
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
@@ -225,12 +225,12 @@ func (s *PostRepositorySuite) TestCreateMethod() {
 }
 
 func (s *PostRepositorySuite) TestUpdateMethod() {
-	type MockDatabasePrivoderBehavior func(*mock_database.MockDatabasePrivoder, context.Context, error)
+	type MockDatabasePrivoderBehavior func(*mock_database.MockDatabasePrivoder, context.Context, int64, error)
 
-	mockDatabasePrivoderBehavior := func(m *mock_database.MockDatabasePrivoder, input context.Context, returns error) {
+	mockDatabasePrivoderBehavior := func(m *mock_database.MockDatabasePrivoder, input context.Context, affected int64, returns error) {
 		m.EXPECT().
-			Exec(input, gomock.Any(), gomock.Any()).
-			Return(returns).
+			ExecAffected(input, gomock.Any(), gomock.Any()).
+			Return(affected, returns).
 			Times(1)
 	}
 
@@ -239,6 +239,7 @@ func (s *PostRepositorySuite) TestUpdateMethod() {
 	methodCases := []struct {
 		Name                         string
 		InputPost                    domain.Post
+		DatabaseResultAffected       int64
 		DatabaseResultError          error
 		MethodResultError            error
 		MockDatabasePrivoderBehavior MockDatabasePrivoderBehavior
@@ -246,6 +247,7 @@ func (s *PostRepositorySuite) TestUpdateMethod() {
 		{
 			Name:                         "Success",
 			InputPost:                    domain.Post{},
+			DatabaseResultAffected:       1,
 			DatabaseResultError:          nil,
 			MethodResultError:            nil,
 			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
@@ -253,13 +255,23 @@ func (s *PostRepositorySuite) TestUpdateMethod() {
 		{
 			Name:                         "NotFound",
 			InputPost:                    domain.Post{},
+			DatabaseResultAffected:       0,
 			DatabaseResultError:          sql.ErrNoRows,
 			MethodResultError:            repoerror.ErrPostNotFound,
 			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
 		},
+		{
+			Name:                         "VersionConflict",
+			InputPost:                    domain.Post{},
+			DatabaseResultAffected:       0,
+			DatabaseResultError:          nil,
+			MethodResultError:            repoerror.ErrPostVersionConflict,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
 		{
 			Name:                         "DatabaseFailure",
 			InputPost:                    domain.Post{},
+			DatabaseResultAffected:       0,
 			DatabaseResultError:          databaseResultError,
 			MethodResultError:            databaseResultError,
 			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
@@ -269,7 +281,7 @@ func (s *PostRepositorySuite) TestUpdateMethod() {
 	for _, currentCase := range methodCases {
 		s.Suite.Run(currentCase.Name, func() {
 			ctx := context.Background()
-			currentCase.MockDatabasePrivoderBehavior(s.MockDatabasePrivoder, ctx, currentCase.DatabaseResultError)
+			currentCase.MockDatabasePrivoderBehavior(s.MockDatabasePrivoder, ctx, currentCase.DatabaseResultAffected, currentCase.DatabaseResultError)
 			err := s.CurrentRepository.Update(ctx, currentCase.InputPost)
 			s.Assertions.Equal(currentCase.MethodResultError, err)
 		})
@@ -327,3 +339,124 @@ func (s *PostRepositorySuite) TestPublishMethod() {
 		})
 	}
 }
+
+func (s *PostRepositorySuite) TestSaveManyMethod() {
+	type MockDatabasePrivoderBehavior func(*mock_database.MockDatabasePrivoder, *mock_database.MockDatabaseTx, context.Context, int64, error)
+
+	mockDatabasePrivoderBehavior := func(m *mock_database.MockDatabasePrivoder, tx *mock_database.MockDatabaseTx, input context.Context, affected int64, returns error) {
+		m.EXPECT().
+			BeginTx(input).
+			Return(tx, nil).
+			Times(1)
+
+		tx.EXPECT().
+			ExecAffected(input, gomock.Any(), gomock.Any()).
+			Return(affected, returns).
+			Times(1)
+
+		if returns != nil || affected == 0 {
+			tx.EXPECT().Rollback().Return(nil).Times(1)
+		} else {
+			tx.EXPECT().ExecAffected(input, gomock.Any(), gomock.Any()).Return(int64(1), nil).Times(1)
+			tx.EXPECT().Commit().Return(nil).Times(1)
+		}
+	}
+
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                         string
+		InputPosts                   []domain.Post
+		DatabaseAffected             int64
+		DatabaseResultError          error
+		MethodResultError            error
+		MockDatabasePrivoderBehavior MockDatabasePrivoderBehavior
+	}{
+		{
+			Name:                         "Success",
+			InputPosts:                   []domain.Post{{}, {}},
+			DatabaseAffected:             1,
+			DatabaseResultError:          nil,
+			MethodResultError:            nil,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+		{
+			Name:                         "VersionConflict",
+			InputPosts:                   []domain.Post{{}, {}},
+			DatabaseAffected:             0,
+			DatabaseResultError:          nil,
+			MethodResultError:            repoerror.ErrPostVersionConflict,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+		{
+			Name:                         "DatabaseFailure",
+			InputPosts:                   []domain.Post{{}, {}},
+			DatabaseResultError:          databaseResultError,
+			MethodResultError:            databaseResultError,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			tx := mock_database.NewMockDatabaseTx(s.Controller)
+			currentCase.MockDatabasePrivoderBehavior(s.MockDatabasePrivoder, tx, ctx, currentCase.DatabaseAffected, currentCase.DatabaseResultError)
+			err := s.CurrentRepository.SaveMany(ctx, currentCase.InputPosts)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
+
+func (s *PostRepositorySuite) TestGetAllIDsWithUserIDMethod() {
+	ctx := context.Background()
+	userID := uuid.NewV4()
+	s.MockDatabasePrivoder.EXPECT().
+		Select(ctx, gomock.AssignableToTypeOf(&[]uuid.UUID{}), gomock.Any(), userID).
+		Return(nil).
+		Times(1)
+
+	ids, err := s.CurrentRepository.GetAllIDsWithUserID(ctx, userID)
+	s.Assertions.NoError(err)
+	s.Assertions.Equal([]uuid.UUID{}, ids)
+}
+
+func (s *PostRepositorySuite) TestUnpublishMethod() {
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                string
+		DatabaseAffected    int64
+		DatabaseResultError error
+		MethodResultValue   bool
+	}{
+		{
+			Name:              "Success",
+			DatabaseAffected:  1,
+			MethodResultValue: true,
+		},
+		{
+			Name:             "NotPublished",
+			DatabaseAffected: 0,
+		},
+		{
+			Name:                "DatabaseFailure",
+			DatabaseResultError: databaseResultError,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			post := domain.Post{}
+			post.Init()
+			s.MockDatabasePrivoder.EXPECT().
+				ExecAffected(ctx, gomock.Any(), post.UpdatedAt, post.ID).
+				Return(currentCase.DatabaseAffected, currentCase.DatabaseResultError).
+				Times(1)
+			unpublished, err := s.CurrentRepository.Unpublish(ctx, post)
+			s.Assertions.Equal(currentCase.DatabaseResultError, err)
+			s.Assertions.Equal(currentCase.MethodResultValue, unpublished)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/database"
+	uuid "github.com/satori/go.uuid"
+)
+
+type SessionRepos struct {
+	database database.DatabasePrivoder
+}
+
+func NewSessionRepos(database database.DatabasePrivoder) *SessionRepos {
+	return &SessionRepos{database: database}
+}
+
+func (r *SessionRepos) Create(ctx context.Context, session domain.Session) error {
+	query := fmt.Sprintf("insert into %s (id, user_id, user_agent, ip, created_at, last_seen_at, revoked_at) values (?, ?, ?, ?, ?, ?, ?)", sessionsTable)
+	return r.database.Exec(ctx, query, session.ID, session.UserID, session.UserAgent, session.IP, session.CreatedAt, session.LastSeenAt, session.RevokedAt)
+}
+
+func (r *SessionRepos) Find(ctx context.Context, id uuid.UUID) (domain.Session, error) {
+	var session domain.Session
+	query := fmt.Sprintf("select * from %s where id = ?", sessionsTable)
+	err := r.database.Get(ctx, &session, query, id)
+	if err == sql.ErrNoRows {
+		return session, errors.ErrSessionNotFound
+	}
+	return session, err
+}
+
+func (r *SessionRepos) GetAllActiveWithUserID(ctx context.Context, userID uuid.UUID) ([]domain.Session, error) {
+	var sessions []domain.Session
+	query := fmt.Sprintf("select * from %s where (user_id = ? and revoked_at is null) order by last_seen_at desc", sessionsTable)
+	err := r.database.Select(ctx, &sessions, query, userID)
+	if sessions == nil {
+		sessions = []domain.Session{}
+	}
+	return sessions, err
+}
+
+func (r *SessionRepos) Touch(ctx context.Context, id uuid.UUID, ip string, seenAt time.Time) error {
+	query := fmt.Sprintf("update %s set ip = ?, last_seen_at = ? where id = ?", sessionsTable)
+	return r.database.Exec(ctx, query, ip, seenAt, id)
+}
+
+// Revoke returns not found error when session is already revoked or
+// belongs to another user
+func (r *SessionRepos) Revoke(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	query := fmt.Sprintf("update %s set revoked_at = ? where (id = ? and user_id = ? and revoked_at is null)", sessionsTable)
+	affected, err := r.database.ExecAffected(ctx, query, time.Now(), id, userID)
+	if err == nil && affected == 0 {
+		return errors.ErrSessionNotFound
+	}
+	return err
+}
+
+func (r *SessionRepos) RevokeAllWithUserID(ctx context.Context, userID uuid.UUID) error {
+	query := fmt.Sprintf("update %s set revoked_at = ? where (user_id = ? and revoked_at is null)", sessionsTable)
+	return r.database.Exec(ctx, query, time.Now(), userID)
+}
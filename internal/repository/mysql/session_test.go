@@ -0,0 +1,117 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	repoerror "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/mysql"
+	mock_database "github.com/aintsashqa/go-simple-blog/pkg/database/mocks"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockDatabasePrivoder *mock_database.MockDatabasePrivoder
+
+	CurrentRepository repository.Session
+}
+
+func TestSessionRepositorySuite(t *testing.T) {
+	suite.Run(t, new(SessionRepositorySuite))
+}
+
+func (s *SessionRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockDatabasePrivoder = mock_database.NewMockDatabasePrivoder(s.Controller)
+	s.CurrentRepository = mysql.NewSessionRepos(s.MockDatabasePrivoder)
+}
+
+func (s *SessionRepositorySuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *SessionRepositorySuite) TestFindMethod() {
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                string
+		DatabaseResultError error
+		MethodResultError   error
+	}{
+		{
+			Name: "Success",
+		},
+		{
+			Name:                "NotFound",
+			DatabaseResultError: sql.ErrNoRows,
+			MethodResultError:   repoerror.ErrSessionNotFound,
+		},
+		{
+			Name:                "DatabaseFailure",
+			DatabaseResultError: databaseResultError,
+			MethodResultError:   databaseResultError,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			s.MockDatabasePrivoder.EXPECT().
+				Get(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(currentCase.DatabaseResultError).
+				Times(1)
+			_, err := s.CurrentRepository.Find(ctx, uuid.NewV4())
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
+
+func (s *SessionRepositorySuite) TestRevokeMethod() {
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                   string
+		DatabaseResultAffected int64
+		DatabaseResultError    error
+		MethodResultError      error
+	}{
+		{
+			Name:                   "Success",
+			DatabaseResultAffected: 1,
+		},
+		{
+			Name:                   "NotFound",
+			DatabaseResultAffected: 0,
+			MethodResultError:      repoerror.ErrSessionNotFound,
+		},
+		{
+			Name:                "DatabaseFailure",
+			DatabaseResultError: databaseResultError,
+			MethodResultError:   databaseResultError,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			s.MockDatabasePrivoder.EXPECT().
+				ExecAffected(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(currentCase.DatabaseResultAffected, currentCase.DatabaseResultError).
+				Times(1)
+			err := s.CurrentRepository.Revoke(ctx, uuid.NewV4(), uuid.NewV4())
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
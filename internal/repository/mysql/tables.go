@@ -3,4 +3,23 @@ package mysql
 const (
 	usersTable string = "users"
 	postsTable string = "posts"
+
+	refreshTokensTable        string = "refresh_tokens"
+	sessionsTable             string = "sessions"
+	passwordResetsTable       string = "password_resets"
+	securityEventsTable       string = "security_events"
+	personalAccessTokensTable string = "personal_access_tokens"
+	userIdentitiesTable       string = "user_identities"
+	totpFactorsTable          string = "totp_factors"
+	recoveryCodesTable        string = "recovery_codes"
+	mfaRequiredRolesTable     string = "mfa_required_roles"
+
+	accountDeletionsTable string = "account_deletions"
+
+	followsTable       string = "follows"
+	auditLogsTable     string = "audit_logs"
+	notificationsTable string = "notifications"
+
+	webhooksTable          string = "webhooks"
+	webhookDeliveriesTable string = "webhook_deliveries"
 )
@@ -0,0 +1,53 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/database"
+	uuid "github.com/satori/go.uuid"
+)
+
+type RefreshTokenRepos struct {
+	database database.DatabasePrivoder
+}
+
+func NewRefreshTokenRepos(database database.DatabasePrivoder) *RefreshTokenRepos {
+	return &RefreshTokenRepos{database: database}
+}
+
+func (r *RefreshTokenRepos) Create(ctx context.Context, token domain.RefreshToken) error {
+	query := fmt.Sprintf("insert into %s (id, user_id, family_id, token_hash, created_at, expires_at, revoked_at) values (?, ?, ?, ?, ?, ?, ?)", refreshTokensTable)
+	return r.database.Exec(ctx, query, token.ID, token.UserID, token.FamilyID, token.TokenHash, token.CreatedAt, token.ExpiresAt, token.RevokedAt)
+}
+
+func (r *RefreshTokenRepos) FindWithHash(ctx context.Context, hash string) (domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	query := fmt.Sprintf("select * from %s where token_hash = ?", refreshTokensTable)
+	err := r.database.Get(ctx, &token, query, hash)
+	if err == sql.ErrNoRows {
+		return token, errors.ErrRefreshTokenNotFound
+	}
+	return token, err
+}
+
+// Revoke returns false when token was already revoked by concurrent request
+func (r *RefreshTokenRepos) Revoke(ctx context.Context, id uuid.UUID) (bool, error) {
+	query := fmt.Sprintf("update %s set revoked_at = ? where (id = ? and revoked_at is null)", refreshTokensTable)
+	affected, err := r.database.ExecAffected(ctx, query, time.Now(), id)
+	return affected != 0, err
+}
+
+func (r *RefreshTokenRepos) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	query := fmt.Sprintf("update %s set revoked_at = ? where (family_id = ? and revoked_at is null)", refreshTokensTable)
+	return r.database.Exec(ctx, query, time.Now(), familyID)
+}
+
+func (r *RefreshTokenRepos) RevokeAllWithUserID(ctx context.Context, userID uuid.UUID) error {
+	query := fmt.Sprintf("update %s set revoked_at = ? where (user_id = ? and revoked_at is null)", refreshTokensTable)
+	return r.database.Exec(ctx, query, time.Now(), userID)
+}
@@ -0,0 +1,143 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	repoerror "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/mysql"
+	mock_database "github.com/aintsashqa/go-simple-blog/pkg/database/mocks"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type RefreshTokenRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockDatabasePrivoder *mock_database.MockDatabasePrivoder
+
+	CurrentRepository repository.RefreshToken
+}
+
+func TestRefreshTokenRepositorySuite(t *testing.T) {
+	suite.Run(t, new(RefreshTokenRepositorySuite))
+}
+
+func (s *RefreshTokenRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockDatabasePrivoder = mock_database.NewMockDatabasePrivoder(s.Controller)
+	s.CurrentRepository = mysql.NewRefreshTokenRepos(s.MockDatabasePrivoder)
+}
+
+func (s *RefreshTokenRepositorySuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *RefreshTokenRepositorySuite) TestFindWithHashMethod() {
+	type MockDatabasePrivoderBehavior func(*mock_database.MockDatabasePrivoder, context.Context, error)
+
+	mockDatabasePrivoderBehavior := func(m *mock_database.MockDatabasePrivoder, input context.Context, returns error) {
+		m.EXPECT().
+			Get(input, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(returns).
+			Times(1)
+	}
+
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                         string
+		DatabaseResultError          error
+		MethodResultError            error
+		MockDatabasePrivoderBehavior MockDatabasePrivoderBehavior
+	}{
+		{
+			Name:                         "Success",
+			DatabaseResultError:          nil,
+			MethodResultError:            nil,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+		{
+			Name:                         "NotFound",
+			DatabaseResultError:          sql.ErrNoRows,
+			MethodResultError:            repoerror.ErrRefreshTokenNotFound,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+		{
+			Name:                         "DatabaseFailure",
+			DatabaseResultError:          databaseResultError,
+			MethodResultError:            databaseResultError,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			currentCase.MockDatabasePrivoderBehavior(s.MockDatabasePrivoder, ctx, currentCase.DatabaseResultError)
+			_, err := s.CurrentRepository.FindWithHash(ctx, "token-hash")
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
+
+func (s *RefreshTokenRepositorySuite) TestRevokeMethod() {
+	type MockDatabasePrivoderBehavior func(*mock_database.MockDatabasePrivoder, context.Context, int64, error)
+
+	mockDatabasePrivoderBehavior := func(m *mock_database.MockDatabasePrivoder, input context.Context, affected int64, returns error) {
+		m.EXPECT().
+			ExecAffected(input, gomock.Any(), gomock.Any()).
+			Return(affected, returns).
+			Times(1)
+	}
+
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                         string
+		DatabaseResultAffected       int64
+		DatabaseResultError          error
+		MethodResultValue            bool
+		MethodResultError            error
+		MockDatabasePrivoderBehavior MockDatabasePrivoderBehavior
+	}{
+		{
+			Name:                         "Success",
+			DatabaseResultAffected:       1,
+			MethodResultValue:            true,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+		{
+			Name:                         "AlreadyRevoked",
+			DatabaseResultAffected:       0,
+			MethodResultValue:            false,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+		{
+			Name:                         "DatabaseFailure",
+			DatabaseResultError:          databaseResultError,
+			MethodResultValue:            false,
+			MethodResultError:            databaseResultError,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			currentCase.MockDatabasePrivoderBehavior(s.MockDatabasePrivoder, ctx, currentCase.DatabaseResultAffected, currentCase.DatabaseResultError)
+			revoked, err := s.CurrentRepository.Revoke(ctx, uuid.NewV4())
+			s.Assertions.Equal(currentCase.MethodResultValue, revoked)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
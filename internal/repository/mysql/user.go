@@ -12,6 +12,23 @@ import (
 	uuid "github.com/satori/go.uuid"
 )
 
+// profileColumns are public, so they are selected for every user
+var profileColumns []string = []string{"display_name", "bio", "avatar_url", "location", "website", "social_links"}
+
+func publicColumns() []string {
+	return append([]string{"id", "username", "created_at", "updated_at", "version"}, profileColumns...)
+}
+
+// adminColumns include private fields of user except password
+func adminColumns() []string {
+	return append([]string{"id", "email", "username", "email_verified_at", "roles", "suspended_at", "created_at", "updated_at", "deleted_at", "version"}, profileColumns...)
+}
+
+// searchPattern matches value anywhere, wildcards of value are escaped
+func searchPattern(value string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value) + "%"
+}
+
 type UserRepos struct {
 	database database.DatabasePrivoder
 }
@@ -21,8 +38,12 @@ func NewUserRepos(database database.DatabasePrivoder) *UserRepos {
 }
 
 func (r *UserRepos) Create(ctx context.Context, user domain.User) error {
-	query := fmt.Sprintf("insert into %s (id, email, username, encrypted_password, created_at, updated_at, deleted_at) values (?, ?, ?, ?, ?, ?, ?)", usersTable)
-	return r.database.Exec(ctx, query, user.ID, user.Email, user.Username, user.Password, user.CreatedAt, user.UpdatedAt, user.DeletedAt)
+	query := fmt.Sprintf("insert into %s (id, email, username, encrypted_password, roles, created_at, updated_at, deleted_at) values (?, ?, ?, ?, ?, ?, ?, ?)", usersTable)
+	err := r.database.Exec(ctx, query, user.ID, user.Email, user.Username, user.Password, user.Roles, user.CreatedAt, user.UpdatedAt, user.DeletedAt)
+	if err == database.ErrDuplicateEntry {
+		return errors.ErrUserAlreadyExists
+	}
+	return err
 }
 
 func (r *UserRepos) GetByEmail(ctx context.Context, email string) (domain.User, error) {
@@ -35,6 +56,28 @@ func (r *UserRepos) GetByEmail(ctx context.Context, email string) (domain.User,
 	return user, err
 }
 
+// GetDeletedByEmail returns user in grace period after account deletion
+func (r *UserRepos) GetDeletedByEmail(ctx context.Context, email string) (domain.User, error) {
+	var user domain.User
+	query := fmt.Sprintf("select * from %s where (email = ? and deleted_at is not null)", usersTable)
+	err := r.database.Get(ctx, &user, query, email)
+	if err == sql.ErrNoRows {
+		return user, errors.ErrUserNotFound
+	}
+	return user, err
+}
+
+// GetByUsername relies on case insensitive collation of username column
+func (r *UserRepos) GetByUsername(ctx context.Context, username string) (domain.User, error) {
+	var user domain.User
+	query := fmt.Sprintf("select %s from %s where (username = ? and deleted_at is null)", strings.Join(publicColumns(), ", "), usersTable)
+	err := r.database.Get(ctx, &user, query, username)
+	if err == sql.ErrNoRows {
+		return user, errors.ErrUserNotFound
+	}
+	return user, err
+}
+
 func (r *UserRepos) find(ctx context.Context, id uuid.UUID, columns ...string) (domain.User, error) {
 	var user domain.User
 	if len(columns) == 0 {
@@ -50,18 +93,254 @@ func (r *UserRepos) find(ctx context.Context, id uuid.UUID, columns ...string) (
 }
 
 func (r *UserRepos) Find(ctx context.Context, id uuid.UUID) (domain.User, error) {
-	return r.find(ctx, id, "id", "username", "created_at", "updated_at")
+	return r.find(ctx, id, publicColumns()...)
 }
 
 func (r *UserRepos) Self(ctx context.Context, id uuid.UUID) (domain.User, error) {
-	return r.find(ctx, id, "id", "email", "username", "created_at", "updated_at")
+	columns := append([]string{"id", "email", "username", "email_verified_at", "roles", "suspended_at", "created_at", "updated_at", "version"}, profileColumns...)
+	return r.find(ctx, id, columns...)
+}
+
+// FindValidator selects only columns required by conditional requests
+func (r *UserRepos) FindValidator(ctx context.Context, id uuid.UUID) (domain.Validator, error) {
+	user, err := r.find(ctx, id, "id", "updated_at", "version")
+	if err != nil {
+		return domain.Validator{}, err
+	}
+	return user.Validator(), nil
 }
 
 func (r *UserRepos) Update(ctx context.Context, user domain.User) error {
-	query := fmt.Sprintf("update %s set username = ?, updated_at = ? where (id = ? and deleted_at is null)", usersTable)
-	err := r.database.Exec(ctx, query, user.Username, user.UpdatedAt, user.ID)
+	query := fmt.Sprintf("update %s set username = ?, display_name = ?, bio = ?, avatar_url = ?, location = ?, website = ?, social_links = ?, updated_at = ?, version = ? where (id = ? and version = ? and deleted_at is null)", usersTable)
+	affected, err := r.database.ExecAffected(ctx, query, user.Username, user.DisplayName, user.Bio, user.AvatarURL, user.Location, user.Website, user.SocialLinks, user.UpdatedAt, user.Version, user.ID, user.Version-1)
+	if err == sql.ErrNoRows {
+		return errors.ErrUserNotFound
+	}
+	if err == database.ErrDuplicateEntry {
+		return errors.ErrUserAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.ErrUserVersionConflict
+	}
+	return nil
+}
+
+func (r *UserRepos) VerifyEmail(ctx context.Context, user domain.User) error {
+	query := fmt.Sprintf("update %s set email_verified_at = ?, updated_at = ?, version = version + 1 where (id = ? and deleted_at is null)", usersTable)
+	err := r.database.Exec(ctx, query, user.EmailVerifiedAt, user.UpdatedAt, user.ID)
+	if err == sql.ErrNoRows {
+		return errors.ErrUserNotFound
+	}
+	return err
+}
+
+func (r *UserRepos) UpdateRoles(ctx context.Context, user domain.User) error {
+	query := fmt.Sprintf("update %s set roles = ?, updated_at = ?, version = version + 1 where (id = ? and deleted_at is null)", usersTable)
+	err := r.database.Exec(ctx, query, user.Roles, user.UpdatedAt, user.ID)
+	if err == sql.ErrNoRows {
+		return errors.ErrUserNotFound
+	}
+	return err
+}
+
+func (r *UserRepos) UpdateSuspension(ctx context.Context, user domain.User) error {
+	query := fmt.Sprintf("update %s set suspended_at = ?, updated_at = ?, version = version + 1 where (id = ? and deleted_at is null)", usersTable)
+	err := r.database.Exec(ctx, query, user.SuspendedAt, user.UpdatedAt, user.ID)
+	if err == sql.ErrNoRows {
+		return errors.ErrUserNotFound
+	}
+	return err
+}
+
+// Search matches users by part of email or username, deleted users are
+// included only on demand
+func (r *UserRepos) Search(ctx context.Context, value string, includeDeleted bool, offset, count int) ([]domain.User, error) {
+	var users []domain.User
+	query := fmt.Sprintf("select %s from %s where %s order by created_at desc limit ?, ?", strings.Join(adminColumns(), ", "), usersTable, searchCondition(includeDeleted))
+	pattern := searchPattern(value)
+	err := r.database.Select(ctx, &users, query, pattern, pattern, offset, count)
+	if users == nil {
+		users = []domain.User{}
+	}
+	return users, err
+}
+
+func (r *UserRepos) SearchCount(ctx context.Context, value string, includeDeleted bool) (int, error) {
+	var count int
+	query := fmt.Sprintf("select count(*) from %s where %s", usersTable, searchCondition(includeDeleted))
+	pattern := searchPattern(value)
+	err := r.database.QueryRow(ctx, &count, query, pattern, pattern)
+	return count, err
+}
+
+func searchCondition(includeDeleted bool) string {
+	if includeDeleted {
+		return "(email like ? or username like ?)"
+	}
+	return "((email like ? or username like ?) and deleted_at is null)"
+}
+
+// CountWithRole counts active users which are granted role
+func (r *UserRepos) CountWithRole(ctx context.Context, role domain.Role) (int, error) {
+	var count int
+	query := fmt.Sprintf("select count(*) from %s where (find_in_set(?, roles) > 0 and deleted_at is null)", usersTable)
+	err := r.database.QueryRow(ctx, &count, query, role)
+	return count, err
+}
+
+func (r *UserRepos) UpdatePassword(ctx context.Context, user domain.User) error {
+	query := fmt.Sprintf("update %s set encrypted_password = ?, updated_at = ?, version = version + 1 where (id = ? and deleted_at is null)", usersTable)
+	err := r.database.Exec(ctx, query, user.Password, user.UpdatedAt, user.ID)
+	if err == sql.ErrNoRows {
+		return errors.ErrUserNotFound
+	}
+	return err
+}
+
+// RehashPassword replaces hash only when it is not changed since it was read,
+// version is kept because password itself is the same
+func (r *UserRepos) RehashPassword(ctx context.Context, user domain.User, previous string) error {
+	query := fmt.Sprintf("update %s set encrypted_password = ? where (id = ? and encrypted_password = ? and deleted_at is null)", usersTable)
+	return r.database.Exec(ctx, query, user.Password, user.ID, previous)
+}
+
+// UpdateEmail marks new email as verified, because it is changed only after confirmation
+func (r *UserRepos) UpdateEmail(ctx context.Context, user domain.User) error {
+	query := fmt.Sprintf("update %s set email = ?, email_verified_at = ?, updated_at = ?, version = version + 1 where (id = ? and deleted_at is null)", usersTable)
+	err := r.database.Exec(ctx, query, user.Email, user.EmailVerifiedAt, user.UpdatedAt, user.ID)
+	if err == sql.ErrNoRows {
+		return errors.ErrUserNotFound
+	}
+	return err
+}
+
+// SoftDelete hides user, schedules purge with deletion and signs user out
+// from all sessions in single transaction
+func (r *UserRepos) SoftDelete(ctx context.Context, user domain.User, deletion domain.AccountDeletion) error {
+	tx, err := r.database.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("update %s set updated_at = ?, deleted_at = ?, version = version + 1 where (id = ? and deleted_at is null)", usersTable)
+	affected, err := tx.ExecAffected(ctx, query, user.UpdatedAt, user.DeletedAt, user.ID)
+	if err == nil && affected == 0 {
+		err = errors.ErrUserNotFound
+	}
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	queries := []struct {
+		query string
+		args  []interface{}
+	}{
+		{
+			query: fmt.Sprintf("insert into %s (user_id, posts_action, transfer_to, requested_at, purge_at) values (?, ?, ?, ?, ?)", accountDeletionsTable),
+			args:  []interface{}{deletion.UserID, deletion.PostsAction, deletion.TransferTo, deletion.RequestedAt, deletion.PurgeAt},
+		},
+		{
+			query: fmt.Sprintf("update %s set revoked_at = ? where (user_id = ? and revoked_at is null)", refreshTokensTable),
+			args:  []interface{}{user.UpdatedAt, user.ID},
+		},
+		{
+			query: fmt.Sprintf("update %s set revoked_at = ? where (user_id = ? and revoked_at is null)", sessionsTable),
+			args:  []interface{}{user.UpdatedAt, user.ID},
+		},
+	}
+
+	for _, current := range queries {
+		if err := tx.Exec(ctx, current.query, current.args...); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return err
+			}
+
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *UserRepos) Restore(ctx context.Context, user domain.User) error {
+	query := fmt.Sprintf("update %s set updated_at = ?, deleted_at = null, version = version + 1 where (id = ? and deleted_at is not null)", usersTable)
+	err := r.database.Exec(ctx, query, user.UpdatedAt, user.ID)
 	if err == sql.ErrNoRows {
 		return errors.ErrUserNotFound
 	}
 	return err
 }
+
+// purgeQueries remove everything what belongs to user. Foreign keys of
+// columns are not enforced by InnoDB, so rows are removed explicitly.
+// Audit logs are kept
+func purgeQueries() []string {
+	queries := []string{
+		fmt.Sprintf("delete from %s where webhook_id in (select id from %s where user_id = ?)", webhookDeliveriesTable, webhooksTable),
+		fmt.Sprintf("delete from %s where (user_id = ? or actor_id = ? or post_id in (select id from %s where user_id = ?))", notificationsTable, postsTable),
+		fmt.Sprintf("delete from %s where (follower_id = ? or followee_id = ?)", followsTable),
+	}
+
+	tables := []string{
+		webhooksTable,
+		postsTable,
+		refreshTokensTable,
+		sessionsTable,
+		passwordResetsTable,
+		securityEventsTable,
+		personalAccessTokensTable,
+		userIdentitiesTable,
+		totpFactorsTable,
+		recoveryCodesTable,
+		accountDeletionsTable,
+	}
+	for _, table := range tables {
+		queries = append(queries, fmt.Sprintf("delete from %s where user_id = ?", table))
+	}
+
+	return queries
+}
+
+// Purge removes soft deleted user together with remaining posts, tokens and
+// other rows of user in single transaction
+func (r *UserRepos) Purge(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.database.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("delete from %s where (id = ? and deleted_at is not null)", usersTable)
+	affected, err := tx.ExecAffected(ctx, query, id)
+	if err != nil || affected == 0 {
+		// User was restored, so nothing is removed
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+
+		return err
+	}
+
+	for _, query := range purgeQueries() {
+		args := make([]interface{}, strings.Count(query, "?"))
+		for i := range args {
+			args[i] = id
+		}
+
+		if err := tx.Exec(ctx, query, args...); err != nil {
+			if err := tx.Rollback(); err != nil {
+				return err
+			}
+
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -10,8 +10,10 @@ import (
 	"github.com/aintsashqa/go-simple-blog/internal/repository"
 	repoerror "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
 	"github.com/aintsashqa/go-simple-blog/internal/repository/mysql"
+	"github.com/aintsashqa/go-simple-blog/pkg/database"
 	mock_database "github.com/aintsashqa/go-simple-blog/pkg/database/mocks"
 	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -69,6 +71,13 @@ func (s *UserRepositorySuite) TestCreateMethod() {
 			MethodResultError:            nil,
 			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
 		},
+		{
+			Name:                         "DuplicateEntry",
+			InputUser:                    domain.User{},
+			DatabaseResultError:          database.ErrDuplicateEntry,
+			MethodResultError:            repoerror.ErrUserAlreadyExists,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
 		{
 			Name:                         "DatabaseFailure",
 			InputUser:                    domain.User{},
@@ -154,3 +163,186 @@ func (s *UserRepositorySuite) TestGetByEmailMethod() {
 		})
 	}
 }
+
+func (s *UserRepositorySuite) TestGetByUsernameMethod() {
+	type MockDatabasePrivoderBehavior func(*mock_database.MockDatabasePrivoder, context.Context, string, error)
+
+	mockDatabasePrivoderBehavior := func(m *mock_database.MockDatabasePrivoder, inputContext context.Context, inputUsername string, returns error) {
+		m.EXPECT().
+			Get(inputContext, gomock.AssignableToTypeOf(&domain.User{}), gomock.Any(), inputUsername).
+			Return(returns).
+			Times(1).
+			Do(func(_ context.Context, user *domain.User, _, _ string) error {
+				user.Username = inputUsername
+				return nil
+			})
+	}
+
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                         string
+		InputUsername                string
+		DatabaseResultError          error
+		MethodResultValue            domain.User
+		MethodResultError            error
+		MockDatabasePrivoderBehavior MockDatabasePrivoderBehavior
+	}{
+		{
+			Name:                "Success",
+			InputUsername:       "username",
+			DatabaseResultError: nil,
+			MethodResultValue: domain.User{
+				Username: "username",
+			},
+			MethodResultError:            nil,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+		{
+			Name:                         "NotFound",
+			InputUsername:                "",
+			DatabaseResultError:          sql.ErrNoRows,
+			MethodResultValue:            domain.User{},
+			MethodResultError:            repoerror.ErrUserNotFound,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+		{
+			Name:                         "DatabaseFailure",
+			InputUsername:                "",
+			DatabaseResultError:          databaseResultError,
+			MethodResultValue:            domain.User{},
+			MethodResultError:            databaseResultError,
+			MockDatabasePrivoderBehavior: mockDatabasePrivoderBehavior,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			currentCase.MockDatabasePrivoderBehavior(s.MockDatabasePrivoder, ctx, currentCase.InputUsername, currentCase.DatabaseResultError)
+			result, err := s.CurrentRepository.GetByUsername(ctx, currentCase.InputUsername)
+			s.Assertions.Equal(currentCase.MethodResultValue.Username, result.Username)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
+
+func (s *UserRepositorySuite) TestSearchMethod() {
+	ctx := context.Background()
+
+	s.MockDatabasePrivoder.EXPECT().
+		Select(ctx, gomock.AssignableToTypeOf(&[]domain.User{}), gomock.Any(), `%50\%\_off%`, `%50\%\_off%`, 0, 15).
+		Return(nil).
+		Times(1)
+
+	result, err := s.CurrentRepository.Search(ctx, "50%_off", false, 0, 15)
+	s.Assertions.Equal([]domain.User{}, result)
+	s.Assertions.NoError(err)
+}
+
+func (s *UserRepositorySuite) TestPurgeMethod() {
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                string
+		DatabaseAffected    int64
+		DatabaseResultError error
+		MethodResultError   error
+	}{
+		{
+			Name:             "Success",
+			DatabaseAffected: 1,
+		},
+		{
+			Name:             "NotDeleted",
+			DatabaseAffected: 0,
+		},
+		{
+			Name:                "DatabaseFailure",
+			DatabaseResultError: databaseResultError,
+			MethodResultError:   databaseResultError,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			id := uuid.NewV4()
+			tx := mock_database.NewMockDatabaseTx(s.Controller)
+			s.MockDatabasePrivoder.EXPECT().
+				BeginTx(ctx).
+				Return(tx, nil).
+				Times(1)
+			tx.EXPECT().
+				ExecAffected(ctx, gomock.Any(), id).
+				Return(currentCase.DatabaseAffected, currentCase.DatabaseResultError).
+				Times(1)
+
+			if currentCase.DatabaseAffected == 0 {
+				tx.EXPECT().Rollback().Return(nil).Times(1)
+			} else {
+				// Posts, tokens and everything else of user are removed
+				tx.EXPECT().Exec(ctx, gomock.Any(), gomock.Any()).Return(nil).MinTimes(1)
+				tx.EXPECT().Commit().Return(nil).Times(1)
+			}
+
+			err := s.CurrentRepository.Purge(ctx, id)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
+
+func (s *UserRepositorySuite) TestSoftDeleteMethod() {
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                string
+		DatabaseAffected    int64
+		DatabaseResultError error
+		MethodResultError   error
+	}{
+		{
+			Name:             "Success",
+			DatabaseAffected: 1,
+		},
+		{
+			Name:              "NotFound",
+			DatabaseAffected:  0,
+			MethodResultError: repoerror.ErrUserNotFound,
+		},
+		{
+			Name:                "DatabaseFailure",
+			DatabaseResultError: databaseResultError,
+			MethodResultError:   databaseResultError,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			user := domain.User{}
+			user.Init()
+			user.Delete()
+			tx := mock_database.NewMockDatabaseTx(s.Controller)
+			s.MockDatabasePrivoder.EXPECT().
+				BeginTx(ctx).
+				Return(tx, nil).
+				Times(1)
+			tx.EXPECT().
+				ExecAffected(ctx, gomock.Any(), gomock.Any()).
+				Return(currentCase.DatabaseAffected, currentCase.DatabaseResultError).
+				Times(1)
+
+			if currentCase.MethodResultError != nil {
+				tx.EXPECT().Rollback().Return(nil).Times(1)
+			} else {
+				// Deletion is created and tokens are revoked in same transaction
+				tx.EXPECT().Exec(ctx, gomock.Any(), gomock.Any()).Return(nil).Times(3)
+				tx.EXPECT().Commit().Return(nil).Times(1)
+			}
+
+			err := s.CurrentRepository.SoftDelete(ctx, user, domain.AccountDeletion{UserID: user.ID})
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
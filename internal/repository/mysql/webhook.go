@@ -0,0 +1,149 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/database"
+	uuid "github.com/satori/go.uuid"
+)
+
+type WebhookRepos struct {
+	database database.DatabasePrivoder
+}
+
+func NewWebhookRepos(database database.DatabasePrivoder) *WebhookRepos {
+	return &WebhookRepos{database: database}
+}
+
+func (r *WebhookRepos) Create(ctx context.Context, webhook domain.Webhook) error {
+	query := fmt.Sprintf("insert into %s (id, user_id, url, secret, events, global, created_at) values (?, ?, ?, ?, ?, ?, ?)", webhooksTable)
+	return r.database.Exec(ctx, query, webhook.ID, webhook.UserID, webhook.URL, webhook.Secret, webhook.Events, webhook.Global, webhook.CreatedAt)
+}
+
+func (r *WebhookRepos) Find(ctx context.Context, id uuid.UUID) (domain.Webhook, error) {
+	var webhook domain.Webhook
+	query := fmt.Sprintf("select * from %s where id = ?", webhooksTable)
+	err := r.database.Get(ctx, &webhook, query, id)
+	if err == sql.ErrNoRows {
+		return webhook, errors.ErrWebhookNotFound
+	}
+	return webhook, err
+}
+
+func (r *WebhookRepos) FindWithUserID(ctx context.Context, id uuid.UUID, userID uuid.UUID) (domain.Webhook, error) {
+	var webhook domain.Webhook
+	query := fmt.Sprintf("select * from %s where (id = ? and user_id = ?)", webhooksTable)
+	err := r.database.Get(ctx, &webhook, query, id, userID)
+	if err == sql.ErrNoRows {
+		return webhook, errors.ErrWebhookNotFound
+	}
+	return webhook, err
+}
+
+func (r *WebhookRepos) GetAllWithUserID(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	query := fmt.Sprintf("select * from %s where user_id = ? order by created_at desc", webhooksTable)
+	err := r.database.Select(ctx, &webhooks, query, userID)
+	if webhooks == nil {
+		webhooks = []domain.Webhook{}
+	}
+	return webhooks, err
+}
+
+// GetAllSubscribed returns webhooks of user and global webhooks which
+// subscribed to event
+func (r *WebhookRepos) GetAllSubscribed(ctx context.Context, event domain.WebhookEvent, userID uuid.UUID) ([]domain.Webhook, error) {
+	var webhooks []domain.Webhook
+	query := fmt.Sprintf("select * from %s where ((user_id = ? or global = true) and find_in_set(?, events) > 0)", webhooksTable)
+	err := r.database.Select(ctx, &webhooks, query, userID, event)
+	if webhooks == nil {
+		webhooks = []domain.Webhook{}
+	}
+	return webhooks, err
+}
+
+// Delete returns not found error when webhook belongs to another user,
+// deliveries of webhook are removed by cascade
+func (r *WebhookRepos) Delete(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	query := fmt.Sprintf("delete from %s where (id = ? and user_id = ?)", webhooksTable)
+	affected, err := r.database.ExecAffected(ctx, query, id, userID)
+	if err == nil && affected == 0 {
+		return errors.ErrWebhookNotFound
+	}
+	return err
+}
+
+type WebhookDeliveryRepos struct {
+	database database.DatabasePrivoder
+}
+
+func NewWebhookDeliveryRepos(database database.DatabasePrivoder) *WebhookDeliveryRepos {
+	return &WebhookDeliveryRepos{database: database}
+}
+
+func (r *WebhookDeliveryRepos) Create(ctx context.Context, delivery domain.WebhookDelivery) error {
+	query := fmt.Sprintf("insert into %s (id, webhook_id, event, payload, status, attempts, status_code, error, next_attempt_at, created_at, updated_at) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", webhookDeliveriesTable)
+	return r.database.Exec(ctx, query,
+		delivery.ID, delivery.WebhookID, delivery.Event, delivery.Payload, delivery.Status, delivery.Attempts,
+		delivery.StatusCode, delivery.Error, delivery.NextAttemptAt, delivery.CreatedAt, delivery.UpdatedAt,
+	)
+}
+
+func (r *WebhookDeliveryRepos) FindWithWebhookID(ctx context.Context, id uuid.UUID, webhookID uuid.UUID) (domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	query := fmt.Sprintf("select * from %s where (id = ? and webhook_id = ?)", webhookDeliveriesTable)
+	err := r.database.Get(ctx, &delivery, query, id, webhookID)
+	if err == sql.ErrNoRows {
+		return delivery, errors.ErrWebhookDeliveryNotFound
+	}
+	return delivery, err
+}
+
+// GetAllWithWebhookID returns delivery log of webhook, recent deliveries first
+func (r *WebhookDeliveryRepos) GetAllWithWebhookID(ctx context.Context, webhookID uuid.UUID, offset, count int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	query := fmt.Sprintf("select * from %s where webhook_id = ? order by created_at desc limit ?, ?", webhookDeliveriesTable)
+	err := r.database.Select(ctx, &deliveries, query, webhookID, offset, count)
+	if deliveries == nil {
+		deliveries = []domain.WebhookDelivery{}
+	}
+	return deliveries, err
+}
+
+func (r *WebhookDeliveryRepos) CountWithWebhookID(ctx context.Context, webhookID uuid.UUID) (int, error) {
+	var count int
+	query := fmt.Sprintf("select count(*) from %s where webhook_id = ?", webhookDeliveriesTable)
+	err := r.database.QueryRow(ctx, &count, query, webhookID)
+	return count, err
+}
+
+// GetAllDue returns pending deliveries which next attempt is not later than time
+func (r *WebhookDeliveryRepos) GetAllDue(ctx context.Context, now time.Time, count int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	query := fmt.Sprintf("select * from %s where (status = ? and next_attempt_at <= ?) order by next_attempt_at asc limit ?", webhookDeliveriesTable)
+	err := r.database.Select(ctx, &deliveries, query, domain.PendingWebhookDelivery, now, count)
+	if deliveries == nil {
+		deliveries = []domain.WebhookDelivery{}
+	}
+	return deliveries, err
+}
+
+// Claim postpones next attempt of delivery until time, so other instances
+// do not send same delivery. Delivery claimed by another instance is not claimed
+func (r *WebhookDeliveryRepos) Claim(ctx context.Context, delivery domain.WebhookDelivery, until time.Time) (bool, error) {
+	query := fmt.Sprintf("update %s set next_attempt_at = ? where (id = ? and status = ? and next_attempt_at = ?)", webhookDeliveriesTable)
+	affected, err := r.database.ExecAffected(ctx, query, until, delivery.ID, domain.PendingWebhookDelivery, delivery.NextAttemptAt)
+	return affected != 0, err
+}
+
+func (r *WebhookDeliveryRepos) Update(ctx context.Context, delivery domain.WebhookDelivery) error {
+	query := fmt.Sprintf("update %s set status = ?, attempts = ?, status_code = ?, error = ?, next_attempt_at = ?, updated_at = ? where id = ?", webhookDeliveriesTable)
+	return r.database.Exec(ctx, query,
+		delivery.Status, delivery.Attempts, delivery.StatusCode, delivery.Error, delivery.NextAttemptAt, delivery.UpdatedAt, delivery.ID,
+	)
+}
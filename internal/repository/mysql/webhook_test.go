@@ -0,0 +1,135 @@
+package mysql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	repoerror "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/mysql"
+	mock_database "github.com/aintsashqa/go-simple-blog/pkg/database/mocks"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockDatabasePrivoder *mock_database.MockDatabasePrivoder
+
+	CurrentWebhookRepository  *mysql.WebhookRepos
+	CurrentDeliveryRepository *mysql.WebhookDeliveryRepos
+}
+
+func TestWebhookRepositorySuite(t *testing.T) {
+	suite.Run(t, new(WebhookRepositorySuite))
+}
+
+func (s *WebhookRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockDatabasePrivoder = mock_database.NewMockDatabasePrivoder(s.Controller)
+	s.CurrentWebhookRepository = mysql.NewWebhookRepos(s.MockDatabasePrivoder)
+	s.CurrentDeliveryRepository = mysql.NewWebhookDeliveryRepos(s.MockDatabasePrivoder)
+}
+
+func (s *WebhookRepositorySuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *WebhookRepositorySuite) TestDeleteMethod() {
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                string
+		DatabaseAffected    int64
+		DatabaseResultError error
+		MethodResultError   error
+	}{
+		{
+			Name:             "Success",
+			DatabaseAffected: 1,
+		},
+		{
+			Name:              "NotFound",
+			DatabaseAffected:  0,
+			MethodResultError: repoerror.ErrWebhookNotFound,
+		},
+		{
+			Name:                "DatabaseFailure",
+			DatabaseResultError: databaseResultError,
+			MethodResultError:   databaseResultError,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			s.MockDatabasePrivoder.EXPECT().
+				ExecAffected(ctx, gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(currentCase.DatabaseAffected, currentCase.DatabaseResultError).
+				Times(1)
+			err := s.CurrentWebhookRepository.Delete(ctx, uuid.NewV4(), uuid.NewV4())
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
+
+func (s *WebhookRepositorySuite) TestGetAllSubscribedMethod() {
+	ctx := context.Background()
+	s.MockDatabasePrivoder.EXPECT().
+		Select(ctx, gomock.AssignableToTypeOf(&[]domain.Webhook{}), gomock.Any(), gomock.Any(), domain.PostCreatedWebhookEvent).
+		Return(nil).
+		Times(1)
+
+	webhooks, err := s.CurrentWebhookRepository.GetAllSubscribed(ctx, domain.PostCreatedWebhookEvent, uuid.NewV4())
+	s.Assertions.NoError(err)
+	s.Assertions.NotNil(webhooks)
+	s.Assertions.Len(webhooks, 0)
+}
+
+func (s *WebhookRepositorySuite) TestClaimMethod() {
+	databaseResultError := errors.New("DatabaseResultError")
+
+	methodCases := []struct {
+		Name                string
+		DatabaseAffected    int64
+		DatabaseResultError error
+		MethodResultValue   bool
+	}{
+		{
+			Name:              "Success",
+			DatabaseAffected:  1,
+			MethodResultValue: true,
+		},
+		{
+			Name:             "ClaimedByAnother",
+			DatabaseAffected: 0,
+		},
+		{
+			Name:                "DatabaseFailure",
+			DatabaseResultError: databaseResultError,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			ctx := context.Background()
+			delivery := domain.NewWebhookDelivery(uuid.NewV4(), domain.PostDeletedWebhookEvent, "{}")
+			s.MockDatabasePrivoder.EXPECT().
+				ExecAffected(ctx, gomock.Any(), gomock.Any(), delivery.ID, domain.PendingWebhookDelivery, delivery.NextAttemptAt).
+				Return(currentCase.DatabaseAffected, currentCase.DatabaseResultError).
+				Times(1)
+			claimed, err := s.CurrentDeliveryRepository.Claim(ctx, delivery, time.Now().Add(time.Minute))
+			s.Assertions.Equal(currentCase.DatabaseResultError, err)
+			s.Assertions.Equal(currentCase.MethodResultValue, claimed)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/aintsashqa/go-simple-blog/internal/domain"
 	"github.com/aintsashqa/go-simple-blog/internal/repository/mysql"
@@ -14,36 +15,231 @@ type (
 	User interface {
 		Create(context.Context, domain.User) error
 		GetByEmail(context.Context, string) (domain.User, error)
+		GetByUsername(context.Context, string) (domain.User, error)
 		Find(context.Context, uuid.UUID) (domain.User, error)
 		Self(context.Context, uuid.UUID) (domain.User, error)
+		FindValidator(context.Context, uuid.UUID) (domain.Validator, error)
 		Update(context.Context, domain.User) error
+		VerifyEmail(context.Context, domain.User) error
+		UpdateRoles(context.Context, domain.User) error
+		CountWithRole(context.Context, domain.Role) (int, error)
+		UpdateSuspension(context.Context, domain.User) error
+		Search(context.Context, string, bool, int, int) ([]domain.User, error)
+		SearchCount(context.Context, string, bool) (int, error)
+		UpdatePassword(context.Context, domain.User) error
+		RehashPassword(context.Context, domain.User, string) error
+		UpdateEmail(context.Context, domain.User) error
+		GetDeletedByEmail(context.Context, string) (domain.User, error)
+		SoftDelete(context.Context, domain.User, domain.AccountDeletion) error
+		Restore(context.Context, domain.User) error
+		Purge(context.Context, uuid.UUID) error
 	}
 
 	Post interface {
 		Find(context.Context, uuid.UUID) (domain.Post, error)
+		FindValidator(context.Context, uuid.UUID) (domain.Validator, error)
 		FindWithPrimaryAndUserID(context.Context, uuid.UUID, uuid.UUID) (domain.Post, error)
+		GetAllWithPrimariesAndUserID(context.Context, []uuid.UUID, uuid.UUID) ([]domain.Post, error)
 		GetAllPublished(context.Context, int, int) ([]domain.Post, error)
 		GetAllPublishedWithUserID(context.Context, uuid.UUID, int, int) ([]domain.Post, error)
 		GetAllWithUserID(context.Context, uuid.UUID, int, int) ([]domain.Post, error)
+		GetAllFeed(context.Context, uuid.UUID, domain.FeedCursor, int) ([]domain.Post, error)
+		FindWithDeleted(context.Context, uuid.UUID) (domain.Post, error)
+		GetAllDeleted(context.Context, int, int) ([]domain.Post, error)
+		AllDeletedCount(context.Context) (int, error)
 		AllPublishedCount(context.Context) (int, error)
 		AllPublishedCountWithUserID(context.Context, uuid.UUID) (int, error)
 		TotalCountWithUserID(context.Context, uuid.UUID) (int, error)
+		GetAllIDsWithUserID(context.Context, uuid.UUID) ([]uuid.UUID, error)
 		Create(context.Context, domain.Post) error
 		Update(context.Context, domain.Post) error
 		Publish(context.Context, domain.Post) error
+		Unpublish(context.Context, domain.Post) (bool, error)
 		SoftDelete(context.Context, domain.Post) error
+		SaveMany(context.Context, []domain.Post) error
+		TransferAll(context.Context, uuid.UUID, uuid.UUID) error
+	}
+
+	RefreshToken interface {
+		Create(context.Context, domain.RefreshToken) error
+		FindWithHash(context.Context, string) (domain.RefreshToken, error)
+		Revoke(context.Context, uuid.UUID) (bool, error)
+		RevokeFamily(context.Context, uuid.UUID) error
+		RevokeAllWithUserID(context.Context, uuid.UUID) error
+	}
+
+	Session interface {
+		Create(context.Context, domain.Session) error
+		Find(context.Context, uuid.UUID) (domain.Session, error)
+		GetAllActiveWithUserID(context.Context, uuid.UUID) ([]domain.Session, error)
+		Touch(context.Context, uuid.UUID, string, time.Time) error
+		Revoke(context.Context, uuid.UUID, uuid.UUID) error
+		RevokeAllWithUserID(context.Context, uuid.UUID) error
+	}
+
+	PasswordReset interface {
+		Create(context.Context, domain.PasswordReset) error
+		FindWithHash(context.Context, string) (domain.PasswordReset, error)
+		UseWithHash(context.Context, string) (bool, error)
+		UseAll(context.Context, uuid.UUID) error
+	}
+
+	SecurityEvent interface {
+		Create(context.Context, domain.SecurityEvent) error
+	}
+
+	AccountDeletion interface {
+		Delete(context.Context, uuid.UUID) error
+		GetAllDue(context.Context, time.Time, int) ([]domain.AccountDeletion, error)
+	}
+
+	PersonalAccessToken interface {
+		Create(context.Context, domain.PersonalAccessToken) error
+		FindWithHash(context.Context, string) (domain.PersonalAccessToken, error)
+		GetAllWithUserID(context.Context, uuid.UUID) ([]domain.PersonalAccessToken, error)
+		Delete(context.Context, uuid.UUID, uuid.UUID) error
+		DeleteAllWithUserID(context.Context, uuid.UUID) error
+		Touch(context.Context, uuid.UUID, time.Time) error
+	}
+
+	UserIdentity interface {
+		Create(context.Context, domain.UserIdentity) error
+		Find(context.Context, string, string) (domain.UserIdentity, error)
+	}
+
+	TOTPFactor interface {
+		Save(context.Context, domain.TOTPFactor) error
+		Find(context.Context, uuid.UUID) (domain.TOTPFactor, error)
+		Confirm(context.Context, domain.TOTPFactor) error
+		Use(context.Context, uuid.UUID, int64) (bool, error)
+		Delete(context.Context, uuid.UUID) error
+	}
+
+	RecoveryCode interface {
+		ReplaceAll(context.Context, uuid.UUID, []domain.RecoveryCode) error
+		GetAllUnusedWithUserID(context.Context, uuid.UUID) ([]domain.RecoveryCode, error)
+		UseWithHash(context.Context, uuid.UUID, string) (bool, error)
+		DeleteAll(context.Context, uuid.UUID) error
+	}
+
+	// MFAPolicy keeps roles which users must enable second factor
+	MFAPolicy interface {
+		GetRequiredRoles(context.Context) (domain.Roles, error)
+		SetRequiredRoles(context.Context, domain.Roles) error
+	}
+
+	AuditLog interface {
+		Create(context.Context, domain.AuditLog) error
+	}
+
+	Follow interface {
+		Create(context.Context, domain.Follow) error
+		Delete(context.Context, uuid.UUID, uuid.UUID) error
+		GetAllFollowers(context.Context, uuid.UUID, int, int) ([]domain.User, error)
+		GetAllFollowing(context.Context, uuid.UUID, int, int) ([]domain.User, error)
+		FollowersCount(context.Context, uuid.UUID) (int, error)
+		FollowingCount(context.Context, uuid.UUID) (int, error)
+	}
+
+	Notification interface {
+		Create(context.Context, domain.Notification) error
+		CreateForFollowers(context.Context, domain.Notification) error
+		FindWithUserID(context.Context, uuid.UUID, uuid.UUID) (domain.Notification, error)
+		GetAllWithUserID(context.Context, uuid.UUID, int, int) ([]domain.Notification, error)
+		CountWithUserID(context.Context, uuid.UUID) (int, error)
+		UnreadCountWithUserID(context.Context, uuid.UUID) (int, error)
+		MarkRead(context.Context, uuid.UUID, time.Time) error
+		MarkAllRead(context.Context, uuid.UUID, time.Time) error
+	}
+
+	Webhook interface {
+		Create(context.Context, domain.Webhook) error
+		Find(context.Context, uuid.UUID) (domain.Webhook, error)
+		FindWithUserID(context.Context, uuid.UUID, uuid.UUID) (domain.Webhook, error)
+		GetAllWithUserID(context.Context, uuid.UUID) ([]domain.Webhook, error)
+		GetAllSubscribed(context.Context, domain.WebhookEvent, uuid.UUID) ([]domain.Webhook, error)
+		Delete(context.Context, uuid.UUID, uuid.UUID) error
+	}
+
+	// WebhookDelivery is claimed before attempt, so delivery is attempted
+	// by single instance at time
+	WebhookDelivery interface {
+		Create(context.Context, domain.WebhookDelivery) error
+		FindWithWebhookID(context.Context, uuid.UUID, uuid.UUID) (domain.WebhookDelivery, error)
+		GetAllWithWebhookID(context.Context, uuid.UUID, int, int) ([]domain.WebhookDelivery, error)
+		CountWithWebhookID(context.Context, uuid.UUID) (int, error)
+		GetAllDue(context.Context, time.Time, int) ([]domain.WebhookDelivery, error)
+		Claim(context.Context, domain.WebhookDelivery, time.Time) (bool, error)
+		Update(context.Context, domain.WebhookDelivery) error
+	}
+
+	// TokenDenylist keeps identifiers of revoked access tokens until they expire,
+	// all tokens of user issued before revocation time are revoked as well
+	TokenDenylist interface {
+		Add(context.Context, string, time.Duration) error
+		Contains(context.Context, string) (bool, error)
+		RevokeBefore(context.Context, uuid.UUID, time.Time, time.Duration) error
+		RevokedBefore(context.Context, uuid.UUID) (time.Time, error)
+	}
+
+	// SignInAttempt counts failed sign in attempts with key of account or
+	// client address, counter is forgotten after window without failures
+	SignInAttempt interface {
+		Fail(context.Context, string, time.Duration) (int64, error)
+		Lock(context.Context, string, time.Time) error
+		LockedUntil(context.Context, string) (time.Time, error)
+		Reset(context.Context, string) error
+	}
+
+	// MFAChallenge keeps nonces of challenges issued after password was
+	// checked, challenge is accepted while its nonce is kept and is spent
+	// by successful verification
+	MFAChallenge interface {
+		Add(context.Context, string, time.Duration) error
+		Contains(context.Context, string) (bool, error)
+		Use(context.Context, string, time.Duration) (bool, error)
 	}
 
 	Repository struct {
 		User
 		Post
+		RefreshToken
+		Session
+		PasswordReset
+		SecurityEvent
+		AccountDeletion
+		Follow
+		AuditLog
+		PersonalAccessToken
+		UserIdentity
+		TOTPFactor
+		RecoveryCode
+		MFAPolicy
+		Notification
+		Webhook
+		WebhookDelivery
 	}
 )
 
 func NewRepository(database database.DatabasePrivoder) *Repository {
 	return &Repository{
-		User: mysql.NewUserRepos(database),
-		Post: mysql.NewPostRepos(database),
+		User:                mysql.NewUserRepos(database),
+		Post:                mysql.NewPostRepos(database),
+		RefreshToken:        mysql.NewRefreshTokenRepos(database),
+		Session:             mysql.NewSessionRepos(database),
+		PasswordReset:       mysql.NewPasswordResetRepos(database),
+		SecurityEvent:       mysql.NewSecurityEventRepos(database),
+		AccountDeletion:     mysql.NewAccountDeletionRepos(database),
+		Follow:              mysql.NewFollowRepos(database),
+		AuditLog:            mysql.NewAuditLogRepos(database),
+		PersonalAccessToken: mysql.NewPersonalAccessTokenRepos(database),
+		UserIdentity:        mysql.NewUserIdentityRepos(database),
+		TOTPFactor:          mysql.NewTOTPFactorRepos(database),
+		RecoveryCode:        mysql.NewRecoveryCodeRepos(database),
+		MFAPolicy:           mysql.NewMFAPolicyRepos(database),
+		Notification:        mysql.NewNotificationRepos(database),
+		Webhook:             mysql.NewWebhookRepos(database),
+		WebhookDelivery:     mysql.NewWebhookDeliveryRepos(database),
 	}
 }
 
@@ -54,3 +250,63 @@ func (r *Repository) UserProvider() User {
 func (r *Repository) PostProvider() Post {
 	return r.Post
 }
+
+func (r *Repository) RefreshTokenProvider() RefreshToken {
+	return r.RefreshToken
+}
+
+func (r *Repository) SessionProvider() Session {
+	return r.Session
+}
+
+func (r *Repository) PasswordResetProvider() PasswordReset {
+	return r.PasswordReset
+}
+
+func (r *Repository) SecurityEventProvider() SecurityEvent {
+	return r.SecurityEvent
+}
+
+func (r *Repository) AccountDeletionProvider() AccountDeletion {
+	return r.AccountDeletion
+}
+
+func (r *Repository) FollowProvider() Follow {
+	return r.Follow
+}
+
+func (r *Repository) AuditLogProvider() AuditLog {
+	return r.AuditLog
+}
+
+func (r *Repository) PersonalAccessTokenProvider() PersonalAccessToken {
+	return r.PersonalAccessToken
+}
+
+func (r *Repository) UserIdentityProvider() UserIdentity {
+	return r.UserIdentity
+}
+
+func (r *Repository) TOTPFactorProvider() TOTPFactor {
+	return r.TOTPFactor
+}
+
+func (r *Repository) RecoveryCodeProvider() RecoveryCode {
+	return r.RecoveryCode
+}
+
+func (r *Repository) MFAPolicyProvider() MFAPolicy {
+	return r.MFAPolicy
+}
+
+func (r *Repository) NotificationProvider() Notification {
+	return r.Notification
+}
+
+func (r *Repository) WebhookProvider() Webhook {
+	return r.Webhook
+}
+
+func (r *Repository) WebhookDeliveryProvider() WebhookDelivery {
+	return r.WebhookDelivery
+}
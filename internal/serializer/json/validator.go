@@ -0,0 +1,23 @@
+package json
+
+import (
+	"encoding/json"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+)
+
+type JsonValidatorSerializer struct{}
+
+func NewValidatorSerializer() *JsonValidatorSerializer {
+	return new(JsonValidatorSerializer)
+}
+
+func (s *JsonValidatorSerializer) Serialize(validator domain.Validator) ([]byte, error) {
+	return json.Marshal(validator)
+}
+
+func (s *JsonValidatorSerializer) Deserialize(value []byte) (domain.Validator, error) {
+	var validator domain.Validator
+	err := json.Unmarshal(value, &validator)
+	return validator, err
+}
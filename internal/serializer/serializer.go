@@ -16,15 +16,22 @@ type (
 		Deserialize([]byte) (domain.Post, error)
 	}
 
+	ValidatorSerializer interface {
+		Serialize(domain.Validator) ([]byte, error)
+		Deserialize([]byte) (domain.Validator, error)
+	}
+
 	Serializer struct {
-		User UserSerializer
-		Post PostSerializer
+		User      UserSerializer
+		Post      PostSerializer
+		Validator ValidatorSerializer
 	}
 )
 
 func NewSerializer() *Serializer {
 	return &Serializer{
-		User: json.NewUserSerializer(),
-		Post: json.NewPostSerializer(),
+		User:      json.NewUserSerializer(),
+		Post:      json.NewPostSerializer(),
+		Validator: json.NewValidatorSerializer(),
 	}
 }
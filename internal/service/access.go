@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/random"
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	// PersonalAccessTokenPrefix tells personal access tokens from access tokens
+	PersonalAccessTokenPrefix string = "sbp_"
+
+	personalAccessTokenSize       int = 20
+	personalAccessTokenPrefixSize int = 8
+
+	// Last usage is tracked with minute precision, so token used
+	// on every request does not update database every time
+	personalAccessTokenTouchInterval time.Duration = time.Minute
+)
+
+type PersonalAccessTokenService struct {
+	repo  repository.PersonalAccessToken
+	users repository.User
+}
+
+func NewPersonalAccessTokenService(repo repository.PersonalAccessToken, users repository.User) *PersonalAccessTokenService {
+	return &PersonalAccessTokenService{repo: repo, users: users}
+}
+
+// Create returns token value only once, only hash of value is stored
+func (s *PersonalAccessTokenService) Create(ctx context.Context, input CreatePersonalAccessTokenInput) (domain.PersonalAccessToken, string, error) {
+	secret, err := random.Token(personalAccessTokenSize)
+	if err != nil {
+		return domain.PersonalAccessToken{}, "", err
+	}
+
+	value := PersonalAccessTokenPrefix + secret
+	token := domain.PersonalAccessToken{
+		ID:        uuid.NewV4(),
+		UserID:    input.UserID,
+		Name:      strings.TrimSpace(input.Name),
+		Prefix:    value[:len(PersonalAccessTokenPrefix)+personalAccessTokenPrefixSize],
+		TokenHash: hashToken(value),
+		Scopes:    input.Scopes,
+		CreatedAt: time.Now(),
+	}
+
+	if err := token.Validate(); err != nil {
+		return domain.PersonalAccessToken{}, "", err
+	}
+
+	if err := s.repo.Create(ctx, token); err != nil {
+		return domain.PersonalAccessToken{}, "", err
+	}
+
+	return token, value, nil
+}
+
+func (s *PersonalAccessTokenService) GetAll(ctx context.Context, userID uuid.UUID) ([]domain.PersonalAccessToken, error) {
+	return s.repo.GetAllWithUserID(ctx, userID)
+}
+
+func (s *PersonalAccessTokenService) Revoke(ctx context.Context, input RevokePersonalAccessTokenInput) error {
+	return s.repo.Delete(ctx, input.ID, input.UserID)
+}
+
+// Authenticate reads roles of user on every request, because personal
+// access tokens are long lived and roles could be changed
+func (s *PersonalAccessTokenService) Authenticate(ctx context.Context, input AuthenticateUserInput) (Identity, error) {
+	if !strings.HasPrefix(input.Token, PersonalAccessTokenPrefix) {
+		return Identity{}, errors.ErrAccessTokenInvalid
+	}
+
+	token, err := s.repo.FindWithHash(ctx, hashToken(input.Token))
+	if err != nil {
+		if err == repoerrors.ErrPersonalAccessTokenNotFound {
+			return Identity{}, errors.ErrAccessTokenInvalid
+		}
+		return Identity{}, err
+	}
+
+	user, err := s.users.Self(ctx, token.UserID)
+	if err != nil {
+		if err == repoerrors.ErrUserNotFound {
+			return Identity{}, errors.ErrAccessTokenInvalid
+		}
+		return Identity{}, err
+	}
+
+	if user.IsSuspended() {
+		return Identity{}, errors.ErrUserSuspended
+	}
+
+	now := time.Now()
+	if !token.LastUsedAt.Valid || now.Sub(token.LastUsedAt.Time) >= personalAccessTokenTouchInterval {
+		if err := s.repo.Touch(ctx, token.ID, now); err != nil {
+			return Identity{}, err
+		}
+		token.LastUsedAt = null.TimeFrom(now)
+	}
+
+	return Identity{
+		UserID:                user.ID,
+		Roles:                 user.Roles,
+		Scopes:                token.Scopes,
+		PersonalAccessTokenID: token.ID,
+	}, nil
+}
@@ -0,0 +1,194 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v4"
+)
+
+type PersonalAccessTokenServiceSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockPersonalAccessTokenRepository *mock_repository.MockPersonalAccessToken
+	MockUserRepository                *mock_repository.MockUser
+
+	CurrentService service.PersonalAccessToken
+}
+
+func TestPersonalAccessTokenServiceSuite(t *testing.T) {
+	suite.Run(t, new(PersonalAccessTokenServiceSuite))
+}
+
+func (s *PersonalAccessTokenServiceSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockPersonalAccessTokenRepository = mock_repository.NewMockPersonalAccessToken(s.Controller)
+	s.MockUserRepository = mock_repository.NewMockUser(s.Controller)
+	s.CurrentService = service.NewPersonalAccessTokenService(s.MockPersonalAccessTokenRepository, s.MockUserRepository)
+}
+
+func (s *PersonalAccessTokenServiceSuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *PersonalAccessTokenServiceSuite) TestCreateMethod() {
+	type MockBehavior func(s *PersonalAccessTokenServiceSuite)
+
+	mockCreateBehavior := func(s *PersonalAccessTokenServiceSuite) {
+		s.MockPersonalAccessTokenRepository.EXPECT().
+			Create(context.Background(), gomock.AssignableToTypeOf(domain.PersonalAccessToken{})).
+			Return(nil).
+			Times(1)
+	}
+
+	methodCases := []struct {
+		Name              string
+		ServiceInput      service.CreatePersonalAccessTokenInput
+		MethodResultError error
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name:         "Success",
+			ServiceInput: service.CreatePersonalAccessTokenInput{UserID: uuid.NewV4(), Name: "ci", Scopes: domain.Scopes{domain.PostsWriteScope}},
+			MockBehavior: mockCreateBehavior,
+		},
+		{
+			Name:              "EmptyName",
+			ServiceInput:      service.CreatePersonalAccessTokenInput{UserID: uuid.NewV4(), Name: " ", Scopes: domain.Scopes{domain.PostsWriteScope}},
+			MethodResultError: domain.ErrPersonalAccessTokenNameEmptyValue,
+		},
+		{
+			Name:              "EmptyScopes",
+			ServiceInput:      service.CreatePersonalAccessTokenInput{UserID: uuid.NewV4(), Name: "ci", Scopes: domain.Scopes{}},
+			MethodResultError: domain.ErrPersonalAccessTokenScopesEmptyValue,
+		},
+		{
+			Name:              "InvalidScope",
+			ServiceInput:      service.CreatePersonalAccessTokenInput{UserID: uuid.NewV4(), Name: "ci", Scopes: domain.Scopes{"posts:admin"}},
+			MethodResultError: domain.ErrPersonalAccessTokenScopesInvalidValue,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			if currentCase.MockBehavior != nil {
+				currentCase.MockBehavior(s)
+			}
+			token, value, err := s.CurrentService.Create(context.Background(), currentCase.ServiceInput)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			if err == nil {
+				s.Assertions.True(strings.HasPrefix(value, service.PersonalAccessTokenPrefix))
+				s.Assertions.True(strings.HasPrefix(value, token.Prefix))
+				s.Assertions.NotEqual(value, token.TokenHash)
+			}
+		})
+	}
+}
+
+func (s *PersonalAccessTokenServiceSuite) TestAuthenticateMethod() {
+	type MockBehavior func(s *PersonalAccessTokenServiceSuite, token domain.PersonalAccessToken, user domain.User)
+
+	mockFindBehavior := func(returns error) MockBehavior {
+		return func(s *PersonalAccessTokenServiceSuite, token domain.PersonalAccessToken, user domain.User) {
+			s.MockPersonalAccessTokenRepository.EXPECT().
+				FindWithHash(context.Background(), gomock.Any()).
+				Return(token, returns).
+				Times(1)
+		}
+	}
+
+	mockUserBehavior := func(s *PersonalAccessTokenServiceSuite, token domain.PersonalAccessToken, user domain.User) {
+		mockFindBehavior(nil)(s, token, user)
+		s.MockUserRepository.EXPECT().
+			Self(context.Background(), token.UserID).
+			Return(user, nil).
+			Times(1)
+	}
+
+	mockTouchBehavior := func(s *PersonalAccessTokenServiceSuite, token domain.PersonalAccessToken, user domain.User) {
+		mockUserBehavior(s, token, user)
+		s.MockPersonalAccessTokenRepository.EXPECT().
+			Touch(context.Background(), token.ID, gomock.AssignableToTypeOf(time.Time{})).
+			Return(nil).
+			Times(1)
+	}
+
+	user := domain.User{Model: domain.Model{ID: uuid.NewV4()}, Roles: domain.Roles{domain.AuthorRole}}
+	token := domain.PersonalAccessToken{ID: uuid.NewV4(), UserID: user.ID, Scopes: domain.Scopes{domain.PostsReadScope}}
+	recentlyUsed := token
+	recentlyUsed.LastUsedAt = null.TimeFrom(time.Now())
+
+	methodCases := []struct {
+		Name              string
+		ServiceInput      service.AuthenticateUserInput
+		Token             domain.PersonalAccessToken
+		User              domain.User
+		MethodResultError error
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name:         "Success",
+			ServiceInput: service.AuthenticateUserInput{Token: "sbp_valid"},
+			Token:        token,
+			User:         user,
+			MockBehavior: mockTouchBehavior,
+		},
+		{
+			Name:         "RecentlyUsed",
+			ServiceInput: service.AuthenticateUserInput{Token: "sbp_valid"},
+			Token:        recentlyUsed,
+			User:         user,
+			MockBehavior: mockUserBehavior,
+		},
+		{
+			Name:              "InvalidPrefix",
+			ServiceInput:      service.AuthenticateUserInput{Token: "access-token"},
+			MethodResultError: serviceerrors.ErrAccessTokenInvalid,
+		},
+		{
+			Name:              "NotFound",
+			ServiceInput:      service.AuthenticateUserInput{Token: "sbp_revoked"},
+			MethodResultError: serviceerrors.ErrAccessTokenInvalid,
+			MockBehavior:      mockFindBehavior(repoerrors.ErrPersonalAccessTokenNotFound),
+		},
+		{
+			Name:              "Suspended",
+			ServiceInput:      service.AuthenticateUserInput{Token: "sbp_valid"},
+			Token:             token,
+			User:              domain.User{Model: domain.Model{ID: user.ID}, SuspendedAt: null.TimeFrom(time.Now())},
+			MethodResultError: serviceerrors.ErrUserSuspended,
+			MockBehavior:      mockUserBehavior,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			if currentCase.MockBehavior != nil {
+				currentCase.MockBehavior(s, currentCase.Token, currentCase.User)
+			}
+			identity, err := s.CurrentService.Authenticate(context.Background(), currentCase.ServiceInput)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			if err == nil {
+				s.Assertions.Equal(user.ID, identity.UserID)
+				s.Assertions.True(identity.IsPersonalAccessToken())
+				s.Assertions.True(identity.HasScope(domain.PostsReadScope))
+				s.Assertions.False(identity.HasScope(domain.PostsWriteScope))
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/hash"
+	"github.com/aintsashqa/go-simple-blog/pkg/logger"
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+const accountPurgeBatchSize int = 100
+
+type AccountService struct {
+	users       repository.User
+	posts       repository.Post
+	deletions   repository.AccountDeletion
+	events      repository.SecurityEvent
+	hasher      hash.HashProvider
+	tokens      Token
+	mfa         MFA
+	logger      logger.Logger
+	gracePeriod time.Duration
+}
+
+func NewAccountService(
+	users repository.User,
+	posts repository.Post,
+	deletions repository.AccountDeletion,
+	events repository.SecurityEvent,
+	hasher hash.HashProvider,
+	tokens Token,
+	mfa MFA,
+	logger logger.Logger,
+	gracePeriod time.Duration,
+) *AccountService {
+	return &AccountService{
+		users:       users,
+		posts:       posts,
+		deletions:   deletions,
+		events:      events,
+		hasher:      hasher,
+		tokens:      tokens,
+		mfa:         mfa,
+		logger:      logger,
+		gracePeriod: gracePeriod,
+	}
+}
+
+// Delete hides user and posts immediately, but posts are handled
+// and account is removed only by purge after grace period
+func (s *AccountService) Delete(ctx context.Context, input DeleteAccountInput) (domain.AccountDeletion, error) {
+	user, err := authorizeUser(ctx, s.users, s.hasher, input.ID, input.Password)
+	if err != nil {
+		return domain.AccountDeletion{}, err
+	}
+
+	if !input.PostsAction.IsValid() {
+		return domain.AccountDeletion{}, errors.ErrPostsActionInvalid
+	}
+
+	now := time.Now()
+	deletion := domain.AccountDeletion{
+		UserID:      user.ID,
+		PostsAction: input.PostsAction,
+		RequestedAt: now,
+		PurgeAt:     now.Add(s.gracePeriod),
+	}
+
+	if input.PostsAction == domain.TransferPostsAction {
+		// Ghost user is author of anonymized posts only
+		if input.TransferTo == uuid.Nil || uuid.Equal(input.TransferTo, user.ID) || uuid.Equal(input.TransferTo, domain.GhostUserID) {
+			return domain.AccountDeletion{}, errors.ErrTransferUserInvalid
+		}
+
+		if _, err := s.users.Find(ctx, input.TransferTo); err != nil {
+			if err == repoerrors.ErrUserNotFound {
+				return domain.AccountDeletion{}, errors.ErrTransferUserInvalid
+			}
+			return domain.AccountDeletion{}, err
+		}
+
+		deletion.TransferTo = uuid.NullUUID{UUID: input.TransferTo, Valid: true}
+	}
+
+	user.DeletedAt = null.NewTime(now, true)
+	user.Update()
+
+	if err := s.users.SoftDelete(ctx, user, deletion); err != nil {
+		return domain.AccountDeletion{}, err
+	}
+
+	// Sessions are already revoked together with deletion, only issued
+	// access tokens are left
+	if err := s.tokens.RevokeAll(ctx, user.ID); err != nil {
+		s.logger.Errorf("service.Account.Delete error: %s", err)
+	}
+
+	err = s.events.Create(ctx, domain.NewSecurityEvent(user.ID, domain.AccountDeletedSecurityEvent))
+	return deletion, err
+}
+
+// Restore cancels deletion during grace period and signs user in
+func (s *AccountService) Restore(ctx context.Context, input RestoreAccountInput) (SignInResult, error) {
+	user, err := s.users.GetDeletedByEmail(ctx, input.Email)
+	if err != nil {
+		return SignInResult{}, err
+	}
+
+	if err := s.hasher.Compare(user.Password, input.Password); err != nil {
+		return SignInResult{}, errors.ErrCurrentPasswordMismatch
+	}
+
+	user.Update()
+	if err := s.users.Restore(ctx, user); err != nil {
+		return SignInResult{}, err
+	}
+
+	if err := s.deletions.Delete(ctx, user.ID); err != nil {
+		return SignInResult{}, err
+	}
+
+	if err := s.events.Create(ctx, domain.NewSecurityEvent(user.ID, domain.AccountRestoredSecurityEvent)); err != nil {
+		return SignInResult{}, err
+	}
+
+	return s.mfa.SignIn(ctx, user, input.Client)
+}
+
+// Purge removes accounts which grace period is over, failed account
+// does not stop others and is retried by next purge
+func (s *AccountService) Purge(ctx context.Context) (int, error) {
+	deletions, err := s.deletions.GetAllDue(ctx, time.Now(), accountPurgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, deletion := range deletions {
+		if err := s.purge(ctx, deletion); err != nil {
+			s.logger.Errorf("service.Account.Purge error: %s", err)
+			continue
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+func (s *AccountService) purge(ctx context.Context, deletion domain.AccountDeletion) error {
+	switch deletion.PostsAction {
+
+	case domain.AnonymizePostsAction:
+		if err := s.posts.TransferAll(ctx, deletion.UserID, domain.GhostUserID); err != nil {
+			return err
+		}
+
+	case domain.TransferPostsAction:
+		// Posts are anonymized when receiver was deleted during grace period
+		receiver := deletion.TransferTo.UUID
+		if _, err := s.users.Find(ctx, receiver); err != nil {
+			if err != repoerrors.ErrUserNotFound {
+				return err
+			}
+			receiver = domain.GhostUserID
+		}
+
+		if err := s.posts.TransferAll(ctx, deletion.UserID, receiver); err != nil {
+			return err
+		}
+
+	}
+
+	// Posts left with user are removed together with user
+	return s.users.Purge(ctx, deletion.UserID)
+}
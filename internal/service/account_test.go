@@ -0,0 +1,267 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	mock_service "github.com/aintsashqa/go-simple-blog/internal/service/mocks"
+	mock_hash "github.com/aintsashqa/go-simple-blog/pkg/hash/mocks"
+	mock_logger "github.com/aintsashqa/go-simple-blog/pkg/logger/mocks"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type AccountServiceSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockUserRepository            *mock_repository.MockUser
+	MockPostRepository            *mock_repository.MockPost
+	MockAccountDeletionRepository *mock_repository.MockAccountDeletion
+	MockSecurityEventRepository   *mock_repository.MockSecurityEvent
+	MockHashProvider              *mock_hash.MockHashProvider
+	MockTokenService              *mock_service.MockToken
+	MockMFAService                *mock_service.MockMFA
+	MockLogger                    *mock_logger.MockLogger
+
+	CurrentService service.Account
+}
+
+func TestAccountServiceSuite(t *testing.T) {
+	suite.Run(t, new(AccountServiceSuite))
+}
+
+func (s *AccountServiceSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockUserRepository = mock_repository.NewMockUser(s.Controller)
+	s.MockPostRepository = mock_repository.NewMockPost(s.Controller)
+	s.MockAccountDeletionRepository = mock_repository.NewMockAccountDeletion(s.Controller)
+	s.MockSecurityEventRepository = mock_repository.NewMockSecurityEvent(s.Controller)
+	s.MockHashProvider = mock_hash.NewMockHashProvider(s.Controller)
+	s.MockTokenService = mock_service.NewMockToken(s.Controller)
+	s.MockMFAService = mock_service.NewMockMFA(s.Controller)
+	s.MockLogger = mock_logger.NewMockLogger(s.Controller)
+	s.CurrentService = service.NewAccountService(
+		s.MockUserRepository,
+		s.MockPostRepository,
+		s.MockAccountDeletionRepository,
+		s.MockSecurityEventRepository,
+		s.MockHashProvider,
+		s.MockTokenService,
+		s.MockMFAService,
+		s.MockLogger,
+		24*time.Hour,
+	)
+}
+
+func (s *AccountServiceSuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *AccountServiceSuite) TestDeleteMethod() {
+	type MockBehavior func(s *AccountServiceSuite, input service.DeleteAccountInput)
+
+	user := domain.User{Model: domain.Model{ID: uuid.NewV4(), Version: 1}, Email: "root@example.com", Password: "secret-hash"}
+	receiver := uuid.NewV4()
+
+	mockDeletedBehavior := func(s *AccountServiceSuite, input service.DeleteAccountInput) {
+		s.MockUserRepository.EXPECT().
+			SoftDelete(context.Background(), gomock.AssignableToTypeOf(domain.User{}), gomock.AssignableToTypeOf(domain.AccountDeletion{})).
+			DoAndReturn(func(_ context.Context, deleted domain.User, deletion domain.AccountDeletion) error {
+				s.Assertions.True(deleted.DeletedAt.Valid)
+				s.Assertions.Equal(deleted.ID, deletion.UserID)
+				return nil
+			}).
+			Times(1)
+		s.MockTokenService.EXPECT().
+			RevokeAll(context.Background(), user.ID).
+			Return(nil).
+			Times(1)
+		s.MockSecurityEventRepository.EXPECT().
+			Create(context.Background(), gomock.AssignableToTypeOf(domain.SecurityEvent{})).
+			Return(nil).
+			Times(1)
+	}
+
+	mockReceiverBehavior := func(returns error) MockBehavior {
+		return func(s *AccountServiceSuite, input service.DeleteAccountInput) {
+			s.MockUserRepository.EXPECT().
+				Find(context.Background(), input.TransferTo).
+				Return(domain.User{}, returns).
+				Times(1)
+			if returns == nil {
+				mockDeletedBehavior(s, input)
+			}
+		}
+	}
+
+	methodCases := []struct {
+		Name              string
+		ServiceInput      service.DeleteAccountInput
+		MethodResultError error
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name:         "Anonymize",
+			ServiceInput: service.DeleteAccountInput{ID: user.ID, Password: "secret", PostsAction: domain.AnonymizePostsAction},
+			MockBehavior: mockDeletedBehavior,
+		},
+		{
+			Name:         "Transfer",
+			ServiceInput: service.DeleteAccountInput{ID: user.ID, Password: "secret", PostsAction: domain.TransferPostsAction, TransferTo: receiver},
+			MockBehavior: mockReceiverBehavior(nil),
+		},
+		{
+			Name:              "TransferToUnknownUser",
+			ServiceInput:      service.DeleteAccountInput{ID: user.ID, Password: "secret", PostsAction: domain.TransferPostsAction, TransferTo: receiver},
+			MethodResultError: serviceerrors.ErrTransferUserInvalid,
+			MockBehavior:      mockReceiverBehavior(repoerrors.ErrUserNotFound),
+		},
+		{
+			Name:              "TransferToSelf",
+			ServiceInput:      service.DeleteAccountInput{ID: user.ID, Password: "secret", PostsAction: domain.TransferPostsAction, TransferTo: user.ID},
+			MethodResultError: serviceerrors.ErrTransferUserInvalid,
+		},
+		{
+			Name:              "TransferToGhost",
+			ServiceInput:      service.DeleteAccountInput{ID: user.ID, Password: "secret", PostsAction: domain.TransferPostsAction, TransferTo: domain.GhostUserID},
+			MethodResultError: serviceerrors.ErrTransferUserInvalid,
+		},
+		{
+			Name:              "InvalidPostsAction",
+			ServiceInput:      service.DeleteAccountInput{ID: user.ID, Password: "secret", PostsAction: "archive"},
+			MethodResultError: serviceerrors.ErrPostsActionInvalid,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			s.MockUserRepository.EXPECT().
+				Self(context.Background(), user.ID).
+				Return(domain.User{Model: user.Model, Email: user.Email}, nil).
+				Times(1)
+			s.MockUserRepository.EXPECT().
+				GetByEmail(context.Background(), user.Email).
+				Return(user, nil).
+				Times(1)
+			s.MockHashProvider.EXPECT().
+				Compare(user.Password, currentCase.ServiceInput.Password).
+				Return(nil).
+				Times(1)
+			if currentCase.MockBehavior != nil {
+				currentCase.MockBehavior(s, currentCase.ServiceInput)
+			}
+			result, err := s.CurrentService.Delete(context.Background(), currentCase.ServiceInput)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			if err == nil {
+				s.Assertions.Equal(currentCase.ServiceInput.PostsAction, result.PostsAction)
+				s.Assertions.True(result.PurgeAt.After(result.RequestedAt))
+			}
+		})
+	}
+}
+
+func (s *AccountServiceSuite) TestPurgeMethod() {
+	type MockBehavior func(s *AccountServiceSuite, deletion domain.AccountDeletion)
+
+	repositoryResultError := errors.New("RepositoryResultError")
+	receiver := uuid.NewV4()
+
+	mockPurgeBehavior := func(s *AccountServiceSuite, deletion domain.AccountDeletion) {
+		s.MockUserRepository.EXPECT().
+			Purge(context.Background(), deletion.UserID).
+			Return(nil).
+			Times(1)
+	}
+
+	mockTransferBehavior := func(to uuid.UUID) MockBehavior {
+		return func(s *AccountServiceSuite, deletion domain.AccountDeletion) {
+			s.MockPostRepository.EXPECT().
+				TransferAll(context.Background(), deletion.UserID, to).
+				Return(nil).
+				Times(1)
+			mockPurgeBehavior(s, deletion)
+		}
+	}
+
+	mockReceiverBehavior := func(returns error, to uuid.UUID) MockBehavior {
+		return func(s *AccountServiceSuite, deletion domain.AccountDeletion) {
+			s.MockUserRepository.EXPECT().
+				Find(context.Background(), deletion.TransferTo.UUID).
+				Return(domain.User{}, returns).
+				Times(1)
+			mockTransferBehavior(to)(s, deletion)
+		}
+	}
+
+	methodCases := []struct {
+		Name              string
+		Deletion          domain.AccountDeletion
+		MethodResultValue int
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name:              "Delete",
+			Deletion:          domain.AccountDeletion{UserID: uuid.NewV4(), PostsAction: domain.DeletePostsAction},
+			MethodResultValue: 1,
+			MockBehavior:      mockPurgeBehavior,
+		},
+		{
+			Name:              "Anonymize",
+			Deletion:          domain.AccountDeletion{UserID: uuid.NewV4(), PostsAction: domain.AnonymizePostsAction},
+			MethodResultValue: 1,
+			MockBehavior:      mockTransferBehavior(domain.GhostUserID),
+		},
+		{
+			Name:              "Transfer",
+			Deletion:          domain.AccountDeletion{UserID: uuid.NewV4(), PostsAction: domain.TransferPostsAction, TransferTo: uuid.NullUUID{UUID: receiver, Valid: true}},
+			MethodResultValue: 1,
+			MockBehavior:      mockReceiverBehavior(nil, receiver),
+		},
+		{
+			Name:              "TransferToDeletedUser",
+			Deletion:          domain.AccountDeletion{UserID: uuid.NewV4(), PostsAction: domain.TransferPostsAction, TransferTo: uuid.NullUUID{UUID: receiver, Valid: true}},
+			MethodResultValue: 1,
+			MockBehavior:      mockReceiverBehavior(repoerrors.ErrUserNotFound, domain.GhostUserID),
+		},
+		{
+			Name:              "RepositoryFailure",
+			Deletion:          domain.AccountDeletion{UserID: uuid.NewV4(), PostsAction: domain.AnonymizePostsAction},
+			MethodResultValue: 0,
+			MockBehavior: func(s *AccountServiceSuite, deletion domain.AccountDeletion) {
+				s.MockPostRepository.EXPECT().
+					TransferAll(context.Background(), deletion.UserID, domain.GhostUserID).
+					Return(repositoryResultError).
+					Times(1)
+				s.MockLogger.EXPECT().
+					Errorf(gomock.Any(), repositoryResultError).
+					Times(1)
+			},
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			s.MockAccountDeletionRepository.EXPECT().
+				GetAllDue(context.Background(), gomock.Any(), gomock.Any()).
+				Return([]domain.AccountDeletion{currentCase.Deletion}, nil).
+				Times(1)
+			currentCase.MockBehavior(s, currentCase.Deletion)
+			result, err := s.CurrentService.Purge(context.Background())
+			s.Assertions.NoError(err)
+			s.Assertions.Equal(currentCase.MethodResultValue, result)
+		})
+	}
+}
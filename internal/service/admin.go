@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+type AdminService struct {
+	users    repository.User
+	posts    repository.Post
+	audit    repository.AuditLog
+	policies repository.MFAPolicy
+	notifier Notifier
+	webhooks WebhookDispatcher
+	tokens   Token
+}
+
+func NewAdminService(users repository.User, posts repository.Post, audit repository.AuditLog, policies repository.MFAPolicy, notifier Notifier, webhooks WebhookDispatcher, tokens Token) *AdminService {
+	return &AdminService{users: users, posts: posts, audit: audit, policies: policies, notifier: notifier, webhooks: webhooks, tokens: tokens}
+}
+
+func (s *AdminService) SearchUsers(ctx context.Context, opt SearchUsersOptions) (UserPagination, error) {
+	users, err := s.users.Search(ctx, opt.Query, opt.IncludeDeleted, pageOffset(opt.CurrentPage, opt.UsersPerPage), opt.UsersPerPage)
+	if err != nil {
+		return UserPagination{}, err
+	}
+
+	count, err := s.users.SearchCount(ctx, opt.Query, opt.IncludeDeleted)
+	if err != nil {
+		return UserPagination{}, err
+	}
+
+	return newUserPagination(users, count, PaginateFollowOptions{CurrentPage: opt.CurrentPage, UsersPerPage: opt.UsersPerPage}), nil
+}
+
+// Suspend signs user out from all sessions, suspended user could not
+// sign in until unsuspended
+func (s *AdminService) Suspend(ctx context.Context, input ModerateUserInput) (domain.User, error) {
+	if uuid.Equal(input.ActorID, input.UserID) {
+		return domain.User{}, errors.ErrSuspendSelf
+	}
+
+	user, err := s.users.Self(ctx, input.UserID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if user.IsSuspended() {
+		return user, nil
+	}
+
+	user.SuspendedAt = null.NewTime(time.Now(), true)
+	user.Update()
+
+	if err := s.users.UpdateSuspension(ctx, user); err != nil {
+		return domain.User{}, err
+	}
+
+	if err := s.tokens.RevokeAll(ctx, user.ID); err != nil {
+		return domain.User{}, err
+	}
+
+	return user, s.audit.Create(ctx, domain.NewAuditLog(input.ActorID, domain.UserSuspendedAuditAction, domain.UserAuditTarget, user.ID))
+}
+
+func (s *AdminService) Unsuspend(ctx context.Context, input ModerateUserInput) (domain.User, error) {
+	user, err := s.users.Self(ctx, input.UserID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if !user.IsSuspended() {
+		return user, nil
+	}
+
+	user.SuspendedAt = null.NewTime(time.Time{}, false)
+	user.Update()
+
+	if err := s.users.UpdateSuspension(ctx, user); err != nil {
+		return domain.User{}, err
+	}
+
+	return user, s.audit.Create(ctx, domain.NewAuditLog(input.ActorID, domain.UserUnsuspendedAuditAction, domain.UserAuditTarget, user.ID))
+}
+
+// UnpublishPost hides post of any user, post stays available for its author
+func (s *AdminService) UnpublishPost(ctx context.Context, input ModeratePostInput) (domain.Post, error) {
+	post, err := s.posts.FindWithDeleted(ctx, input.PostID)
+	if err != nil {
+		return domain.Post{}, err
+	}
+
+	if post.DeletedAt.Valid {
+		return domain.Post{}, repoerrors.ErrPostNotFound
+	}
+
+	post.PublishedAt = null.NewTime(time.Now(), false)
+	post.Update()
+
+	// Only publication is changed, so edits made by author meanwhile are kept
+	unpublished, err := s.posts.Unpublish(ctx, post)
+	if err != nil {
+		return domain.Post{}, err
+	}
+
+	if unpublished {
+		s.notifier.PostUnpublished(ctx, post, input.ActorID)
+	}
+
+	return post, s.audit.Create(ctx, domain.NewAuditLog(input.ActorID, domain.PostUnpublishedAuditAction, domain.PostAuditTarget, post.ID))
+}
+
+func (s *AdminService) DeletePost(ctx context.Context, input ModeratePostInput) error {
+	post, err := s.posts.FindWithDeleted(ctx, input.PostID)
+	if err != nil {
+		return err
+	}
+
+	if post.DeletedAt.Valid {
+		return errors.ErrPostAlreadyDeleted
+	}
+
+	post.Delete()
+
+	if err := s.posts.SoftDelete(ctx, post); err != nil {
+		if err == repoerrors.ErrPostNotFound {
+			return errors.ErrPostAlreadyDeleted
+		}
+		return err
+	}
+
+	s.webhooks.Dispatch(ctx, domain.PostDeletedWebhookEvent, post.UserID, post)
+	return s.audit.Create(ctx, domain.NewAuditLog(input.ActorID, domain.PostDeletedAuditAction, domain.PostAuditTarget, post.ID))
+}
+
+func (s *AdminService) DeletedPosts(ctx context.Context, opt PaginatePostOptions) (PostPagination, error) {
+	posts, err := s.posts.GetAllDeleted(ctx, pageOffset(opt.CurrentPage, opt.PostsPerPage), opt.PostsPerPage)
+	if err != nil {
+		return PostPagination{}, err
+	}
+
+	count, err := s.posts.AllDeletedCount(ctx)
+	if err != nil {
+		return PostPagination{}, err
+	}
+
+	previousPage, nextPage := pages(opt.CurrentPage, opt.PostsPerPage, count)
+
+	return PostPagination{
+		Posts:        posts,
+		PostsCount:   count,
+		PreviousPage: previousPage,
+		CurrentPage:  opt.CurrentPage,
+		NextPage:     nextPage,
+		PostsPerPage: opt.PostsPerPage,
+	}, nil
+}
+
+func (s *AdminService) MFARoles(ctx context.Context) (domain.Roles, error) {
+	return s.policies.GetRequiredRoles(ctx)
+}
+
+// SetMFARoles replaces roles which require second factor, users of these
+// roles must enroll factor on next sign in
+func (s *AdminService) SetMFARoles(ctx context.Context, input SetMFARolesInput) (domain.Roles, error) {
+	roles := domain.Roles{}
+	for _, role := range input.Roles {
+		if !role.IsValid() {
+			return nil, errors.ErrMFARolesInvalid
+		}
+		if !roles.HasAny(role) {
+			roles = append(roles, role)
+		}
+	}
+
+	if err := s.policies.SetRequiredRoles(ctx, roles); err != nil {
+		return nil, err
+	}
+
+	return roles, s.audit.Create(ctx, domain.NewAuditLog(input.ActorID, domain.MFARolesChangedAuditAction, domain.SettingsAuditTarget, uuid.Nil))
+}
@@ -0,0 +1,300 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	mock_service "github.com/aintsashqa/go-simple-blog/internal/service/mocks"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v4"
+)
+
+type AdminServiceSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockUserRepository      *mock_repository.MockUser
+	MockPostRepository      *mock_repository.MockPost
+	MockAuditLogRepository  *mock_repository.MockAuditLog
+	MockMFAPolicyRepository *mock_repository.MockMFAPolicy
+	MockNotifier            *mock_service.MockNotifier
+	MockWebhookDispatcher   *mock_service.MockWebhookDispatcher
+	MockTokenService        *mock_service.MockToken
+
+	CurrentService service.Admin
+}
+
+func TestAdminServiceSuite(t *testing.T) {
+	suite.Run(t, new(AdminServiceSuite))
+}
+
+func (s *AdminServiceSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockUserRepository = mock_repository.NewMockUser(s.Controller)
+	s.MockPostRepository = mock_repository.NewMockPost(s.Controller)
+	s.MockAuditLogRepository = mock_repository.NewMockAuditLog(s.Controller)
+	s.MockMFAPolicyRepository = mock_repository.NewMockMFAPolicy(s.Controller)
+	s.MockNotifier = mock_service.NewMockNotifier(s.Controller)
+	s.MockWebhookDispatcher = mock_service.NewMockWebhookDispatcher(s.Controller)
+	s.MockTokenService = mock_service.NewMockToken(s.Controller)
+	s.CurrentService = service.NewAdminService(s.MockUserRepository, s.MockPostRepository, s.MockAuditLogRepository, s.MockMFAPolicyRepository, s.MockNotifier, s.MockWebhookDispatcher, s.MockTokenService)
+}
+
+func (s *AdminServiceSuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *AdminServiceSuite) expectAuditLog(action domain.AuditAction, targetID uuid.UUID) {
+	s.MockAuditLogRepository.EXPECT().
+		Create(context.Background(), gomock.AssignableToTypeOf(domain.AuditLog{})).
+		DoAndReturn(func(_ context.Context, log domain.AuditLog) error {
+			s.Assertions.Equal(action, log.Action)
+			s.Assertions.Equal(targetID, log.TargetID)
+			return nil
+		}).
+		Times(1)
+}
+
+func (s *AdminServiceSuite) TestSuspendMethod() {
+	type MockBehavior func(s *AdminServiceSuite, input service.ModerateUserInput)
+
+	actor := uuid.NewV4()
+
+	mockSelfBehavior := func(user domain.User, returns error) MockBehavior {
+		return func(s *AdminServiceSuite, input service.ModerateUserInput) {
+			s.MockUserRepository.EXPECT().
+				Self(context.Background(), input.UserID).
+				Return(user, returns).
+				Times(1)
+		}
+	}
+
+	mockSuspendBehavior := func(s *AdminServiceSuite, input service.ModerateUserInput) {
+		mockSelfBehavior(domain.User{Model: domain.Model{ID: input.UserID}}, nil)(s, input)
+		s.MockUserRepository.EXPECT().
+			UpdateSuspension(context.Background(), gomock.AssignableToTypeOf(domain.User{})).
+			DoAndReturn(func(_ context.Context, user domain.User) error {
+				s.Assertions.True(user.IsSuspended())
+				return nil
+			}).
+			Times(1)
+		s.MockTokenService.EXPECT().
+			RevokeAll(context.Background(), input.UserID).
+			Return(nil).
+			Times(1)
+		s.expectAuditLog(domain.UserSuspendedAuditAction, input.UserID)
+	}
+
+	methodCases := []struct {
+		Name              string
+		ServiceInput      service.ModerateUserInput
+		MethodResultError error
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name:         "Success",
+			ServiceInput: service.ModerateUserInput{ActorID: actor, UserID: uuid.NewV4()},
+			MockBehavior: mockSuspendBehavior,
+		},
+		{
+			Name:              "Self",
+			ServiceInput:      service.ModerateUserInput{ActorID: actor, UserID: actor},
+			MethodResultError: serviceerrors.ErrSuspendSelf,
+		},
+		{
+			Name:         "AlreadySuspended",
+			ServiceInput: service.ModerateUserInput{ActorID: actor, UserID: uuid.NewV4()},
+			MockBehavior: mockSelfBehavior(domain.User{SuspendedAt: null.NewTime(time.Now(), true)}, nil),
+		},
+		{
+			Name:              "UserNotFound",
+			ServiceInput:      service.ModerateUserInput{ActorID: actor, UserID: uuid.NewV4()},
+			MethodResultError: repoerrors.ErrUserNotFound,
+			MockBehavior:      mockSelfBehavior(domain.User{}, repoerrors.ErrUserNotFound),
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			if currentCase.MockBehavior != nil {
+				currentCase.MockBehavior(s, currentCase.ServiceInput)
+			}
+			user, err := s.CurrentService.Suspend(context.Background(), currentCase.ServiceInput)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			if err == nil {
+				s.Assertions.True(user.IsSuspended())
+			}
+		})
+	}
+}
+
+func (s *AdminServiceSuite) TestUnsuspendMethod() {
+	input := service.ModerateUserInput{ActorID: uuid.NewV4(), UserID: uuid.NewV4()}
+
+	s.MockUserRepository.EXPECT().
+		Self(context.Background(), input.UserID).
+		Return(domain.User{Model: domain.Model{ID: input.UserID}, SuspendedAt: null.NewTime(time.Now(), true)}, nil).
+		Times(1)
+	s.MockUserRepository.EXPECT().
+		UpdateSuspension(context.Background(), gomock.AssignableToTypeOf(domain.User{})).
+		Return(nil).
+		Times(1)
+	s.expectAuditLog(domain.UserUnsuspendedAuditAction, input.UserID)
+
+	user, err := s.CurrentService.Unsuspend(context.Background(), input)
+	s.Assertions.NoError(err)
+	s.Assertions.False(user.IsSuspended())
+}
+
+func (s *AdminServiceSuite) TestUnpublishPostMethod() {
+	type MockBehavior func(s *AdminServiceSuite, input service.ModeratePostInput)
+
+	mockFindBehavior := func(post domain.Post, returns error) MockBehavior {
+		return func(s *AdminServiceSuite, input service.ModeratePostInput) {
+			s.MockPostRepository.EXPECT().
+				FindWithDeleted(context.Background(), input.PostID).
+				Return(post, returns).
+				Times(1)
+		}
+	}
+
+	mockUnpublishBehavior := func(published bool) MockBehavior {
+		return func(s *AdminServiceSuite, input service.ModeratePostInput) {
+			mockFindBehavior(domain.Post{Model: domain.Model{ID: input.PostID}, PublishedAt: null.NewTime(time.Now(), published)}, nil)(s, input)
+			s.MockPostRepository.EXPECT().
+				Unpublish(context.Background(), gomock.AssignableToTypeOf(domain.Post{})).
+				Return(published, nil).
+				Times(1)
+			if published {
+				s.MockNotifier.EXPECT().
+					PostUnpublished(context.Background(), gomock.AssignableToTypeOf(domain.Post{}), input.ActorID).
+					Times(1)
+			}
+			s.expectAuditLog(domain.PostUnpublishedAuditAction, input.PostID)
+		}
+	}
+
+	methodCases := []struct {
+		Name              string
+		ServiceInput      service.ModeratePostInput
+		MethodResultError error
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name:         "Success",
+			ServiceInput: service.ModeratePostInput{ActorID: uuid.NewV4(), PostID: uuid.NewV4()},
+			MockBehavior: mockUnpublishBehavior(true),
+		},
+		{
+			Name:         "NotPublished",
+			ServiceInput: service.ModeratePostInput{ActorID: uuid.NewV4(), PostID: uuid.NewV4()},
+			MockBehavior: mockUnpublishBehavior(false),
+		},
+		{
+			Name:              "Deleted",
+			ServiceInput:      service.ModeratePostInput{ActorID: uuid.NewV4(), PostID: uuid.NewV4()},
+			MethodResultError: repoerrors.ErrPostNotFound,
+			MockBehavior:      mockFindBehavior(domain.Post{Model: domain.Model{DeletedAt: null.NewTime(time.Now(), true)}}, nil),
+		},
+		{
+			Name:              "NotFound",
+			ServiceInput:      service.ModeratePostInput{ActorID: uuid.NewV4(), PostID: uuid.NewV4()},
+			MethodResultError: repoerrors.ErrPostNotFound,
+			MockBehavior:      mockFindBehavior(domain.Post{}, repoerrors.ErrPostNotFound),
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			currentCase.MockBehavior(s, currentCase.ServiceInput)
+			post, err := s.CurrentService.UnpublishPost(context.Background(), currentCase.ServiceInput)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			if err == nil {
+				s.Assertions.False(post.PublishedAt.Valid)
+			}
+		})
+	}
+}
+
+func (s *AdminServiceSuite) TestDeletePostMethod() {
+	input := service.ModeratePostInput{ActorID: uuid.NewV4(), PostID: uuid.NewV4()}
+
+	s.MockPostRepository.EXPECT().
+		FindWithDeleted(context.Background(), input.PostID).
+		Return(domain.Post{Model: domain.Model{ID: input.PostID}}, nil).
+		Times(1)
+	s.MockPostRepository.EXPECT().
+		SoftDelete(context.Background(), gomock.AssignableToTypeOf(domain.Post{})).
+		DoAndReturn(func(_ context.Context, post domain.Post) error {
+			s.Assertions.True(post.DeletedAt.Valid)
+			return nil
+		}).
+		Times(1)
+	s.MockWebhookDispatcher.EXPECT().
+		Dispatch(context.Background(), domain.PostDeletedWebhookEvent, uuid.Nil, gomock.AssignableToTypeOf(domain.Post{})).
+		Times(1)
+	s.expectAuditLog(domain.PostDeletedAuditAction, input.PostID)
+
+	s.Assertions.NoError(s.CurrentService.DeletePost(context.Background(), input))
+}
+
+func (s *AdminServiceSuite) TestSetMFARolesMethod() {
+	type MockBehavior func(s *AdminServiceSuite, roles domain.Roles)
+
+	mockSetBehavior := func(s *AdminServiceSuite, roles domain.Roles) {
+		s.MockMFAPolicyRepository.EXPECT().
+			SetRequiredRoles(context.Background(), roles).
+			Return(nil).
+			Times(1)
+		s.expectAuditLog(domain.MFARolesChangedAuditAction, uuid.Nil)
+	}
+
+	methodCases := []struct {
+		Name              string
+		ServiceInput      service.SetMFARolesInput
+		MethodResultValue domain.Roles
+		MethodResultError error
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name:              "Success",
+			ServiceInput:      service.SetMFARolesInput{ActorID: uuid.NewV4(), Roles: domain.Roles{domain.AdminRole, domain.EditorRole, domain.AdminRole}},
+			MethodResultValue: domain.Roles{domain.AdminRole, domain.EditorRole},
+			MockBehavior:      mockSetBehavior,
+		},
+		{
+			Name:              "Empty",
+			ServiceInput:      service.SetMFARolesInput{ActorID: uuid.NewV4()},
+			MethodResultValue: domain.Roles{},
+			MockBehavior:      mockSetBehavior,
+		},
+		{
+			Name:              "InvalidRole",
+			ServiceInput:      service.SetMFARolesInput{ActorID: uuid.NewV4(), Roles: domain.Roles{"owner"}},
+			MethodResultError: serviceerrors.ErrMFARolesInvalid,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			if currentCase.MockBehavior != nil {
+				currentCase.MockBehavior(s, currentCase.MethodResultValue)
+			}
+			roles, err := s.CurrentService.SetMFARoles(context.Background(), currentCase.ServiceInput)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			s.Assertions.Equal(currentCase.MethodResultValue, roles)
+		})
+	}
+}
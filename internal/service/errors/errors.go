@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"errors"
+)
+
+var (
+	ErrVersionRequired error = errors.New("Header `If-Match` or field version is required")
+	ErrETagMismatch    error = errors.New("Header `If-Match` does not match current version")
+	ErrVersionMismatch error = errors.New("Field version does not match current version")
+
+	ErrAccessTokenInvalid  error = errors.New("Access token is invalid")
+	ErrAccessTokenRevoked  error = errors.New("Access token is revoked")
+	ErrRefreshTokenInvalid error = errors.New("Refresh token is invalid")
+	ErrRefreshTokenExpired error = errors.New("Refresh token is expired")
+	ErrRefreshTokenReused  error = errors.New("Refresh token was already used, all tokens of session are revoked")
+
+	ErrVerificationTokenInvalid error = errors.New("Verification token is invalid")
+	ErrVerificationTokenExpired error = errors.New("Verification token is expired")
+	ErrEmailNotVerified         error = errors.New("Email address must be verified")
+	ErrEmailAlreadyTaken        error = errors.New("Email address is already taken")
+	ErrUsernameAlreadyTaken     error = errors.New("Username is already taken")
+	ErrCurrentPasswordMismatch  error = errors.New("Current password does not match")
+	ErrUserSuspended            error = errors.New("User is suspended")
+	ErrInvalidCredentials       error = errors.New("Email or password is invalid")
+	ErrSignInLocked             error = errors.New("Too many failed sign in attempts, try again later")
+	ErrSuspendSelf              error = errors.New("Admins could not suspend themselves")
+
+	ErrOIDCDisabled           error = errors.New("OpenID Connect login is not configured")
+	ErrOIDCStateInvalid       error = errors.New("OpenID Connect login state is invalid or expired")
+	ErrOIDCEmailNotVerified   error = errors.New("Email address of external identity must be verified")
+	ErrOIDCAccountNotVerified error = errors.New("Account with same email address must be verified before linking")
+
+	ErrMFAChallengeInvalid error = errors.New("Two-factor challenge is invalid or expired")
+	ErrMFACodeInvalid      error = errors.New("Two-factor code is invalid")
+	ErrMFAAlreadyEnabled   error = errors.New("Two-factor authentication is already enabled")
+	ErrMFANotEnabled       error = errors.New("Two-factor authentication is not enabled")
+	ErrMFARequired         error = errors.New("Two-factor authentication is required for role of user")
+	ErrMFARolesInvalid     error = errors.New("Field roles must contain admin, editor, author or reader.")
+
+	ErrPasswordResetTokenInvalid error = errors.New("Password reset token is invalid")
+	ErrPasswordResetTokenExpired error = errors.New("Password reset token is expired")
+
+	ErrPostsActionInvalid  error = errors.New("Field posts must be one of delete, anonymize or transfer.")
+	ErrTransferUserInvalid error = errors.New("Field transfer_to must be id of another existing user.")
+
+	ErrFollowSelf        error = errors.New("Users could not follow themselves")
+	ErrFeedCursorInvalid error = errors.New("Feed cursor is invalid")
+
+	ErrExportNotFound error = errors.New("Export not found")
+	ErrExportNotReady error = errors.New("Export is not ready yet")
+	ErrExportBusy     error = errors.New("Too many exports are in progress, try again later")
+
+	ErrBulkPostActionInvalid  error = errors.New("Field action must be one of publish, unpublish, delete, restore or retag.")
+	ErrBulkPostIDsInvalidSize error = errors.New("Field ids must contain greater than 1 and less 100 values.")
+	ErrPostAlreadyDeleted     error = errors.New("Post is already deleted")
+	ErrPostNotDeleted         error = errors.New("Post is not deleted")
+)
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/markdown"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	"github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/logger"
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	exportBatchSize       int    = 100
+	exportFileExtension   string = ".zip"
+	exportPartFileSuffix  string = ".part"
+	exportProfileFilename string = "profile.json"
+	exportManifestVersion int    = 1
+)
+
+type (
+	exportManifest struct {
+		Version        int                  `json:"version"`
+		UserID         uuid.UUID            `json:"user_id"`
+		ExportedAt     time.Time            `json:"exported_at"`
+		IncludeDeleted bool                 `json:"include_deleted"`
+		Profile        string               `json:"profile"`
+		Posts          []exportManifestPost `json:"posts"`
+	}
+
+	exportManifestPost struct {
+		ID          uuid.UUID `json:"id"`
+		File        string    `json:"file"`
+		Title       string    `json:"title"`
+		Slug        string    `json:"slug"`
+		CreatedAt   time.Time `json:"created_at"`
+		UpdatedAt   time.Time `json:"updated_at"`
+		PublishedAt null.Time `json:"published_at"`
+		DeletedAt   null.Time `json:"deleted_at"`
+	}
+)
+
+type ExportService struct {
+	users      repository.User
+	posts      repository.Post
+	logger     logger.Logger
+	directory  string
+	postsLimit int
+	workers    chan struct{}
+	retention  time.Duration
+}
+
+func NewExportService(
+	users repository.User,
+	posts repository.Post,
+	logger logger.Logger,
+	directory string,
+	postsLimit int,
+	workers int,
+	retention time.Duration,
+) *ExportService {
+	return &ExportService{
+		users:      users,
+		posts:      posts,
+		logger:     logger,
+		directory:  directory,
+		postsLimit: postsLimit,
+		workers:    make(chan struct{}, workers),
+		retention:  retention,
+	}
+}
+
+func (s *ExportService) IsLarge(ctx context.Context, input ExportInput) (bool, error) {
+	count, err := s.posts.TotalCountWithUserID(ctx, input.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	return count > s.postsLimit, nil
+}
+
+// Write streams zip archive with profile, posts and manifest
+func (s *ExportService) Write(ctx context.Context, w io.Writer, input ExportInput) error {
+	user, err := s.users.Self(ctx, input.UserID)
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(w)
+	manifest := exportManifest{
+		Version:        exportManifestVersion,
+		UserID:         user.ID,
+		ExportedAt:     time.Now(),
+		IncludeDeleted: input.IncludeDeleted,
+		Profile:        exportProfileFilename,
+		Posts:          []exportManifestPost{},
+	}
+
+	if err := s.writeJSON(archive, exportProfileFilename, user); err != nil {
+		return err
+	}
+
+	for offset := 0; ; offset += exportBatchSize {
+		posts, err := s.posts.GetAllWithUserID(ctx, input.UserID, offset, exportBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, post := range posts {
+			if post.DeletedAt.Valid && !input.IncludeDeleted {
+				continue
+			}
+
+			// Slugs of deleted posts could be reused, so id keeps names unique
+			filename := fmt.Sprintf("posts/%s-%s.md", post.Slug, post.ID)
+			if err := s.writePost(archive, filename, post); err != nil {
+				return err
+			}
+
+			manifest.Posts = append(manifest.Posts, exportManifestPost{
+				ID:          post.ID,
+				File:        filename,
+				Title:       post.Title,
+				Slug:        post.Slug,
+				CreatedAt:   post.CreatedAt,
+				UpdatedAt:   post.UpdatedAt,
+				PublishedAt: post.PublishedAt,
+				DeletedAt:   post.DeletedAt,
+			})
+		}
+
+		if len(posts) < exportBatchSize {
+			break
+		}
+	}
+
+	if err := s.writeJSON(archive, "manifest.json", manifest); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// Schedule writes export archive in background and returns its id,
+// archive could be downloaded with Open when it is ready. Count of
+// archives written at same time is limited by workers
+func (s *ExportService) Schedule(ctx context.Context, input ExportInput) (string, error) {
+	select {
+	case s.workers <- struct{}{}:
+	default:
+		return "", errors.ErrExportBusy
+	}
+
+	id := uuid.NewV4().String()
+
+	if err := os.MkdirAll(s.userDirectory(input.UserID), os.ModePerm); err != nil {
+		<-s.workers
+		return "", err
+	}
+
+	filename := s.filename(input.UserID, id)
+	file, err := os.Create(filename + exportPartFileSuffix)
+	if err != nil {
+		<-s.workers
+		return "", err
+	}
+
+	go func() {
+		defer func() { <-s.workers }()
+
+		if err := s.writeFile(file, input); err != nil {
+			s.logger.Errorf("service.ExportService.Schedule error: %s", err)
+			os.Remove(file.Name())
+			return
+		}
+
+		if err := os.Rename(file.Name(), filename); err != nil {
+			s.logger.Errorf("service.ExportService.Schedule error: %s", err)
+		}
+	}()
+
+	return id, nil
+}
+
+func (s *ExportService) Open(ctx context.Context, userID uuid.UUID, id string) (io.ReadCloser, error) {
+	if _, err := uuid.FromString(id); err != nil {
+		return nil, errors.ErrExportNotFound
+	}
+
+	filename := s.filename(userID, id)
+	file, err := os.Open(filename)
+	if err == nil {
+		return file, nil
+	}
+
+	if _, err := os.Stat(filename + exportPartFileSuffix); err == nil {
+		return nil, errors.ErrExportNotReady
+	}
+
+	return nil, errors.ErrExportNotFound
+}
+
+// Purge removes archives older than retention, archives which were not
+// finished are removed as well
+func (s *ExportService) Purge(ctx context.Context) (int, error) {
+	expired := time.Now().Add(-s.retention)
+	purged := 0
+
+	err := filepath.Walk(s.directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		if info.IsDir() || !info.ModTime().Before(expired) {
+			return nil
+		}
+
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		purged++
+		return nil
+	})
+
+	return purged, err
+}
+
+func (s *ExportService) writeFile(file *os.File, input ExportInput) error {
+	if err := s.Write(context.Background(), file, input); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+func (s *ExportService) writeJSON(archive *zip.Writer, filename string, value interface{}) error {
+	w, err := archive.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func (s *ExportService) writePost(archive *zip.Writer, filename string, post domain.Post) error {
+	w, err := archive.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	return markdown.Render(w, markdown.Document{
+		FrontMatter: markdown.FrontMatter{
+			Title: post.Title,
+			Slug:  post.Slug,
+			Date:  post.CreatedAt,
+			Tags:  post.Tags,
+			Draft: !post.PublishedAt.Valid,
+		},
+		Content: post.Content,
+	})
+}
+
+func (s *ExportService) userDirectory(userID uuid.UUID) string {
+	return filepath.Join(s.directory, userID.String())
+}
+
+func (s *ExportService) filename(userID uuid.UUID, id string) string {
+	return filepath.Join(s.userDirectory(userID), id+exportFileExtension)
+}
@@ -0,0 +1,200 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	mock_logger "github.com/aintsashqa/go-simple-blog/pkg/logger/mocks"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v4"
+)
+
+type ExportServiceSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockUserRepository *mock_repository.MockUser
+	MockPostRepository *mock_repository.MockPost
+	MockLogger         *mock_logger.MockLogger
+
+	Directory string
+
+	CurrentService service.Export
+}
+
+func TestExportServiceSuite(t *testing.T) {
+	suite.Run(t, new(ExportServiceSuite))
+}
+
+func (s *ExportServiceSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockUserRepository = mock_repository.NewMockUser(s.Controller)
+	s.MockPostRepository = mock_repository.NewMockPost(s.Controller)
+	s.MockLogger = mock_logger.NewMockLogger(s.Controller)
+
+	directory, err := ioutil.TempDir("", "export")
+	s.Assertions.NoError(err)
+	s.Directory = directory
+
+	s.CurrentService = service.NewExportService(s.MockUserRepository, s.MockPostRepository, s.MockLogger, s.Directory, 1, 1, time.Hour)
+}
+
+func (s *ExportServiceSuite) TearDownTest() {
+	s.Controller.Finish()
+	os.RemoveAll(s.Directory)
+}
+
+func (s *ExportServiceSuite) expectUserWithPosts(user domain.User) []domain.Post {
+	published := domain.Post{Title: "Published post", Slug: "published-post", UserID: user.ID, PublishedAt: null.TimeFrom(time.Now())}
+	published.Init()
+	deleted := domain.Post{Title: "Deleted post", Slug: "published-post", UserID: user.ID}
+	deleted.Init()
+	deleted.Delete()
+
+	s.MockUserRepository.EXPECT().
+		Self(gomock.Any(), user.ID).
+		Return(user, nil).
+		Times(1)
+
+	s.MockPostRepository.EXPECT().
+		GetAllWithUserID(gomock.Any(), user.ID, 0, gomock.Any()).
+		Return([]domain.Post{published, deleted}, nil).
+		Times(1)
+
+	return []domain.Post{published, deleted}
+}
+
+func (s *ExportServiceSuite) archiveFiles(value []byte) []string {
+	archive, err := zip.NewReader(bytes.NewReader(value), int64(len(value)))
+	s.Assertions.NoError(err)
+
+	files := []string{}
+	for _, file := range archive.File {
+		files = append(files, file.Name)
+	}
+	return files
+}
+
+func (s *ExportServiceSuite) TestWriteMethod() {
+	methodCases := []struct {
+		Name           string
+		IncludeDeleted bool
+		ArchiveFiles   func(posts []domain.Post) []string
+	}{
+		{
+			Name:           "Success",
+			IncludeDeleted: false,
+			ArchiveFiles: func(posts []domain.Post) []string {
+				return []string{"profile.json", "posts/published-post-" + posts[0].ID.String() + ".md", "manifest.json"}
+			},
+		},
+		{
+			// Deleted post with same slug is written to another file
+			Name:           "SuccessIncludeDeleted",
+			IncludeDeleted: true,
+			ArchiveFiles: func(posts []domain.Post) []string {
+				return []string{"profile.json", "posts/published-post-" + posts[0].ID.String() + ".md", "posts/published-post-" + posts[1].ID.String() + ".md", "manifest.json"}
+			},
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			user := domain.User{Email: "test@example.com"}
+			user.Init()
+			posts := s.expectUserWithPosts(user)
+
+			var buffer bytes.Buffer
+			err := s.CurrentService.Write(context.Background(), &buffer, service.ExportInput{UserID: user.ID, IncludeDeleted: currentCase.IncludeDeleted})
+			s.Assertions.NoError(err)
+			s.Assertions.Equal(currentCase.ArchiveFiles(posts), s.archiveFiles(buffer.Bytes()))
+		})
+	}
+}
+
+func (s *ExportServiceSuite) TestScheduleMethod() {
+	user := domain.User{Email: "test@example.com"}
+	user.Init()
+	s.expectUserWithPosts(user)
+
+	ctx := context.Background()
+	id, err := s.CurrentService.Schedule(ctx, service.ExportInput{UserID: user.ID})
+	s.Assertions.NoError(err)
+
+	s.Assertions.Eventually(func() bool {
+		archive, err := s.CurrentService.Open(ctx, user.ID, id)
+		if err != nil {
+			return false
+		}
+		defer archive.Close()
+
+		value, err := ioutil.ReadAll(archive)
+		return err == nil && len(s.archiveFiles(value)) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = s.CurrentService.Open(ctx, uuid.NewV4(), id)
+	s.Assertions.Equal(serviceerrors.ErrExportNotFound, err)
+}
+
+func (s *ExportServiceSuite) TestScheduleMethodBusy() {
+	user := domain.User{Email: "test@example.com"}
+	user.Init()
+
+	// Worker is held by export which could not read user yet
+	release := make(chan struct{})
+	s.MockUserRepository.EXPECT().
+		Self(gomock.Any(), user.ID).
+		DoAndReturn(func(context.Context, uuid.UUID) (domain.User, error) {
+			<-release
+			return domain.User{}, serviceerrors.ErrExportNotFound
+		}).
+		Times(1)
+	finished := make(chan struct{})
+	s.MockLogger.EXPECT().
+		Errorf(gomock.Any(), gomock.Any()).
+		Do(func(string, ...interface{}) { close(finished) }).
+		Times(1)
+
+	ctx := context.Background()
+	_, err := s.CurrentService.Schedule(ctx, service.ExportInput{UserID: user.ID})
+	s.Assertions.NoError(err)
+
+	_, err = s.CurrentService.Schedule(ctx, service.ExportInput{UserID: user.ID})
+	s.Assertions.Equal(serviceerrors.ErrExportBusy, err)
+
+	close(release)
+	<-finished
+}
+
+func (s *ExportServiceSuite) TestPurgeMethod() {
+	userDirectory := filepath.Join(s.Directory, uuid.NewV4().String())
+	s.Assertions.NoError(os.MkdirAll(userDirectory, os.ModePerm))
+
+	expired := filepath.Join(userDirectory, uuid.NewV4().String()+".zip")
+	recent := filepath.Join(userDirectory, uuid.NewV4().String()+".zip")
+	s.Assertions.NoError(ioutil.WriteFile(expired, []byte{}, 0644))
+	s.Assertions.NoError(ioutil.WriteFile(recent, []byte{}, 0644))
+	s.Assertions.NoError(os.Chtimes(expired, time.Now().Add(-2*time.Hour), time.Now().Add(-2*time.Hour)))
+
+	purged, err := s.CurrentService.Purge(context.Background())
+	s.Assertions.NoError(err)
+	s.Assertions.Equal(1, purged)
+	s.Assertions.NoFileExists(expired)
+	s.Assertions.FileExists(recent)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	"github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+type FollowService struct {
+	repo  repository.Follow
+	users repository.User
+	posts repository.Post
+}
+
+func NewFollowService(repo repository.Follow, users repository.User, posts repository.Post) *FollowService {
+	return &FollowService{repo: repo, users: users, posts: posts}
+}
+
+func (s *FollowService) Follow(ctx context.Context, input FollowInput) error {
+	if uuid.Equal(input.FollowerID, input.FolloweeID) {
+		return errors.ErrFollowSelf
+	}
+
+	if _, err := s.users.Find(ctx, input.FolloweeID); err != nil {
+		return err
+	}
+
+	return s.repo.Create(ctx, domain.NewFollow(input.FollowerID, input.FolloweeID))
+}
+
+func (s *FollowService) Unfollow(ctx context.Context, input FollowInput) error {
+	return s.repo.Delete(ctx, input.FollowerID, input.FolloweeID)
+}
+
+func (s *FollowService) Followers(ctx context.Context, opt PaginateFollowOptions) (UserPagination, error) {
+	if _, err := s.users.Find(ctx, opt.UserID); err != nil {
+		return UserPagination{}, err
+	}
+
+	users, err := s.repo.GetAllFollowers(ctx, opt.UserID, pageOffset(opt.CurrentPage, opt.UsersPerPage), opt.UsersPerPage)
+	if err != nil {
+		return UserPagination{}, err
+	}
+
+	count, err := s.repo.FollowersCount(ctx, opt.UserID)
+	if err != nil {
+		return UserPagination{}, err
+	}
+
+	return newUserPagination(users, count, opt), nil
+}
+
+func (s *FollowService) Following(ctx context.Context, opt PaginateFollowOptions) (UserPagination, error) {
+	if _, err := s.users.Find(ctx, opt.UserID); err != nil {
+		return UserPagination{}, err
+	}
+
+	users, err := s.repo.GetAllFollowing(ctx, opt.UserID, pageOffset(opt.CurrentPage, opt.UsersPerPage), opt.UsersPerPage)
+	if err != nil {
+		return UserPagination{}, err
+	}
+
+	count, err := s.repo.FollowingCount(ctx, opt.UserID)
+	if err != nil {
+		return UserPagination{}, err
+	}
+
+	return newUserPagination(users, count, opt), nil
+}
+
+func newUserPagination(users []domain.User, count int, opt PaginateFollowOptions) UserPagination {
+	previousPage, nextPage := pages(opt.CurrentPage, opt.UsersPerPage, count)
+
+	return UserPagination{
+		Users:        users,
+		UsersCount:   count,
+		PreviousPage: previousPage,
+		CurrentPage:  opt.CurrentPage,
+		NextPage:     nextPage,
+		UsersPerPage: opt.UsersPerPage,
+	}
+}
+
+// Feed uses cursor instead of pages, so posts published while reading
+// do not shift next page
+func (s *FollowService) Feed(ctx context.Context, opt FeedOptions) (Feed, error) {
+	cursor, err := decodeFeedCursor(opt.Cursor)
+	if err != nil {
+		return Feed{}, err
+	}
+
+	posts, err := s.posts.GetAllFeed(ctx, opt.UserID, cursor, opt.PostsPerPage)
+	if err != nil {
+		return Feed{}, err
+	}
+
+	feed := Feed{Posts: posts}
+	if len(posts) == opt.PostsPerPage && len(posts) > 0 {
+		last := posts[len(posts)-1]
+		feed.NextCursor = encodeFeedCursor(domain.FeedCursor{PublishedAt: last.PublishedAt.Time, PostID: last.ID})
+	}
+
+	return feed, nil
+}
+
+func encodeFeedCursor(cursor domain.FeedCursor) string {
+	value := fmt.Sprintf("%s|%s", cursor.PublishedAt.UTC().Format(time.RFC3339Nano), cursor.PostID)
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+func decodeFeedCursor(value string) (domain.FeedCursor, error) {
+	if value == "" {
+		return domain.FeedCursor{}, nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return domain.FeedCursor{}, errors.ErrFeedCursorInvalid
+	}
+
+	pieces := strings.Split(string(decoded), "|")
+	if len(pieces) != 2 {
+		return domain.FeedCursor{}, errors.ErrFeedCursorInvalid
+	}
+
+	publishedAt, err := time.Parse(time.RFC3339Nano, pieces[0])
+	if err != nil {
+		return domain.FeedCursor{}, errors.ErrFeedCursorInvalid
+	}
+
+	id, err := uuid.FromString(pieces[1])
+	if err != nil {
+		return domain.FeedCursor{}, errors.ErrFeedCursorInvalid
+	}
+
+	return domain.FeedCursor{PublishedAt: publishedAt, PostID: id}, nil
+}
@@ -0,0 +1,165 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v4"
+)
+
+type FollowServiceSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockFollowRepository *mock_repository.MockFollow
+	MockUserRepository   *mock_repository.MockUser
+	MockPostRepository   *mock_repository.MockPost
+
+	CurrentService service.Follow
+}
+
+func TestFollowServiceSuite(t *testing.T) {
+	suite.Run(t, new(FollowServiceSuite))
+}
+
+func (s *FollowServiceSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockFollowRepository = mock_repository.NewMockFollow(s.Controller)
+	s.MockUserRepository = mock_repository.NewMockUser(s.Controller)
+	s.MockPostRepository = mock_repository.NewMockPost(s.Controller)
+	s.CurrentService = service.NewFollowService(s.MockFollowRepository, s.MockUserRepository, s.MockPostRepository)
+}
+
+func (s *FollowServiceSuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *FollowServiceSuite) TestFollowMethod() {
+	type MockBehavior func(s *FollowServiceSuite, input service.FollowInput)
+
+	follower := uuid.NewV4()
+
+	mockFolloweeBehavior := func(returns error) MockBehavior {
+		return func(s *FollowServiceSuite, input service.FollowInput) {
+			s.MockUserRepository.EXPECT().
+				Find(context.Background(), input.FolloweeID).
+				Return(domain.User{}, returns).
+				Times(1)
+			if returns == nil {
+				s.MockFollowRepository.EXPECT().
+					Create(context.Background(), gomock.AssignableToTypeOf(domain.Follow{})).
+					Return(nil).
+					Times(1)
+			}
+		}
+	}
+
+	methodCases := []struct {
+		Name              string
+		ServiceInput      service.FollowInput
+		MethodResultError error
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name:         "Success",
+			ServiceInput: service.FollowInput{FollowerID: follower, FolloweeID: uuid.NewV4()},
+			MockBehavior: mockFolloweeBehavior(nil),
+		},
+		{
+			Name:              "Self",
+			ServiceInput:      service.FollowInput{FollowerID: follower, FolloweeID: follower},
+			MethodResultError: serviceerrors.ErrFollowSelf,
+		},
+		{
+			Name:              "FolloweeNotFound",
+			ServiceInput:      service.FollowInput{FollowerID: follower, FolloweeID: uuid.NewV4()},
+			MethodResultError: repoerrors.ErrUserNotFound,
+			MockBehavior:      mockFolloweeBehavior(repoerrors.ErrUserNotFound),
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			if currentCase.MockBehavior != nil {
+				currentCase.MockBehavior(s, currentCase.ServiceInput)
+			}
+			err := s.CurrentService.Follow(context.Background(), currentCase.ServiceInput)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
+
+func (s *FollowServiceSuite) TestFollowersMethod() {
+	user := uuid.NewV4()
+
+	s.MockUserRepository.EXPECT().
+		Find(context.Background(), user).
+		Return(domain.User{}, nil).
+		Times(1)
+	s.MockFollowRepository.EXPECT().
+		GetAllFollowers(context.Background(), user, 2, 2).
+		Return([]domain.User{{}}, nil).
+		Times(1)
+	s.MockFollowRepository.EXPECT().
+		FollowersCount(context.Background(), user).
+		Return(3, nil).
+		Times(1)
+
+	result, err := s.CurrentService.Followers(context.Background(), service.PaginateFollowOptions{UserID: user, CurrentPage: 2, UsersPerPage: 2})
+	s.Assertions.NoError(err)
+	s.Assertions.Equal(service.UserPagination{
+		Users:        []domain.User{{}},
+		UsersCount:   3,
+		PreviousPage: 1,
+		CurrentPage:  2,
+		NextPage:     2,
+		UsersPerPage: 2,
+	}, result)
+}
+
+func (s *FollowServiceSuite) TestFeedMethod() {
+	user := uuid.NewV4()
+	publishedAt := time.Now().Truncate(time.Second).UTC()
+	posts := []domain.Post{
+		{Model: domain.Model{ID: uuid.NewV4()}, PublishedAt: null.TimeFrom(publishedAt.Add(time.Minute))},
+		{Model: domain.Model{ID: uuid.NewV4()}, PublishedAt: null.TimeFrom(publishedAt)},
+	}
+
+	s.Suite.Run("InvalidCursor", func() {
+		_, err := s.CurrentService.Feed(context.Background(), service.FeedOptions{UserID: user, Cursor: "invalid", PostsPerPage: 2})
+		s.Assertions.Equal(serviceerrors.ErrFeedCursorInvalid, err)
+	})
+
+	s.Suite.Run("NextPage", func() {
+		s.MockPostRepository.EXPECT().
+			GetAllFeed(context.Background(), user, domain.FeedCursor{}, 2).
+			Return(posts, nil).
+			Times(1)
+
+		first, err := s.CurrentService.Feed(context.Background(), service.FeedOptions{UserID: user, PostsPerPage: 2})
+		s.Assertions.NoError(err)
+		s.Assertions.NotEmpty(first.NextCursor)
+
+		s.MockPostRepository.EXPECT().
+			GetAllFeed(context.Background(), user, domain.FeedCursor{PublishedAt: publishedAt, PostID: posts[1].ID}, 2).
+			Return(posts[:1], nil).
+			Times(1)
+
+		last, err := s.CurrentService.Feed(context.Background(), service.FeedOptions{UserID: user, Cursor: first.NextCursor, PostsPerPage: 2})
+		s.Assertions.NoError(err)
+		s.Assertions.Empty(last.NextCursor)
+	})
+}
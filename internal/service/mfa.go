@@ -0,0 +1,502 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/hash"
+	"github.com/aintsashqa/go-simple-blog/pkg/random"
+	"github.com/aintsashqa/go-simple-blog/pkg/signature"
+	"github.com/aintsashqa/go-simple-blog/pkg/totp"
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	mfaChallengePurpose  string        = "mfa_challenge"
+	mfaChallengeLifetime time.Duration = 5 * time.Minute
+	mfaNonceSize         int           = 32
+
+	recoveryCodesCount int = 10
+	recoveryCodeSize   int = 5
+)
+
+// mfaChallenge has purpose, so other payloads signed with same key could
+// not be passed as challenge
+type mfaChallenge struct {
+	Purpose   string    `json:"purpose"`
+	Nonce     string    `json:"nonce"`
+	UserID    uuid.UUID `json:"user_id"`
+	Enroll    bool      `json:"enroll,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type MFAService struct {
+	users    repository.User
+	factors  repository.TOTPFactor
+	codes    repository.RecoveryCode
+	policies repository.MFAPolicy
+	// nonces keep challenges issued after password was checked
+	nonces   repository.MFAChallenge
+	events   repository.SecurityEvent
+	hasher   hash.HashProvider
+	signer   signature.SignatureProvider
+	tokens   Token
+	throttle SignInThrottle
+	issuer   string
+}
+
+func NewMFAService(
+	users repository.User,
+	factors repository.TOTPFactor,
+	codes repository.RecoveryCode,
+	policies repository.MFAPolicy,
+	nonces repository.MFAChallenge,
+	events repository.SecurityEvent,
+	hasher hash.HashProvider,
+	signer signature.SignatureProvider,
+	tokens Token,
+	throttle SignInThrottle,
+	issuer string,
+) *MFAService {
+	return &MFAService{
+		users:    users,
+		factors:  factors,
+		codes:    codes,
+		policies: policies,
+		nonces:   nonces,
+		events:   events,
+		hasher:   hasher,
+		signer:   signature.WithPurpose(signer, mfaChallengePurpose),
+		tokens:   tokens,
+		throttle: throttle,
+		issuer:   issuer,
+	}
+}
+
+// SignIn issues tokens for user whose first factor was checked, challenge is
+// returned instead when factor is enabled or required by role of user
+func (s *MFAService) SignIn(ctx context.Context, user domain.User, client Client) (SignInResult, error) {
+	if user.IsSuspended() {
+		return SignInResult{}, errors.ErrUserSuspended
+	}
+
+	enabled, err := s.enabled(ctx, user.ID)
+	if err != nil {
+		return SignInResult{}, err
+	}
+
+	if !enabled {
+		required, err := s.required(ctx, user)
+		if err != nil {
+			return SignInResult{}, err
+		}
+
+		if !required {
+			tokens, err := s.tokens.Issue(ctx, user.ID, client)
+			return SignInResult{Tokens: tokens}, err
+		}
+	}
+
+	nonce, err := random.Token(mfaNonceSize)
+	if err != nil {
+		return SignInResult{}, err
+	}
+
+	if err := s.nonces.Add(ctx, nonce, mfaChallengeLifetime); err != nil {
+		return SignInResult{}, err
+	}
+
+	challenge := mfaChallenge{
+		Purpose:   mfaChallengePurpose,
+		Nonce:     nonce,
+		UserID:    user.ID,
+		Enroll:    !enabled,
+		ExpiresAt: time.Now().Add(mfaChallengeLifetime),
+	}
+
+	payload, err := json.Marshal(challenge)
+	if err != nil {
+		return SignInResult{}, err
+	}
+
+	return SignInResult{Challenge: MFAChallenge{
+		Token:              s.signer.Sign(payload),
+		EnrollmentRequired: challenge.Enroll,
+		ExpiresAt:          challenge.ExpiresAt,
+	}}, nil
+}
+
+// Verify checks second factor and issues tokens, enrollment started by
+// challenge is confirmed by first code. Challenge could be retried until it
+// expires, so failed codes are throttled per user, and it is spent when
+// tokens are issued
+func (s *MFAService) Verify(ctx context.Context, input VerifyMFAInput) (MFAVerification, error) {
+	challenge, err := s.challenge(ctx, input.Token)
+	if err != nil {
+		return MFAVerification{}, err
+	}
+
+	if err := s.throttle.CheckSecondFactor(ctx, challenge.UserID); err != nil {
+		return MFAVerification{}, err
+	}
+
+	factor, err := s.factors.Find(ctx, challenge.UserID)
+	if err == repoerrors.ErrTOTPFactorNotFound {
+		return MFAVerification{}, errors.ErrMFANotEnabled
+	}
+	if err != nil {
+		return MFAVerification{}, err
+	}
+
+	var verification MFAVerification
+	switch {
+
+	case challenge.Enroll && !factor.IsConfirmed():
+		if _, err := s.enrollee(ctx, challenge.UserID); err != nil {
+			return MFAVerification{}, err
+		}
+		verification.RecoveryCodes, err = s.confirm(ctx, factor, input.Code)
+
+	// Factor enabled after challenge was issued is checked as usual
+	case factor.IsConfirmed() && input.RecoveryCode != "":
+		err = s.useRecoveryCode(ctx, factor.UserID, input.RecoveryCode)
+
+	case factor.IsConfirmed():
+		err = s.useCode(ctx, factor, input.Code)
+
+	default:
+		err = errors.ErrMFANotEnabled
+	}
+	if err == errors.ErrMFACodeInvalid {
+		if err := s.throttle.FailSecondFactor(ctx, factor.UserID); err != nil {
+			return MFAVerification{}, err
+		}
+	}
+	if err != nil {
+		return MFAVerification{}, err
+	}
+
+	if err := s.throttle.ResetSecondFactor(ctx, factor.UserID); err != nil {
+		return MFAVerification{}, err
+	}
+
+	used, err := s.nonces.Use(ctx, challenge.Nonce, mfaChallengeLifetime)
+	if err != nil {
+		return MFAVerification{}, err
+	}
+	if !used {
+		return MFAVerification{}, errors.ErrMFAChallengeInvalid
+	}
+
+	verification.Tokens, err = s.tokens.Issue(ctx, factor.UserID, input.Client)
+	return verification, err
+}
+
+// EnrollChallenge starts enrollment of user, whose role requires second
+// factor, before user is signed in
+func (s *MFAService) EnrollChallenge(ctx context.Context, input MFAChallengeInput) (TOTPEnrollment, error) {
+	challenge, err := s.challenge(ctx, input.Token)
+	if err != nil {
+		return TOTPEnrollment{}, err
+	}
+
+	if !challenge.Enroll {
+		return TOTPEnrollment{}, errors.ErrMFAAlreadyEnabled
+	}
+
+	user, err := s.enrollee(ctx, challenge.UserID)
+	if err != nil {
+		return TOTPEnrollment{}, err
+	}
+
+	return s.enroll(ctx, user)
+}
+
+func (s *MFAService) Status(ctx context.Context, userID uuid.UUID) (MFAStatus, error) {
+	user, err := s.users.Self(ctx, userID)
+	if err != nil {
+		return MFAStatus{}, err
+	}
+
+	var status MFAStatus
+	if status.Enabled, err = s.enabled(ctx, user.ID); err != nil {
+		return MFAStatus{}, err
+	}
+
+	if status.Required, err = s.required(ctx, user); err != nil {
+		return MFAStatus{}, err
+	}
+
+	if status.Enabled {
+		codes, err := s.codes.GetAllUnusedWithUserID(ctx, user.ID)
+		if err != nil {
+			return MFAStatus{}, err
+		}
+		status.RecoveryCodes = len(codes)
+	}
+
+	return status, nil
+}
+
+// Enroll generates new secret, factor is not enabled until first code
+// is confirmed, so unfinished enrollment does not lock user out
+func (s *MFAService) Enroll(ctx context.Context, userID uuid.UUID) (TOTPEnrollment, error) {
+	user, err := s.users.Self(ctx, userID)
+	if err != nil {
+		return TOTPEnrollment{}, err
+	}
+
+	return s.enroll(ctx, user)
+}
+
+func (s *MFAService) enroll(ctx context.Context, user domain.User) (TOTPEnrollment, error) {
+	enabled, err := s.enabled(ctx, user.ID)
+	if err != nil {
+		return TOTPEnrollment{}, err
+	}
+	if enabled {
+		return TOTPEnrollment{}, errors.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return TOTPEnrollment{}, err
+	}
+
+	if err := s.factors.Save(ctx, domain.NewTOTPFactor(user.ID, secret)); err != nil {
+		return TOTPEnrollment{}, err
+	}
+
+	return TOTPEnrollment{Secret: secret, URI: totp.URI(s.issuer, user.Email, secret)}, nil
+}
+
+// Confirm enables factor and returns recovery codes, codes are shown once
+func (s *MFAService) Confirm(ctx context.Context, input ConfirmTOTPInput) ([]string, error) {
+	factor, err := s.factors.Find(ctx, input.UserID)
+	if err == repoerrors.ErrTOTPFactorNotFound {
+		return nil, errors.ErrMFANotEnabled
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if factor.IsConfirmed() {
+		return nil, errors.ErrMFAAlreadyEnabled
+	}
+
+	return s.confirm(ctx, factor, input.Code)
+}
+
+// Disable removes factor and recovery codes, factor required by role of
+// user could not be disabled
+func (s *MFAService) Disable(ctx context.Context, input AuthorizeMFAInput) error {
+	user, err := authorizeUser(ctx, s.users, s.hasher, input.UserID, input.Password)
+	if err != nil {
+		return err
+	}
+
+	required, err := s.required(ctx, user)
+	if err != nil {
+		return err
+	}
+	if required {
+		return errors.ErrMFARequired
+	}
+
+	enabled, err := s.enabled(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return errors.ErrMFANotEnabled
+	}
+
+	if err := s.factors.Delete(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if err := s.codes.DeleteAll(ctx, user.ID); err != nil {
+		return err
+	}
+
+	return s.events.Create(ctx, domain.NewSecurityEvent(user.ID, domain.MFADisabledSecurityEvent))
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes of user
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, input AuthorizeMFAInput) ([]string, error) {
+	user, err := authorizeUser(ctx, s.users, s.hasher, input.UserID, input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	enabled, err := s.enabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, errors.ErrMFANotEnabled
+	}
+
+	codes, err := s.recoveryCodes(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, s.events.Create(ctx, domain.NewSecurityEvent(user.ID, domain.RecoveryCodesCreatedSecurityEvent))
+}
+
+func (s *MFAService) challenge(ctx context.Context, token string) (mfaChallenge, error) {
+	var challenge mfaChallenge
+
+	value, err := s.signer.Verify(token)
+	if err != nil {
+		return challenge, errors.ErrMFAChallengeInvalid
+	}
+
+	if err := json.Unmarshal(value, &challenge); err != nil || challenge.Purpose != mfaChallengePurpose {
+		return challenge, errors.ErrMFAChallengeInvalid
+	}
+
+	if time.Now().After(challenge.ExpiresAt) {
+		return challenge, errors.ErrMFAChallengeInvalid
+	}
+
+	// Challenge is accepted only when it was issued after password was
+	// checked and was not spent yet
+	issued, err := s.nonces.Contains(ctx, challenge.Nonce)
+	if err != nil {
+		return challenge, err
+	}
+	if !issued {
+		return challenge, errors.ErrMFAChallengeInvalid
+	}
+
+	return challenge, nil
+}
+
+// enrollee loads user enrolling by challenge, factor should be still required
+// by roles of user, because they could be changed after challenge was issued
+func (s *MFAService) enrollee(ctx context.Context, userID uuid.UUID) (domain.User, error) {
+	user, err := s.users.Self(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	required, err := s.required(ctx, user)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if !required {
+		return domain.User{}, errors.ErrMFAChallengeInvalid
+	}
+
+	return user, nil
+}
+
+func (s *MFAService) enabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	factor, err := s.factors.Find(ctx, userID)
+	if err == repoerrors.ErrTOTPFactorNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return factor.IsConfirmed(), nil
+}
+
+func (s *MFAService) required(ctx context.Context, user domain.User) (bool, error) {
+	roles, err := s.policies.GetRequiredRoles(ctx)
+	if err != nil {
+		return false, err
+	}
+	return user.Roles.HasAny(roles...), nil
+}
+
+func (s *MFAService) confirm(ctx context.Context, factor domain.TOTPFactor, code string) ([]string, error) {
+	counter, ok := totp.Validate(factor.Secret, code, time.Now())
+	if !ok {
+		return nil, errors.ErrMFACodeInvalid
+	}
+
+	factor.LastCounter = counter
+	factor.ConfirmedAt = null.NewTime(time.Now(), true)
+	if err := s.factors.Confirm(ctx, factor); err != nil {
+		if err == repoerrors.ErrTOTPFactorNotFound {
+			return nil, errors.ErrMFAAlreadyEnabled
+		}
+		return nil, err
+	}
+
+	codes, err := s.recoveryCodes(ctx, factor.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, s.events.Create(ctx, domain.NewSecurityEvent(factor.UserID, domain.MFAEnabledSecurityEvent))
+}
+
+// useCode accepts code only once, codes of period before last accepted one
+// are rejected as well
+func (s *MFAService) useCode(ctx context.Context, factor domain.TOTPFactor, code string) error {
+	counter, ok := totp.Validate(factor.Secret, code, time.Now())
+	if !ok {
+		return errors.ErrMFACodeInvalid
+	}
+
+	used, err := s.factors.Use(ctx, factor.UserID, counter)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errors.ErrMFACodeInvalid
+	}
+	return nil
+}
+
+// useRecoveryCode looks code up by hash, codes are random, so they are
+// hashed like refresh tokens instead of passwords
+func (s *MFAService) useRecoveryCode(ctx context.Context, userID uuid.UUID, value string) error {
+	used, err := s.codes.UseWithHash(ctx, userID, hashToken(normalizeRecoveryCode(value)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errors.ErrMFACodeInvalid
+	}
+
+	return s.events.Create(ctx, domain.NewSecurityEvent(userID, domain.RecoveryCodeUsedSecurityEvent))
+}
+
+// recoveryCodes replaces codes of user, codes are formatted as two groups
+// of hex digits, so they are easier to copy
+func (s *MFAService) recoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	values := make([]string, 0, recoveryCodesCount)
+	codes := make([]domain.RecoveryCode, 0, recoveryCodesCount)
+
+	for i := 0; i < recoveryCodesCount; i++ {
+		value, err := random.Token(recoveryCodeSize)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value[:recoveryCodeSize]+"-"+value[recoveryCodeSize:])
+		codes = append(codes, domain.NewRecoveryCode(userID, hashToken(value)))
+	}
+
+	if err := s.codes.ReplaceAll(ctx, userID, codes); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+func normalizeRecoveryCode(value string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(value))
+}
@@ -0,0 +1,576 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	mock_service "github.com/aintsashqa/go-simple-blog/internal/service/mocks"
+	mock_hash "github.com/aintsashqa/go-simple-blog/pkg/hash/mocks"
+	"github.com/aintsashqa/go-simple-blog/pkg/signature/hmac"
+	"github.com/aintsashqa/go-simple-blog/pkg/totp"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v4"
+)
+
+type MFAServiceSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockUserRepository          *mock_repository.MockUser
+	MockTOTPFactorRepository    *mock_repository.MockTOTPFactor
+	MockRecoveryCodeRepository  *mock_repository.MockRecoveryCode
+	MockMFAPolicyRepository     *mock_repository.MockMFAPolicy
+	MockMFAChallengeRepository  *mock_repository.MockMFAChallenge
+	MockSecurityEventRepository *mock_repository.MockSecurityEvent
+	MockHashProvider            *mock_hash.MockHashProvider
+	MockTokenService            *mock_service.MockToken
+	MockSignInThrottle          *mock_service.MockSignInThrottle
+
+	User   domain.User
+	Secret string
+	Client service.Client
+	Tokens service.Tokens
+
+	CurrentService service.MFA
+}
+
+func TestMFAServiceSuite(t *testing.T) {
+	suite.Run(t, new(MFAServiceSuite))
+}
+
+func (s *MFAServiceSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockUserRepository = mock_repository.NewMockUser(s.Controller)
+	s.MockTOTPFactorRepository = mock_repository.NewMockTOTPFactor(s.Controller)
+	s.MockRecoveryCodeRepository = mock_repository.NewMockRecoveryCode(s.Controller)
+	s.MockMFAPolicyRepository = mock_repository.NewMockMFAPolicy(s.Controller)
+	s.MockMFAChallengeRepository = mock_repository.NewMockMFAChallenge(s.Controller)
+	s.MockSecurityEventRepository = mock_repository.NewMockSecurityEvent(s.Controller)
+	s.MockHashProvider = mock_hash.NewMockHashProvider(s.Controller)
+	s.MockTokenService = mock_service.NewMockToken(s.Controller)
+	s.MockSignInThrottle = mock_service.NewMockSignInThrottle(s.Controller)
+	s.CurrentService = service.NewMFAService(
+		s.MockUserRepository,
+		s.MockTOTPFactorRepository,
+		s.MockRecoveryCodeRepository,
+		s.MockMFAPolicyRepository,
+		s.MockMFAChallengeRepository,
+		s.MockSecurityEventRepository,
+		s.MockHashProvider,
+		hmac.NewHMACSignatureProvider("signing-key"),
+		s.MockTokenService,
+		s.MockSignInThrottle,
+		"Simple Blog",
+	)
+
+	s.User = domain.User{Model: domain.Model{ID: uuid.NewV4()}, Email: "editor@example.com", Roles: domain.Roles{domain.EditorRole}}
+	s.Secret, _ = totp.GenerateSecret()
+	s.Client = service.Client{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}
+	s.Tokens = service.Tokens{AccessToken: "access-token", RefreshToken: "refresh-token"}
+}
+
+func (s *MFAServiceSuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *MFAServiceSuite) factor(confirmed bool) domain.TOTPFactor {
+	factor := domain.NewTOTPFactor(s.User.ID, s.Secret)
+	factor.ConfirmedAt = null.NewTime(time.Now(), confirmed)
+	return factor
+}
+
+func (s *MFAServiceSuite) expectFactor(factor domain.TOTPFactor, err error) {
+	s.MockTOTPFactorRepository.EXPECT().
+		Find(context.Background(), s.User.ID).
+		Return(factor, err).
+		Times(1)
+}
+
+func (s *MFAServiceSuite) expectRequiredRoles(roles domain.Roles) {
+	s.MockMFAPolicyRepository.EXPECT().
+		GetRequiredRoles(context.Background()).
+		Return(roles, nil).
+		Times(1)
+}
+
+func (s *MFAServiceSuite) expectUser(user domain.User) {
+	s.MockUserRepository.EXPECT().
+		Self(context.Background(), s.User.ID).
+		Return(user, nil).
+		Times(1)
+}
+
+func (s *MFAServiceSuite) expectNonce() {
+	s.MockMFAChallengeRepository.EXPECT().
+		Add(context.Background(), gomock.Any(), 5*time.Minute).
+		Return(nil).
+		Times(1)
+}
+
+func (s *MFAServiceSuite) expectNonceIssued(issued bool) {
+	s.MockMFAChallengeRepository.EXPECT().
+		Contains(context.Background(), gomock.Any()).
+		Return(issued, nil).
+		Times(1)
+}
+
+func (s *MFAServiceSuite) expectNonceUsed(used bool) {
+	s.MockMFAChallengeRepository.EXPECT().
+		Use(context.Background(), gomock.Any(), 5*time.Minute).
+		Return(used, nil).
+		Times(1)
+}
+
+func (s *MFAServiceSuite) expectIssue() {
+	s.MockTokenService.EXPECT().
+		Issue(context.Background(), s.User.ID, s.Client).
+		Return(s.Tokens, nil).
+		Times(1)
+}
+
+func (s *MFAServiceSuite) expectEvent(eventType domain.SecurityEventType) {
+	s.MockSecurityEventRepository.EXPECT().
+		Create(context.Background(), gomock.AssignableToTypeOf(domain.SecurityEvent{})).
+		DoAndReturn(func(_ context.Context, event domain.SecurityEvent) error {
+			s.Assertions.Equal(eventType, event.Type)
+			return nil
+		}).
+		Times(1)
+}
+
+// challenge signs user in with enabled or required factor
+func (s *MFAServiceSuite) challenge(enroll bool) string {
+	if enroll {
+		s.expectFactor(domain.TOTPFactor{}, repoerrors.ErrTOTPFactorNotFound)
+		s.expectRequiredRoles(domain.Roles{domain.EditorRole})
+	} else {
+		s.expectFactor(s.factor(true), nil)
+	}
+	s.expectNonce()
+
+	result, err := s.CurrentService.SignIn(context.Background(), s.User, s.Client)
+	s.Assertions.NoError(err)
+	s.Assertions.Equal(enroll, result.Challenge.EnrollmentRequired)
+	s.Assertions.NotEmpty(result.Challenge.Token)
+	return result.Challenge.Token
+}
+
+func (s *MFAServiceSuite) TestSignInMethod() {
+	type MockBehavior func(s *MFAServiceSuite)
+
+	methodCases := []struct {
+		Name              string
+		Suspended         bool
+		IsChallenge       bool
+		MethodResultError error
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name: "WithoutFactor",
+			MockBehavior: func(s *MFAServiceSuite) {
+				s.expectFactor(domain.TOTPFactor{}, repoerrors.ErrTOTPFactorNotFound)
+				s.expectRequiredRoles(domain.Roles{domain.AdminRole})
+				s.expectIssue()
+			},
+		},
+		{
+			Name: "UnconfirmedFactor",
+			MockBehavior: func(s *MFAServiceSuite) {
+				s.expectFactor(s.factor(false), nil)
+				s.expectRequiredRoles(domain.Roles{})
+				s.expectIssue()
+			},
+		},
+		{
+			Name:        "EnabledFactor",
+			IsChallenge: true,
+			MockBehavior: func(s *MFAServiceSuite) {
+				s.expectFactor(s.factor(true), nil)
+				s.expectNonce()
+			},
+		},
+		{
+			Name:        "RequiredFactor",
+			IsChallenge: true,
+			MockBehavior: func(s *MFAServiceSuite) {
+				s.expectFactor(domain.TOTPFactor{}, repoerrors.ErrTOTPFactorNotFound)
+				s.expectRequiredRoles(domain.Roles{domain.EditorRole})
+				s.expectNonce()
+			},
+		},
+		{
+			Name:              "Suspended",
+			Suspended:         true,
+			MethodResultError: serviceerrors.ErrUserSuspended,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			if currentCase.MockBehavior != nil {
+				currentCase.MockBehavior(s)
+			}
+			user := s.User
+			user.SuspendedAt = null.NewTime(time.Now(), currentCase.Suspended)
+			result, err := s.CurrentService.SignIn(context.Background(), user, s.Client)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			if currentCase.MethodResultError != nil {
+				return
+			}
+			s.Assertions.Equal(currentCase.IsChallenge, result.Challenge.Token != "")
+			if !currentCase.IsChallenge {
+				s.Assertions.Equal(s.Tokens, result.Tokens)
+			}
+		})
+	}
+}
+
+func (s *MFAServiceSuite) TestVerifyMethod() {
+	type MockBehavior func(s *MFAServiceSuite)
+
+	code, _ := totp.Code(s.Secret, time.Now())
+	mockRecoveryCodesBehavior := func(used bool, returns error) MockBehavior {
+		return func(s *MFAServiceSuite) {
+			s.expectFactor(s.factor(true), nil)
+			sum := sha256.Sum256([]byte("abcde12345"))
+			s.MockRecoveryCodeRepository.EXPECT().
+				UseWithHash(context.Background(), s.User.ID, hex.EncodeToString(sum[:])).
+				Return(used, returns).
+				Times(1)
+			if used {
+				s.expectEvent(domain.RecoveryCodeUsedSecurityEvent)
+				s.expectIssue()
+			}
+		}
+	}
+
+	methodCases := []struct {
+		Name              string
+		Enroll            bool
+		ServiceInput      service.VerifyMFAInput
+		RecoveryCodes     int
+		MethodResultError error
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name:         "Code",
+			ServiceInput: service.VerifyMFAInput{Code: code},
+			MockBehavior: func(s *MFAServiceSuite) {
+				s.expectFactor(s.factor(true), nil)
+				s.MockTOTPFactorRepository.EXPECT().
+					Use(context.Background(), s.User.ID, totp.Counter(time.Now())).
+					Return(true, nil).
+					Times(1)
+				s.expectIssue()
+			},
+		},
+		{
+			// Challenge verified concurrently is spent only once
+			Name:              "UsedChallenge",
+			ServiceInput:      service.VerifyMFAInput{Code: code},
+			MethodResultError: serviceerrors.ErrMFAChallengeInvalid,
+			MockBehavior: func(s *MFAServiceSuite) {
+				s.expectFactor(s.factor(true), nil)
+				s.MockTOTPFactorRepository.EXPECT().
+					Use(context.Background(), s.User.ID, totp.Counter(time.Now())).
+					Return(true, nil).
+					Times(1)
+				s.MockSignInThrottle.EXPECT().
+					ResetSecondFactor(context.Background(), s.User.ID).
+					Return(nil).
+					Times(1)
+				s.expectNonceUsed(false)
+			},
+		},
+		{
+			Name:              "ReusedCode",
+			ServiceInput:      service.VerifyMFAInput{Code: code},
+			MethodResultError: serviceerrors.ErrMFACodeInvalid,
+			MockBehavior: func(s *MFAServiceSuite) {
+				s.expectFactor(s.factor(true), nil)
+				s.MockTOTPFactorRepository.EXPECT().
+					Use(context.Background(), s.User.ID, gomock.Any()).
+					Return(false, nil).
+					Times(1)
+			},
+		},
+		{
+			Name:              "InvalidCode",
+			ServiceInput:      service.VerifyMFAInput{Code: "000000x"},
+			MethodResultError: serviceerrors.ErrMFACodeInvalid,
+			MockBehavior: func(s *MFAServiceSuite) {
+				s.expectFactor(s.factor(true), nil)
+			},
+		},
+		{
+			Name:         "RecoveryCode",
+			ServiceInput: service.VerifyMFAInput{RecoveryCode: "ABCDE-12345"},
+			MockBehavior: mockRecoveryCodesBehavior(true, nil),
+		},
+		{
+			Name:              "UsedRecoveryCode",
+			ServiceInput:      service.VerifyMFAInput{RecoveryCode: "abcde-12345"},
+			MethodResultError: serviceerrors.ErrMFACodeInvalid,
+			MockBehavior:      mockRecoveryCodesBehavior(false, nil),
+		},
+		{
+			Name:          "Enrollment",
+			Enroll:        true,
+			ServiceInput:  service.VerifyMFAInput{Code: code},
+			RecoveryCodes: 10,
+			MockBehavior: func(s *MFAServiceSuite) {
+				s.expectFactor(s.factor(false), nil)
+				s.expectUser(s.User)
+				s.expectRequiredRoles(domain.Roles{domain.EditorRole})
+				s.MockTOTPFactorRepository.EXPECT().
+					Confirm(context.Background(), gomock.AssignableToTypeOf(domain.TOTPFactor{})).
+					DoAndReturn(func(_ context.Context, factor domain.TOTPFactor) error {
+						s.Assertions.True(factor.IsConfirmed())
+						s.Assertions.Equal(totp.Counter(time.Now()), factor.LastCounter)
+						return nil
+					}).
+					Times(1)
+				s.MockRecoveryCodeRepository.EXPECT().
+					ReplaceAll(context.Background(), s.User.ID, gomock.Len(10)).
+					Return(nil).
+					Times(1)
+				s.expectEvent(domain.MFAEnabledSecurityEvent)
+				s.expectIssue()
+			},
+		},
+		{
+			// Role of user was changed after challenge was issued
+			Name:              "EnrollmentNotRequired",
+			Enroll:            true,
+			ServiceInput:      service.VerifyMFAInput{Code: code},
+			MethodResultError: serviceerrors.ErrMFAChallengeInvalid,
+			MockBehavior: func(s *MFAServiceSuite) {
+				s.expectFactor(s.factor(false), nil)
+				s.expectUser(s.User)
+				s.expectRequiredRoles(domain.Roles{domain.AdminRole})
+			},
+		},
+		{
+			Name:              "EnrollmentNotStarted",
+			Enroll:            true,
+			ServiceInput:      service.VerifyMFAInput{Code: code},
+			MethodResultError: serviceerrors.ErrMFANotEnabled,
+			MockBehavior: func(s *MFAServiceSuite) {
+				s.expectFactor(domain.TOTPFactor{}, repoerrors.ErrTOTPFactorNotFound)
+			},
+		},
+		{
+			// Code is not checked while verification is locked
+			Name:              "Locked",
+			ServiceInput:      service.VerifyMFAInput{Code: code},
+			MethodResultError: serviceerrors.ErrSignInLocked,
+			MockBehavior:      func(s *MFAServiceSuite) {},
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			input := currentCase.ServiceInput
+			input.Token = s.challenge(currentCase.Enroll)
+			input.Client = s.Client
+			s.expectNonceIssued(true)
+
+			var locked error
+			if currentCase.MethodResultError == serviceerrors.ErrSignInLocked {
+				locked = serviceerrors.ErrSignInLocked
+			}
+			s.MockSignInThrottle.EXPECT().
+				CheckSecondFactor(context.Background(), s.User.ID).
+				Return(locked).
+				Times(1)
+			switch currentCase.MethodResultError {
+			case nil:
+				s.MockSignInThrottle.EXPECT().
+					ResetSecondFactor(context.Background(), s.User.ID).
+					Return(nil).
+					Times(1)
+				s.expectNonceUsed(true)
+			case serviceerrors.ErrMFACodeInvalid:
+				s.MockSignInThrottle.EXPECT().
+					FailSecondFactor(context.Background(), s.User.ID).
+					Return(nil).
+					Times(1)
+			}
+			currentCase.MockBehavior(s)
+			result, err := s.CurrentService.Verify(context.Background(), input)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			if err == nil {
+				s.Assertions.Equal(s.Tokens, result.Tokens)
+				s.Assertions.Len(result.RecoveryCodes, currentCase.RecoveryCodes)
+			}
+		})
+	}
+}
+
+func (s *MFAServiceSuite) TestVerifyInvalidChallenge() {
+	for _, token := range []string{"", "invalid", hmac.NewHMACSignatureProvider("signing-key").Sign([]byte(`{"user_id":"` + s.User.ID.String() + `","expires_at":"2100-01-01T00:00:00Z"}`))} {
+		_, err := s.CurrentService.Verify(context.Background(), service.VerifyMFAInput{Token: token, Code: "123456"})
+		s.Assertions.Equal(serviceerrors.ErrMFAChallengeInvalid, err)
+	}
+
+	// Challenge is signed, but its nonce was spent or expired
+	token := s.challenge(false)
+	s.expectNonceIssued(false)
+	_, err := s.CurrentService.Verify(context.Background(), service.VerifyMFAInput{Token: token, Code: "123456"})
+	s.Assertions.Equal(serviceerrors.ErrMFAChallengeInvalid, err)
+}
+
+func (s *MFAServiceSuite) TestEnrollChallengeMethod() {
+	type MockBehavior func(s *MFAServiceSuite)
+
+	methodCases := []struct {
+		Name              string
+		MethodResultError error
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name: "Success",
+			MockBehavior: func(s *MFAServiceSuite) {
+				s.expectUser(s.User)
+				s.expectRequiredRoles(domain.Roles{domain.EditorRole})
+				s.expectFactor(domain.TOTPFactor{}, repoerrors.ErrTOTPFactorNotFound)
+				s.MockTOTPFactorRepository.EXPECT().
+					Save(context.Background(), gomock.AssignableToTypeOf(domain.TOTPFactor{})).
+					Return(nil).
+					Times(1)
+			},
+		},
+		{
+			// Role of user was changed after challenge was issued
+			Name:              "NotRequired",
+			MethodResultError: serviceerrors.ErrMFAChallengeInvalid,
+			MockBehavior: func(s *MFAServiceSuite) {
+				s.expectUser(s.User)
+				s.expectRequiredRoles(domain.Roles{domain.AdminRole})
+			},
+		},
+		{
+			// Factor was enabled after challenge was issued
+			Name:              "AlreadyEnabled",
+			MethodResultError: serviceerrors.ErrMFAAlreadyEnabled,
+			MockBehavior: func(s *MFAServiceSuite) {
+				s.expectUser(s.User)
+				s.expectRequiredRoles(domain.Roles{domain.EditorRole})
+				s.expectFactor(s.factor(true), nil)
+			},
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			token := s.challenge(true)
+			s.expectNonceIssued(true)
+			currentCase.MockBehavior(s)
+			_, err := s.CurrentService.EnrollChallenge(context.Background(), service.MFAChallengeInput{Token: token})
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
+
+func (s *MFAServiceSuite) TestEnrollMethod() {
+	s.MockUserRepository.EXPECT().
+		Self(context.Background(), s.User.ID).
+		Return(s.User, nil).
+		Times(1)
+	s.expectFactor(domain.TOTPFactor{}, repoerrors.ErrTOTPFactorNotFound)
+	s.MockTOTPFactorRepository.EXPECT().
+		Save(context.Background(), gomock.AssignableToTypeOf(domain.TOTPFactor{})).
+		DoAndReturn(func(_ context.Context, factor domain.TOTPFactor) error {
+			s.Assertions.False(factor.IsConfirmed())
+			s.Secret = factor.Secret
+			return nil
+		}).
+		Times(1)
+
+	enrollment, err := s.CurrentService.Enroll(context.Background(), s.User.ID)
+	s.Assertions.NoError(err)
+	s.Assertions.Equal(s.Secret, enrollment.Secret)
+	s.Assertions.Equal(totp.URI("Simple Blog", s.User.Email, s.Secret), enrollment.URI)
+}
+
+func (s *MFAServiceSuite) TestDisableMethod() {
+	type MockBehavior func(s *MFAServiceSuite)
+
+	mockAuthorizeBehavior := func(s *MFAServiceSuite) {
+		s.MockUserRepository.EXPECT().
+			Self(context.Background(), s.User.ID).
+			Return(s.User, nil).
+			Times(1)
+		s.MockUserRepository.EXPECT().
+			GetByEmail(context.Background(), s.User.Email).
+			Return(s.User, nil).
+			Times(1)
+		s.MockHashProvider.EXPECT().
+			Compare(gomock.Any(), "secret").
+			Return(nil).
+			Times(1)
+	}
+
+	methodCases := []struct {
+		Name              string
+		MethodResultError error
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name: "Success",
+			MockBehavior: func(s *MFAServiceSuite) {
+				mockAuthorizeBehavior(s)
+				s.expectRequiredRoles(domain.Roles{domain.AdminRole})
+				s.expectFactor(s.factor(true), nil)
+				s.MockTOTPFactorRepository.EXPECT().
+					Delete(context.Background(), s.User.ID).
+					Return(nil).
+					Times(1)
+				s.MockRecoveryCodeRepository.EXPECT().
+					DeleteAll(context.Background(), s.User.ID).
+					Return(nil).
+					Times(1)
+				s.expectEvent(domain.MFADisabledSecurityEvent)
+			},
+		},
+		{
+			Name:              "Required",
+			MethodResultError: serviceerrors.ErrMFARequired,
+			MockBehavior: func(s *MFAServiceSuite) {
+				mockAuthorizeBehavior(s)
+				s.expectRequiredRoles(domain.Roles{domain.EditorRole})
+			},
+		},
+		{
+			Name:              "NotEnabled",
+			MethodResultError: serviceerrors.ErrMFANotEnabled,
+			MockBehavior: func(s *MFAServiceSuite) {
+				mockAuthorizeBehavior(s)
+				s.expectRequiredRoles(domain.Roles{})
+				s.expectFactor(s.factor(false), nil)
+			},
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			currentCase.MockBehavior(s)
+			err := s.CurrentService.Disable(context.Background(), service.AuthorizeMFAInput{UserID: s.User.ID, Password: "secret"})
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
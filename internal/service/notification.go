@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	"github.com/aintsashqa/go-simple-blog/pkg/logger"
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+type NotificationService struct {
+	repo   repository.Notification
+	logger logger.Logger
+}
+
+func NewNotificationService(repo repository.Notification, logger logger.Logger) *NotificationService {
+	return &NotificationService{repo: repo, logger: logger}
+}
+
+// PostPublished notifies followers of author, notification is created for
+// every follower by single query
+func (s *NotificationService) PostPublished(ctx context.Context, post domain.Post) {
+	notification := domain.NewPostNotification(uuid.Nil, domain.PostPublishedNotification, post.UserID, post.ID)
+	if err := s.repo.CreateForFollowers(ctx, notification); err != nil {
+		s.logger.Errorf("service.Notification.PostPublished error: %s", err)
+	}
+}
+
+// PostUnpublished notifies author that post was unpublished by moderator
+func (s *NotificationService) PostUnpublished(ctx context.Context, post domain.Post, actorID uuid.UUID) {
+	notification := domain.NewPostNotification(post.UserID, domain.PostUnpublishedNotification, actorID, post.ID)
+	if err := s.repo.Create(ctx, notification); err != nil {
+		s.logger.Errorf("service.Notification.PostUnpublished error: %s", err)
+	}
+}
+
+func (s *NotificationService) SecurityEvent(ctx context.Context, event domain.SecurityEvent) {
+	if err := s.repo.Create(ctx, domain.NewSecurityEventNotification(event)); err != nil {
+		s.logger.Errorf("service.Notification.SecurityEvent error: %s", err)
+	}
+}
+
+func (s *NotificationService) GetAll(ctx context.Context, opt PaginateNotificationOptions) (NotificationPagination, error) {
+	notifications, err := s.repo.GetAllWithUserID(ctx, opt.UserID, pageOffset(opt.CurrentPage, opt.NotificationsPerPage), opt.NotificationsPerPage)
+	if err != nil {
+		return NotificationPagination{}, err
+	}
+
+	count, err := s.repo.CountWithUserID(ctx, opt.UserID)
+	if err != nil {
+		return NotificationPagination{}, err
+	}
+
+	unread, err := s.repo.UnreadCountWithUserID(ctx, opt.UserID)
+	if err != nil {
+		return NotificationPagination{}, err
+	}
+
+	previousPage, nextPage := pages(opt.CurrentPage, opt.NotificationsPerPage, count)
+
+	return NotificationPagination{
+		Notifications:        notifications,
+		NotificationsCount:   count,
+		UnreadCount:          unread,
+		PreviousPage:         previousPage,
+		CurrentPage:          opt.CurrentPage,
+		NextPage:             nextPage,
+		NotificationsPerPage: opt.NotificationsPerPage,
+	}, nil
+}
+
+// MarkRead is idempotent, notification of another user is not found
+func (s *NotificationService) MarkRead(ctx context.Context, input MarkNotificationReadInput) (domain.Notification, error) {
+	notification, err := s.repo.FindWithUserID(ctx, input.ID, input.UserID)
+	if err != nil {
+		return domain.Notification{}, err
+	}
+
+	if notification.IsRead() {
+		return notification, nil
+	}
+
+	notification.ReadAt = null.NewTime(time.Now(), true)
+	if err := s.repo.MarkRead(ctx, notification.ID, notification.ReadAt.Time); err != nil {
+		return domain.Notification{}, err
+	}
+
+	return notification, nil
+}
+
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	return s.repo.MarkAllRead(ctx, userID, time.Now())
+}
+
+// SecurityEventNotifier notifies user about every recorded security event,
+// so services recording events do not depend on notifications
+type SecurityEventNotifier struct {
+	repository.SecurityEvent
+	notifier Notifier
+}
+
+func NewSecurityEventNotifier(events repository.SecurityEvent, notifier Notifier) *SecurityEventNotifier {
+	return &SecurityEventNotifier{SecurityEvent: events, notifier: notifier}
+}
+
+func (r *SecurityEventNotifier) Create(ctx context.Context, event domain.SecurityEvent) error {
+	if err := r.SecurityEvent.Create(ctx, event); err != nil {
+		return err
+	}
+
+	r.notifier.SecurityEvent(ctx, event)
+	return nil
+}
@@ -0,0 +1,193 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	mock_service "github.com/aintsashqa/go-simple-blog/internal/service/mocks"
+	mock_logger "github.com/aintsashqa/go-simple-blog/pkg/logger/mocks"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v4"
+)
+
+type NotificationServiceSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockNotificationRepository *mock_repository.MockNotification
+	MockLogger                 *mock_logger.MockLogger
+
+	CurrentService *service.NotificationService
+}
+
+func TestNotificationServiceSuite(t *testing.T) {
+	suite.Run(t, new(NotificationServiceSuite))
+}
+
+func (s *NotificationServiceSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockNotificationRepository = mock_repository.NewMockNotification(s.Controller)
+	s.MockLogger = mock_logger.NewMockLogger(s.Controller)
+	s.CurrentService = service.NewNotificationService(s.MockNotificationRepository, s.MockLogger)
+}
+
+func (s *NotificationServiceSuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *NotificationServiceSuite) TestPostPublishedMethod() {
+	post := domain.Post{Model: domain.Model{ID: uuid.NewV4()}, UserID: uuid.NewV4()}
+
+	s.MockNotificationRepository.EXPECT().
+		CreateForFollowers(context.Background(), gomock.AssignableToTypeOf(domain.Notification{})).
+		DoAndReturn(func(_ context.Context, notification domain.Notification) error {
+			s.Assertions.Equal(domain.PostPublishedNotification, notification.Type)
+			s.Assertions.Equal(post.UserID, notification.ActorID.UUID)
+			s.Assertions.Equal(post.ID, notification.PostID.UUID)
+			return errors.New("RepositoryResultError")
+		}).
+		Times(1)
+	s.MockLogger.EXPECT().
+		Errorf(gomock.Any(), gomock.Any()).
+		Times(1)
+
+	s.CurrentService.PostPublished(context.Background(), post)
+}
+
+func (s *NotificationServiceSuite) TestGetAllMethod() {
+	type MockBehavior func(s *NotificationServiceSuite, opt service.PaginateNotificationOptions, returns error)
+
+	mockBehavior := func(s *NotificationServiceSuite, opt service.PaginateNotificationOptions, returns error) {
+		s.MockNotificationRepository.EXPECT().
+			GetAllWithUserID(context.Background(), opt.UserID, 10, opt.NotificationsPerPage).
+			Return([]domain.Notification{{}, {}}, returns).
+			Times(1)
+		if returns != nil {
+			return
+		}
+		s.MockNotificationRepository.EXPECT().
+			CountWithUserID(context.Background(), opt.UserID).
+			Return(25, nil).
+			Times(1)
+		s.MockNotificationRepository.EXPECT().
+			UnreadCountWithUserID(context.Background(), opt.UserID).
+			Return(3, nil).
+			Times(1)
+	}
+
+	repositoryResultError := errors.New("RepositoryResultError")
+
+	methodCases := []struct {
+		Name              string
+		RepositoryError   error
+		MethodResultError error
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name:         "Success",
+			MockBehavior: mockBehavior,
+		},
+		{
+			Name:              "RepositoryFailure",
+			RepositoryError:   repositoryResultError,
+			MethodResultError: repositoryResultError,
+			MockBehavior:      mockBehavior,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			opt := service.PaginateNotificationOptions{UserID: uuid.NewV4(), CurrentPage: 2, NotificationsPerPage: 10}
+			currentCase.MockBehavior(s, opt, currentCase.RepositoryError)
+			pagination, err := s.CurrentService.GetAll(context.Background(), opt)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			if err == nil {
+				s.Assertions.Len(pagination.Notifications, 2)
+				s.Assertions.Equal(25, pagination.NotificationsCount)
+				s.Assertions.Equal(3, pagination.UnreadCount)
+				s.Assertions.Equal(1, pagination.PreviousPage)
+				s.Assertions.Equal(3, pagination.NextPage)
+			}
+		})
+	}
+}
+
+func (s *NotificationServiceSuite) TestMarkReadMethod() {
+	type MockBehavior func(s *NotificationServiceSuite, input service.MarkNotificationReadInput)
+
+	mockFindBehavior := func(notification domain.Notification, returns error) MockBehavior {
+		return func(s *NotificationServiceSuite, input service.MarkNotificationReadInput) {
+			s.MockNotificationRepository.EXPECT().
+				FindWithUserID(context.Background(), input.ID, input.UserID).
+				Return(notification, returns).
+				Times(1)
+		}
+	}
+
+	mockMarkReadBehavior := func(s *NotificationServiceSuite, input service.MarkNotificationReadInput) {
+		mockFindBehavior(domain.Notification{ID: input.ID, UserID: input.UserID}, nil)(s, input)
+		s.MockNotificationRepository.EXPECT().
+			MarkRead(context.Background(), input.ID, gomock.Any()).
+			Return(nil).
+			Times(1)
+	}
+
+	methodCases := []struct {
+		Name              string
+		MethodResultError error
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name:         "Success",
+			MockBehavior: mockMarkReadBehavior,
+		},
+		{
+			Name:         "AlreadyRead",
+			MockBehavior: mockFindBehavior(domain.Notification{ReadAt: null.NewTime(time.Now(), true)}, nil),
+		},
+		{
+			Name:              "NotFound",
+			MethodResultError: repoerrors.ErrNotificationNotFound,
+			MockBehavior:      mockFindBehavior(domain.Notification{}, repoerrors.ErrNotificationNotFound),
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			input := service.MarkNotificationReadInput{ID: uuid.NewV4(), UserID: uuid.NewV4()}
+			currentCase.MockBehavior(s, input)
+			notification, err := s.CurrentService.MarkRead(context.Background(), input)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			if err == nil {
+				s.Assertions.True(notification.IsRead())
+			}
+		})
+	}
+}
+
+func (s *NotificationServiceSuite) TestSecurityEventNotifier() {
+	events := mock_repository.NewMockSecurityEvent(s.Controller)
+	notifier := mock_service.NewMockNotifier(s.Controller)
+	event := domain.NewSecurityEvent(uuid.NewV4(), domain.PasswordChangedSecurityEvent)
+
+	events.EXPECT().Create(context.Background(), event).Return(nil).Times(1)
+	notifier.EXPECT().SecurityEvent(context.Background(), event).Times(1)
+	s.Assertions.NoError(service.NewSecurityEventNotifier(events, notifier).Create(context.Background(), event))
+
+	// Event which was not recorded is not notified
+	repositoryResultError := errors.New("RepositoryResultError")
+	events.EXPECT().Create(context.Background(), event).Return(repositoryResultError).Times(1)
+	s.Assertions.Equal(repositoryResultError, service.NewSecurityEventNotifier(events, notifier).Create(context.Background(), event))
+}
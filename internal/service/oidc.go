@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/hash"
+	"github.com/aintsashqa/go-simple-blog/pkg/oidc"
+	"github.com/aintsashqa/go-simple-blog/pkg/random"
+	"github.com/aintsashqa/go-simple-blog/pkg/signature"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	oidcSessionPurpose  string        = "oidc_session"
+	oidcValueSize       int           = 32
+	oidcSessionLifetime time.Duration = 10 * time.Minute
+)
+
+// oidcSession keeps login request between redirect to identity provider
+// and callback, session is signed and stored by client
+type oidcSession struct {
+	State     string    `json:"state"`
+	Nonce     string    `json:"nonce"`
+	Verifier  string    `json:"verifier"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type OIDCService struct {
+	provider   oidc.Provider
+	users      repository.User
+	identities repository.UserIdentity
+	hasher     hash.HashProvider
+	signer     signature.SignatureProvider
+	mfa        MFA
+}
+
+func NewOIDCService(
+	provider oidc.Provider,
+	users repository.User,
+	identities repository.UserIdentity,
+	hasher hash.HashProvider,
+	signer signature.SignatureProvider,
+	mfa MFA,
+) *OIDCService {
+	return &OIDCService{
+		provider:   provider,
+		users:      users,
+		identities: identities,
+		hasher:     hasher,
+		signer:     signature.WithPurpose(signer, oidcSessionPurpose),
+		mfa:        mfa,
+	}
+}
+
+func (s *OIDCService) Login(ctx context.Context) (OIDCLogin, error) {
+	if s.provider == nil {
+		return OIDCLogin{}, errors.ErrOIDCDisabled
+	}
+
+	session := oidcSession{ExpiresAt: time.Now().Add(oidcSessionLifetime)}
+	for _, value := range []*string{&session.State, &session.Nonce, &session.Verifier} {
+		token, err := random.Token(oidcValueSize)
+		if err != nil {
+			return OIDCLogin{}, err
+		}
+		*value = token
+	}
+
+	url, err := s.provider.AuthCodeURL(ctx, oidc.AuthRequest{State: session.State, Nonce: session.Nonce, Verifier: session.Verifier})
+	if err != nil {
+		return OIDCLogin{}, err
+	}
+
+	payload, err := json.Marshal(session)
+	if err != nil {
+		return OIDCLogin{}, err
+	}
+
+	return OIDCLogin{URL: url, Session: s.signer.Sign(payload), ExpiresAt: session.ExpiresAt}, nil
+}
+
+// Callback signs user in with external identity, identity is linked to
+// user with same verified email or new user is created. Second factor of
+// user is checked as well as on sign in with password
+func (s *OIDCService) Callback(ctx context.Context, input OIDCCallbackInput) (SignInResult, error) {
+	if s.provider == nil {
+		return SignInResult{}, errors.ErrOIDCDisabled
+	}
+
+	session, err := s.session(input)
+	if err != nil {
+		return SignInResult{}, err
+	}
+
+	token, err := s.provider.Exchange(ctx, input.Code, oidc.AuthRequest{State: session.State, Nonce: session.Nonce, Verifier: session.Verifier})
+	if err != nil {
+		return SignInResult{}, err
+	}
+
+	var user domain.User
+	identity, err := s.identities.Find(ctx, s.provider.Name(), token.Subject)
+	switch err {
+
+	case nil:
+		user, err = s.users.Self(ctx, identity.UserID)
+
+	case repoerrors.ErrUserIdentityNotFound:
+		user, err = s.link(ctx, token)
+	}
+	if err != nil {
+		return SignInResult{}, err
+	}
+
+	return s.mfa.SignIn(ctx, user, input.Client)
+}
+
+func (s *OIDCService) session(input OIDCCallbackInput) (oidcSession, error) {
+	var session oidcSession
+
+	value, err := s.signer.Verify(input.Session)
+	if err != nil {
+		return session, errors.ErrOIDCStateInvalid
+	}
+
+	if err := json.Unmarshal(value, &session); err != nil || session.State == "" {
+		return session, errors.ErrOIDCStateInvalid
+	}
+
+	if subtle.ConstantTimeCompare([]byte(session.State), []byte(input.State)) != 1 {
+		return session, errors.ErrOIDCStateInvalid
+	}
+
+	if time.Now().After(session.ExpiresAt) {
+		return session, errors.ErrOIDCStateInvalid
+	}
+
+	return session, nil
+}
+
+// link finds user by email or creates new one, unverified accounts are not
+// linked, because account could be registered by someone else
+func (s *OIDCService) link(ctx context.Context, token oidc.IDToken) (domain.User, error) {
+	if token.Email == "" || !token.EmailVerified {
+		return domain.User{}, errors.ErrOIDCEmailNotVerified
+	}
+
+	user, err := s.users.GetByEmail(ctx, token.Email)
+	switch err {
+
+	case nil:
+		if !user.IsEmailVerified() {
+			return domain.User{}, errors.ErrOIDCAccountNotVerified
+		}
+
+	case repoerrors.ErrUserNotFound:
+		user, err = s.create(ctx, token)
+		if err != nil {
+			return domain.User{}, err
+		}
+
+	default:
+		return domain.User{}, err
+	}
+
+	identity := domain.NewUserIdentity(s.provider.Name(), token.Subject, user.ID, token.Email)
+	return user, s.identities.Create(ctx, identity)
+}
+
+// create registers user with random password, so user could sign in with
+// password only after password reset
+func (s *OIDCService) create(ctx context.Context, token oidc.IDToken) (domain.User, error) {
+	password, err := random.Token(oidcValueSize)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	user := domain.User{
+		Email:    token.Email,
+		Password: password,
+		Roles:    domain.Roles{domain.AuthorRole},
+	}
+	user.Init()
+	user.Username = defaultUsername(user)
+
+	if err := user.Validate(domain.CreateUserValidationAction); err != nil {
+		return domain.User{}, err
+	}
+
+	user.Password = s.hasher.Make(user.Password)
+	if err := s.users.Create(ctx, user); err != nil {
+		return domain.User{}, err
+	}
+
+	// Email is verified by identity provider
+	user.EmailVerifiedAt = null.TimeFrom(time.Now())
+	user.Update()
+
+	return user, s.users.VerifyEmail(ctx, user)
+}
@@ -0,0 +1,244 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	mock_service "github.com/aintsashqa/go-simple-blog/internal/service/mocks"
+	mock_hash "github.com/aintsashqa/go-simple-blog/pkg/hash/mocks"
+	"github.com/aintsashqa/go-simple-blog/pkg/oidc/client"
+	"github.com/aintsashqa/go-simple-blog/pkg/oidc/oidctest"
+	"github.com/aintsashqa/go-simple-blog/pkg/signature/hmac"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v4"
+)
+
+type OIDCServiceSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockUserRepository         *mock_repository.MockUser
+	MockUserIdentityRepository *mock_repository.MockUserIdentity
+	MockHashProvider           *mock_hash.MockHashProvider
+	MockMFAService             *mock_service.MockMFA
+
+	Provider *oidctest.Server
+
+	CurrentService service.OIDC
+}
+
+func TestOIDCServiceSuite(t *testing.T) {
+	suite.Run(t, new(OIDCServiceSuite))
+}
+
+func (s *OIDCServiceSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockUserRepository = mock_repository.NewMockUser(s.Controller)
+	s.MockUserIdentityRepository = mock_repository.NewMockUserIdentity(s.Controller)
+	s.MockHashProvider = mock_hash.NewMockHashProvider(s.Controller)
+	s.MockMFAService = mock_service.NewMockMFA(s.Controller)
+	s.Provider = oidctest.NewServer(oidctest.User{Subject: "subject", Email: "user@example.com", EmailVerified: true})
+	s.CurrentService = service.NewOIDCService(
+		client.NewOIDCProvider(client.Config{
+			Name:         "oidctest",
+			IssuerURL:    s.Provider.URL,
+			ClientID:     oidctest.ClientID,
+			ClientSecret: oidctest.ClientSecret,
+			RedirectURL:  "http://localhost/api/v1/user/oidc/callback",
+		}),
+		s.MockUserRepository,
+		s.MockUserIdentityRepository,
+		s.MockHashProvider,
+		hmac.NewHMACSignatureProvider("signing-key"),
+		s.MockMFAService,
+	)
+}
+
+func (s *OIDCServiceSuite) TearDownTest() {
+	s.Provider.Close()
+	s.Controller.Finish()
+}
+
+// login follows redirect to mock provider and returns callback input
+func (s *OIDCServiceSuite) login() service.OIDCCallbackInput {
+	login, err := s.CurrentService.Login(context.Background())
+	s.Assertions.NoError(err)
+
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := noRedirect.Get(login.URL)
+	s.Assertions.NoError(err)
+	resp.Body.Close()
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	s.Assertions.NoError(err)
+
+	return service.OIDCCallbackInput{
+		Code:    location.Query().Get("code"),
+		State:   location.Query().Get("state"),
+		Session: login.Session,
+	}
+}
+
+func (s *OIDCServiceSuite) TestCallbackMethod() {
+	type MockBehavior func(s *OIDCServiceSuite)
+
+	userID := uuid.NewV4()
+	result := service.SignInResult{Tokens: service.Tokens{AccessToken: "access-token", RefreshToken: "refresh-token"}}
+
+	mockIdentityBehavior := func(returns error) MockBehavior {
+		return func(s *OIDCServiceSuite) {
+			s.MockUserIdentityRepository.EXPECT().
+				Find(context.Background(), "oidctest", "subject").
+				Return(domain.UserIdentity{UserID: userID}, returns).
+				Times(1)
+		}
+	}
+
+	mockSignInBehavior := func(s *OIDCServiceSuite) {
+		s.MockMFAService.EXPECT().
+			SignIn(context.Background(), gomock.AssignableToTypeOf(domain.User{}), gomock.Any()).
+			Return(result, nil).
+			Times(1)
+	}
+
+	mockLinkBehavior := func(s *OIDCServiceSuite) {
+		s.MockUserIdentityRepository.EXPECT().
+			Create(context.Background(), gomock.AssignableToTypeOf(domain.UserIdentity{})).
+			DoAndReturn(func(_ context.Context, identity domain.UserIdentity) error {
+				s.Assertions.Equal("oidctest", identity.Provider)
+				s.Assertions.Equal("subject", identity.Subject)
+				s.Assertions.Equal("user@example.com", identity.Email)
+				return nil
+			}).
+			Times(1)
+	}
+
+	mockEmailBehavior := func(user domain.User, returns error) MockBehavior {
+		return func(s *OIDCServiceSuite) {
+			mockIdentityBehavior(repoerrors.ErrUserIdentityNotFound)(s)
+			s.MockUserRepository.EXPECT().
+				GetByEmail(context.Background(), "user@example.com").
+				Return(user, returns).
+				Times(1)
+		}
+	}
+
+	methodCases := []struct {
+		Name              string
+		User              oidctest.User
+		ChangeInput       func(*service.OIDCCallbackInput)
+		MethodResultValue service.SignInResult
+		MethodResultError error
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name:              "LinkedIdentity",
+			MethodResultValue: result,
+			MockBehavior: func(s *OIDCServiceSuite) {
+				mockIdentityBehavior(nil)(s)
+				s.MockUserRepository.EXPECT().
+					Self(context.Background(), userID).
+					Return(domain.User{Model: domain.Model{ID: userID}}, nil).
+					Times(1)
+				mockSignInBehavior(s)
+			},
+		},
+		{
+			Name:              "LinkVerifiedUser",
+			MethodResultValue: result,
+			MockBehavior: func(s *OIDCServiceSuite) {
+				mockEmailBehavior(domain.User{Model: domain.Model{ID: userID}, EmailVerifiedAt: null.TimeFrom(time.Now())}, nil)(s)
+				mockLinkBehavior(s)
+				mockSignInBehavior(s)
+			},
+		},
+		{
+			Name:              "CreateUser",
+			MethodResultValue: result,
+			MockBehavior: func(s *OIDCServiceSuite) {
+				mockEmailBehavior(domain.User{}, repoerrors.ErrUserNotFound)(s)
+				s.MockHashProvider.EXPECT().
+					Make(gomock.Any()).
+					Return("hashed-password").
+					Times(1)
+				s.MockUserRepository.EXPECT().
+					Create(context.Background(), gomock.AssignableToTypeOf(domain.User{})).
+					DoAndReturn(func(_ context.Context, user domain.User) error {
+						s.Assertions.Equal("user@example.com", user.Email)
+						s.Assertions.Equal(domain.Roles{domain.AuthorRole}, user.Roles)
+						return nil
+					}).
+					Times(1)
+				s.MockUserRepository.EXPECT().
+					VerifyEmail(context.Background(), gomock.AssignableToTypeOf(domain.User{})).
+					Return(nil).
+					Times(1)
+				mockLinkBehavior(s)
+				mockSignInBehavior(s)
+			},
+		},
+		{
+			Name:              "UnverifiedUser",
+			MethodResultError: serviceerrors.ErrOIDCAccountNotVerified,
+			MockBehavior:      mockEmailBehavior(domain.User{Model: domain.Model{ID: userID}}, nil),
+		},
+		{
+			Name:              "UnverifiedIdentityEmail",
+			User:              oidctest.User{Subject: "subject", Email: "user@example.com"},
+			MethodResultError: serviceerrors.ErrOIDCEmailNotVerified,
+			MockBehavior:      mockIdentityBehavior(repoerrors.ErrUserIdentityNotFound),
+		},
+		{
+			Name:              "StateMismatch",
+			ChangeInput:       func(input *service.OIDCCallbackInput) { input.State = "another-state" },
+			MethodResultError: serviceerrors.ErrOIDCStateInvalid,
+		},
+		{
+			Name:              "SessionTampered",
+			ChangeInput:       func(input *service.OIDCCallbackInput) { input.Session = "e30." + input.Session },
+			MethodResultError: serviceerrors.ErrOIDCStateInvalid,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			s.Provider.User = oidctest.User{Subject: "subject", Email: "user@example.com", EmailVerified: true}
+			if currentCase.User.Subject != "" {
+				s.Provider.User = currentCase.User
+			}
+			if currentCase.MockBehavior != nil {
+				currentCase.MockBehavior(s)
+			}
+			input := s.login()
+			if currentCase.ChangeInput != nil {
+				currentCase.ChangeInput(&input)
+			}
+			result, err := s.CurrentService.Callback(context.Background(), input)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			s.Assertions.Equal(currentCase.MethodResultValue, result)
+		})
+	}
+}
+
+func (s *OIDCServiceSuite) TestDisabled() {
+	disabled := service.NewOIDCService(nil, s.MockUserRepository, s.MockUserIdentityRepository, s.MockHashProvider, hmac.NewHMACSignatureProvider("signing-key"), s.MockMFAService)
+
+	_, err := disabled.Login(context.Background())
+	s.Assertions.Equal(serviceerrors.ErrOIDCDisabled, err)
+}
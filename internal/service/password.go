@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/hash"
+	"github.com/aintsashqa/go-simple-blog/pkg/logger"
+	"github.com/aintsashqa/go-simple-blog/pkg/mailer"
+	"github.com/aintsashqa/go-simple-blog/pkg/random"
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	passwordResetTokenSize int = 32
+
+	passwordResetMailSubject string = "Reset your password"
+	passwordResetMailBody    string = "Somebody requested password reset for your account. If it was you, follow the link below:\r\n\r\n%s\r\n\r\nThe link could be used once and expires at %s."
+)
+
+type PasswordService struct {
+	users       repository.User
+	resets      repository.PasswordReset
+	hasher      hash.HashProvider
+	tokens      Token
+	mailer      mailer.MailerProvider
+	logger      logger.Logger
+	url         string
+	expiresTime time.Duration
+}
+
+func NewPasswordService(
+	users repository.User,
+	resets repository.PasswordReset,
+	hasher hash.HashProvider,
+	tokens Token,
+	mailer mailer.MailerProvider,
+	logger logger.Logger,
+	url string,
+	expiresTime time.Duration,
+) *PasswordService {
+	return &PasswordService{
+		users:       users,
+		resets:      resets,
+		hasher:      hasher,
+		tokens:      tokens,
+		mailer:      mailer,
+		logger:      logger,
+		url:         url,
+		expiresTime: expiresTime,
+	}
+}
+
+// Forgot does not report unknown email, so registered emails could not be enumerated
+func (s *PasswordService) Forgot(ctx context.Context, input ForgotPasswordInput) error {
+	user, err := s.users.GetByEmail(ctx, input.Email)
+	if err == repoerrors.ErrUserNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	token, err := random.Token(passwordResetTokenSize)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	reset := domain.PasswordReset{
+		ID:        uuid.NewV4(),
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.expiresTime),
+		UsedAt:    null.NewTime(now, false),
+	}
+
+	if err := s.resets.Create(ctx, reset); err != nil {
+		return err
+	}
+
+	message := mailer.Message{
+		To:      user.Email,
+		Subject: passwordResetMailSubject,
+		Body:    fmt.Sprintf(passwordResetMailBody, fmt.Sprintf(s.url, token), reset.ExpiresAt.Format(time.RFC1123)),
+	}
+
+	go func() {
+		if err := s.mailer.Send(context.Background(), message); err != nil {
+			s.logger.Errorf("service.Password.Forgot error: %s", err)
+		}
+	}()
+
+	return nil
+}
+
+// Reset changes password and signs user out from all sessions. Token is not
+// spent on invalid password, so user could retry with another one
+func (s *PasswordService) Reset(ctx context.Context, input ResetPasswordInput) error {
+	hash := hashToken(input.Token)
+	reset, err := s.resets.FindWithHash(ctx, hash)
+	if err == repoerrors.ErrPasswordResetNotFound {
+		return errors.ErrPasswordResetTokenInvalid
+	}
+	if err != nil {
+		return err
+	}
+
+	if reset.IsUsed() {
+		return errors.ErrPasswordResetTokenInvalid
+	}
+
+	if reset.IsExpired() {
+		return errors.ErrPasswordResetTokenExpired
+	}
+
+	user := domain.User{
+		Model:    domain.Model{ID: reset.UserID, UpdatedAt: time.Now()},
+		Password: input.Password,
+	}
+
+	// Token must not be spent on invalid password
+	if err := user.Validate(domain.UpdatePasswordUserValidationAction); err != nil {
+		return err
+	}
+
+	// Token is claimed before password is changed, so it could be used
+	// only by one of concurrent requests
+	used, err := s.resets.UseWithHash(ctx, hash)
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errors.ErrPasswordResetTokenInvalid
+	}
+
+	user.Password = s.hasher.Make(user.Password)
+	if err := s.users.UpdatePassword(ctx, user); err != nil {
+		return err
+	}
+
+	// Other tokens sent before password was changed are spent too
+	if err := s.resets.UseAll(ctx, user.ID); err != nil {
+		return err
+	}
+
+	return s.tokens.RevokeAll(ctx, user.ID)
+}
@@ -0,0 +1,238 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	mock_service "github.com/aintsashqa/go-simple-blog/internal/service/mocks"
+	mock_hash "github.com/aintsashqa/go-simple-blog/pkg/hash/mocks"
+	mock_logger "github.com/aintsashqa/go-simple-blog/pkg/logger/mocks"
+	mock_mailer "github.com/aintsashqa/go-simple-blog/pkg/mailer/mocks"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v4"
+)
+
+type PasswordServiceSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockUserRepository          *mock_repository.MockUser
+	MockPasswordResetRepository *mock_repository.MockPasswordReset
+	MockHashProvider            *mock_hash.MockHashProvider
+	MockTokenService            *mock_service.MockToken
+	MockMailerProvider          *mock_mailer.MockMailerProvider
+	MockLogger                  *mock_logger.MockLogger
+
+	CurrentService service.Password
+}
+
+func TestPasswordServiceSuite(t *testing.T) {
+	suite.Run(t, new(PasswordServiceSuite))
+}
+
+func (s *PasswordServiceSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockUserRepository = mock_repository.NewMockUser(s.Controller)
+	s.MockPasswordResetRepository = mock_repository.NewMockPasswordReset(s.Controller)
+	s.MockHashProvider = mock_hash.NewMockHashProvider(s.Controller)
+	s.MockTokenService = mock_service.NewMockToken(s.Controller)
+	s.MockMailerProvider = mock_mailer.NewMockMailerProvider(s.Controller)
+	s.MockLogger = mock_logger.NewMockLogger(s.Controller)
+	s.CurrentService = service.NewPasswordService(
+		s.MockUserRepository,
+		s.MockPasswordResetRepository,
+		s.MockHashProvider,
+		s.MockTokenService,
+		s.MockMailerProvider,
+		s.MockLogger,
+		"http://localhost/reset-password?token=%s",
+		time.Hour,
+	)
+}
+
+func (s *PasswordServiceSuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *PasswordServiceSuite) hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *PasswordServiceSuite) TestForgotMethod() {
+	repositoryResultError := errors.New("RepositoryResultError")
+
+	methodCases := []struct {
+		Name                  string
+		ServiceInput          service.ForgotPasswordInput
+		RepositoryResultValue domain.User
+		RepositoryResultError error
+		MethodResultError     error
+	}{
+		{
+			Name:                  "UnknownEmail",
+			ServiceInput:          service.ForgotPasswordInput{Email: "unknown@example.com"},
+			RepositoryResultError: repoerrors.ErrUserNotFound,
+		},
+		{
+			Name:                  "RepositoryFailure",
+			ServiceInput:          service.ForgotPasswordInput{Email: "root@example.com"},
+			RepositoryResultError: repositoryResultError,
+			MethodResultError:     repositoryResultError,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			s.MockUserRepository.EXPECT().
+				GetByEmail(context.Background(), currentCase.ServiceInput.Email).
+				Return(currentCase.RepositoryResultValue, currentCase.RepositoryResultError).
+				Times(1)
+			err := s.CurrentService.Forgot(context.Background(), currentCase.ServiceInput)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
+
+func (s *PasswordServiceSuite) TestResetMethod() {
+	type MockResetBehavior func(s *PasswordServiceSuite, reset domain.PasswordReset, input service.ResetPasswordInput)
+
+	userID := uuid.NewV4()
+	validReset := domain.PasswordReset{
+		ID:        uuid.NewV4(),
+		UserID:    userID,
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	usedReset := validReset
+	usedReset.UsedAt = null.TimeFrom(time.Now())
+	expiredReset := validReset
+	expiredReset.ExpiresAt = time.Now().Add(-time.Minute)
+
+	methodCases := []struct {
+		Name                  string
+		ServiceInput          service.ResetPasswordInput
+		RepositoryResultValue domain.PasswordReset
+		RepositoryResultError error
+		MethodResultError     error
+		MockResetBehavior     MockResetBehavior
+	}{
+		{
+			Name:                  "Success",
+			ServiceInput:          service.ResetPasswordInput{Token: "reset-token", Password: "new-password"},
+			RepositoryResultValue: validReset,
+			MockResetBehavior: func(s *PasswordServiceSuite, reset domain.PasswordReset, input service.ResetPasswordInput) {
+				s.MockPasswordResetRepository.EXPECT().
+					UseWithHash(context.Background(), s.hash(input.Token)).
+					Return(true, nil).
+					Times(1)
+				s.MockHashProvider.EXPECT().
+					Make(input.Password).
+					Return("new-password-hash").
+					Times(1)
+				s.MockUserRepository.EXPECT().
+					UpdatePassword(context.Background(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, user domain.User) error {
+						s.Assertions.Equal(reset.UserID, user.ID)
+						s.Assertions.Equal("new-password-hash", user.Password)
+						return nil
+					}).
+					Times(1)
+				s.MockPasswordResetRepository.EXPECT().
+					UseAll(context.Background(), reset.UserID).
+					Return(nil).
+					Times(1)
+				s.MockTokenService.EXPECT().
+					RevokeAll(context.Background(), reset.UserID).
+					Return(nil).
+					Times(1)
+			},
+		},
+		{
+			Name:                  "UnknownToken",
+			ServiceInput:          service.ResetPasswordInput{Token: "unknown-token", Password: "new-password"},
+			RepositoryResultError: repoerrors.ErrPasswordResetNotFound,
+			MethodResultError:     serviceerrors.ErrPasswordResetTokenInvalid,
+		},
+		{
+			Name:                  "UsedToken",
+			ServiceInput:          service.ResetPasswordInput{Token: "used-token", Password: "new-password"},
+			RepositoryResultValue: usedReset,
+			MethodResultError:     serviceerrors.ErrPasswordResetTokenInvalid,
+		},
+		{
+			Name:                  "ExpiredToken",
+			ServiceInput:          service.ResetPasswordInput{Token: "expired-token", Password: "new-password"},
+			RepositoryResultValue: expiredReset,
+			MethodResultError:     serviceerrors.ErrPasswordResetTokenExpired,
+		},
+		{
+			Name:                  "InvalidPassword",
+			ServiceInput:          service.ResetPasswordInput{Token: "reset-token", Password: "pass"},
+			RepositoryResultValue: validReset,
+			MethodResultError:     domain.ErrUserPasswordInvalidLength,
+		},
+		{
+			// Token was claimed by concurrent request after it was read
+			Name:                  "ConcurrentlyUsedToken",
+			ServiceInput:          service.ResetPasswordInput{Token: "reset-token", Password: "new-password"},
+			RepositoryResultValue: validReset,
+			MethodResultError:     serviceerrors.ErrPasswordResetTokenInvalid,
+			MockResetBehavior: func(s *PasswordServiceSuite, reset domain.PasswordReset, input service.ResetPasswordInput) {
+				s.MockPasswordResetRepository.EXPECT().
+					UseWithHash(context.Background(), s.hash(input.Token)).
+					Return(false, nil).
+					Times(1)
+			},
+		},
+		{
+			Name:                  "UpdatePasswordFailure",
+			ServiceInput:          service.ResetPasswordInput{Token: "reset-token", Password: "new-password"},
+			RepositoryResultValue: validReset,
+			MethodResultError:     repoerrors.ErrUserNotFound,
+			MockResetBehavior: func(s *PasswordServiceSuite, reset domain.PasswordReset, input service.ResetPasswordInput) {
+				s.MockPasswordResetRepository.EXPECT().
+					UseWithHash(context.Background(), s.hash(input.Token)).
+					Return(true, nil).
+					Times(1)
+				s.MockHashProvider.EXPECT().
+					Make(input.Password).
+					Return("new-password-hash").
+					Times(1)
+				s.MockUserRepository.EXPECT().
+					UpdatePassword(context.Background(), gomock.Any()).
+					Return(repoerrors.ErrUserNotFound).
+					Times(1)
+			},
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			s.MockPasswordResetRepository.EXPECT().
+				FindWithHash(context.Background(), s.hash(currentCase.ServiceInput.Token)).
+				Return(currentCase.RepositoryResultValue, currentCase.RepositoryResultError).
+				Times(1)
+			if currentCase.MockResetBehavior != nil {
+				currentCase.MockResetBehavior(s, currentCase.RepositoryResultValue, currentCase.ServiceInput)
+			}
+			err := s.CurrentService.Reset(context.Background(), currentCase.ServiceInput)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
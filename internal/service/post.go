@@ -6,28 +6,50 @@ import (
 
 	"github.com/aintsashqa/go-simple-blog/internal/domain"
 	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/service/errors"
 	"github.com/gosimple/slug"
 	uuid "github.com/satori/go.uuid"
 	"gopkg.in/guregu/null.v4"
 )
 
+const bulkPostMaxSize int = 100
+
 type PostService struct {
-	repo repository.Post
+	repo                 repository.Post
+	users                repository.User
+	notifier             Notifier
+	webhooks             WebhookDispatcher
+	requireVerifiedEmail bool
 }
 
-func NewPostService(repo repository.Post) *PostService {
-	return &PostService{repo: repo}
+func NewPostService(repo repository.Post, users repository.User, notifier Notifier, webhooks WebhookDispatcher, requireVerifiedEmail bool) *PostService {
+	return &PostService{repo: repo, users: users, notifier: notifier, webhooks: webhooks, requireVerifiedEmail: requireVerifiedEmail}
 }
 
 func (s *PostService) Find(ctx context.Context, id uuid.UUID) (domain.Post, error) {
 	return s.repo.Find(ctx, id)
 }
 
-func (s *PostService) offset(page, perPage int) int {
+func (s *PostService) Validator(ctx context.Context, id uuid.UUID) (domain.Validator, error) {
+	return s.repo.FindValidator(ctx, id)
+}
+
+// Validator combines validators of paginated posts with pagination details
+func (p PostPagination) Validator() domain.Validator {
+	validators := make([]domain.Validator, 0, len(p.Posts))
+	for _, post := range p.Posts {
+		validators = append(validators, post.Validator())
+	}
+	return domain.NewCollectionValidator(validators, p.PostsCount, p.CurrentPage, p.PostsPerPage)
+}
+
+func pageOffset(page, perPage int) int {
 	return (page - 1) * perPage
 }
 
-func (s *PostService) pagination(page, perPage, total int) (int, int) {
+// pages returns numbers of previous and next pages, edge pages point to themselves
+func pages(page, perPage, total int) (int, int) {
 	previousPage := page - 1
 	if previousPage < 1 {
 		previousPage = 1
@@ -47,7 +69,7 @@ func (s *PostService) GetAllPublishedPaginate(ctx context.Context, opt PaginateP
 	var posts []domain.Post
 	var err error
 
-	offset := s.offset(opt.CurrentPage, opt.PostsPerPage)
+	offset := pageOffset(opt.CurrentPage, opt.PostsPerPage)
 
 	if opt.UserID != uuid.Nil {
 
@@ -74,7 +96,7 @@ func (s *PostService) GetAllPublishedPaginate(ctx context.Context, opt PaginateP
 		}
 	}
 
-	previousPage, nextPage := s.pagination(opt.CurrentPage, opt.PostsPerPage, count)
+	previousPage, nextPage := pages(opt.CurrentPage, opt.PostsPerPage, count)
 
 	return PostPagination{
 		Posts:        posts,
@@ -87,7 +109,7 @@ func (s *PostService) GetAllPublishedPaginate(ctx context.Context, opt PaginateP
 }
 
 func (s *PostService) GetAllSelfPaginate(ctx context.Context, opt PaginatePostOptions) (PostPagination, error) {
-	offset := s.offset(opt.CurrentPage, opt.PostsPerPage)
+	offset := pageOffset(opt.CurrentPage, opt.PostsPerPage)
 
 	posts, err := s.repo.GetAllWithUserID(ctx, opt.UserID, offset, opt.PostsPerPage)
 	if err != nil {
@@ -99,7 +121,7 @@ func (s *PostService) GetAllSelfPaginate(ctx context.Context, opt PaginatePostOp
 		return PostPagination{}, err
 	}
 
-	previousPage, nextPage := s.pagination(opt.CurrentPage, opt.PostsPerPage, count)
+	previousPage, nextPage := pages(opt.CurrentPage, opt.PostsPerPage, count)
 
 	return PostPagination{
 		Posts:        posts,
@@ -112,26 +134,51 @@ func (s *PostService) GetAllSelfPaginate(ctx context.Context, opt PaginatePostOp
 }
 
 func (s *PostService) Create(ctx context.Context, input CreatePostInput) (domain.Post, error) {
+	if s.requireVerifiedEmail {
+		user, err := s.users.Self(ctx, input.UserID)
+		if err != nil {
+			return domain.Post{}, err
+		}
+
+		if !user.IsEmailVerified() {
+			return domain.Post{}, errors.ErrEmailNotVerified
+		}
+	}
+
 	slugStr := input.Slug
 	if len(slugStr) == 0 {
 		slugStr = slug.Make(input.Title)
 	}
 
 	post := domain.Post{
-		Title:       input.Title,
-		Slug:        slugStr,
-		Content:     input.Content,
-		UserID:      input.UserID,
-		PublishedAt: null.NewTime(time.Now(), input.IsPublished),
+		Title:   input.Title,
+		Slug:    slugStr,
+		Content: input.Content,
+		Tags:    domain.NewTags(input.Tags...),
+		UserID:  input.UserID,
 	}
 	post.Init()
 
+	if !input.CreatedAt.IsZero() {
+		post.CreatedAt = input.CreatedAt
+		post.UpdatedAt = input.CreatedAt
+	}
+	post.PublishedAt = null.NewTime(post.CreatedAt, input.IsPublished)
+
 	if err := post.Validate(domain.CreatePostValidationAction); err != nil {
 		return domain.Post{}, err
 	}
 
-	err := s.repo.Create(ctx, post)
-	return post, err
+	if err := s.repo.Create(ctx, post); err != nil {
+		return post, err
+	}
+
+	s.webhooks.Dispatch(ctx, domain.PostCreatedWebhookEvent, post.UserID, post)
+	if post.PublishedAt.Valid {
+		s.published(ctx, post)
+	}
+
+	return post, nil
 }
 
 func (s *PostService) Update(ctx context.Context, input UpdatePostInput) (domain.Post, error) {
@@ -140,14 +187,20 @@ func (s *PostService) Update(ctx context.Context, input UpdatePostInput) (domain
 		return domain.Post{}, err
 	}
 
+	if err := checkVersion(post.Model, input.ETag, input.Version); err != nil {
+		return post, err
+	}
+
 	slugStr := input.Slug
 	if len(slugStr) == 0 {
 		slugStr = slug.Make(input.Title)
 	}
 
+	wasPublished := post.PublishedAt.Valid
 	post.Title = input.Title
 	post.Slug = slugStr
 	post.Content = input.Content
+	post.Tags = domain.NewTags(input.Tags...)
 	post.PublishedAt = null.NewTime(time.Now(), input.IsPublished)
 	post.Update()
 
@@ -156,23 +209,56 @@ func (s *PostService) Update(ctx context.Context, input UpdatePostInput) (domain
 	}
 
 	if err := s.repo.Update(ctx, post); err != nil {
+		if err == repoerrors.ErrPostVersionConflict {
+			return s.current(ctx, input.ID, err)
+		}
 		return domain.Post{}, err
 	}
 
+	if !wasPublished && post.PublishedAt.Valid {
+		s.published(ctx, post)
+	}
+
 	return post, nil
 }
 
+// current returns actual post with version conflict error
+func (s *PostService) current(ctx context.Context, id uuid.UUID, conflict error) (domain.Post, error) {
+	post, err := s.repo.Find(ctx, id)
+	if err != nil {
+		return domain.Post{}, err
+	}
+
+	return post, conflict
+}
+
 func (s *PostService) Publish(ctx context.Context, id uuid.UUID) (domain.Post, error) {
 	post, err := s.repo.Find(ctx, id)
 	if err != nil {
 		return domain.Post{}, err
 	}
 
+	wasPublished := post.PublishedAt.Valid
 	post.PublishedAt = null.NewTime(time.Now(), true)
 	post.Update()
 
-	err = s.repo.Publish(ctx, post)
-	return post, err
+	if err := s.repo.Publish(ctx, post); err != nil {
+		return post, err
+	}
+
+	// Followers are notified when post becomes published, again after it is
+	// unpublished and published back, but not when it is published already
+	if !wasPublished {
+		s.published(ctx, post)
+	}
+
+	return post, nil
+}
+
+// published notifies followers and webhooks about post which was not published before
+func (s *PostService) published(ctx context.Context, post domain.Post) {
+	s.notifier.PostPublished(ctx, post)
+	s.webhooks.Dispatch(ctx, domain.PostPublishedWebhookEvent, post.UserID, post)
 }
 
 func (s *PostService) SoftDelete(ctx context.Context, input SoftDeletePostInput) error {
@@ -182,5 +268,137 @@ func (s *PostService) SoftDelete(ctx context.Context, input SoftDeletePostInput)
 	}
 
 	post.Delete()
-	return s.repo.SoftDelete(ctx, post)
+	if err := s.repo.SoftDelete(ctx, post); err != nil {
+		return err
+	}
+
+	s.webhooks.Dispatch(ctx, domain.PostDeletedWebhookEvent, post.UserID, post)
+	return nil
+}
+
+// Bulk applies action to every user post in single transaction, posts which
+// could not be changed are skipped and reported in their results. Nothing is
+// saved when any of posts was changed after it was read
+func (s *PostService) Bulk(ctx context.Context, input BulkPostInput) ([]BulkPostResult, error) {
+	if len(input.IDs) == 0 || len(input.IDs) > bulkPostMaxSize {
+		return nil, errors.ErrBulkPostIDsInvalidSize
+	}
+
+	apply, err := s.bulkAction(input)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := s.repo.GetAllWithPrimariesAndUserID(ctx, input.IDs, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uuid.UUID]domain.Post, len(posts))
+	for _, post := range posts {
+		found[post.ID] = post
+	}
+
+	results := make([]BulkPostResult, 0, len(input.IDs))
+	changed := make([]domain.Post, 0, len(posts))
+	published := []domain.Post{}
+	deleted := []domain.Post{}
+	for _, id := range input.IDs {
+		post, ok := found[id]
+		if !ok {
+			results = append(results, BulkPostResult{ID: id, Err: repoerrors.ErrPostNotFound})
+			continue
+		}
+
+		// Skip duplicated ids
+		delete(found, id)
+
+		wasPublished := post.PublishedAt.Valid
+		if err := apply(&post); err != nil {
+			results = append(results, BulkPostResult{ID: id, Err: err})
+			continue
+		}
+
+		post.Update()
+		changed = append(changed, post)
+		if !wasPublished && post.PublishedAt.Valid {
+			published = append(published, post)
+		}
+		if input.Action == DeleteBulkPostAction {
+			deleted = append(deleted, post)
+		}
+		results = append(results, BulkPostResult{ID: id, Post: post})
+	}
+
+	if len(changed) != 0 {
+		if err := s.repo.SaveMany(ctx, changed); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, post := range published {
+		s.published(ctx, post)
+	}
+	for _, post := range deleted {
+		s.webhooks.Dispatch(ctx, domain.PostDeletedWebhookEvent, post.UserID, post)
+	}
+
+	return results, nil
+}
+
+func (s *PostService) bulkAction(input BulkPostInput) (func(*domain.Post) error, error) {
+	switch input.Action {
+
+	case PublishBulkPostAction:
+		return func(post *domain.Post) error {
+			if post.DeletedAt.Valid {
+				return repoerrors.ErrPostNotFound
+			}
+			if !post.PublishedAt.Valid {
+				post.PublishedAt = null.NewTime(time.Now(), true)
+			}
+			return nil
+		}, nil
+
+	case UnpublishBulkPostAction:
+		return func(post *domain.Post) error {
+			if post.DeletedAt.Valid {
+				return repoerrors.ErrPostNotFound
+			}
+			post.PublishedAt = null.NewTime(time.Now(), false)
+			return nil
+		}, nil
+
+	case DeleteBulkPostAction:
+		return func(post *domain.Post) error {
+			if post.DeletedAt.Valid {
+				return errors.ErrPostAlreadyDeleted
+			}
+			// Version is increased once by Bulk for every action
+			post.DeletedAt = null.NewTime(time.Now(), true)
+			return nil
+		}, nil
+
+	case RestoreBulkPostAction:
+		return func(post *domain.Post) error {
+			if !post.DeletedAt.Valid {
+				return errors.ErrPostNotDeleted
+			}
+			post.DeletedAt = null.NewTime(time.Now(), false)
+			return nil
+		}, nil
+
+	case RetagBulkPostAction:
+		tags := domain.NewTags(input.Tags...)
+		return func(post *domain.Post) error {
+			if post.DeletedAt.Valid {
+				return repoerrors.ErrPostNotFound
+			}
+			post.Tags = tags
+			return post.Validate(domain.UpdatePostValidationAction)
+		}, nil
+
+	}
+
+	return nil, errors.ErrBulkPostActionInvalid
 }
@@ -0,0 +1,422 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	mock_service "github.com/aintsashqa/go-simple-blog/internal/service/mocks"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v4"
+)
+
+type PostServiceSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockPostRepository    *mock_repository.MockPost
+	MockUserRepository    *mock_repository.MockUser
+	MockNotifier          *mock_service.MockNotifier
+	MockWebhookDispatcher *mock_service.MockWebhookDispatcher
+
+	CurrentService service.Post
+}
+
+func TestPostServiceSuite(t *testing.T) {
+	suite.Run(t, new(PostServiceSuite))
+}
+
+func (s *PostServiceSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockPostRepository = mock_repository.NewMockPost(s.Controller)
+	s.MockUserRepository = mock_repository.NewMockUser(s.Controller)
+	s.MockNotifier = mock_service.NewMockNotifier(s.Controller)
+	s.MockWebhookDispatcher = mock_service.NewMockWebhookDispatcher(s.Controller)
+	s.CurrentService = service.NewPostService(s.MockPostRepository, s.MockUserRepository, s.MockNotifier, s.MockWebhookDispatcher, true)
+}
+
+func (s *PostServiceSuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *PostServiceSuite) TestBulkMethod() {
+	type MockPostRepositoryBehavior func(m *mock_repository.MockPost, input service.BulkPostInput, returnsPosts []domain.Post, saveCount int, returnsError error)
+
+	mockPostRepositoryBehavior := func(m *mock_repository.MockPost, input service.BulkPostInput, returnsPosts []domain.Post, saveCount int, returnsError error) {
+		m.EXPECT().
+			GetAllWithPrimariesAndUserID(context.Background(), input.IDs, input.UserID).
+			Return(returnsPosts, nil).
+			Times(1)
+
+		versions := make(map[uuid.UUID]int, len(returnsPosts))
+		for _, post := range returnsPosts {
+			versions[post.ID] = post.Version
+		}
+
+		// Saved version must be exactly one above read one, otherwise
+		// repository reports version conflict
+		if saveCount != 0 {
+			m.EXPECT().
+				SaveMany(context.Background(), gomock.Len(saveCount)).
+				Do(func(_ context.Context, posts []domain.Post) {
+					for _, post := range posts {
+						s.Assertions.Equal(versions[post.ID]+1, post.Version)
+					}
+				}).
+				Return(returnsError).
+				Times(1)
+		}
+	}
+
+	repositoryResultError := errors.New("RepositoryResultError")
+
+	userID := uuid.NewV4()
+	draft := domain.Post{UserID: userID}
+	draft.Init()
+	deleted := domain.Post{UserID: userID, PublishedAt: null.TimeFrom(time.Now())}
+	deleted.Init()
+	deleted.Delete()
+	missingID := uuid.NewV4()
+
+	methodCases := []struct {
+		Name                       string
+		ServiceInput               service.BulkPostInput
+		RepositoryPosts            []domain.Post
+		SaveCount                  int
+		PublishedCount             int
+		DeletedCount               int
+		RepositoryResultError      error
+		MethodResultErrors         []error
+		MethodResultError          error
+		MockPostRepositoryBehavior MockPostRepositoryBehavior
+	}{
+		{
+			Name:                       "SuccessPublish",
+			ServiceInput:               service.BulkPostInput{UserID: userID, IDs: []uuid.UUID{draft.ID, deleted.ID, missingID}, Action: service.PublishBulkPostAction},
+			RepositoryPosts:            []domain.Post{draft, deleted},
+			SaveCount:                  1,
+			PublishedCount:             1,
+			MethodResultErrors:         []error{nil, repoerrors.ErrPostNotFound, repoerrors.ErrPostNotFound},
+			MethodResultError:          nil,
+			MockPostRepositoryBehavior: mockPostRepositoryBehavior,
+		},
+		{
+			Name:                       "SuccessRestore",
+			ServiceInput:               service.BulkPostInput{UserID: userID, IDs: []uuid.UUID{draft.ID, deleted.ID}, Action: service.RestoreBulkPostAction},
+			RepositoryPosts:            []domain.Post{draft, deleted},
+			SaveCount:                  1,
+			MethodResultErrors:         []error{serviceerrors.ErrPostNotDeleted, nil},
+			MethodResultError:          nil,
+			MockPostRepositoryBehavior: mockPostRepositoryBehavior,
+		},
+		{
+			Name:                       "SuccessDelete",
+			ServiceInput:               service.BulkPostInput{UserID: userID, IDs: []uuid.UUID{draft.ID, deleted.ID}, Action: service.DeleteBulkPostAction},
+			RepositoryPosts:            []domain.Post{draft, deleted},
+			SaveCount:                  1,
+			DeletedCount:               1,
+			MethodResultErrors:         []error{nil, serviceerrors.ErrPostAlreadyDeleted},
+			MethodResultError:          nil,
+			MockPostRepositoryBehavior: mockPostRepositoryBehavior,
+		},
+		{
+			Name:                       "SuccessNothingChanged",
+			ServiceInput:               service.BulkPostInput{UserID: userID, IDs: []uuid.UUID{deleted.ID}, Action: service.DeleteBulkPostAction},
+			RepositoryPosts:            []domain.Post{deleted},
+			SaveCount:                  0,
+			MethodResultErrors:         []error{serviceerrors.ErrPostAlreadyDeleted},
+			MethodResultError:          nil,
+			MockPostRepositoryBehavior: mockPostRepositoryBehavior,
+		},
+		{
+			Name:                       "InvalidAction",
+			ServiceInput:               service.BulkPostInput{UserID: userID, IDs: []uuid.UUID{draft.ID}, Action: "archive"},
+			MethodResultError:          serviceerrors.ErrBulkPostActionInvalid,
+			MockPostRepositoryBehavior: nil,
+		},
+		{
+			Name:                       "InvalidSize",
+			ServiceInput:               service.BulkPostInput{UserID: userID, Action: service.DeleteBulkPostAction},
+			MethodResultError:          serviceerrors.ErrBulkPostIDsInvalidSize,
+			MockPostRepositoryBehavior: nil,
+		},
+		{
+			Name:                       "RepositoryFailure",
+			ServiceInput:               service.BulkPostInput{UserID: userID, IDs: []uuid.UUID{draft.ID}, Action: service.DeleteBulkPostAction},
+			RepositoryPosts:            []domain.Post{draft},
+			SaveCount:                  1,
+			RepositoryResultError:      repositoryResultError,
+			MethodResultError:          repositoryResultError,
+			MockPostRepositoryBehavior: mockPostRepositoryBehavior,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			if currentCase.MockPostRepositoryBehavior != nil {
+				currentCase.MockPostRepositoryBehavior(s.MockPostRepository, currentCase.ServiceInput, currentCase.RepositoryPosts, currentCase.SaveCount, currentCase.RepositoryResultError)
+			}
+			if currentCase.PublishedCount != 0 {
+				s.MockNotifier.EXPECT().
+					PostPublished(context.Background(), gomock.AssignableToTypeOf(domain.Post{})).
+					Times(currentCase.PublishedCount)
+				s.MockWebhookDispatcher.EXPECT().
+					Dispatch(context.Background(), domain.PostPublishedWebhookEvent, userID, gomock.AssignableToTypeOf(domain.Post{})).
+					Times(currentCase.PublishedCount)
+			}
+			if currentCase.DeletedCount != 0 {
+				s.MockWebhookDispatcher.EXPECT().
+					Dispatch(context.Background(), domain.PostDeletedWebhookEvent, userID, gomock.AssignableToTypeOf(domain.Post{})).
+					Times(currentCase.DeletedCount)
+			}
+			results, err := s.CurrentService.Bulk(context.Background(), currentCase.ServiceInput)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			s.Assertions.Len(results, len(currentCase.MethodResultErrors))
+			for i, result := range results {
+				s.Assertions.Equal(currentCase.ServiceInput.IDs[i], result.ID)
+				s.Assertions.Equal(currentCase.MethodResultErrors[i], result.Err)
+			}
+		})
+	}
+}
+
+func (s *PostServiceSuite) TestPublishMethod() {
+	type MockBehavior func(s *PostServiceSuite, post domain.Post)
+
+	mockPublishBehavior := func(notify bool) MockBehavior {
+		return func(s *PostServiceSuite, post domain.Post) {
+			s.MockPostRepository.EXPECT().
+				Find(context.Background(), post.ID).
+				Return(post, nil).
+				Times(1)
+			s.MockPostRepository.EXPECT().
+				Publish(context.Background(), gomock.AssignableToTypeOf(domain.Post{})).
+				Return(nil).
+				Times(1)
+			if notify {
+				s.MockNotifier.EXPECT().
+					PostPublished(context.Background(), gomock.AssignableToTypeOf(domain.Post{})).
+					Times(1)
+				s.MockWebhookDispatcher.EXPECT().
+					Dispatch(context.Background(), domain.PostPublishedWebhookEvent, post.UserID, gomock.AssignableToTypeOf(domain.Post{})).
+					Times(1)
+			}
+		}
+	}
+
+	draft := domain.Post{UserID: uuid.NewV4()}
+	draft.Init()
+	published := domain.Post{UserID: uuid.NewV4(), PublishedAt: null.TimeFrom(time.Now())}
+	published.Init()
+
+	methodCases := []struct {
+		Name         string
+		CurrentPost  domain.Post
+		MockBehavior MockBehavior
+	}{
+		{
+			Name:         "SuccessFirstPublish",
+			CurrentPost:  draft,
+			MockBehavior: mockPublishBehavior(true),
+		},
+		{
+			Name:         "SuccessAlreadyPublished",
+			CurrentPost:  published,
+			MockBehavior: mockPublishBehavior(false),
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			currentCase.MockBehavior(s, currentCase.CurrentPost)
+			post, err := s.CurrentService.Publish(context.Background(), currentCase.CurrentPost.ID)
+			s.Assertions.NoError(err)
+			s.Assertions.True(post.PublishedAt.Valid)
+		})
+	}
+}
+
+func (s *PostServiceSuite) TestUpdateMethod() {
+	type MockPostRepositoryBehavior func(*mock_repository.MockPost, domain.Post, error)
+
+	updatedPost := domain.Post{
+		Model:   domain.Model{ID: uuid.NewV4(), Version: 2},
+		Title:   "Post title",
+		Content: strings.Repeat("Post content ", 50),
+		UserID:  uuid.NewV4(),
+	}
+	currentPost := updatedPost
+	currentPost.Version = 3
+
+	mockFindBehavior := func(m *mock_repository.MockPost, post domain.Post, returns error) {
+		m.EXPECT().
+			Find(gomock.Any(), post.ID).
+			Return(post, nil).
+			Times(1)
+	}
+
+	mockUpdateBehavior := func(m *mock_repository.MockPost, post domain.Post, returns error) {
+		mockFindBehavior(m, post, nil)
+		m.EXPECT().
+			Update(gomock.Any(), gomock.Any()).
+			Return(returns).
+			Times(1)
+	}
+
+	mockConflictBehavior := func(m *mock_repository.MockPost, post domain.Post, returns error) {
+		mockUpdateBehavior(m, post, returns)
+		m.EXPECT().
+			Find(gomock.Any(), post.ID).
+			Return(currentPost, nil).
+			Times(1)
+	}
+
+	methodCases := []struct {
+		Name                       string
+		ServiceInput               service.UpdatePostInput
+		RepositoryResultError      error
+		MethodResultVersion        int
+		MethodResultError          error
+		MockPostRepositoryBehavior MockPostRepositoryBehavior
+	}{
+		{
+			Name:                       "SuccessWithVersion",
+			ServiceInput:               service.UpdatePostInput{ID: updatedPost.ID, Title: updatedPost.Title, Content: updatedPost.Content, Version: 2},
+			MethodResultVersion:        3,
+			MockPostRepositoryBehavior: mockUpdateBehavior,
+		},
+		{
+			Name:                       "SuccessWithETag",
+			ServiceInput:               service.UpdatePostInput{ID: updatedPost.ID, Title: updatedPost.Title, Content: updatedPost.Content, ETag: `"other", ` + updatedPost.ETag()},
+			MethodResultVersion:        3,
+			MockPostRepositoryBehavior: mockUpdateBehavior,
+		},
+		{
+			Name:                       "VersionRequired",
+			ServiceInput:               service.UpdatePostInput{ID: updatedPost.ID, Title: updatedPost.Title, Content: updatedPost.Content},
+			MethodResultVersion:        2,
+			MethodResultError:          serviceerrors.ErrVersionRequired,
+			MockPostRepositoryBehavior: mockFindBehavior,
+		},
+		{
+			Name:                       "VersionMismatch",
+			ServiceInput:               service.UpdatePostInput{ID: updatedPost.ID, Title: updatedPost.Title, Content: updatedPost.Content, Version: 1},
+			MethodResultVersion:        2,
+			MethodResultError:          serviceerrors.ErrVersionMismatch,
+			MockPostRepositoryBehavior: mockFindBehavior,
+		},
+		{
+			Name:                       "ETagMismatch",
+			ServiceInput:               service.UpdatePostInput{ID: updatedPost.ID, Title: updatedPost.Title, Content: updatedPost.Content, ETag: `"other"`, Version: 2},
+			MethodResultVersion:        2,
+			MethodResultError:          serviceerrors.ErrETagMismatch,
+			MockPostRepositoryBehavior: mockFindBehavior,
+		},
+		{
+			Name:                       "RepositoryConflict",
+			ServiceInput:               service.UpdatePostInput{ID: updatedPost.ID, Title: updatedPost.Title, Content: updatedPost.Content, Version: 2},
+			RepositoryResultError:      repoerrors.ErrPostVersionConflict,
+			MethodResultVersion:        3,
+			MethodResultError:          repoerrors.ErrPostVersionConflict,
+			MockPostRepositoryBehavior: mockConflictBehavior,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			currentCase.MockPostRepositoryBehavior(s.MockPostRepository, updatedPost, currentCase.RepositoryResultError)
+			post, err := s.CurrentService.Update(context.Background(), currentCase.ServiceInput)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			s.Assertions.Equal(currentCase.MethodResultVersion, post.Version)
+		})
+	}
+}
+
+func (s *PostServiceSuite) TestCreateMethod() {
+	type MockUserRepositoryBehavior func(*mock_repository.MockUser, domain.User, error)
+	type MockPostRepositoryBehavior func(*mock_repository.MockPost, error)
+
+	mockUserRepositoryBehavior := func(m *mock_repository.MockUser, user domain.User, returns error) {
+		m.EXPECT().
+			Self(gomock.Any(), user.ID).
+			Return(user, returns).
+			Times(1)
+	}
+
+	mockPostRepositoryBehavior := func(m *mock_repository.MockPost, returns error) {
+		m.EXPECT().
+			Create(gomock.Any(), gomock.AssignableToTypeOf(domain.Post{})).
+			Return(returns).
+			Times(1)
+	}
+
+	repositoryResultError := errors.New("RepositoryResultError")
+
+	verifiedUser := domain.User{
+		Model:           domain.Model{ID: uuid.NewV4()},
+		EmailVerifiedAt: null.NewTime(time.Now(), true),
+	}
+	unverifiedUser := domain.User{
+		Model: domain.Model{ID: uuid.NewV4()},
+	}
+
+	methodCases := []struct {
+		Name                       string
+		CurrentUser                domain.User
+		UserRepositoryResultError  error
+		MethodResultError          error
+		MockUserRepositoryBehavior MockUserRepositoryBehavior
+		MockPostRepositoryBehavior MockPostRepositoryBehavior
+	}{
+		{
+			Name:                       "Success",
+			CurrentUser:                verifiedUser,
+			MockUserRepositoryBehavior: mockUserRepositoryBehavior,
+			MockPostRepositoryBehavior: mockPostRepositoryBehavior,
+		},
+		{
+			Name:                       "EmailNotVerified",
+			CurrentUser:                unverifiedUser,
+			MethodResultError:          serviceerrors.ErrEmailNotVerified,
+			MockUserRepositoryBehavior: mockUserRepositoryBehavior,
+		},
+		{
+			Name:                       "RepositoryFailure",
+			CurrentUser:                verifiedUser,
+			UserRepositoryResultError:  repositoryResultError,
+			MethodResultError:          repositoryResultError,
+			MockUserRepositoryBehavior: mockUserRepositoryBehavior,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			currentCase.MockUserRepositoryBehavior(s.MockUserRepository, currentCase.CurrentUser, currentCase.UserRepositoryResultError)
+			if currentCase.MockPostRepositoryBehavior != nil {
+				currentCase.MockPostRepositoryBehavior(s.MockPostRepository, nil)
+				s.MockWebhookDispatcher.EXPECT().
+					Dispatch(context.Background(), domain.PostCreatedWebhookEvent, currentCase.CurrentUser.ID, gomock.AssignableToTypeOf(domain.Post{})).
+					Times(1)
+			}
+			_, err := s.CurrentService.Create(context.Background(), service.CreatePostInput{
+				Title:   "Post title",
+				Content: strings.Repeat("Post content ", 50),
+				UserID:  currentCase.CurrentUser.ID,
+			})
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
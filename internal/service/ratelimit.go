@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/logger"
+	"github.com/aintsashqa/go-simple-blog/pkg/ratelimit"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// Groups of routes limited together, limits of groups are configured
+	AuthRateLimitGroup  string = "auth"
+	WriteRateLimitGroup string = "write"
+)
+
+type RateLimitService struct {
+	limiter  ratelimit.LimiterProvider
+	fallback ratelimit.LimiterProvider
+	logger   logger.Logger
+	limits   map[string]ratelimit.Limit
+}
+
+func NewRateLimitService(
+	limiter ratelimit.LimiterProvider,
+	fallback ratelimit.LimiterProvider,
+	logger logger.Logger,
+	limits map[string]ratelimit.Limit,
+) *RateLimitService {
+	return &RateLimitService{
+		limiter:  limiter,
+		fallback: fallback,
+		logger:   logger,
+		limits:   limits,
+	}
+}
+
+// Allow counts request of user, or of address when user is anonymous.
+// Groups without configured limit are not limited
+func (s *RateLimitService) Allow(ctx context.Context, input RateLimitInput) (ratelimit.Result, error) {
+	limit, found := s.limits[input.Group]
+	if !found || limit.Requests <= 0 || limit.Period <= 0 {
+		return ratelimit.Result{Allowed: true}, nil
+	}
+
+	key := input.Group + ":ip:" + input.IP
+	if !uuid.Equal(input.UserID, uuid.Nil) {
+		key = input.Group + ":user:" + input.UserID.String()
+	}
+
+	result, err := s.limiter.Allow(ctx, key, limit)
+	if err != nil {
+		// Requests are still limited per instance while limiter is unavailable
+		s.logger.Errorf("service.RateLimit.Allow error: %s", err)
+		return s.fallback.Allow(ctx, key, limit)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,125 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	mock_logger "github.com/aintsashqa/go-simple-blog/pkg/logger/mocks"
+	"github.com/aintsashqa/go-simple-blog/pkg/ratelimit"
+	mock_ratelimit "github.com/aintsashqa/go-simple-blog/pkg/ratelimit/mocks"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type RateLimitServiceSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockLimiterProvider  *mock_ratelimit.MockLimiterProvider
+	MockFallbackProvider *mock_ratelimit.MockLimiterProvider
+	MockLogger           *mock_logger.MockLogger
+
+	Limit ratelimit.Limit
+
+	CurrentService service.RateLimit
+}
+
+func TestRateLimitServiceSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitServiceSuite))
+}
+
+func (s *RateLimitServiceSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockLimiterProvider = mock_ratelimit.NewMockLimiterProvider(s.Controller)
+	s.MockFallbackProvider = mock_ratelimit.NewMockLimiterProvider(s.Controller)
+	s.MockLogger = mock_logger.NewMockLogger(s.Controller)
+	s.Limit = ratelimit.Limit{Requests: 20, Period: time.Minute}
+	s.CurrentService = service.NewRateLimitService(
+		s.MockLimiterProvider,
+		s.MockFallbackProvider,
+		s.MockLogger,
+		map[string]ratelimit.Limit{service.AuthRateLimitGroup: s.Limit},
+	)
+}
+
+func (s *RateLimitServiceSuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *RateLimitServiceSuite) TestAllowMethod() {
+	type MockBehavior func(s *RateLimitServiceSuite, result ratelimit.Result)
+
+	userID := uuid.NewV4()
+	limited := ratelimit.Result{Allowed: true, Limit: 20, Remaining: 19, ResetAfter: time.Minute}
+
+	mockAllowBehavior := func(key string) MockBehavior {
+		return func(s *RateLimitServiceSuite, result ratelimit.Result) {
+			s.MockLimiterProvider.EXPECT().
+				Allow(context.Background(), key, s.Limit).
+				Return(result, nil).
+				Times(1)
+		}
+	}
+
+	methodCases := []struct {
+		Name         string
+		ServiceInput service.RateLimitInput
+		MethodResult ratelimit.Result
+		MockBehavior MockBehavior
+	}{
+		{
+			Name:         "Anonymous",
+			ServiceInput: service.RateLimitInput{Group: service.AuthRateLimitGroup, IP: "127.0.0.1"},
+			MethodResult: limited,
+			MockBehavior: mockAllowBehavior("auth:ip:127.0.0.1"),
+		},
+		{
+			Name:         "User",
+			ServiceInput: service.RateLimitInput{Group: service.AuthRateLimitGroup, UserID: userID, IP: "127.0.0.1"},
+			MethodResult: limited,
+			MockBehavior: mockAllowBehavior("auth:user:" + userID.String()),
+		},
+		{
+			Name:         "Unlimited",
+			ServiceInput: service.RateLimitInput{Group: service.WriteRateLimitGroup, IP: "127.0.0.1"},
+			MethodResult: ratelimit.Result{Allowed: true},
+		},
+		{
+			Name:         "Fallback",
+			ServiceInput: service.RateLimitInput{Group: service.AuthRateLimitGroup, IP: "127.0.0.1"},
+			MethodResult: limited,
+			MockBehavior: func(s *RateLimitServiceSuite, result ratelimit.Result) {
+				s.MockLimiterProvider.EXPECT().
+					Allow(context.Background(), "auth:ip:127.0.0.1", s.Limit).
+					Return(ratelimit.Result{}, errors.New("LimiterUnavailable")).
+					Times(1)
+				s.MockLogger.EXPECT().
+					Errorf(gomock.Any(), gomock.Any()).
+					Times(1)
+				s.MockFallbackProvider.EXPECT().
+					Allow(context.Background(), "auth:ip:127.0.0.1", s.Limit).
+					Return(result, nil).
+					Times(1)
+			},
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			if currentCase.MockBehavior != nil {
+				currentCase.MockBehavior(s, currentCase.MethodResult)
+			}
+			result, err := s.CurrentService.Allow(context.Background(), currentCase.ServiceInput)
+			s.Assertions.NoError(err)
+			s.Assertions.Equal(currentCase.MethodResult, result)
+		})
+	}
+}
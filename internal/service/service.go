@@ -3,6 +3,7 @@ package service
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/aintsashqa/go-simple-blog/internal/domain"
@@ -10,48 +11,310 @@ import (
 	"github.com/aintsashqa/go-simple-blog/pkg/auth"
 	"github.com/aintsashqa/go-simple-blog/pkg/hash"
 	"github.com/aintsashqa/go-simple-blog/pkg/logger"
+	"github.com/aintsashqa/go-simple-blog/pkg/mailer"
+	"github.com/aintsashqa/go-simple-blog/pkg/oidc"
+	"github.com/aintsashqa/go-simple-blog/pkg/ratelimit"
+	"github.com/aintsashqa/go-simple-blog/pkg/signature"
+	"github.com/aintsashqa/go-simple-blog/pkg/webhook"
 	uuid "github.com/satori/go.uuid"
 )
 
+const (
+	PublishBulkPostAction   BulkPostAction = "publish"
+	UnpublishBulkPostAction BulkPostAction = "unpublish"
+	DeleteBulkPostAction    BulkPostAction = "delete"
+	RestoreBulkPostAction   BulkPostAction = "restore"
+	RetagBulkPostAction     BulkPostAction = "retag"
+)
+
 type (
 	SignUpUserInput struct {
 		Email    string
+		Username string
 		Password string
 	}
 
+	// Client describes device of user, it is recorded with session
+	Client struct {
+		UserAgent string
+		IP        string
+	}
+
 	SignInUserInput struct {
 		Email    string
 		Password string
+		Client
+	}
+
+	SignInThrottleConfig struct {
+		AccountLimit   int64
+		IPLimit        int64
+		LockoutTime    time.Duration
+		MaxLockoutTime time.Duration
+		FailuresWindow time.Duration
 	}
 
 	Tokens struct {
-		AccessToken string
+		AccessToken  string
+		RefreshToken string
+		ExpiresIn    time.Duration
 	}
 
 	AuthenticateUserInput struct {
 		Token string
+		IP    string
+	}
+
+	// Identity is authenticated user, roles are read from access token.
+	// Scopes are checked only for personal access tokens
+	Identity struct {
+		UserID                uuid.UUID
+		Roles                 domain.Roles
+		Scopes                domain.Scopes
+		SessionID             uuid.UUID
+		PersonalAccessTokenID uuid.UUID
 	}
 
+	// UpdateUserInput is partial, only fields which are not nil are changed
 	UpdateUserInput struct {
+		ID          uuid.UUID
+		Username    *string
+		DisplayName *string
+		Bio         *string
+		AvatarURL   *string
+		Location    *string
+		Website     *string
+		SocialLinks *[]string
+		Version     int
+		ETag        string
+	}
+
+	ChangePasswordInput struct {
+		ID              uuid.UUID
+		CurrentPassword string
+		NewPassword     string
+	}
+
+	ChangeEmailInput struct {
 		ID       uuid.UUID
-		Username string
+		Email    string
+		Password string
+	}
+
+	// SignInResult holds tokens or challenge of second factor, challenge is
+	// returned instead of tokens when user must pass second factor
+	SignInResult struct {
+		Tokens    Tokens
+		Challenge MFAChallenge
 	}
 
 	User interface {
 		SignUp(context.Context, SignUpUserInput) (domain.User, error)
-		SignIn(context.Context, SignInUserInput) (Tokens, error)
-		Authenticate(context.Context, AuthenticateUserInput) (uuid.UUID, error)
+		SignIn(context.Context, SignInUserInput) (SignInResult, error)
 		Find(context.Context, uuid.UUID) (domain.User, error)
+		FindByUsername(context.Context, string) (domain.User, error)
+		Validator(context.Context, uuid.UUID) (domain.Validator, error)
 		Self(context.Context, uuid.UUID) (domain.User, error)
 		Update(context.Context, UpdateUserInput) (domain.User, error)
+		ChangePassword(context.Context, ChangePasswordInput) error
+		ChangeEmail(context.Context, ChangeEmailInput) error
+	}
+
+	// OIDCLogin holds url of identity provider and signed session, session
+	// must be returned on callback
+	OIDCLogin struct {
+		URL       string
+		Session   string
+		ExpiresAt time.Time
+	}
+
+	OIDCCallbackInput struct {
+		Code    string
+		State   string
+		Session string
+		Client
+	}
+
+	OIDC interface {
+		Login(context.Context) (OIDCLogin, error)
+		Callback(context.Context, OIDCCallbackInput) (SignInResult, error)
+	}
+
+	// MFAChallenge is signed and passed back with code of second factor,
+	// user without factor must enroll it first when role requires it
+	MFAChallenge struct {
+		Token              string
+		EnrollmentRequired bool
+		ExpiresAt          time.Time
+	}
+
+	MFAChallengeInput struct {
+		Token string
+	}
+
+	// VerifyMFAInput contains either code of authenticator app or recovery code
+	VerifyMFAInput struct {
+		Token        string
+		Code         string
+		RecoveryCode string
+		Client
+	}
+
+	// MFAVerification holds recovery codes only when factor was enrolled
+	// by challenge, codes are shown once
+	MFAVerification struct {
+		Tokens        Tokens
+		RecoveryCodes []string
+	}
+
+	TOTPEnrollment struct {
+		Secret string
+		URI    string
+	}
+
+	ConfirmTOTPInput struct {
+		UserID uuid.UUID
+		Code   string
+	}
+
+	AuthorizeMFAInput struct {
+		UserID   uuid.UUID
+		Password string
+	}
+
+	MFAStatus struct {
+		Enabled       bool
+		Required      bool
+		RecoveryCodes int
+	}
+
+	// SignInThrottle tracks failed sign in attempts of account and client
+	// address, both are locked out with exponential backoff after limit
+	SignInThrottle interface {
+		Check(context.Context, SignInUserInput) error
+		Fail(context.Context, SignInUserInput) error
+		Reset(context.Context, SignInUserInput) error
+		CheckSecondFactor(context.Context, uuid.UUID) error
+		FailSecondFactor(context.Context, uuid.UUID) error
+		ResetSecondFactor(context.Context, uuid.UUID) error
+	}
+
+	MFA interface {
+		SignIn(context.Context, domain.User, Client) (SignInResult, error)
+		Verify(context.Context, VerifyMFAInput) (MFAVerification, error)
+		EnrollChallenge(context.Context, MFAChallengeInput) (TOTPEnrollment, error)
+		Status(context.Context, uuid.UUID) (MFAStatus, error)
+		Enroll(context.Context, uuid.UUID) (TOTPEnrollment, error)
+		Confirm(context.Context, ConfirmTOTPInput) ([]string, error)
+		Disable(context.Context, AuthorizeMFAInput) error
+		RegenerateRecoveryCodes(context.Context, AuthorizeMFAInput) ([]string, error)
+	}
+
+	VerifyEmailInput struct {
+		Token string
+	}
+
+	ConfirmEmailChangeInput struct {
+		Token string
+	}
+
+	Verification interface {
+		Send(context.Context, domain.User) error
+		Verify(context.Context, VerifyEmailInput) (domain.User, error)
+		SendEmailChange(context.Context, domain.User, string) error
+		ConfirmEmailChange(context.Context, ConfirmEmailChangeInput) (domain.User, error)
+	}
+
+	ForgotPasswordInput struct {
+		Email string
+	}
+
+	ResetPasswordInput struct {
+		Token    string
+		Password string
+	}
+
+	Password interface {
+		Forgot(context.Context, ForgotPasswordInput) error
+		Reset(context.Context, ResetPasswordInput) error
+	}
+
+	DeleteAccountInput struct {
+		ID          uuid.UUID
+		Password    string
+		PostsAction domain.PostsAction
+		TransferTo  uuid.UUID
+	}
+
+	RestoreAccountInput struct {
+		Email    string
+		Password string
+		Client
+	}
+
+	Account interface {
+		Delete(context.Context, DeleteAccountInput) (domain.AccountDeletion, error)
+		Restore(context.Context, RestoreAccountInput) (SignInResult, error)
+		Purge(context.Context) (int, error)
+	}
+
+	RefreshTokenInput struct {
+		RefreshToken string
+		Client
+	}
+
+	SignOutInput struct {
+		AccessToken  string
+		RefreshToken string
+	}
+
+	Token interface {
+		Issue(context.Context, uuid.UUID, Client) (Tokens, error)
+		Refresh(context.Context, RefreshTokenInput) (Tokens, error)
+		SignOut(context.Context, SignOutInput) error
+		Authenticate(context.Context, AuthenticateUserInput) (Identity, error)
+		RevokeAll(context.Context, uuid.UUID) error
+		PublicKeys(context.Context) []auth.PublicKey
+	}
+
+	CreatePersonalAccessTokenInput struct {
+		UserID uuid.UUID
+		Name   string
+		Scopes domain.Scopes
+	}
+
+	RevokePersonalAccessTokenInput struct {
+		ID     uuid.UUID
+		UserID uuid.UUID
+	}
+
+	RevokeSessionInput struct {
+		ID     uuid.UUID
+		UserID uuid.UUID
+	}
+
+	Session interface {
+		GetAll(context.Context, uuid.UUID) ([]domain.Session, error)
+		Revoke(context.Context, RevokeSessionInput) error
+		RevokeAll(context.Context, uuid.UUID) error
+	}
+
+	PersonalAccessToken interface {
+		Create(context.Context, CreatePersonalAccessTokenInput) (domain.PersonalAccessToken, string, error)
+		GetAll(context.Context, uuid.UUID) ([]domain.PersonalAccessToken, error)
+		Revoke(context.Context, RevokePersonalAccessTokenInput) error
+		Authenticate(context.Context, AuthenticateUserInput) (Identity, error)
 	}
 
 	CreatePostInput struct {
 		Title       string
 		Slug        string
 		Content     string
+		Tags        []string
 		UserID      uuid.UUID
 		IsPublished bool
+		// CreatedAt keeps original post date, current time is used when empty
+		CreatedAt time.Time
 	}
 
 	UpdatePostInput struct {
@@ -59,7 +322,10 @@ type (
 		Title       string
 		Slug        string
 		Content     string
+		Tags        []string
 		IsPublished bool
+		Version     int
+		ETag        string
 	}
 
 	PaginatePostOptions struct {
@@ -82,25 +348,267 @@ type (
 		PostID uuid.UUID
 	}
 
+	BulkPostAction string
+
+	BulkPostInput struct {
+		UserID uuid.UUID
+		IDs    []uuid.UUID
+		Action BulkPostAction
+		Tags   []string
+	}
+
+	BulkPostResult struct {
+		ID   uuid.UUID
+		Post domain.Post
+		Err  error
+	}
+
 	Post interface {
 		Find(context.Context, uuid.UUID) (domain.Post, error)
+		Validator(context.Context, uuid.UUID) (domain.Validator, error)
 		GetAllPublishedPaginate(context.Context, PaginatePostOptions) (PostPagination, error)
 		GetAllSelfPaginate(context.Context, PaginatePostOptions) (PostPagination, error)
 		Create(context.Context, CreatePostInput) (domain.Post, error)
 		Update(context.Context, UpdatePostInput) (domain.Post, error)
 		Publish(context.Context, uuid.UUID) (domain.Post, error)
 		SoftDelete(context.Context, SoftDeletePostInput) error
+		Bulk(context.Context, BulkPostInput) ([]BulkPostResult, error)
+	}
+
+	FollowInput struct {
+		FollowerID uuid.UUID
+		FolloweeID uuid.UUID
+	}
+
+	PaginateFollowOptions struct {
+		UserID       uuid.UUID
+		CurrentPage  int
+		UsersPerPage int
+	}
+
+	UserPagination struct {
+		Users        []domain.User
+		UsersCount   int
+		PreviousPage int
+		CurrentPage  int
+		NextPage     int
+		UsersPerPage int
+	}
+
+	FeedOptions struct {
+		UserID       uuid.UUID
+		Cursor       string
+		PostsPerPage int
+	}
+
+	// Feed holds cursor of next page, cursor is empty on last page
+	Feed struct {
+		Posts      []domain.Post
+		NextCursor string
+	}
+
+	Follow interface {
+		Follow(context.Context, FollowInput) error
+		Unfollow(context.Context, FollowInput) error
+		Followers(context.Context, PaginateFollowOptions) (UserPagination, error)
+		Following(context.Context, PaginateFollowOptions) (UserPagination, error)
+		Feed(context.Context, FeedOptions) (Feed, error)
+	}
+
+	SearchUsersOptions struct {
+		Query          string
+		IncludeDeleted bool
+		CurrentPage    int
+		UsersPerPage   int
+	}
+
+	ModerateUserInput struct {
+		ActorID uuid.UUID
+		UserID  uuid.UUID
+	}
+
+	ModeratePostInput struct {
+		ActorID uuid.UUID
+		PostID  uuid.UUID
+	}
+
+	SetMFARolesInput struct {
+		ActorID uuid.UUID
+		Roles   domain.Roles
+	}
+
+	// Admin actions are written to audit log
+	Admin interface {
+		SearchUsers(context.Context, SearchUsersOptions) (UserPagination, error)
+		Suspend(context.Context, ModerateUserInput) (domain.User, error)
+		Unsuspend(context.Context, ModerateUserInput) (domain.User, error)
+		UnpublishPost(context.Context, ModeratePostInput) (domain.Post, error)
+		DeletePost(context.Context, ModeratePostInput) error
+		DeletedPosts(context.Context, PaginatePostOptions) (PostPagination, error)
+		MFARoles(context.Context) (domain.Roles, error)
+		SetMFARoles(context.Context, SetMFARolesInput) (domain.Roles, error)
+	}
+
+	ExportInput struct {
+		UserID         uuid.UUID
+		IncludeDeleted bool
+	}
+
+	Export interface {
+		IsLarge(context.Context, ExportInput) (bool, error)
+		Write(context.Context, io.Writer, ExportInput) error
+		Schedule(context.Context, ExportInput) (string, error)
+		Open(context.Context, uuid.UUID, string) (io.ReadCloser, error)
+		Purge(context.Context) (int, error)
+	}
+
+	PaginateNotificationOptions struct {
+		UserID               uuid.UUID
+		CurrentPage          int
+		NotificationsPerPage int
+	}
+
+	// NotificationPagination holds count of unread notifications of user
+	// besides pagination details
+	NotificationPagination struct {
+		Notifications        []domain.Notification
+		NotificationsCount   int
+		UnreadCount          int
+		PreviousPage         int
+		CurrentPage          int
+		NextPage             int
+		NotificationsPerPage int
+	}
+
+	MarkNotificationReadInput struct {
+		ID     uuid.UUID
+		UserID uuid.UUID
+	}
+
+	// Notifier is called by other services, notifications are best effort,
+	// so failure to notify does not fail action of service
+	Notifier interface {
+		PostPublished(context.Context, domain.Post)
+		PostUnpublished(context.Context, domain.Post, uuid.UUID)
+		SecurityEvent(context.Context, domain.SecurityEvent)
+	}
+
+	Notification interface {
+		GetAll(context.Context, PaginateNotificationOptions) (NotificationPagination, error)
+		MarkRead(context.Context, MarkNotificationReadInput) (domain.Notification, error)
+		MarkAllRead(context.Context, uuid.UUID) error
+	}
+
+	CreateWebhookInput struct {
+		UserID uuid.UUID
+		URL    string
+		Events domain.WebhookEvents
+		Global bool
+	}
+
+	DeleteWebhookInput struct {
+		ID     uuid.UUID
+		UserID uuid.UUID
+	}
+
+	PaginateWebhookDeliveryOptions struct {
+		WebhookID         uuid.UUID
+		UserID            uuid.UUID
+		CurrentPage       int
+		DeliveriesPerPage int
+	}
+
+	WebhookDeliveryPagination struct {
+		Deliveries        []domain.WebhookDelivery
+		DeliveriesCount   int
+		PreviousPage      int
+		CurrentPage       int
+		NextPage          int
+		DeliveriesPerPage int
+	}
+
+	RedeliverWebhookInput struct {
+		ID        uuid.UUID
+		WebhookID uuid.UUID
+		UserID    uuid.UUID
+	}
+
+	// WebhookConfig describes retries of deliveries, claimed delivery is
+	// not attempted by other instances until lease is over
+	WebhookConfig struct {
+		MaxAttempts int
+		BackoffBase time.Duration
+		BackoffMax  time.Duration
+		Lease       time.Duration
+		BatchSize   int
+	}
+
+	// WebhookDispatcher is called by other services, like notifications
+	// dispatch is best effort and does not fail action of service
+	WebhookDispatcher interface {
+		Dispatch(context.Context, domain.WebhookEvent, uuid.UUID, interface{})
+	}
+
+	Webhook interface {
+		Create(context.Context, CreateWebhookInput) (domain.Webhook, error)
+		GetAll(context.Context, uuid.UUID) ([]domain.Webhook, error)
+		Delete(context.Context, DeleteWebhookInput) error
+		Deliveries(context.Context, PaginateWebhookDeliveryOptions) (WebhookDeliveryPagination, error)
+		Redeliver(context.Context, RedeliverWebhookInput) (domain.WebhookDelivery, error)
+		Deliver(context.Context) (int, error)
+	}
+
+	RateLimitInput struct {
+		Group  string
+		UserID uuid.UUID
+		IP     string
+	}
+
+	RateLimit interface {
+		Allow(context.Context, RateLimitInput) (ratelimit.Result, error)
 	}
 
 	Service struct {
 		User
+		Token
+		Session
+		PersonalAccessToken
+		OIDC
+		MFA
+		Verification
+		Password
+		Account
 		Post
+		Follow
+		Export
+		Admin
+		Notification
+		Webhook
+		RateLimit
 		Logger logger.Logger
 	}
 
 	DataProvider interface {
 		UserProvider() repository.User
 		PostProvider() repository.Post
+		RefreshTokenProvider() repository.RefreshToken
+		SessionProvider() repository.Session
+		PasswordResetProvider() repository.PasswordReset
+		SecurityEventProvider() repository.SecurityEvent
+		AccountDeletionProvider() repository.AccountDeletion
+		FollowProvider() repository.Follow
+		TokenDenylistProvider() repository.TokenDenylist
+		SignInAttemptProvider() repository.SignInAttempt
+		MFAChallengeProvider() repository.MFAChallenge
+		AuditLogProvider() repository.AuditLog
+		PersonalAccessTokenProvider() repository.PersonalAccessToken
+		UserIdentityProvider() repository.UserIdentity
+		TOTPFactorProvider() repository.TOTPFactor
+		RecoveryCodeProvider() repository.RecoveryCode
+		MFAPolicyProvider() repository.MFAPolicy
+		NotificationProvider() repository.Notification
+		WebhookProvider() repository.Webhook
+		WebhookDeliveryProvider() repository.WebhookDelivery
 	}
 
 	ServiceDependencies struct {
@@ -109,13 +617,147 @@ type (
 		Hasher                        hash.HashProvider
 		Authorization                 auth.AuthorizationProvider
 		AuthorizationTokenExpiresTime time.Duration
+		RefreshTokenExpiresTime       time.Duration
+		Mailer                        mailer.MailerProvider
+		Signer                        signature.SignatureProvider
+		OIDC                          oidc.Provider
+		TOTPIssuer                    string
+		SignInThrottle                SignInThrottleConfig
+		RateLimiter                   ratelimit.LimiterProvider
+		FallbackRateLimiter           ratelimit.LimiterProvider
+		RateLimits                    map[string]ratelimit.Limit
+		VerificationURL               string
+		EmailChangeURL                string
+		VerificationExpiresTime       time.Duration
+		RequireVerifiedEmail          bool
+		PasswordResetURL              string
+		PasswordResetExpiresTime      time.Duration
+		AccountDeletionGracePeriod    time.Duration
+		ExportDirectory               string
+		ExportPostsLimit              int
+		ExportWorkers                 int
+		ExportRetention               time.Duration
+
+		Webhooks      webhook.Provider
+		WebhookConfig WebhookConfig
 	}
 )
 
 func NewService(deps ServiceDependencies) *Service {
+	notificationService := NewNotificationService(deps.DataProvider.NotificationProvider(), deps.Logger)
+	securityEvents := NewSecurityEventNotifier(deps.DataProvider.SecurityEventProvider(), notificationService)
+	webhookService := NewWebhookService(
+		deps.DataProvider.WebhookProvider(),
+		deps.DataProvider.WebhookDeliveryProvider(),
+		deps.Webhooks,
+		deps.Logger,
+		deps.WebhookConfig,
+	)
+
+	signInThrottle := NewSignInThrottleService(
+		deps.DataProvider.SignInAttemptProvider(),
+		deps.DataProvider.UserProvider(),
+		securityEvents,
+		deps.Mailer,
+		deps.Logger,
+		deps.SignInThrottle,
+	)
+
+	tokenService := NewTokenService(
+		deps.DataProvider.RefreshTokenProvider(),
+		deps.DataProvider.SessionProvider(),
+		deps.DataProvider.PersonalAccessTokenProvider(),
+		deps.DataProvider.UserProvider(),
+		deps.DataProvider.TokenDenylistProvider(),
+		deps.Authorization,
+		deps.AuthorizationTokenExpiresTime,
+		deps.RefreshTokenExpiresTime,
+	)
+
+	verificationService := NewVerificationService(
+		deps.DataProvider.UserProvider(),
+		securityEvents,
+		deps.Mailer,
+		deps.Signer,
+		deps.Logger,
+		deps.VerificationURL,
+		deps.EmailChangeURL,
+		deps.VerificationExpiresTime,
+	)
+
+	mfaService := NewMFAService(
+		deps.DataProvider.UserProvider(),
+		deps.DataProvider.TOTPFactorProvider(),
+		deps.DataProvider.RecoveryCodeProvider(),
+		deps.DataProvider.MFAPolicyProvider(),
+		deps.DataProvider.MFAChallengeProvider(),
+		securityEvents,
+		deps.Hasher,
+		deps.Signer,
+		tokenService,
+		signInThrottle,
+		deps.TOTPIssuer,
+	)
+
 	return &Service{
-		User:   NewUserService(deps.DataProvider.UserProvider(), deps.Hasher, deps.Authorization, deps.AuthorizationTokenExpiresTime),
-		Post:   NewPostService(deps.DataProvider.PostProvider()),
+		User:         NewUserService(deps.DataProvider.UserProvider(), securityEvents, deps.Hasher, mfaService, verificationService, signInThrottle, webhookService),
+		Token:        tokenService,
+		Session:      NewSessionService(deps.DataProvider.SessionProvider(), deps.DataProvider.RefreshTokenProvider(), tokenService),
+		MFA:          mfaService,
+		Verification: verificationService,
+		PersonalAccessToken: NewPersonalAccessTokenService(
+			deps.DataProvider.PersonalAccessTokenProvider(),
+			deps.DataProvider.UserProvider(),
+		),
+		OIDC: NewOIDCService(
+			deps.OIDC,
+			deps.DataProvider.UserProvider(),
+			deps.DataProvider.UserIdentityProvider(),
+			deps.Hasher,
+			deps.Signer,
+			mfaService,
+		),
+		Password: NewPasswordService(
+			deps.DataProvider.UserProvider(),
+			deps.DataProvider.PasswordResetProvider(),
+			deps.Hasher,
+			tokenService,
+			deps.Mailer,
+			deps.Logger,
+			deps.PasswordResetURL,
+			deps.PasswordResetExpiresTime,
+		),
+		Account: NewAccountService(
+			deps.DataProvider.UserProvider(),
+			deps.DataProvider.PostProvider(),
+			deps.DataProvider.AccountDeletionProvider(),
+			securityEvents,
+			deps.Hasher,
+			tokenService,
+			mfaService,
+			deps.Logger,
+			deps.AccountDeletionGracePeriod,
+		),
+		Notification: notificationService,
+		Webhook:      webhookService,
+		RateLimit: NewRateLimitService(
+			deps.RateLimiter,
+			deps.FallbackRateLimiter,
+			deps.Logger,
+			deps.RateLimits,
+		),
+		Post:   NewPostService(deps.DataProvider.PostProvider(), deps.DataProvider.UserProvider(), notificationService, webhookService, deps.RequireVerifiedEmail),
+		Follow: NewFollowService(deps.DataProvider.FollowProvider(), deps.DataProvider.UserProvider(), deps.DataProvider.PostProvider()),
+		Export: NewExportService(
+			deps.DataProvider.UserProvider(),
+			deps.DataProvider.PostProvider(),
+			deps.Logger,
+			deps.ExportDirectory,
+			deps.ExportPostsLimit,
+			deps.ExportWorkers,
+			deps.ExportRetention,
+		),
+		Admin:  NewAdminService(deps.DataProvider.UserProvider(), deps.DataProvider.PostProvider(), deps.DataProvider.AuditLogProvider(), deps.DataProvider.MFAPolicyProvider(), notificationService, webhookService, tokenService),
 		Logger: deps.Logger,
 	}
 }
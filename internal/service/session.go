@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	uuid "github.com/satori/go.uuid"
+)
+
+type SessionService struct {
+	repo          repository.Session
+	refreshTokens repository.RefreshToken
+	tokens        Token
+}
+
+func NewSessionService(repo repository.Session, refreshTokens repository.RefreshToken, tokens Token) *SessionService {
+	return &SessionService{repo: repo, refreshTokens: refreshTokens, tokens: tokens}
+}
+
+func (s *SessionService) GetAll(ctx context.Context, userID uuid.UUID) ([]domain.Session, error) {
+	return s.repo.GetAllActiveWithUserID(ctx, userID)
+}
+
+// Revoke signs out single session, its access tokens are rejected at once
+// and refresh tokens could not be rotated anymore
+func (s *SessionService) Revoke(ctx context.Context, input RevokeSessionInput) error {
+	if err := s.repo.Revoke(ctx, input.ID, input.UserID); err != nil {
+		return err
+	}
+
+	return s.refreshTokens.RevokeFamily(ctx, input.ID)
+}
+
+func (s *SessionService) RevokeAll(ctx context.Context, userID uuid.UUID) error {
+	return s.tokens.RevokeAll(ctx, userID)
+}
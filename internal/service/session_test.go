@@ -0,0 +1,89 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	mock_service "github.com/aintsashqa/go-simple-blog/internal/service/mocks"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SessionServiceSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockSessionRepository      *mock_repository.MockSession
+	MockRefreshTokenRepository *mock_repository.MockRefreshToken
+	MockTokenService           *mock_service.MockToken
+
+	CurrentService service.Session
+}
+
+func TestSessionServiceSuite(t *testing.T) {
+	suite.Run(t, new(SessionServiceSuite))
+}
+
+func (s *SessionServiceSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockSessionRepository = mock_repository.NewMockSession(s.Controller)
+	s.MockRefreshTokenRepository = mock_repository.NewMockRefreshToken(s.Controller)
+	s.MockTokenService = mock_service.NewMockToken(s.Controller)
+	s.CurrentService = service.NewSessionService(s.MockSessionRepository, s.MockRefreshTokenRepository, s.MockTokenService)
+}
+
+func (s *SessionServiceSuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *SessionServiceSuite) TestRevokeMethod() {
+	type MockBehavior func(s *SessionServiceSuite, input service.RevokeSessionInput)
+
+	input := service.RevokeSessionInput{ID: uuid.NewV4(), UserID: uuid.NewV4()}
+
+	methodCases := []struct {
+		Name              string
+		MethodResultError error
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name: "Success",
+			MockBehavior: func(s *SessionServiceSuite, input service.RevokeSessionInput) {
+				s.MockSessionRepository.EXPECT().
+					Revoke(context.Background(), input.ID, input.UserID).
+					Return(nil).
+					Times(1)
+				s.MockRefreshTokenRepository.EXPECT().
+					RevokeFamily(context.Background(), input.ID).
+					Return(nil).
+					Times(1)
+			},
+		},
+		{
+			Name:              "NotFound",
+			MethodResultError: repoerrors.ErrSessionNotFound,
+			MockBehavior: func(s *SessionServiceSuite, input service.RevokeSessionInput) {
+				s.MockSessionRepository.EXPECT().
+					Revoke(context.Background(), input.ID, input.UserID).
+					Return(repoerrors.ErrSessionNotFound).
+					Times(1)
+			},
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			currentCase.MockBehavior(s, input)
+			err := s.CurrentService.Revoke(context.Background(), input)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/logger"
+	"github.com/aintsashqa/go-simple-blog/pkg/mailer"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	signInLockedMailSubject string = "Sign in to your account is locked"
+	signInLockedMailBody    string = "There were too many failed attempts to sign in to your account, so sign in is locked until %s. If it was not you, consider changing your password."
+)
+
+type SignInThrottleService struct {
+	attempts repository.SignInAttempt
+	users    repository.User
+	events   repository.SecurityEvent
+	mailer   mailer.MailerProvider
+	logger   logger.Logger
+	config   SignInThrottleConfig
+}
+
+func NewSignInThrottleService(
+	attempts repository.SignInAttempt,
+	users repository.User,
+	events repository.SecurityEvent,
+	mailer mailer.MailerProvider,
+	logger logger.Logger,
+	config SignInThrottleConfig,
+) *SignInThrottleService {
+	return &SignInThrottleService{
+		attempts: attempts,
+		users:    users,
+		events:   events,
+		mailer:   mailer,
+		logger:   logger,
+		config:   config,
+	}
+}
+
+func (s *SignInThrottleService) Check(ctx context.Context, input SignInUserInput) error {
+	for _, key := range s.keys(input) {
+		until, err := s.attempts.LockedUntil(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		if time.Now().Before(until) {
+			return errors.ErrSignInLocked
+		}
+	}
+
+	return nil
+}
+
+// Fail locks account and address when limit is reached, lockout is doubled
+// with every next failure. Owner of account is notified once per lockout series
+func (s *SignInThrottleService) Fail(ctx context.Context, input SignInUserInput) error {
+	failures, err := s.attempts.Fail(ctx, accountKey(input.Email), s.config.FailuresWindow)
+	if err != nil {
+		return err
+	}
+
+	if failures >= s.config.AccountLimit {
+		until := time.Now().Add(s.lockout(failures - s.config.AccountLimit))
+		if err := s.attempts.Lock(ctx, accountKey(input.Email), until); err != nil {
+			return err
+		}
+
+		if failures == s.config.AccountLimit {
+			if err := s.notify(ctx, input.Email, until); err != nil {
+				return err
+			}
+		}
+	}
+
+	if input.IP == "" {
+		return nil
+	}
+
+	failures, err = s.attempts.Fail(ctx, ipKey(input.IP), s.config.FailuresWindow)
+	if err != nil {
+		return err
+	}
+
+	if failures >= s.config.IPLimit {
+		until := time.Now().Add(s.lockout(failures - s.config.IPLimit))
+		return s.attempts.Lock(ctx, ipKey(input.IP), until)
+	}
+
+	return nil
+}
+
+// Reset forgets failures of account only, otherwise anybody could reset
+// counter of address by signing in to own account
+func (s *SignInThrottleService) Reset(ctx context.Context, input SignInUserInput) error {
+	return s.attempts.Reset(ctx, accountKey(input.Email))
+}
+
+// CheckSecondFactor is checked before code of challenge is verified, lock
+// is kept for user, so new challenge does not reset it
+func (s *SignInThrottleService) CheckSecondFactor(ctx context.Context, userID uuid.UUID) error {
+	until, err := s.attempts.LockedUntil(ctx, secondFactorKey(userID))
+	if err != nil {
+		return err
+	}
+
+	if time.Now().Before(until) {
+		return errors.ErrSignInLocked
+	}
+
+	return nil
+}
+
+// FailSecondFactor locks verification of second factor like Fail does for
+// account, failures are not reset by correct password
+func (s *SignInThrottleService) FailSecondFactor(ctx context.Context, userID uuid.UUID) error {
+	failures, err := s.attempts.Fail(ctx, secondFactorKey(userID), s.config.FailuresWindow)
+	if err != nil {
+		return err
+	}
+
+	if failures < s.config.AccountLimit {
+		return nil
+	}
+
+	until := time.Now().Add(s.lockout(failures - s.config.AccountLimit))
+	if err := s.attempts.Lock(ctx, secondFactorKey(userID), until); err != nil {
+		return err
+	}
+
+	if failures != s.config.AccountLimit {
+		return nil
+	}
+
+	user, err := s.users.Self(ctx, userID)
+	if err == repoerrors.ErrUserNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.notify(ctx, user.Email, until)
+}
+
+func (s *SignInThrottleService) ResetSecondFactor(ctx context.Context, userID uuid.UUID) error {
+	return s.attempts.Reset(ctx, secondFactorKey(userID))
+}
+
+func (s *SignInThrottleService) lockout(exceeded int64) time.Duration {
+	lockout := s.config.LockoutTime
+	for i := int64(0); i < exceeded && lockout < s.config.MaxLockoutTime; i++ {
+		lockout *= 2
+	}
+
+	if lockout > s.config.MaxLockoutTime {
+		return s.config.MaxLockoutTime
+	}
+	return lockout
+}
+
+func (s *SignInThrottleService) notify(ctx context.Context, email string, until time.Time) error {
+	user, err := s.users.GetByEmail(ctx, email)
+	if err == repoerrors.ErrUserNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.events.Create(ctx, domain.NewSecurityEvent(user.ID, domain.SignInLockedSecurityEvent)); err != nil {
+		return err
+	}
+
+	message := mailer.Message{
+		To:      user.Email,
+		Subject: signInLockedMailSubject,
+		Body:    fmt.Sprintf(signInLockedMailBody, until.Format(time.RFC1123)),
+	}
+
+	go func() {
+		if err := s.mailer.Send(context.Background(), message); err != nil {
+			s.logger.Errorf("service.SignInThrottle.Fail error: %s", err)
+		}
+	}()
+
+	return nil
+}
+
+func (s *SignInThrottleService) keys(input SignInUserInput) []string {
+	keys := []string{accountKey(input.Email)}
+	if input.IP != "" {
+		keys = append(keys, ipKey(input.IP))
+	}
+	return keys
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+func secondFactorKey(userID uuid.UUID) string {
+	return "mfa:" + userID.String()
+}
@@ -0,0 +1,319 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	mock_logger "github.com/aintsashqa/go-simple-blog/pkg/logger/mocks"
+	"github.com/aintsashqa/go-simple-blog/pkg/mailer"
+	mock_mailer "github.com/aintsashqa/go-simple-blog/pkg/mailer/mocks"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SignInThrottleServiceSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockSignInAttemptRepository *mock_repository.MockSignInAttempt
+	MockUserRepository          *mock_repository.MockUser
+	MockSecurityEventRepository *mock_repository.MockSecurityEvent
+	MockMailerProvider          *mock_mailer.MockMailerProvider
+	MockLogger                  *mock_logger.MockLogger
+
+	CurrentService service.SignInThrottle
+}
+
+func TestSignInThrottleServiceSuite(t *testing.T) {
+	suite.Run(t, new(SignInThrottleServiceSuite))
+}
+
+func (s *SignInThrottleServiceSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockSignInAttemptRepository = mock_repository.NewMockSignInAttempt(s.Controller)
+	s.MockUserRepository = mock_repository.NewMockUser(s.Controller)
+	s.MockSecurityEventRepository = mock_repository.NewMockSecurityEvent(s.Controller)
+	s.MockMailerProvider = mock_mailer.NewMockMailerProvider(s.Controller)
+	s.MockLogger = mock_logger.NewMockLogger(s.Controller)
+	s.CurrentService = service.NewSignInThrottleService(
+		s.MockSignInAttemptRepository,
+		s.MockUserRepository,
+		s.MockSecurityEventRepository,
+		s.MockMailerProvider,
+		s.MockLogger,
+		service.SignInThrottleConfig{
+			AccountLimit:   5,
+			IPLimit:        50,
+			LockoutTime:    time.Minute,
+			MaxLockoutTime: time.Hour,
+			FailuresWindow: 24 * time.Hour,
+		},
+	)
+}
+
+func (s *SignInThrottleServiceSuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *SignInThrottleServiceSuite) TestCheckMethod() {
+	input := service.SignInUserInput{Email: " Root@Example.com", Client: service.Client{IP: "127.0.0.1"}}
+
+	methodCases := []struct {
+		Name              string
+		AccountLocked     time.Time
+		IPLocked          time.Time
+		MethodResultError error
+	}{
+		{
+			Name: "NotLocked",
+		},
+		{
+			Name:          "LockExpired",
+			AccountLocked: time.Now().Add(-time.Minute),
+		},
+		{
+			Name:              "AccountLocked",
+			AccountLocked:     time.Now().Add(time.Minute),
+			MethodResultError: serviceerrors.ErrSignInLocked,
+		},
+		{
+			Name:              "IPLocked",
+			IPLocked:          time.Now().Add(time.Minute),
+			MethodResultError: serviceerrors.ErrSignInLocked,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			s.MockSignInAttemptRepository.EXPECT().
+				LockedUntil(context.Background(), "account:root@example.com").
+				Return(currentCase.AccountLocked, nil).
+				Times(1)
+			if currentCase.AccountLocked.Before(time.Now()) {
+				s.MockSignInAttemptRepository.EXPECT().
+					LockedUntil(context.Background(), "ip:127.0.0.1").
+					Return(currentCase.IPLocked, nil).
+					Times(1)
+			}
+			err := s.CurrentService.Check(context.Background(), input)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
+
+func (s *SignInThrottleServiceSuite) TestFailMethod() {
+	type MockBehavior func(s *SignInThrottleServiceSuite)
+
+	input := service.SignInUserInput{Email: "root@example.com", Client: service.Client{IP: "127.0.0.1"}}
+	user := domain.User{Model: domain.Model{ID: uuid.NewV4()}, Email: "root@example.com"}
+
+	mockFailBehavior := func(s *SignInThrottleServiceSuite, key string, returns int64) {
+		s.MockSignInAttemptRepository.EXPECT().
+			Fail(context.Background(), key, 24*time.Hour).
+			Return(returns, nil).
+			Times(1)
+	}
+
+	mockLockBehavior := func(s *SignInThrottleServiceSuite, key string, lockout time.Duration) {
+		s.MockSignInAttemptRepository.EXPECT().
+			Lock(context.Background(), key, gomock.AssignableToTypeOf(time.Time{})).
+			DoAndReturn(func(_ context.Context, _ string, until time.Time) error {
+				s.Assertions.WithinDuration(time.Now().Add(lockout), until, time.Second)
+				return nil
+			}).
+			Times(1)
+	}
+
+	methodCases := []struct {
+		Name         string
+		MockBehavior MockBehavior
+	}{
+		{
+			Name: "BelowLimit",
+			MockBehavior: func(s *SignInThrottleServiceSuite) {
+				mockFailBehavior(s, "account:root@example.com", 4)
+				mockFailBehavior(s, "ip:127.0.0.1", 4)
+			},
+		},
+		{
+			Name: "AccountLocked",
+			MockBehavior: func(s *SignInThrottleServiceSuite) {
+				mockFailBehavior(s, "account:root@example.com", 5)
+				mockLockBehavior(s, "account:root@example.com", time.Minute)
+				s.MockUserRepository.EXPECT().
+					GetByEmail(context.Background(), input.Email).
+					Return(user, nil).
+					Times(1)
+				s.MockSecurityEventRepository.EXPECT().
+					Create(context.Background(), gomock.AssignableToTypeOf(domain.SecurityEvent{})).
+					DoAndReturn(func(_ context.Context, event domain.SecurityEvent) error {
+						s.Assertions.Equal(user.ID, event.UserID)
+						s.Assertions.Equal(domain.SignInLockedSecurityEvent, event.Type)
+						return nil
+					}).
+					Times(1)
+
+				sent := make(chan struct{})
+				s.MockMailerProvider.EXPECT().
+					Send(context.Background(), gomock.AssignableToTypeOf(mailer.Message{})).
+					DoAndReturn(func(_ context.Context, message mailer.Message) error {
+						s.Assertions.Equal(user.Email, message.To)
+						close(sent)
+						return nil
+					}).
+					Times(1)
+				s.T().Cleanup(func() { <-sent })
+
+				mockFailBehavior(s, "ip:127.0.0.1", 5)
+			},
+		},
+		{
+			Name: "UnknownAccountLocked",
+			MockBehavior: func(s *SignInThrottleServiceSuite) {
+				mockFailBehavior(s, "account:root@example.com", 5)
+				mockLockBehavior(s, "account:root@example.com", time.Minute)
+				s.MockUserRepository.EXPECT().
+					GetByEmail(context.Background(), input.Email).
+					Return(domain.User{}, repoerrors.ErrUserNotFound).
+					Times(1)
+				mockFailBehavior(s, "ip:127.0.0.1", 5)
+			},
+		},
+		{
+			Name: "LockoutDoubled",
+			MockBehavior: func(s *SignInThrottleServiceSuite) {
+				mockFailBehavior(s, "account:root@example.com", 8)
+				mockLockBehavior(s, "account:root@example.com", 8*time.Minute)
+				mockFailBehavior(s, "ip:127.0.0.1", 8)
+			},
+		},
+		{
+			Name: "LockoutLimited",
+			MockBehavior: func(s *SignInThrottleServiceSuite) {
+				mockFailBehavior(s, "account:root@example.com", 100)
+				mockLockBehavior(s, "account:root@example.com", time.Hour)
+				mockFailBehavior(s, "ip:127.0.0.1", 100)
+				mockLockBehavior(s, "ip:127.0.0.1", time.Hour)
+			},
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			currentCase.MockBehavior(s)
+			err := s.CurrentService.Fail(context.Background(), input)
+			s.Assertions.NoError(err)
+		})
+	}
+}
+
+func (s *SignInThrottleServiceSuite) TestResetMethod() {
+	s.MockSignInAttemptRepository.EXPECT().
+		Reset(context.Background(), "account:root@example.com").
+		Return(nil).
+		Times(1)
+
+	err := s.CurrentService.Reset(context.Background(), service.SignInUserInput{Email: "root@example.com", Client: service.Client{IP: "127.0.0.1"}})
+	s.Assertions.NoError(err)
+}
+
+func (s *SignInThrottleServiceSuite) TestFailSecondFactorMethod() {
+	type MockBehavior func(s *SignInThrottleServiceSuite, key string)
+
+	user := domain.User{Model: domain.Model{ID: uuid.NewV4()}, Email: "root@example.com"}
+	key := "mfa:" + user.ID.String()
+
+	mockFailBehavior := func(returns int64) MockBehavior {
+		return func(s *SignInThrottleServiceSuite, key string) {
+			s.MockSignInAttemptRepository.EXPECT().
+				Fail(context.Background(), key, 24*time.Hour).
+				Return(returns, nil).
+				Times(1)
+		}
+	}
+
+	mockLockBehavior := func(failures int64, lockout time.Duration) MockBehavior {
+		return func(s *SignInThrottleServiceSuite, key string) {
+			mockFailBehavior(failures)(s, key)
+			s.MockSignInAttemptRepository.EXPECT().
+				Lock(context.Background(), key, gomock.AssignableToTypeOf(time.Time{})).
+				DoAndReturn(func(_ context.Context, _ string, until time.Time) error {
+					s.Assertions.WithinDuration(time.Now().Add(lockout), until, time.Second)
+					return nil
+				}).
+				Times(1)
+		}
+	}
+
+	methodCases := []struct {
+		Name         string
+		MockBehavior MockBehavior
+	}{
+		{
+			Name:         "BelowLimit",
+			MockBehavior: mockFailBehavior(4),
+		},
+		{
+			Name: "Locked",
+			MockBehavior: func(s *SignInThrottleServiceSuite, key string) {
+				mockLockBehavior(5, time.Minute)(s, key)
+				s.MockUserRepository.EXPECT().
+					Self(context.Background(), user.ID).
+					Return(user, nil).
+					Times(1)
+				s.MockUserRepository.EXPECT().
+					GetByEmail(context.Background(), user.Email).
+					Return(user, nil).
+					Times(1)
+				s.MockSecurityEventRepository.EXPECT().
+					Create(context.Background(), gomock.AssignableToTypeOf(domain.SecurityEvent{})).
+					Return(nil).
+					Times(1)
+
+				sent := make(chan struct{})
+				s.MockMailerProvider.EXPECT().
+					Send(context.Background(), gomock.AssignableToTypeOf(mailer.Message{})).
+					DoAndReturn(func(context.Context, mailer.Message) error {
+						close(sent)
+						return nil
+					}).
+					Times(1)
+				s.T().Cleanup(func() { <-sent })
+			},
+		},
+		{
+			Name:         "LockoutDoubled",
+			MockBehavior: mockLockBehavior(7, 4*time.Minute),
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			currentCase.MockBehavior(s, key)
+			err := s.CurrentService.FailSecondFactor(context.Background(), user.ID)
+			s.Assertions.NoError(err)
+		})
+	}
+}
+
+func (s *SignInThrottleServiceSuite) TestCheckSecondFactorMethod() {
+	userID := uuid.NewV4()
+	s.MockSignInAttemptRepository.EXPECT().
+		LockedUntil(context.Background(), "mfa:"+userID.String()).
+		Return(time.Now().Add(time.Minute), nil).
+		Times(1)
+
+	err := s.CurrentService.CheckSecondFactor(context.Background(), userID)
+	s.Assertions.Equal(serviceerrors.ErrSignInLocked, err)
+}
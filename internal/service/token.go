@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/auth"
+	"github.com/aintsashqa/go-simple-blog/pkg/random"
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	refreshTokenSize int = 32
+
+	// Session is touched with minute precision, so access token used
+	// on every request does not update database every time
+	sessionTouchInterval time.Duration = time.Minute
+	sessionUserAgentSize int           = 255
+)
+
+type TokenService struct {
+	repo                    repository.RefreshToken
+	sessions                repository.Session
+	accessTokens            repository.PersonalAccessToken
+	users                   repository.User
+	denylist                repository.TokenDenylist
+	auth                    auth.AuthorizationProvider
+	accessTokenExpiresTime  time.Duration
+	refreshTokenExpiresTime time.Duration
+}
+
+func NewTokenService(
+	repo repository.RefreshToken,
+	sessions repository.Session,
+	accessTokens repository.PersonalAccessToken,
+	users repository.User,
+	denylist repository.TokenDenylist,
+	auth auth.AuthorizationProvider,
+	accessTokenExpiresTime time.Duration,
+	refreshTokenExpiresTime time.Duration,
+) *TokenService {
+	return &TokenService{
+		repo:                    repo,
+		sessions:                sessions,
+		accessTokens:            accessTokens,
+		users:                   users,
+		denylist:                denylist,
+		auth:                    auth,
+		accessTokenExpiresTime:  accessTokenExpiresTime,
+		refreshTokenExpiresTime: refreshTokenExpiresTime,
+	}
+}
+
+// hashToken is used instead of password hasher, because refresh tokens
+// have enough entropy and must be looked up by hash
+func hashToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+// Issue starts new session of signed in user, id of session is used as
+// family of refresh tokens
+func (s *TokenService) Issue(ctx context.Context, userID uuid.UUID, client Client) (Tokens, error) {
+	user, err := s.user(ctx, userID)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	userAgent := client.UserAgent
+	if len(userAgent) > sessionUserAgentSize {
+		userAgent = userAgent[:sessionUserAgentSize]
+	}
+
+	session := domain.NewSession(user.ID, userAgent, client.IP)
+	if err := s.sessions.Create(ctx, session); err != nil {
+		return Tokens{}, err
+	}
+
+	return s.issue(ctx, user, session.ID)
+}
+
+func (s *TokenService) user(ctx context.Context, userID uuid.UUID) (domain.User, error) {
+	user, err := s.users.Self(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if user.IsSuspended() {
+		return domain.User{}, errors.ErrUserSuspended
+	}
+
+	return user, nil
+}
+
+// issue reads current roles of user, so changed roles are applied on next refresh
+func (s *TokenService) issue(ctx context.Context, user domain.User, sessionID uuid.UUID) (Tokens, error) {
+	roles := make([]string, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		roles = append(roles, string(role))
+	}
+
+	accessToken, err := s.auth.NewToken(auth.TokenParams{
+		UserID:    user.ID,
+		TokenID:   uuid.NewV4().String(),
+		SessionID: sessionID.String(),
+		Roles:     roles,
+		ExpiresAt: s.accessTokenExpiresTime,
+	})
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	refreshToken, err := random.Token(refreshTokenSize)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	now := time.Now()
+	token := domain.RefreshToken{
+		ID:        uuid.NewV4(),
+		UserID:    user.ID,
+		FamilyID:  sessionID,
+		TokenHash: hashToken(refreshToken),
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.refreshTokenExpiresTime),
+		RevokedAt: null.NewTime(now, false),
+	}
+
+	if err := s.repo.Create(ctx, token); err != nil {
+		return Tokens{}, err
+	}
+
+	return Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    s.accessTokenExpiresTime,
+	}, nil
+}
+
+// Refresh rotates refresh token, presenting already rotated token means it
+// was leaked, so whole family is revoked
+func (s *TokenService) Refresh(ctx context.Context, input RefreshTokenInput) (Tokens, error) {
+	token, err := s.repo.FindWithHash(ctx, hashToken(input.RefreshToken))
+	if err == repoerrors.ErrRefreshTokenNotFound {
+		return Tokens{}, errors.ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	if token.IsRevoked() {
+		return Tokens{}, s.revokeFamily(ctx, token)
+	}
+
+	if token.IsExpired() {
+		return Tokens{}, errors.ErrRefreshTokenExpired
+	}
+
+	// Families issued before sessions were recorded have no session,
+	// so users of them must sign in again
+	session, err := s.sessions.Find(ctx, token.FamilyID)
+	if err == repoerrors.ErrSessionNotFound {
+		return Tokens{}, errors.ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	if session.IsRevoked() {
+		return Tokens{}, errors.ErrRefreshTokenInvalid
+	}
+
+	user, err := s.user(ctx, token.UserID)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	revoked, err := s.repo.Revoke(ctx, token.ID)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	if !revoked {
+		// Token was rotated by concurrent request
+		return Tokens{}, s.revokeFamily(ctx, token)
+	}
+
+	if err := s.sessions.Touch(ctx, session.ID, input.IP, time.Now()); err != nil {
+		return Tokens{}, err
+	}
+
+	return s.issue(ctx, user, session.ID)
+}
+
+// revokeFamily signs out session of leaked token
+func (s *TokenService) revokeFamily(ctx context.Context, token domain.RefreshToken) error {
+	if err := s.repo.RevokeFamily(ctx, token.FamilyID); err != nil {
+		return err
+	}
+
+	if err := s.sessions.Revoke(ctx, token.FamilyID, token.UserID); err != nil && err != repoerrors.ErrSessionNotFound {
+		return err
+	}
+
+	return errors.ErrRefreshTokenReused
+}
+
+// SignOut denies access token until it expires, revokes its session and
+// family of refresh token
+func (s *TokenService) SignOut(ctx context.Context, input SignOutInput) error {
+	claims, err := s.auth.Parse(input.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	if exp := time.Until(claims.ExpiresAt); len(claims.TokenID) != 0 && exp > 0 {
+		if err := s.denylist.Add(ctx, claims.TokenID, exp); err != nil {
+			return err
+		}
+	}
+
+	if sessionID := uuid.FromStringOrNil(claims.SessionID); !uuid.Equal(sessionID, uuid.Nil) {
+		if err := s.sessions.Revoke(ctx, sessionID, claims.UserID); err != nil && err != repoerrors.ErrSessionNotFound {
+			return err
+		}
+
+		if err := s.repo.RevokeFamily(ctx, sessionID); err != nil {
+			return err
+		}
+	}
+
+	if len(input.RefreshToken) == 0 {
+		return nil
+	}
+
+	token, err := s.repo.FindWithHash(ctx, hashToken(input.RefreshToken))
+	if err == repoerrors.ErrRefreshTokenNotFound {
+		return errors.ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return err
+	}
+
+	if token.UserID != claims.UserID {
+		return errors.ErrRefreshTokenInvalid
+	}
+
+	return s.repo.RevokeFamily(ctx, token.FamilyID)
+}
+
+func (s *TokenService) Authenticate(ctx context.Context, input AuthenticateUserInput) (Identity, error) {
+	claims, err := s.auth.Parse(input.Token)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	// Tokens without identifier or session could not be revoked
+	sessionID := uuid.FromStringOrNil(claims.SessionID)
+	if len(claims.TokenID) == 0 || uuid.Equal(sessionID, uuid.Nil) {
+		return Identity{}, errors.ErrAccessTokenInvalid
+	}
+
+	denied, err := s.denylist.Contains(ctx, claims.TokenID)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	if denied {
+		return Identity{}, errors.ErrAccessTokenRevoked
+	}
+
+	before, err := s.denylist.RevokedBefore(ctx, claims.UserID)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	// Tokens are issued with seconds precision, so token issued
+	// within same second as revocation is revoked too
+	if !before.IsZero() && !claims.IssuedAt.After(before) {
+		return Identity{}, errors.ErrAccessTokenRevoked
+	}
+
+	if err := s.checkSession(ctx, sessionID, claims.UserID, input.IP); err != nil {
+		return Identity{}, err
+	}
+
+	// Suspended user is rejected even when token is not revoked yet
+	user, err := s.users.Self(ctx, claims.UserID)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	if user.IsSuspended() {
+		return Identity{}, errors.ErrUserSuspended
+	}
+
+	// Roles are taken from user, so demoted user loses access before
+	// token expires
+	return Identity{UserID: user.ID, Roles: user.Roles, SessionID: sessionID}, nil
+}
+
+// checkSession rejects access tokens of revoked session at once, without
+// waiting for token to expire
+func (s *TokenService) checkSession(ctx context.Context, sessionID uuid.UUID, userID uuid.UUID, ip string) error {
+	session, err := s.sessions.Find(ctx, sessionID)
+	if err == repoerrors.ErrSessionNotFound {
+		return errors.ErrAccessTokenRevoked
+	}
+	if err != nil {
+		return err
+	}
+
+	if !uuid.Equal(session.UserID, userID) {
+		return errors.ErrAccessTokenInvalid
+	}
+
+	if session.IsRevoked() {
+		return errors.ErrAccessTokenRevoked
+	}
+
+	if now := time.Now(); now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		return s.sessions.Touch(ctx, session.ID, ip, now)
+	}
+
+	return nil
+}
+
+func (i Identity) HasRole(roles ...domain.Role) bool {
+	return i.Roles.HasAny(roles...)
+}
+
+// IsPersonalAccessToken reports whether user is authenticated with
+// personal access token instead of access token of session
+func (i Identity) IsPersonalAccessToken() bool {
+	return !uuid.Equal(i.PersonalAccessTokenID, uuid.Nil)
+}
+
+// HasScope reports whether scope is granted, access tokens of session
+// are granted all scopes
+func (i Identity) HasScope(scope domain.Scope) bool {
+	return !i.IsPersonalAccessToken() || i.Scopes.Contains(scope)
+}
+
+// RevokeAll signs user out from all sessions, personal access tokens are
+// revoked as well
+func (s *TokenService) RevokeAll(ctx context.Context, userID uuid.UUID) error {
+	if err := s.sessions.RevokeAllWithUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.accessTokens.DeleteAllWithUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.RevokeAllWithUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	return s.denylist.RevokeBefore(ctx, userID, time.Now(), s.accessTokenExpiresTime)
+}
+
+// PublicKeys lets other services verify access tokens without shared secret
+func (s *TokenService) PublicKeys(ctx context.Context) []auth.PublicKey {
+	return s.auth.PublicKeys()
+}
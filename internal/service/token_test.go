@@ -0,0 +1,514 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/auth"
+	mock_auth "github.com/aintsashqa/go-simple-blog/pkg/auth/mocks"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v4"
+)
+
+type TokenServiceSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockRefreshTokenRepository *mock_repository.MockRefreshToken
+	MockSessionRepository      *mock_repository.MockSession
+	MockAccessTokenRepository  *mock_repository.MockPersonalAccessToken
+	MockUserRepository         *mock_repository.MockUser
+	MockTokenDenylist          *mock_repository.MockTokenDenylist
+	MockAuthProvider           *mock_auth.MockAuthorizationProvider
+
+	AccessTokenExpiresTime  time.Duration
+	RefreshTokenExpiresTime time.Duration
+
+	CurrentService service.Token
+}
+
+func TestTokenServiceSuite(t *testing.T) {
+	suite.Run(t, new(TokenServiceSuite))
+}
+
+func (s *TokenServiceSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockRefreshTokenRepository = mock_repository.NewMockRefreshToken(s.Controller)
+	s.MockSessionRepository = mock_repository.NewMockSession(s.Controller)
+	s.MockAccessTokenRepository = mock_repository.NewMockPersonalAccessToken(s.Controller)
+	s.MockUserRepository = mock_repository.NewMockUser(s.Controller)
+	s.MockTokenDenylist = mock_repository.NewMockTokenDenylist(s.Controller)
+	s.MockAuthProvider = mock_auth.NewMockAuthorizationProvider(s.Controller)
+	s.AccessTokenExpiresTime = time.Duration(time.Minute * 15)
+	s.RefreshTokenExpiresTime = time.Duration(time.Hour * 24)
+	s.CurrentService = service.NewTokenService(
+		s.MockRefreshTokenRepository,
+		s.MockSessionRepository,
+		s.MockAccessTokenRepository,
+		s.MockUserRepository,
+		s.MockTokenDenylist,
+		s.MockAuthProvider,
+		s.AccessTokenExpiresTime,
+		s.RefreshTokenExpiresTime,
+	)
+}
+
+func (s *TokenServiceSuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *TokenServiceSuite) TestIssueMethod() {
+	userID := uuid.NewV4()
+	client := service.Client{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}
+
+	s.MockUserRepository.EXPECT().
+		Self(context.Background(), userID).
+		Return(domain.User{Model: domain.Model{ID: userID}, Roles: domain.Roles{domain.AuthorRole, domain.EditorRole}}, nil).
+		Times(1)
+
+	var session domain.Session
+	s.MockSessionRepository.EXPECT().
+		Create(context.Background(), gomock.AssignableToTypeOf(domain.Session{})).
+		DoAndReturn(func(_ context.Context, created domain.Session) error {
+			session = created
+			return nil
+		}).
+		Times(1)
+	s.MockAuthProvider.EXPECT().
+		NewToken(gomock.AssignableToTypeOf(auth.TokenParams{})).
+		DoAndReturn(func(params auth.TokenParams) (string, error) {
+			s.Assertions.Equal([]string{"author", "editor"}, params.Roles)
+			s.Assertions.Equal(session.ID.String(), params.SessionID)
+			return "access-token-valid", nil
+		}).
+		Times(1)
+
+	var stored domain.RefreshToken
+	s.MockRefreshTokenRepository.EXPECT().
+		Create(context.Background(), gomock.AssignableToTypeOf(domain.RefreshToken{})).
+		DoAndReturn(func(_ context.Context, token domain.RefreshToken) error {
+			stored = token
+			return nil
+		}).
+		Times(1)
+
+	result, err := s.CurrentService.Issue(context.Background(), userID, client)
+	s.Assertions.NoError(err)
+	s.Assertions.Equal("access-token-valid", result.AccessToken)
+	s.Assertions.Equal(s.AccessTokenExpiresTime, result.ExpiresIn)
+	s.Assertions.NotEmpty(result.RefreshToken)
+	s.Assertions.NotEqual(result.RefreshToken, stored.TokenHash)
+	s.Assertions.Equal(userID, stored.UserID)
+	s.Assertions.False(stored.IsRevoked())
+	s.Assertions.Equal(session.ID, stored.FamilyID)
+	s.Assertions.Equal(userID, session.UserID)
+	s.Assertions.Equal(client.UserAgent, session.UserAgent)
+	s.Assertions.Equal(client.IP, session.IP)
+}
+
+func (s *TokenServiceSuite) TestRefreshMethod() {
+	type MockBehavior func(s *TokenServiceSuite, token domain.RefreshToken, returns error)
+
+	repositoryResultError := errors.New("RepositoryResultError")
+
+	activeToken := domain.RefreshToken{
+		ID:        uuid.NewV4(),
+		UserID:    uuid.NewV4(),
+		FamilyID:  uuid.NewV4(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	revokedToken := activeToken
+	revokedToken.RevokedAt = null.NewTime(time.Now(), true)
+
+	expiredToken := activeToken
+	expiredToken.ExpiresAt = time.Now().Add(-time.Hour)
+
+	activeSession := domain.Session{
+		ID:         activeToken.FamilyID,
+		UserID:     activeToken.UserID,
+		LastSeenAt: time.Now().Add(-time.Hour),
+	}
+
+	revokedSession := activeSession
+	revokedSession.RevokedAt = null.NewTime(time.Now(), true)
+
+	mockFindBehavior := func(s *TokenServiceSuite, token domain.RefreshToken, returns error) {
+		s.MockRefreshTokenRepository.EXPECT().
+			FindWithHash(context.Background(), gomock.Any()).
+			Return(token, returns).
+			Times(1)
+	}
+
+	mockFindSessionBehavior := func(s *TokenServiceSuite, token domain.RefreshToken, session domain.Session, returns error) {
+		mockFindBehavior(s, token, nil)
+		s.MockSessionRepository.EXPECT().
+			Find(context.Background(), token.FamilyID).
+			Return(session, returns).
+			Times(1)
+	}
+
+	mockSelfBehavior := func(s *TokenServiceSuite, token domain.RefreshToken) {
+		s.MockUserRepository.EXPECT().
+			Self(context.Background(), token.UserID).
+			Return(domain.User{Model: domain.Model{ID: token.UserID}}, nil).
+			Times(1)
+	}
+
+	mockRevokeSessionBehavior := func(s *TokenServiceSuite, token domain.RefreshToken) {
+		s.MockSessionRepository.EXPECT().
+			Revoke(context.Background(), token.FamilyID, token.UserID).
+			Return(nil).
+			Times(1)
+	}
+
+	mockReuseBehavior := func(s *TokenServiceSuite, token domain.RefreshToken, returns error) {
+		mockFindBehavior(s, token, nil)
+		s.MockRefreshTokenRepository.EXPECT().
+			RevokeFamily(context.Background(), token.FamilyID).
+			Return(returns).
+			Times(1)
+		if returns == nil {
+			mockRevokeSessionBehavior(s, token)
+		}
+	}
+
+	mockConcurrentBehavior := func(s *TokenServiceSuite, token domain.RefreshToken, returns error) {
+		mockFindSessionBehavior(s, token, activeSession, nil)
+		mockSelfBehavior(s, token)
+		s.MockRefreshTokenRepository.EXPECT().
+			Revoke(context.Background(), token.ID).
+			Return(false, nil).
+			Times(1)
+		s.MockRefreshTokenRepository.EXPECT().
+			RevokeFamily(context.Background(), token.FamilyID).
+			Return(returns).
+			Times(1)
+		mockRevokeSessionBehavior(s, token)
+	}
+
+	mockRotateBehavior := func(s *TokenServiceSuite, token domain.RefreshToken, returns error) {
+		mockFindSessionBehavior(s, token, activeSession, nil)
+		mockSelfBehavior(s, token)
+		s.MockRefreshTokenRepository.EXPECT().
+			Revoke(context.Background(), token.ID).
+			Return(true, nil).
+			Times(1)
+		s.MockSessionRepository.EXPECT().
+			Touch(context.Background(), activeSession.ID, "127.0.0.1", gomock.AssignableToTypeOf(time.Time{})).
+			Return(nil).
+			Times(1)
+		s.MockRefreshTokenRepository.EXPECT().
+			Create(context.Background(), gomock.AssignableToTypeOf(domain.RefreshToken{})).
+			DoAndReturn(func(_ context.Context, rotated domain.RefreshToken) error {
+				s.Assertions.Equal(token.FamilyID, rotated.FamilyID)
+				s.Assertions.Equal(token.UserID, rotated.UserID)
+				return returns
+			}).
+			Times(1)
+		s.MockAuthProvider.EXPECT().
+			NewToken(gomock.AssignableToTypeOf(auth.TokenParams{})).
+			DoAndReturn(func(params auth.TokenParams) (string, error) {
+				s.Assertions.Equal(activeSession.ID.String(), params.SessionID)
+				return "access-token-valid", nil
+			}).
+			Times(1)
+	}
+
+	methodCases := []struct {
+		Name                  string
+		CurrentToken          domain.RefreshToken
+		RepositoryResultError error
+		AccessTokenIssued     bool
+		MethodResultError     error
+		MockBehavior          MockBehavior
+	}{
+		{
+			Name:              "Success",
+			CurrentToken:      activeToken,
+			AccessTokenIssued: true,
+			MockBehavior:      mockRotateBehavior,
+		},
+		{
+			Name:                  "NotFound",
+			RepositoryResultError: repoerrors.ErrRefreshTokenNotFound,
+			MethodResultError:     serviceerrors.ErrRefreshTokenInvalid,
+			MockBehavior:          mockFindBehavior,
+		},
+		{
+			Name:              "Expired",
+			CurrentToken:      expiredToken,
+			MethodResultError: serviceerrors.ErrRefreshTokenExpired,
+			MockBehavior:      mockFindBehavior,
+		},
+		{
+			Name:              "SessionNotFound",
+			CurrentToken:      activeToken,
+			MethodResultError: serviceerrors.ErrRefreshTokenInvalid,
+			MockBehavior: func(s *TokenServiceSuite, token domain.RefreshToken, _ error) {
+				mockFindSessionBehavior(s, token, domain.Session{}, repoerrors.ErrSessionNotFound)
+			},
+		},
+		{
+			Name:              "SessionRevoked",
+			CurrentToken:      activeToken,
+			MethodResultError: serviceerrors.ErrRefreshTokenInvalid,
+			MockBehavior: func(s *TokenServiceSuite, token domain.RefreshToken, _ error) {
+				mockFindSessionBehavior(s, token, revokedSession, nil)
+			},
+		},
+		{
+			Name:              "Reused",
+			CurrentToken:      revokedToken,
+			MethodResultError: serviceerrors.ErrRefreshTokenReused,
+			MockBehavior:      mockReuseBehavior,
+		},
+		{
+			Name:              "ReusedConcurrently",
+			CurrentToken:      activeToken,
+			MethodResultError: serviceerrors.ErrRefreshTokenReused,
+			MockBehavior:      mockConcurrentBehavior,
+		},
+		{
+			Name:                  "RepositoryFailure",
+			CurrentToken:          revokedToken,
+			RepositoryResultError: repositoryResultError,
+			MethodResultError:     repositoryResultError,
+			MockBehavior:          mockReuseBehavior,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			currentCase.MockBehavior(s, currentCase.CurrentToken, currentCase.RepositoryResultError)
+			result, err := s.CurrentService.Refresh(context.Background(), service.RefreshTokenInput{
+				RefreshToken: "refresh-token",
+				Client:       service.Client{IP: "127.0.0.1"},
+			})
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			s.Assertions.Equal(currentCase.AccessTokenIssued, len(result.AccessToken) != 0)
+		})
+	}
+}
+
+func (s *TokenServiceSuite) TestAuthenticateMethod() {
+	type MockTokenDenylistBehavior func(m *mock_repository.MockTokenDenylist, claims auth.Claims, denied bool, before time.Time)
+
+	mockTokenDenylistBehavior := func(m *mock_repository.MockTokenDenylist, claims auth.Claims, denied bool, before time.Time) {
+		m.EXPECT().
+			Contains(context.Background(), claims.TokenID).
+			Return(denied, nil).
+			Times(1)
+		if !denied {
+			m.EXPECT().
+				RevokedBefore(context.Background(), claims.UserID).
+				Return(before, nil).
+				Times(1)
+		}
+	}
+
+	type MockUserBehavior func(m *mock_repository.MockUser, id uuid.UUID, suspended bool)
+
+	mockUserBehavior := func(m *mock_repository.MockUser, id uuid.UUID, suspended bool) {
+		m.EXPECT().
+			Self(context.Background(), id).
+			Return(domain.User{Model: domain.Model{ID: id}, Roles: domain.Roles{domain.AuthorRole}, SuspendedAt: null.NewTime(time.Now(), suspended)}, nil).
+			Times(1)
+	}
+
+	type MockSessionBehavior func(m *mock_repository.MockSession, id uuid.UUID, session domain.Session)
+
+	mockSessionBehavior := func(m *mock_repository.MockSession, id uuid.UUID, session domain.Session) {
+		m.EXPECT().
+			Find(context.Background(), id).
+			Return(session, nil).
+			Times(1)
+		if !session.IsRevoked() {
+			m.EXPECT().
+				Touch(context.Background(), id, "127.0.0.1", gomock.AssignableToTypeOf(time.Time{})).
+				Return(nil).
+				Times(1)
+		}
+	}
+
+	authResultError := errors.New("AuthResultError")
+	id := uuid.NewV4()
+	sessionID := uuid.NewV4()
+	activeSession := domain.Session{ID: sessionID, UserID: id, LastSeenAt: time.Now().Add(-time.Hour)}
+	revokedSession := activeSession
+	revokedSession.RevokedAt = null.NewTime(time.Now(), true)
+	issuedAt := time.Now().Truncate(time.Second)
+
+	methodCases := []struct {
+		Name                      string
+		ServiceInput              service.AuthenticateUserInput
+		AuthResultClaims          auth.Claims
+		AuthResultError           error
+		Denied                    bool
+		RevokedBefore             time.Time
+		Suspended                 bool
+		MethodResultValue         uuid.UUID
+		MethodResultRoles         domain.Roles
+		MethodResultError         error
+		Session                   domain.Session
+		MockTokenDenylistBehavior MockTokenDenylistBehavior
+		MockSessionBehavior       MockSessionBehavior
+		MockUserBehavior          MockUserBehavior
+	}{
+		{
+			Name:                      "Success",
+			ServiceInput:              service.AuthenticateUserInput{Token: "access-token-valid", IP: "127.0.0.1"},
+			AuthResultClaims:          auth.Claims{UserID: id, TokenID: "token-id", SessionID: sessionID.String(), Roles: []string{"author"}, IssuedAt: issuedAt},
+			MethodResultValue:         id,
+			MethodResultRoles:         domain.Roles{domain.AuthorRole},
+			Session:                   activeSession,
+			MockTokenDenylistBehavior: mockTokenDenylistBehavior,
+			MockSessionBehavior:       mockSessionBehavior,
+			MockUserBehavior:          mockUserBehavior,
+		},
+		{
+			// Token was issued before admin role was removed
+			Name:                      "Demoted",
+			ServiceInput:              service.AuthenticateUserInput{Token: "access-token-valid", IP: "127.0.0.1"},
+			AuthResultClaims:          auth.Claims{UserID: id, TokenID: "token-id", SessionID: sessionID.String(), Roles: []string{"admin"}, IssuedAt: issuedAt},
+			MethodResultValue:         id,
+			MethodResultRoles:         domain.Roles{domain.AuthorRole},
+			Session:                   activeSession,
+			MockTokenDenylistBehavior: mockTokenDenylistBehavior,
+			MockSessionBehavior:       mockSessionBehavior,
+			MockUserBehavior:          mockUserBehavior,
+		},
+		{
+			Name:                      "IssuedAfterRevocation",
+			ServiceInput:              service.AuthenticateUserInput{Token: "access-token-valid", IP: "127.0.0.1"},
+			AuthResultClaims:          auth.Claims{UserID: id, TokenID: "token-id", SessionID: sessionID.String(), IssuedAt: issuedAt},
+			RevokedBefore:             issuedAt.Add(-time.Second),
+			MethodResultValue:         id,
+			Session:                   activeSession,
+			MockTokenDenylistBehavior: mockTokenDenylistBehavior,
+			MockSessionBehavior:       mockSessionBehavior,
+			MockUserBehavior:          mockUserBehavior,
+		},
+		{
+			Name:                      "Suspended",
+			ServiceInput:              service.AuthenticateUserInput{Token: "access-token-valid", IP: "127.0.0.1"},
+			AuthResultClaims:          auth.Claims{UserID: id, TokenID: "token-id", SessionID: sessionID.String(), IssuedAt: issuedAt},
+			Suspended:                 true,
+			MethodResultValue:         uuid.Nil,
+			MethodResultError:         serviceerrors.ErrUserSuspended,
+			Session:                   activeSession,
+			MockTokenDenylistBehavior: mockTokenDenylistBehavior,
+			MockSessionBehavior:       mockSessionBehavior,
+			MockUserBehavior:          mockUserBehavior,
+		},
+		{
+			Name:              "AuthFailure",
+			ServiceInput:      service.AuthenticateUserInput{},
+			AuthResultError:   authResultError,
+			MethodResultValue: uuid.Nil,
+			MethodResultError: authResultError,
+		},
+		{
+			Name:              "MissingTokenID",
+			ServiceInput:      service.AuthenticateUserInput{Token: "access-token-legacy"},
+			AuthResultClaims:  auth.Claims{UserID: id},
+			MethodResultValue: uuid.Nil,
+			MethodResultError: serviceerrors.ErrAccessTokenInvalid,
+		},
+		{
+			Name:              "MissingSessionID",
+			ServiceInput:      service.AuthenticateUserInput{Token: "access-token-legacy"},
+			AuthResultClaims:  auth.Claims{UserID: id, TokenID: "token-id"},
+			MethodResultValue: uuid.Nil,
+			MethodResultError: serviceerrors.ErrAccessTokenInvalid,
+		},
+		{
+			Name:                      "SessionRevoked",
+			ServiceInput:              service.AuthenticateUserInput{Token: "access-token-valid", IP: "127.0.0.1"},
+			AuthResultClaims:          auth.Claims{UserID: id, TokenID: "token-id", SessionID: sessionID.String(), IssuedAt: issuedAt},
+			Session:                   revokedSession,
+			MethodResultValue:         uuid.Nil,
+			MethodResultError:         serviceerrors.ErrAccessTokenRevoked,
+			MockTokenDenylistBehavior: mockTokenDenylistBehavior,
+			MockSessionBehavior:       mockSessionBehavior,
+		},
+		{
+			Name:                      "Revoked",
+			ServiceInput:              service.AuthenticateUserInput{Token: "access-token-revoked"},
+			AuthResultClaims:          auth.Claims{UserID: id, TokenID: "token-id", SessionID: sessionID.String(), IssuedAt: issuedAt},
+			Denied:                    true,
+			MethodResultValue:         uuid.Nil,
+			MethodResultError:         serviceerrors.ErrAccessTokenRevoked,
+			MockTokenDenylistBehavior: mockTokenDenylistBehavior,
+		},
+		{
+			Name:                      "IssuedBeforeRevocation",
+			ServiceInput:              service.AuthenticateUserInput{Token: "access-token-revoked"},
+			AuthResultClaims:          auth.Claims{UserID: id, TokenID: "token-id", SessionID: sessionID.String(), IssuedAt: issuedAt},
+			RevokedBefore:             issuedAt,
+			MethodResultValue:         uuid.Nil,
+			MethodResultError:         serviceerrors.ErrAccessTokenRevoked,
+			MockTokenDenylistBehavior: mockTokenDenylistBehavior,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			s.MockAuthProvider.EXPECT().
+				Parse(currentCase.ServiceInput.Token).
+				Return(currentCase.AuthResultClaims, currentCase.AuthResultError).
+				Times(1)
+			if currentCase.MockTokenDenylistBehavior != nil {
+				currentCase.MockTokenDenylistBehavior(s.MockTokenDenylist, currentCase.AuthResultClaims, currentCase.Denied, currentCase.RevokedBefore)
+			}
+			if currentCase.MockSessionBehavior != nil {
+				currentCase.MockSessionBehavior(s.MockSessionRepository, sessionID, currentCase.Session)
+			}
+			if currentCase.MockUserBehavior != nil {
+				currentCase.MockUserBehavior(s.MockUserRepository, currentCase.AuthResultClaims.UserID, currentCase.Suspended)
+			}
+			result, err := s.CurrentService.Authenticate(context.Background(), currentCase.ServiceInput)
+			s.Assertions.Equal(currentCase.MethodResultValue, result.UserID)
+			if currentCase.MethodResultRoles != nil {
+				s.Assertions.Equal(currentCase.MethodResultRoles, result.Roles)
+			}
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
+
+func (s *TokenServiceSuite) TestRevokeAllMethod() {
+	userID := uuid.NewV4()
+
+	s.MockSessionRepository.EXPECT().
+		RevokeAllWithUserID(context.Background(), userID).
+		Return(nil).
+		Times(1)
+	s.MockAccessTokenRepository.EXPECT().
+		DeleteAllWithUserID(context.Background(), userID).
+		Return(nil).
+		Times(1)
+	s.MockRefreshTokenRepository.EXPECT().
+		RevokeAllWithUserID(context.Background(), userID).
+		Return(nil).
+		Times(1)
+	s.MockTokenDenylist.EXPECT().
+		RevokeBefore(context.Background(), userID, gomock.Any(), s.AccessTokenExpiresTime).
+		Return(nil).
+		Times(1)
+
+	s.Assertions.NoError(s.CurrentService.RevokeAll(context.Background(), userID))
+}
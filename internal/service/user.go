@@ -3,70 +3,142 @@ package service
 import (
 	"context"
 	"fmt"
-	"time"
+	"strings"
+	"sync"
 
 	"github.com/aintsashqa/go-simple-blog/internal/domain"
 	"github.com/aintsashqa/go-simple-blog/internal/repository"
-	"github.com/aintsashqa/go-simple-blog/pkg/auth"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/service/errors"
 	"github.com/aintsashqa/go-simple-blog/pkg/hash"
 	uuid "github.com/satori/go.uuid"
 )
 
 type UserService struct {
-	repo             repository.User
-	hasher           hash.HashProvider
-	auth             auth.AuthorizationProvider
-	tokenExpiresTime time.Duration
+	repo         repository.User
+	events       repository.SecurityEvent
+	hasher       hash.HashProvider
+	mfa          MFA
+	verification Verification
+	throttle     SignInThrottle
+	webhooks     WebhookDispatcher
+
+	// dummy is compared on sign in with unknown email, so it takes as long
+	// as with registered one, it is made lazily by current hasher
+	dummy     string
+	dummyOnce sync.Once
 }
 
-func NewUserService(
-	repo repository.User,
-	hasher hash.HashProvider,
-	auth auth.AuthorizationProvider,
-	tokenExpiresTime time.Duration,
-) *UserService {
-	return &UserService{repo: repo, hasher: hasher, auth: auth, tokenExpiresTime: tokenExpiresTime}
+const dummyPassword = "dummy-password"
+
+func NewUserService(repo repository.User, events repository.SecurityEvent, hasher hash.HashProvider, mfa MFA, verification Verification, throttle SignInThrottle, webhooks WebhookDispatcher) *UserService {
+	return &UserService{repo: repo, events: events, hasher: hasher, mfa: mfa, verification: verification, throttle: throttle, webhooks: webhooks}
 }
 
 func (s *UserService) SignUp(ctx context.Context, input SignUpUserInput) (domain.User, error) {
 	user := domain.User{
 		Email:    input.Email,
-		Username: fmt.Sprintf("Username%d", time.Now().Unix()),
+		Username: input.Username,
 		Password: input.Password,
+		Roles:    domain.Roles{domain.AuthorRole},
 	}
 	user.Init()
 
+	if user.Username == "" {
+		user.Username = defaultUsername(user)
+	}
+
 	if err := user.Validate(domain.CreateUserValidationAction); err != nil {
 		return domain.User{}, err
 	}
 
+	if err := s.checkUsername(ctx, user); err != nil {
+		return domain.User{}, err
+	}
+
 	user.Password = s.hasher.Make(user.Password)
-	err := s.repo.Create(ctx, user)
+	if err := s.repo.Create(ctx, user); err != nil {
+		return user, err
+	}
+
+	err := s.verification.Send(ctx, user)
 	return user, err
 }
 
-func (s *UserService) SignIn(ctx context.Context, input SignInUserInput) (Tokens, error) {
+// defaultUsername is generated from id, username could be chosen later
+func defaultUsername(user domain.User) string {
+	return fmt.Sprintf("user_%s", strings.ReplaceAll(user.ID.String(), "-", "")[:12])
+}
+
+// SignIn reports unknown email and wrong password with same error, so
+// registered emails could not be enumerated
+func (s *UserService) SignIn(ctx context.Context, input SignInUserInput) (SignInResult, error) {
+	if err := s.throttle.Check(ctx, input); err != nil {
+		return SignInResult{}, err
+	}
+
 	user, err := s.repo.GetByEmail(ctx, input.Email)
+	if err == repoerrors.ErrUserNotFound {
+		s.hasher.Compare(s.dummyHash(), input.Password)
+		return SignInResult{}, s.fail(ctx, input)
+	}
 	if err != nil {
-		return Tokens{}, err
+		return SignInResult{}, err
 	}
 
 	if err := s.hasher.Compare(user.Password, input.Password); err != nil {
-		return Tokens{}, err
+		return SignInResult{}, s.fail(ctx, input)
+	}
+
+	if err := s.throttle.Reset(ctx, input); err != nil {
+		return SignInResult{}, err
+	}
+
+	if err := s.rehash(ctx, user, input.Password); err != nil {
+		return SignInResult{}, err
 	}
 
-	accessToken, err := s.auth.NewToken(auth.TokenParams{
-		UserID:    user.ID,
-		ExpiresAt: s.tokenExpiresTime,
+	return s.mfa.SignIn(ctx, user, input.Client)
+}
+
+// rehash upgrades hash made by outdated algorithm or parameters, it is
+// possible only on sign in when password is known
+func (s *UserService) rehash(ctx context.Context, user domain.User, password string) error {
+	if !s.hasher.NeedsRehash(user.Password) {
+		return nil
+	}
+
+	previous := user.Password
+	user.Password = s.hasher.Make(password)
+	return s.repo.RehashPassword(ctx, user, previous)
+}
+
+func (s *UserService) dummyHash() string {
+	s.dummyOnce.Do(func() {
+		s.dummy = s.hasher.Make(dummyPassword)
 	})
+	return s.dummy
+}
 
-	return Tokens{AccessToken: accessToken}, err
+func (s *UserService) fail(ctx context.Context, input SignInUserInput) error {
+	if err := s.throttle.Fail(ctx, input); err != nil {
+		return err
+	}
+	return errors.ErrInvalidCredentials
 }
 
 func (s *UserService) Find(ctx context.Context, id uuid.UUID) (domain.User, error) {
 	return s.repo.Find(ctx, id)
 }
 
+func (s *UserService) FindByUsername(ctx context.Context, username string) (domain.User, error) {
+	return s.repo.GetByUsername(ctx, username)
+}
+
+func (s *UserService) Validator(ctx context.Context, id uuid.UUID) (domain.Validator, error) {
+	return s.repo.FindValidator(ctx, id)
+}
+
 func (s *UserService) Self(ctx context.Context, id uuid.UUID) (domain.User, error) {
 	return s.repo.Self(ctx, id)
 }
@@ -77,20 +149,155 @@ func (s *UserService) Update(ctx context.Context, input UpdateUserInput) (domain
 		return domain.User{}, err
 	}
 
-	user.Username = input.Username
+	if err := checkVersion(user.Model, input.ETag, input.Version); err != nil {
+		return user, err
+	}
+
+	previousUsername := user.Username
+	input.apply(&user)
 	user.Update()
 
-	if err := user.Validate(domain.UpdateUserValidationAction); err != nil {
+	// Usernames created before they were validated are kept until changed
+	usernameChanged := user.Username != previousUsername
+	action := domain.UpdateProfileUserValidationAction
+	if usernameChanged {
+		action = domain.UpdateUserValidationAction
+	}
+
+	if err := user.Validate(action); err != nil {
 		return domain.User{}, err
 	}
 
+	if usernameChanged {
+		if err := s.checkUsername(ctx, user); err != nil {
+			return domain.User{}, err
+		}
+	}
+
 	if err := s.repo.Update(ctx, user); err != nil {
+		if err == repoerrors.ErrUserVersionConflict {
+			return s.current(ctx, input.ID, err)
+		}
+		return domain.User{}, err
+	}
+
+	s.webhooks.Dispatch(ctx, domain.UserUpdatedWebhookEvent, user.ID, user)
+	return user, nil
+}
+
+func (s *UserService) ChangePassword(ctx context.Context, input ChangePasswordInput) error {
+	user, err := authorizeUser(ctx, s.repo, s.hasher, input.ID, input.CurrentPassword)
+	if err != nil {
+		return err
+	}
+
+	user.Password = input.NewPassword
+	if err := user.Validate(domain.UpdatePasswordUserValidationAction); err != nil {
+		return err
+	}
+
+	user.Password = s.hasher.Make(user.Password)
+	user.Update()
+
+	if err := s.repo.UpdatePassword(ctx, user); err != nil {
+		return err
+	}
+
+	return s.events.Create(ctx, domain.NewSecurityEvent(user.ID, domain.PasswordChangedSecurityEvent))
+}
+
+// ChangeEmail only sends confirmation link to new email, see Verification.ConfirmEmailChange
+func (s *UserService) ChangeEmail(ctx context.Context, input ChangeEmailInput) error {
+	user, err := authorizeUser(ctx, s.repo, s.hasher, input.ID, input.Password)
+	if err != nil {
+		return err
+	}
+
+	changed := domain.User{Email: input.Email}
+	if err := changed.Validate(domain.UpdateEmailUserValidationAction); err != nil {
+		return err
+	}
+
+	if _, err := s.repo.GetByEmail(ctx, changed.Email); err != repoerrors.ErrUserNotFound {
+		if err == nil {
+			return errors.ErrEmailAlreadyTaken
+		}
+		return err
+	}
+
+	if err := s.verification.SendEmailChange(ctx, user, changed.Email); err != nil {
+		return err
+	}
+
+	return s.events.Create(ctx, domain.NewSecurityEvent(user.ID, domain.EmailChangeRequestedSecurityEvent))
+}
+
+// checkUsername fails when username belongs to another user, unique index
+// still guards against concurrent requests
+func (s *UserService) checkUsername(ctx context.Context, user domain.User) error {
+	owner, err := s.repo.GetByUsername(ctx, user.Username)
+	if err == repoerrors.ErrUserNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !uuid.Equal(owner.ID, user.ID) {
+		return errors.ErrUsernameAlreadyTaken
+	}
+	return nil
+}
+
+// authorizeUser returns user with encrypted password when password matches
+func authorizeUser(ctx context.Context, users repository.User, hasher hash.HashProvider, id uuid.UUID, password string) (domain.User, error) {
+	self, err := users.Self(ctx, id)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	user, err := users.GetByEmail(ctx, self.Email)
+	if err != nil {
 		return domain.User{}, err
 	}
 
+	if err := hasher.Compare(user.Password, password); err != nil {
+		return domain.User{}, errors.ErrCurrentPasswordMismatch
+	}
+
 	return user, nil
 }
 
-func (s *UserService) Authenticate(ctx context.Context, input AuthenticateUserInput) (uuid.UUID, error) {
-	return s.auth.Parse(input.Token)
+func (input UpdateUserInput) apply(user *domain.User) {
+	if input.Username != nil {
+		user.Username = *input.Username
+	}
+	if input.DisplayName != nil {
+		user.DisplayName = *input.DisplayName
+	}
+	if input.Bio != nil {
+		user.Bio = *input.Bio
+	}
+	if input.AvatarURL != nil {
+		user.AvatarURL = *input.AvatarURL
+	}
+	if input.Location != nil {
+		user.Location = *input.Location
+	}
+	if input.Website != nil {
+		user.Website = *input.Website
+	}
+	if input.SocialLinks != nil {
+		user.SocialLinks = domain.SocialLinks(*input.SocialLinks)
+	}
+}
+
+// current returns actual user with version conflict error
+func (s *UserService) current(ctx context.Context, id uuid.UUID, conflict error) (domain.User, error) {
+	user, err := s.repo.Find(ctx, id)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	return user, conflict
 }
@@ -4,13 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
-	"time"
 
 	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
 	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
 	"github.com/aintsashqa/go-simple-blog/internal/service"
-	"github.com/aintsashqa/go-simple-blog/pkg/auth"
-	mock_auth "github.com/aintsashqa/go-simple-blog/pkg/auth/mocks"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	mock_service "github.com/aintsashqa/go-simple-blog/internal/service/mocks"
 	mock_hash "github.com/aintsashqa/go-simple-blog/pkg/hash/mocks"
 	"github.com/golang/mock/gomock"
 	uuid "github.com/satori/go.uuid"
@@ -24,11 +24,13 @@ type UserServiceSuite struct {
 
 	Controller *gomock.Controller
 
-	MockUserRepository *mock_repository.MockUser
-	MockHashProvider   *mock_hash.MockHashProvider
-	MockAuthProvider   *mock_auth.MockAuthorizationProvider
-
-	TokenExpiresTime time.Duration
+	MockUserRepository          *mock_repository.MockUser
+	MockSecurityEventRepository *mock_repository.MockSecurityEvent
+	MockHashProvider            *mock_hash.MockHashProvider
+	MockMFAService              *mock_service.MockMFA
+	MockVerification            *mock_service.MockVerification
+	MockSignInThrottle          *mock_service.MockSignInThrottle
+	MockWebhookDispatcher       *mock_service.MockWebhookDispatcher
 
 	CurrentService service.User
 }
@@ -41,10 +43,13 @@ func (s *UserServiceSuite) SetupTest() {
 	s.Assertions = require.New(s.T())
 	s.Controller = gomock.NewController(s.T())
 	s.MockUserRepository = mock_repository.NewMockUser(s.Controller)
+	s.MockSecurityEventRepository = mock_repository.NewMockSecurityEvent(s.Controller)
 	s.MockHashProvider = mock_hash.NewMockHashProvider(s.Controller)
-	s.MockAuthProvider = mock_auth.NewMockAuthorizationProvider(s.Controller)
-	s.TokenExpiresTime = time.Duration(time.Hour * 10)
-	s.CurrentService = service.NewUserService(s.MockUserRepository, s.MockHashProvider, s.MockAuthProvider, s.TokenExpiresTime)
+	s.MockMFAService = mock_service.NewMockMFA(s.Controller)
+	s.MockVerification = mock_service.NewMockVerification(s.Controller)
+	s.MockSignInThrottle = mock_service.NewMockSignInThrottle(s.Controller)
+	s.MockWebhookDispatcher = mock_service.NewMockWebhookDispatcher(s.Controller)
+	s.CurrentService = service.NewUserService(s.MockUserRepository, s.MockSecurityEventRepository, s.MockHashProvider, s.MockMFAService, s.MockVerification, s.MockSignInThrottle, s.MockWebhookDispatcher)
 }
 
 func (s *UserServiceSuite) TearDownTest() {
@@ -56,10 +61,22 @@ func (s *UserServiceSuite) TestSignUpMethod() {
 	type MockHashProviderBehavior func(m *mock_hash.MockHashProvider, input string, returns string)
 
 	mockUserRepositoryBehavior := func(m *mock_repository.MockUser, returns error) {
+		m.EXPECT().
+			GetByUsername(context.Background(), gomock.Any()).
+			Return(domain.User{}, repoerrors.ErrUserNotFound).
+			Times(1)
+
 		m.EXPECT().
 			Create(context.Background(), gomock.AssignableToTypeOf(domain.User{})).
 			Return(returns).
 			Times(1)
+
+		if returns == nil {
+			s.MockVerification.EXPECT().
+				Send(context.Background(), gomock.AssignableToTypeOf(domain.User{})).
+				Return(nil).
+				Times(1)
+		}
 	}
 
 	mockHashProviderBehavior := func(m *mock_hash.MockHashProvider, input string, returns string) {
@@ -69,6 +86,13 @@ func (s *UserServiceSuite) TestSignUpMethod() {
 			Times(1)
 	}
 
+	mockTakenUsernameBehavior := func(m *mock_repository.MockUser, returns error) {
+		m.EXPECT().
+			GetByUsername(context.Background(), "Taken_Name").
+			Return(domain.User{Model: domain.Model{ID: uuid.NewV4()}, Username: "taken_name"}, nil).
+			Times(1)
+	}
+
 	repositoryResultError := errors.New("RepositoryResultError")
 
 	methodCases := []struct {
@@ -104,6 +128,32 @@ func (s *UserServiceSuite) TestSignUpMethod() {
 			MockUserRepositoryBehavior: nil,
 			MockHashProviderBehavior:   nil,
 		},
+		{
+			Name:         "ReservedUsername",
+			PasswordHash: "secret-hash",
+			ServiceInput: service.SignUpUserInput{
+				Email:    "test@example.com",
+				Username: "Admin",
+				Password: "secret",
+			},
+			RepositoryResultError:      nil,
+			ServiceResultError:         domain.ErrUserUsernameReserved,
+			MockUserRepositoryBehavior: nil,
+			MockHashProviderBehavior:   nil,
+		},
+		{
+			Name:         "UsernameAlreadyTaken",
+			PasswordHash: "secret-hash",
+			ServiceInput: service.SignUpUserInput{
+				Email:    "test@example.com",
+				Username: "Taken_Name",
+				Password: "secret",
+			},
+			RepositoryResultError:      nil,
+			ServiceResultError:         serviceerrors.ErrUsernameAlreadyTaken,
+			MockUserRepositoryBehavior: mockTakenUsernameBehavior,
+			MockHashProviderBehavior:   nil,
+		},
 		{
 			Name:         "RepositoryFailure",
 			PasswordHash: "secret-hash",
@@ -127,209 +177,437 @@ func (s *UserServiceSuite) TestSignUpMethod() {
 				currentCase.MockHashProviderBehavior(s.MockHashProvider, currentCase.ServiceInput.Password, currentCase.PasswordHash)
 			}
 			user, err := s.CurrentService.SignUp(context.Background(), currentCase.ServiceInput)
-			s.Assertions.Equal(currentCase.ServiceInput.Email, user.Email)
+			if currentCase.MockHashProviderBehavior != nil {
+				s.Assertions.Equal(currentCase.ServiceInput.Email, user.Email)
+			}
 			s.Assertions.Equal(currentCase.ServiceResultError, err)
 		})
 	}
 }
 
 func (s *UserServiceSuite) TestSignInMethod() {
-	type MockUserRepositoryBehavior func(m *mock_repository.MockUser, input service.SignInUserInput, returnsUser domain.User, returnsError error)
-	type MockHashProviderBehavior func(m *mock_hash.MockHashProvider, inputHashPassword string, inputPassword string, returns error)
-	type MockAuthProviderBehavior func(m *mock_auth.MockAuthorizationProvider, inputUserID uuid.UUID, inputExpiresAt time.Duration, returnsAccessToken string, returnsError error)
+	type MockSignInThrottleBehavior func(m *mock_service.MockSignInThrottle, input service.SignInUserInput)
+	type MockUserRepositoryBehavior func(m *mock_repository.MockUser, input service.SignInUserInput, user domain.User)
+	type MockHashProviderBehavior func(m *mock_hash.MockHashProvider, input service.SignInUserInput, user domain.User)
+	type MockMFAServiceBehavior func(m *mock_service.MockMFA, input service.SignInUserInput, user domain.User)
+
+	user := domain.User{Model: domain.Model{ID: uuid.NewV4()}, Email: "test@example.com", Password: "secret-hash"}
+	input := service.SignInUserInput{Email: "test@example.com", Password: "secret", Client: service.Client{UserAgent: "Mozilla/5.0", IP: "127.0.0.1"}}
+	result := service.SignInResult{Tokens: service.Tokens{AccessToken: "access-token-valid"}}
+
+	repositoryResultError := errors.New("RepositoryResultError")
+	hashResultError := errors.New("HashResultError")
+	authResultError := errors.New("AuthResultError")
+
+	mockCheckedBehavior := func(m *mock_service.MockSignInThrottle, input service.SignInUserInput) {
+		m.EXPECT().
+			Check(context.Background(), input).
+			Return(nil).
+			Times(1)
+	}
+
+	mockLockedBehavior := func(m *mock_service.MockSignInThrottle, input service.SignInUserInput) {
+		m.EXPECT().
+			Check(context.Background(), input).
+			Return(serviceerrors.ErrSignInLocked).
+			Times(1)
+	}
+
+	mockFailedBehavior := func(m *mock_service.MockSignInThrottle, input service.SignInUserInput) {
+		mockCheckedBehavior(m, input)
+		m.EXPECT().
+			Fail(context.Background(), input).
+			Return(nil).
+			Times(1)
+	}
 
-	mockUserRepositoryBehavior := func(m *mock_repository.MockUser, input service.SignInUserInput, returnsUser domain.User, returnsError error) {
+	mockResetBehavior := func(m *mock_service.MockSignInThrottle, input service.SignInUserInput) {
+		mockCheckedBehavior(m, input)
+		m.EXPECT().
+			Reset(context.Background(), input).
+			Return(nil).
+			Times(1)
+	}
+
+	mockFoundBehavior := func(m *mock_repository.MockUser, input service.SignInUserInput, user domain.User) {
 		m.EXPECT().
 			GetByEmail(context.Background(), input.Email).
-			Return(returnsUser, returnsError).
+			Return(user, nil).
 			Times(1)
 	}
 
-	mockHashProviderBehavior := func(m *mock_hash.MockHashProvider, inputHashPassword string, inputPassword string, returns error) {
+	mockNotFoundBehavior := func(m *mock_repository.MockUser, input service.SignInUserInput, user domain.User) {
 		m.EXPECT().
-			Compare(inputHashPassword, inputPassword).
-			Return(returns).
+			GetByEmail(context.Background(), input.Email).
+			Return(domain.User{}, repoerrors.ErrUserNotFound).
 			Times(1)
 	}
 
-	mockAuthProviderBehavior := func(m *mock_auth.MockAuthorizationProvider, inputUserID uuid.UUID, inputExpiresAt time.Duration, returnsAccessToken string, returnsError error) {
+	mockRepositoryFailureBehavior := func(m *mock_repository.MockUser, input service.SignInUserInput, user domain.User) {
 		m.EXPECT().
-			NewToken(auth.TokenParams{UserID: inputUserID, ExpiresAt: inputExpiresAt}).
-			Return(returnsAccessToken, returnsError).
+			GetByEmail(context.Background(), input.Email).
+			Return(domain.User{}, repositoryResultError).
 			Times(1)
 	}
 
-	repositoryResultError := errors.New("RepositoryResultError")
-	hashResultError := errors.New("HashResultError")
-	authResultError := errors.New("AuthResultError")
+	mockRehashedBehavior := func(m *mock_repository.MockUser, input service.SignInUserInput, user domain.User) {
+		mockFoundBehavior(m, input, user)
+		rehashed := user
+		rehashed.Password = "secret-rehash"
+		m.EXPECT().
+			RehashPassword(context.Background(), rehashed, user.Password).
+			Return(nil).
+			Times(1)
+	}
+
+	mockMatchedBehavior := func(m *mock_hash.MockHashProvider, input service.SignInUserInput, user domain.User) {
+		m.EXPECT().
+			Compare(user.Password, input.Password).
+			Return(nil).
+			Times(1)
+		m.EXPECT().
+			NeedsRehash(user.Password).
+			Return(false).
+			Times(1)
+	}
+
+	mockOutdatedBehavior := func(m *mock_hash.MockHashProvider, input service.SignInUserInput, user domain.User) {
+		m.EXPECT().
+			Compare(user.Password, input.Password).
+			Return(nil).
+			Times(1)
+		m.EXPECT().
+			NeedsRehash(user.Password).
+			Return(true).
+			Times(1)
+		m.EXPECT().
+			Make(input.Password).
+			Return("secret-rehash").
+			Times(1)
+	}
+
+	mockMismatchedBehavior := func(m *mock_hash.MockHashProvider, input service.SignInUserInput, user domain.User) {
+		m.EXPECT().
+			Compare(user.Password, input.Password).
+			Return(hashResultError).
+			Times(1)
+	}
+
+	mockDummyBehavior := func(m *mock_hash.MockHashProvider, input service.SignInUserInput, user domain.User) {
+		m.EXPECT().
+			Make(gomock.Any()).
+			Return("dummy-hash").
+			Times(1)
+		m.EXPECT().
+			Compare("dummy-hash", input.Password).
+			Return(hashResultError).
+			Times(1)
+	}
+
+	mockSignedInBehavior := func(m *mock_service.MockMFA, input service.SignInUserInput, user domain.User) {
+		m.EXPECT().
+			SignIn(context.Background(), user, input.Client).
+			Return(result, nil).
+			Times(1)
+	}
+
+	mockAuthFailureBehavior := func(m *mock_service.MockMFA, input service.SignInUserInput, user domain.User) {
+		m.EXPECT().
+			SignIn(context.Background(), user, input.Client).
+			Return(result, authResultError).
+			Times(1)
+	}
 
 	methodCases := []struct {
 		Name                       string
-		ServiceInput               service.SignInUserInput
-		CurrentUser                domain.User
-		AccessToken                string
-		RepositoryResultError      error
-		HashResultError            error
-		AuthResultError            error
-		MethodResultValue          service.Tokens
+		MethodResultValue          service.SignInResult
 		MethodResultError          error
+		MockSignInThrottleBehavior MockSignInThrottleBehavior
 		MockUserRepositoryBehavior MockUserRepositoryBehavior
 		MockHashProviderBehavior   MockHashProviderBehavior
-		MockAuthProviderBehavior   MockAuthProviderBehavior
+		MockMFAServiceBehavior     MockMFAServiceBehavior
 	}{
 		{
-			Name: "Success",
-			ServiceInput: service.SignInUserInput{
-				Email:    "test@example.com",
-				Password: "secret",
-			},
-			CurrentUser: domain.User{
-				Password: "secret-hash",
-			},
-			AccessToken:           "access-token-valid",
-			RepositoryResultError: nil,
-			HashResultError:       nil,
-			AuthResultError:       nil,
-			MethodResultValue: service.Tokens{
-				AccessToken: "access-token-valid",
-			},
+			Name:                       "Success",
+			MethodResultValue:          result,
 			MethodResultError:          nil,
-			MockUserRepositoryBehavior: mockUserRepositoryBehavior,
-			MockHashProviderBehavior:   mockHashProviderBehavior,
-			MockAuthProviderBehavior:   mockAuthProviderBehavior,
+			MockSignInThrottleBehavior: mockResetBehavior,
+			MockUserRepositoryBehavior: mockFoundBehavior,
+			MockHashProviderBehavior:   mockMatchedBehavior,
+			MockMFAServiceBehavior:     mockSignedInBehavior,
 		},
 		{
-			Name: "RepositoryFailure",
-			ServiceInput: service.SignInUserInput{
-				Email:    "test@example.com",
-				Password: "secret",
-			},
-			CurrentUser: domain.User{
-				Password: "secret-hash",
-			},
-			RepositoryResultError:      repositoryResultError,
-			HashResultError:            nil,
-			AuthResultError:            nil,
-			MethodResultValue:          service.Tokens{},
-			MethodResultError:          repositoryResultError,
-			MockUserRepositoryBehavior: mockUserRepositoryBehavior,
+			Name:                       "Rehashed",
+			MethodResultValue:          result,
+			MethodResultError:          nil,
+			MockSignInThrottleBehavior: mockResetBehavior,
+			MockUserRepositoryBehavior: mockRehashedBehavior,
+			MockHashProviderBehavior:   mockOutdatedBehavior,
+			MockMFAServiceBehavior:     mockSignedInBehavior,
+		},
+		{
+			Name:                       "Locked",
+			MethodResultValue:          service.SignInResult{},
+			MethodResultError:          serviceerrors.ErrSignInLocked,
+			MockSignInThrottleBehavior: mockLockedBehavior,
+			MockUserRepositoryBehavior: nil,
 			MockHashProviderBehavior:   nil,
-			MockAuthProviderBehavior:   nil,
+			MockMFAServiceBehavior:     nil,
 		},
 		{
-			Name: "HasherFailure",
-			ServiceInput: service.SignInUserInput{
-				Email:    "test@example.com",
-				Password: "secret1",
-			},
-			CurrentUser: domain.User{
-				Password: "secret-hash",
-			},
-			RepositoryResultError:      nil,
-			HashResultError:            hashResultError,
-			AuthResultError:            nil,
-			MethodResultValue:          service.Tokens{},
-			MethodResultError:          hashResultError,
-			MockUserRepositoryBehavior: mockUserRepositoryBehavior,
-			MockHashProviderBehavior:   mockHashProviderBehavior,
-			MockAuthProviderBehavior:   nil,
+			Name:                       "UnknownEmail",
+			MethodResultValue:          service.SignInResult{},
+			MethodResultError:          serviceerrors.ErrInvalidCredentials,
+			MockSignInThrottleBehavior: mockFailedBehavior,
+			MockUserRepositoryBehavior: mockNotFoundBehavior,
+			MockHashProviderBehavior:   mockDummyBehavior,
+			MockMFAServiceBehavior:     nil,
 		},
 		{
-			Name: "AuthFailure",
-			ServiceInput: service.SignInUserInput{
-				Email:    "test@example.com",
-				Password: "secret",
-			},
-			CurrentUser: domain.User{
-				Password: "secret-hash",
-			},
-			RepositoryResultError:      nil,
-			HashResultError:            nil,
-			AuthResultError:            authResultError,
-			MethodResultValue:          service.Tokens{},
+			Name:                       "WrongPassword",
+			MethodResultValue:          service.SignInResult{},
+			MethodResultError:          serviceerrors.ErrInvalidCredentials,
+			MockSignInThrottleBehavior: mockFailedBehavior,
+			MockUserRepositoryBehavior: mockFoundBehavior,
+			MockHashProviderBehavior:   mockMismatchedBehavior,
+			MockMFAServiceBehavior:     nil,
+		},
+		{
+			Name:                       "RepositoryFailure",
+			MethodResultValue:          service.SignInResult{},
+			MethodResultError:          repositoryResultError,
+			MockSignInThrottleBehavior: mockCheckedBehavior,
+			MockUserRepositoryBehavior: mockRepositoryFailureBehavior,
+			MockHashProviderBehavior:   nil,
+			MockMFAServiceBehavior:     nil,
+		},
+		{
+			Name:                       "AuthFailure",
+			MethodResultValue:          result,
 			MethodResultError:          authResultError,
-			MockUserRepositoryBehavior: mockUserRepositoryBehavior,
-			MockHashProviderBehavior:   mockHashProviderBehavior,
-			MockAuthProviderBehavior:   mockAuthProviderBehavior,
+			MockSignInThrottleBehavior: mockResetBehavior,
+			MockUserRepositoryBehavior: mockFoundBehavior,
+			MockHashProviderBehavior:   mockMatchedBehavior,
+			MockMFAServiceBehavior:     mockAuthFailureBehavior,
 		},
 	}
 
 	for _, currentCase := range methodCases {
 		s.Suite.Run(currentCase.Name, func() {
-			currentCase.CurrentUser.Init()
+			if currentCase.MockSignInThrottleBehavior != nil {
+				currentCase.MockSignInThrottleBehavior(s.MockSignInThrottle, input)
+			}
 			if currentCase.MockUserRepositoryBehavior != nil {
-				currentCase.MockUserRepositoryBehavior(s.MockUserRepository, currentCase.ServiceInput, currentCase.CurrentUser, currentCase.RepositoryResultError)
+				currentCase.MockUserRepositoryBehavior(s.MockUserRepository, input, user)
 			}
 			if currentCase.MockHashProviderBehavior != nil {
-				currentCase.MockHashProviderBehavior(s.MockHashProvider, currentCase.CurrentUser.Password, currentCase.ServiceInput.Password, currentCase.HashResultError)
+				currentCase.MockHashProviderBehavior(s.MockHashProvider, input, user)
 			}
-			if currentCase.MockAuthProviderBehavior != nil {
-				currentCase.MockAuthProviderBehavior(s.MockAuthProvider, currentCase.CurrentUser.ID, s.TokenExpiresTime, currentCase.AccessToken, currentCase.AuthResultError)
+			if currentCase.MockMFAServiceBehavior != nil {
+				currentCase.MockMFAServiceBehavior(s.MockMFAService, input, user)
 			}
-			result, err := s.CurrentService.SignIn(context.Background(), currentCase.ServiceInput)
-			s.Assertions.Equal(result, currentCase.MethodResultValue)
-			if currentCase.RepositoryResultError != nil {
-				s.Assertions.Equal(currentCase.RepositoryResultError, err)
-			} else if currentCase.HashResultError != nil {
-				s.Assertions.Equal(currentCase.HashResultError, err)
-			} else if currentCase.AuthResultError != nil {
-				s.Assertions.Equal(currentCase.AuthResultError, err)
-			} else {
-				s.Assertions.NoError(err)
+			result, err := s.CurrentService.SignIn(context.Background(), input)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			if err == nil {
+				s.Assertions.Equal(currentCase.MethodResultValue, result)
 			}
 		})
 	}
 }
 
-func (s *UserServiceSuite) TestAuthenticateMethod() {
-	type MockAuthProviderBehavior func(m *mock_auth.MockAuthorizationProvider, input string, returnsID uuid.UUID, returnsError error)
+func (s *UserServiceSuite) TestChangePasswordMethod() {
+	type MockHashProviderBehavior func(m *mock_hash.MockHashProvider, input service.ChangePasswordInput)
+
+	user := domain.User{Model: domain.Model{ID: uuid.NewV4(), Version: 1}, Email: "root@example.com", Password: "secret-hash"}
+	hashResultError := errors.New("HashResultError")
 
-	mockAuthProviderBehavior := func(m *mock_auth.MockAuthorizationProvider, input string, returnsID uuid.UUID, returnsError error) {
+	mockChangedBehavior := func(m *mock_hash.MockHashProvider, input service.ChangePasswordInput) {
 		m.EXPECT().
-			Parse(input).
-			Return(returnsID, returnsError).
+			Compare(user.Password, input.CurrentPassword).
+			Return(nil).
+			Times(1)
+		m.EXPECT().
+			Make(input.NewPassword).
+			Return("new-secret-hash").
+			Times(1)
+		s.MockUserRepository.EXPECT().
+			UpdatePassword(context.Background(), gomock.AssignableToTypeOf(domain.User{})).
+			DoAndReturn(func(_ context.Context, changed domain.User) error {
+				s.Assertions.Equal("new-secret-hash", changed.Password)
+				return nil
+			}).
+			Times(1)
+		s.MockSecurityEventRepository.EXPECT().
+			Create(context.Background(), gomock.AssignableToTypeOf(domain.SecurityEvent{})).
+			DoAndReturn(func(_ context.Context, event domain.SecurityEvent) error {
+				s.Assertions.Equal(domain.PasswordChangedSecurityEvent, event.Type)
+				return nil
+			}).
 			Times(1)
 	}
 
-	authResultError := errors.New("AuthResultError")
-	id := uuid.NewV4()
+	mockCompareBehavior := func(m *mock_hash.MockHashProvider, input service.ChangePasswordInput) {
+		m.EXPECT().
+			Compare(user.Password, input.CurrentPassword).
+			Return(hashResultError).
+			Times(1)
+	}
 
 	methodCases := []struct {
 		Name                     string
-		ServiceInput             service.AuthenticateUserInput
-		InputToken               string
-		AuthResultError          error
-		MethodResultValue        uuid.UUID
+		ServiceInput             service.ChangePasswordInput
 		MethodResultError        error
-		MockAuthProviderBehavior MockAuthProviderBehavior
+		MockHashProviderBehavior MockHashProviderBehavior
+	}{
+		{
+			Name:                     "Success",
+			ServiceInput:             service.ChangePasswordInput{ID: user.ID, CurrentPassword: "secret", NewPassword: "new-secret"},
+			MockHashProviderBehavior: mockChangedBehavior,
+		},
+		{
+			Name:                     "CurrentPasswordMismatch",
+			ServiceInput:             service.ChangePasswordInput{ID: user.ID, CurrentPassword: "wrong-secret", NewPassword: "new-secret"},
+			MethodResultError:        serviceerrors.ErrCurrentPasswordMismatch,
+			MockHashProviderBehavior: mockCompareBehavior,
+		},
+		{
+			Name:              "InvalidNewPassword",
+			ServiceInput:      service.ChangePasswordInput{ID: user.ID, CurrentPassword: "secret", NewPassword: "new"},
+			MethodResultError: domain.ErrUserPasswordInvalidLength,
+			MockHashProviderBehavior: func(m *mock_hash.MockHashProvider, input service.ChangePasswordInput) {
+				m.EXPECT().
+					Compare(user.Password, input.CurrentPassword).
+					Return(nil).
+					Times(1)
+			},
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			s.MockUserRepository.EXPECT().
+				Self(context.Background(), user.ID).
+				Return(domain.User{Model: user.Model, Email: user.Email}, nil).
+				Times(1)
+			s.MockUserRepository.EXPECT().
+				GetByEmail(context.Background(), user.Email).
+				Return(user, nil).
+				Times(1)
+			currentCase.MockHashProviderBehavior(s.MockHashProvider, currentCase.ServiceInput)
+			err := s.CurrentService.ChangePassword(context.Background(), currentCase.ServiceInput)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+		})
+	}
+}
+
+func (s *UserServiceSuite) TestUpdateMethod() {
+	// Username is too short, it was created before usernames were validated
+	user := domain.User{
+		Model:       domain.Model{ID: uuid.NewV4(), Version: 1},
+		Username:    "un",
+		DisplayName: "Display Name",
+		Bio:         "Some **bio**",
+		Website:     "https://example.com",
+	}
+
+	username := "new_username"
+	takenUsername := "taken_username"
+	bio := ""
+	links := []string{"https://github.com/username"}
+	invalidWebsite := "example.com"
+
+	methodCases := []struct {
+		Name              string
+		ServiceInput      service.UpdateUserInput
+		MethodResultValue domain.User
+		MethodResultError error
 	}{
 		{
 			Name: "Success",
-			ServiceInput: service.AuthenticateUserInput{
-				Token: "access-token-valid",
+			ServiceInput: service.UpdateUserInput{
+				ID:          user.ID,
+				Username:    &username,
+				Bio:         &bio,
+				SocialLinks: &links,
+				Version:     user.Version,
+			},
+			MethodResultValue: domain.User{
+				Model:       domain.Model{ID: user.ID, Version: 2},
+				Username:    username,
+				DisplayName: user.DisplayName,
+				Bio:         bio,
+				Website:     user.Website,
+				SocialLinks: domain.SocialLinks(links),
 			},
-			InputToken:               "access-token-valid",
-			AuthResultError:          nil,
-			MethodResultValue:        id,
-			MethodResultError:        nil,
-			MockAuthProviderBehavior: mockAuthProviderBehavior,
 		},
 		{
-			Name:                     "AuthFailure",
-			ServiceInput:             service.AuthenticateUserInput{},
-			AuthResultError:          authResultError,
-			MethodResultValue:        uuid.Nil,
-			MethodResultError:        authResultError,
-			MockAuthProviderBehavior: mockAuthProviderBehavior,
+			Name: "SuccessUnchangedUsername",
+			ServiceInput: service.UpdateUserInput{
+				ID:      user.ID,
+				Bio:     &bio,
+				Version: user.Version,
+			},
+			MethodResultValue: domain.User{
+				Model:       domain.Model{ID: user.ID, Version: 2},
+				Username:    user.Username,
+				DisplayName: user.DisplayName,
+				Bio:         bio,
+				Website:     user.Website,
+			},
+		},
+		{
+			Name: "InvalidWebsite",
+			ServiceInput: service.UpdateUserInput{
+				ID:      user.ID,
+				Website: &invalidWebsite,
+				Version: user.Version,
+			},
+			MethodResultError: domain.ErrUserWebsiteInvalidValue,
+		},
+		{
+			Name: "UsernameAlreadyTaken",
+			ServiceInput: service.UpdateUserInput{
+				ID:       user.ID,
+				Username: &takenUsername,
+				Version:  user.Version,
+			},
+			MethodResultError: serviceerrors.ErrUsernameAlreadyTaken,
 		},
 	}
 
 	for _, currentCase := range methodCases {
 		s.Suite.Run(currentCase.Name, func() {
-			currentCase.MockAuthProviderBehavior(s.MockAuthProvider, currentCase.InputToken, currentCase.MethodResultValue, currentCase.MethodResultError)
-			result, err := s.CurrentService.Authenticate(context.Background(), currentCase.ServiceInput)
-			s.Assertions.Equal(currentCase.MethodResultValue, result)
+			s.MockUserRepository.EXPECT().
+				Find(context.Background(), user.ID).
+				Return(user, nil).
+				Times(1)
+			switch {
+			case currentCase.MethodResultError == nil && currentCase.ServiceInput.Username != nil:
+				s.MockUserRepository.EXPECT().
+					GetByUsername(context.Background(), username).
+					Return(domain.User{}, repoerrors.ErrUserNotFound).
+					Times(1)
+			case currentCase.MethodResultError == serviceerrors.ErrUsernameAlreadyTaken:
+				s.MockUserRepository.EXPECT().
+					GetByUsername(context.Background(), takenUsername).
+					Return(domain.User{Model: domain.Model{ID: uuid.NewV4()}, Username: takenUsername}, nil).
+					Times(1)
+			}
+			if currentCase.MethodResultError == nil {
+				s.MockUserRepository.EXPECT().
+					Update(context.Background(), gomock.AssignableToTypeOf(domain.User{})).
+					Return(nil).
+					Times(1)
+				s.MockWebhookDispatcher.EXPECT().
+					Dispatch(context.Background(), domain.UserUpdatedWebhookEvent, user.ID, gomock.AssignableToTypeOf(domain.User{})).
+					Times(1)
+			}
+			result, err := s.CurrentService.Update(context.Background(), currentCase.ServiceInput)
 			s.Assertions.Equal(currentCase.MethodResultError, err)
+			if err == nil {
+				result.UpdatedAt = currentCase.MethodResultValue.UpdatedAt
+				s.Assertions.Equal(currentCase.MethodResultValue, result)
+			}
 		})
 	}
 }
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	"github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	"github.com/aintsashqa/go-simple-blog/pkg/logger"
+	"github.com/aintsashqa/go-simple-blog/pkg/mailer"
+	"github.com/aintsashqa/go-simple-blog/pkg/signature"
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	verificationPurpose string = "email_verification"
+	emailChangePurpose  string = "email_change"
+
+	verificationMailSubject string = "Confirm your email address"
+	verificationMailBody    string = "Please confirm your email address by following the link below:\r\n\r\n%s\r\n\r\nThe link expires at %s."
+
+	emailChangeMailSubject string = "Confirm your new email address"
+	emailChangeMailBody    string = "Somebody requested to change email of your account to this address. If it was you, follow the link below:\r\n\r\n%s\r\n\r\nThe link expires at %s."
+)
+
+// verificationPayload contains email, so link becomes invalid after email was changed,
+// verification and email change links are signed for own purposes, so one link
+// could not be used as another
+type verificationPayload struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Email     string    `json:"email"`
+	NewEmail  string    `json:"new_email,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type VerificationService struct {
+	users          repository.User
+	events         repository.SecurityEvent
+	mailer         mailer.MailerProvider
+	signer         signature.SignatureProvider
+	changeSigner   signature.SignatureProvider
+	logger         logger.Logger
+	url            string
+	emailChangeURL string
+	expiresTime    time.Duration
+}
+
+func NewVerificationService(
+	users repository.User,
+	events repository.SecurityEvent,
+	mailer mailer.MailerProvider,
+	signer signature.SignatureProvider,
+	logger logger.Logger,
+	url string,
+	emailChangeURL string,
+	expiresTime time.Duration,
+) *VerificationService {
+	return &VerificationService{
+		users:          users,
+		events:         events,
+		mailer:         mailer,
+		signer:         signature.WithPurpose(signer, verificationPurpose),
+		changeSigner:   signature.WithPurpose(signer, emailChangePurpose),
+		logger:         logger,
+		url:            url,
+		emailChangeURL: emailChangeURL,
+		expiresTime:    expiresTime,
+	}
+}
+
+func (s *VerificationService) send(message mailer.Message) {
+	go func() {
+		if err := s.mailer.Send(context.Background(), message); err != nil {
+			s.logger.Errorf("service.Verification.send error: %s", err)
+		}
+	}()
+}
+
+func (s *VerificationService) payload(signer signature.SignatureProvider, token string) (verificationPayload, error) {
+	var payload verificationPayload
+
+	value, err := signer.Verify(token)
+	if err != nil {
+		return payload, errors.ErrVerificationTokenInvalid
+	}
+
+	if err := json.Unmarshal(value, &payload); err != nil {
+		return payload, errors.ErrVerificationTokenInvalid
+	}
+
+	if time.Now().After(payload.ExpiresAt) {
+		return payload, errors.ErrVerificationTokenExpired
+	}
+
+	return payload, nil
+}
+
+// Send delivers verification link in background, so slow mail server
+// does not block sign up
+func (s *VerificationService) Send(ctx context.Context, user domain.User) error {
+	payload, err := json.Marshal(verificationPayload{
+		UserID:    user.ID,
+		Email:     user.Email,
+		ExpiresAt: time.Now().Add(s.expiresTime),
+	})
+	if err != nil {
+		return err
+	}
+
+	s.send(mailer.Message{
+		To:      user.Email,
+		Subject: verificationMailSubject,
+		Body:    fmt.Sprintf(verificationMailBody, fmt.Sprintf(s.url, s.signer.Sign(payload)), time.Now().Add(s.expiresTime).Format(time.RFC1123)),
+	})
+
+	return nil
+}
+
+func (s *VerificationService) Verify(ctx context.Context, input VerifyEmailInput) (domain.User, error) {
+	payload, err := s.payload(s.signer, input.Token)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if payload.NewEmail != "" {
+		return domain.User{}, errors.ErrVerificationTokenInvalid
+	}
+
+	user, err := s.users.GetByEmail(ctx, payload.Email)
+	if err == repoerrors.ErrUserNotFound {
+		return domain.User{}, errors.ErrVerificationTokenInvalid
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if !uuid.Equal(user.ID, payload.UserID) {
+		return domain.User{}, errors.ErrVerificationTokenInvalid
+	}
+
+	if user.IsEmailVerified() {
+		return user, nil
+	}
+
+	user.EmailVerifiedAt = null.NewTime(time.Now(), true)
+	user.Update()
+
+	err = s.users.VerifyEmail(ctx, user)
+	return user, err
+}
+
+// SendEmailChange delivers confirmation link to new email, email is not changed
+// until link is followed
+func (s *VerificationService) SendEmailChange(ctx context.Context, user domain.User, email string) error {
+	payload, err := json.Marshal(verificationPayload{
+		UserID:    user.ID,
+		Email:     user.Email,
+		NewEmail:  email,
+		ExpiresAt: time.Now().Add(s.expiresTime),
+	})
+	if err != nil {
+		return err
+	}
+
+	s.send(mailer.Message{
+		To:      email,
+		Subject: emailChangeMailSubject,
+		Body:    fmt.Sprintf(emailChangeMailBody, fmt.Sprintf(s.emailChangeURL, s.changeSigner.Sign(payload)), time.Now().Add(s.expiresTime).Format(time.RFC1123)),
+	})
+
+	return nil
+}
+
+func (s *VerificationService) ConfirmEmailChange(ctx context.Context, input ConfirmEmailChangeInput) (domain.User, error) {
+	payload, err := s.payload(s.changeSigner, input.Token)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if payload.NewEmail == "" {
+		return domain.User{}, errors.ErrVerificationTokenInvalid
+	}
+
+	user, err := s.users.Self(ctx, payload.UserID)
+	if err == repoerrors.ErrUserNotFound {
+		return domain.User{}, errors.ErrVerificationTokenInvalid
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	// Email was changed by another link after this one was sent
+	if user.Email != payload.Email {
+		return domain.User{}, errors.ErrVerificationTokenInvalid
+	}
+
+	if _, err := s.users.GetByEmail(ctx, payload.NewEmail); err != repoerrors.ErrUserNotFound {
+		if err == nil {
+			return domain.User{}, errors.ErrEmailAlreadyTaken
+		}
+		return domain.User{}, err
+	}
+
+	user.Email = payload.NewEmail
+	user.EmailVerifiedAt = null.NewTime(time.Now(), true)
+	user.Update()
+
+	if err := s.users.UpdateEmail(ctx, user); err != nil {
+		return domain.User{}, err
+	}
+
+	err = s.events.Create(ctx, domain.NewSecurityEvent(user.ID, domain.EmailChangedSecurityEvent))
+	return user, err
+}
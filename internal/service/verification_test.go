@@ -0,0 +1,295 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	serviceerrors "github.com/aintsashqa/go-simple-blog/internal/service/errors"
+	mock_logger "github.com/aintsashqa/go-simple-blog/pkg/logger/mocks"
+	mock_mailer "github.com/aintsashqa/go-simple-blog/pkg/mailer/mocks"
+	"github.com/aintsashqa/go-simple-blog/pkg/signature"
+	"github.com/aintsashqa/go-simple-blog/pkg/signature/hmac"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v4"
+)
+
+type VerificationServiceSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockUserRepository          *mock_repository.MockUser
+	MockSecurityEventRepository *mock_repository.MockSecurityEvent
+	MockMailerProvider          *mock_mailer.MockMailerProvider
+	MockLogger                  *mock_logger.MockLogger
+	Signer                      *hmac.HMACSignatureProvider
+
+	CurrentService service.Verification
+}
+
+func TestVerificationServiceSuite(t *testing.T) {
+	suite.Run(t, new(VerificationServiceSuite))
+}
+
+func (s *VerificationServiceSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockUserRepository = mock_repository.NewMockUser(s.Controller)
+	s.MockSecurityEventRepository = mock_repository.NewMockSecurityEvent(s.Controller)
+	s.MockMailerProvider = mock_mailer.NewMockMailerProvider(s.Controller)
+	s.MockLogger = mock_logger.NewMockLogger(s.Controller)
+	s.Signer = hmac.NewHMACSignatureProvider("verification-signing-key")
+	s.CurrentService = service.NewVerificationService(
+		s.MockUserRepository,
+		s.MockSecurityEventRepository,
+		s.MockMailerProvider,
+		s.Signer,
+		s.MockLogger,
+		"http://localhost/verify-email?token=%s",
+		"http://localhost/confirm-email?token=%s",
+		time.Hour,
+	)
+}
+
+func (s *VerificationServiceSuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+func (s *VerificationServiceSuite) token(userID uuid.UUID, email string, expiresAt time.Time) string {
+	payload, err := json.Marshal(map[string]interface{}{
+		"user_id":    userID,
+		"email":      email,
+		"expires_at": expiresAt,
+	})
+	s.Assertions.NoError(err)
+	return signature.WithPurpose(s.Signer, "email_verification").Sign(payload)
+}
+
+func (s *VerificationServiceSuite) emailChangeToken(userID uuid.UUID, email string, newEmail string, expiresAt time.Time) string {
+	payload, err := json.Marshal(map[string]interface{}{
+		"user_id":    userID,
+		"email":      email,
+		"new_email":  newEmail,
+		"expires_at": expiresAt,
+	})
+	s.Assertions.NoError(err)
+	return signature.WithPurpose(s.Signer, "email_change").Sign(payload)
+}
+
+func (s *VerificationServiceSuite) TestVerifyMethod() {
+	type MockUserRepositoryBehavior func(*mock_repository.MockUser, domain.User, error)
+
+	repositoryResultError := errors.New("RepositoryResultError")
+
+	user := domain.User{Model: domain.Model{ID: uuid.NewV4(), Version: 1}, Email: "root@example.com"}
+	verifiedUser := user
+	verifiedUser.EmailVerifiedAt = null.NewTime(time.Now(), true)
+
+	mockGetByEmailBehavior := func(m *mock_repository.MockUser, user domain.User, returns error) {
+		m.EXPECT().
+			GetByEmail(context.Background(), "root@example.com").
+			Return(user, returns).
+			Times(1)
+	}
+
+	mockVerifyEmailBehavior := func(m *mock_repository.MockUser, user domain.User, returns error) {
+		mockGetByEmailBehavior(m, user, nil)
+		m.EXPECT().
+			VerifyEmail(context.Background(), gomock.AssignableToTypeOf(domain.User{})).
+			Return(returns).
+			Times(1)
+	}
+
+	methodCases := []struct {
+		Name                       string
+		Token                      string
+		CurrentUser                domain.User
+		RepositoryResultError      error
+		MethodResultVerified       bool
+		MethodResultError          error
+		MockUserRepositoryBehavior MockUserRepositoryBehavior
+	}{
+		{
+			Name:                       "Success",
+			Token:                      s.token(user.ID, user.Email, time.Now().Add(time.Hour)),
+			CurrentUser:                user,
+			MethodResultVerified:       true,
+			MockUserRepositoryBehavior: mockVerifyEmailBehavior,
+		},
+		{
+			Name:                       "AlreadyVerified",
+			Token:                      s.token(user.ID, user.Email, time.Now().Add(time.Hour)),
+			CurrentUser:                verifiedUser,
+			MethodResultVerified:       true,
+			MockUserRepositoryBehavior: mockGetByEmailBehavior,
+		},
+		{
+			Name:              "InvalidSignature",
+			Token:             hmac.NewHMACSignatureProvider("other-key").Sign([]byte("{}")),
+			MethodResultError: serviceerrors.ErrVerificationTokenInvalid,
+		},
+		{
+			Name:              "Expired",
+			Token:             s.token(user.ID, user.Email, time.Now().Add(-time.Hour)),
+			MethodResultError: serviceerrors.ErrVerificationTokenExpired,
+		},
+		{
+			Name:              "EmailChangeToken",
+			Token:             s.emailChangeToken(user.ID, user.Email, "new@example.com", time.Now().Add(time.Hour)),
+			MethodResultError: serviceerrors.ErrVerificationTokenInvalid,
+		},
+		{
+			Name:                       "UserNotFound",
+			Token:                      s.token(user.ID, user.Email, time.Now().Add(time.Hour)),
+			RepositoryResultError:      repoerrors.ErrUserNotFound,
+			MethodResultError:          serviceerrors.ErrVerificationTokenInvalid,
+			MockUserRepositoryBehavior: mockGetByEmailBehavior,
+		},
+		{
+			Name:                       "AnotherUser",
+			Token:                      s.token(uuid.NewV4(), user.Email, time.Now().Add(time.Hour)),
+			CurrentUser:                user,
+			MethodResultError:          serviceerrors.ErrVerificationTokenInvalid,
+			MockUserRepositoryBehavior: mockGetByEmailBehavior,
+		},
+		{
+			Name:                       "RepositoryFailure",
+			Token:                      s.token(user.ID, user.Email, time.Now().Add(time.Hour)),
+			CurrentUser:                user,
+			RepositoryResultError:      repositoryResultError,
+			MethodResultVerified:       true,
+			MethodResultError:          repositoryResultError,
+			MockUserRepositoryBehavior: mockVerifyEmailBehavior,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			if currentCase.MockUserRepositoryBehavior != nil {
+				currentCase.MockUserRepositoryBehavior(s.MockUserRepository, currentCase.CurrentUser, currentCase.RepositoryResultError)
+			}
+			result, err := s.CurrentService.Verify(context.Background(), service.VerifyEmailInput{Token: currentCase.Token})
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			s.Assertions.Equal(currentCase.MethodResultVerified, result.IsEmailVerified())
+		})
+	}
+}
+
+func (s *VerificationServiceSuite) TestConfirmEmailChangeMethod() {
+	type MockUserRepositoryBehavior func(*mock_repository.MockUser, domain.User, error)
+
+	repositoryResultError := errors.New("RepositoryResultError")
+
+	user := domain.User{Model: domain.Model{ID: uuid.NewV4(), Version: 1}, Email: "root@example.com"}
+	newEmail := "new@example.com"
+
+	mockSelfBehavior := func(m *mock_repository.MockUser, user domain.User, returns error) {
+		m.EXPECT().
+			Self(context.Background(), user.ID).
+			Return(user, returns).
+			Times(1)
+	}
+
+	mockTakenBehavior := func(m *mock_repository.MockUser, user domain.User, returns error) {
+		mockSelfBehavior(m, user, nil)
+		m.EXPECT().
+			GetByEmail(context.Background(), newEmail).
+			Return(domain.User{}, returns).
+			Times(1)
+	}
+
+	mockUpdateEmailBehavior := func(m *mock_repository.MockUser, user domain.User, returns error) {
+		mockTakenBehavior(m, user, repoerrors.ErrUserNotFound)
+		m.EXPECT().
+			UpdateEmail(context.Background(), gomock.AssignableToTypeOf(domain.User{})).
+			DoAndReturn(func(_ context.Context, changed domain.User) error {
+				s.Assertions.Equal(newEmail, changed.Email)
+				s.Assertions.True(changed.IsEmailVerified())
+				return returns
+			}).
+			Times(1)
+	}
+
+	methodCases := []struct {
+		Name                       string
+		Token                      string
+		CurrentUser                domain.User
+		RepositoryResultError      error
+		MethodResultEmail          string
+		MethodResultError          error
+		MockUserRepositoryBehavior MockUserRepositoryBehavior
+	}{
+		{
+			Name:                       "Success",
+			Token:                      s.emailChangeToken(user.ID, user.Email, newEmail, time.Now().Add(time.Hour)),
+			CurrentUser:                user,
+			MethodResultEmail:          newEmail,
+			MockUserRepositoryBehavior: mockUpdateEmailBehavior,
+		},
+		{
+			Name:              "VerificationToken",
+			Token:             s.token(user.ID, user.Email, time.Now().Add(time.Hour)),
+			MethodResultError: serviceerrors.ErrVerificationTokenInvalid,
+		},
+		{
+			Name:              "OtherPurpose",
+			Token:             signature.WithPurpose(s.Signer, "mfa_challenge").Sign([]byte(`{"user_id":"` + user.ID.String() + `","email":"root@example.com","new_email":"new@example.com","expires_at":"2100-01-01T00:00:00Z"}`)),
+			MethodResultError: serviceerrors.ErrVerificationTokenInvalid,
+		},
+		{
+			Name:              "Expired",
+			Token:             s.emailChangeToken(user.ID, user.Email, newEmail, time.Now().Add(-time.Hour)),
+			MethodResultError: serviceerrors.ErrVerificationTokenExpired,
+		},
+		{
+			Name:                       "EmailAlreadyChanged",
+			Token:                      s.emailChangeToken(user.ID, "old@example.com", newEmail, time.Now().Add(time.Hour)),
+			CurrentUser:                user,
+			MethodResultError:          serviceerrors.ErrVerificationTokenInvalid,
+			MockUserRepositoryBehavior: mockSelfBehavior,
+		},
+		{
+			Name:                       "EmailTaken",
+			Token:                      s.emailChangeToken(user.ID, user.Email, newEmail, time.Now().Add(time.Hour)),
+			CurrentUser:                user,
+			MethodResultError:          serviceerrors.ErrEmailAlreadyTaken,
+			MockUserRepositoryBehavior: mockTakenBehavior,
+		},
+		{
+			Name:                       "RepositoryFailure",
+			Token:                      s.emailChangeToken(user.ID, user.Email, newEmail, time.Now().Add(time.Hour)),
+			CurrentUser:                user,
+			RepositoryResultError:      repositoryResultError,
+			MethodResultError:          repositoryResultError,
+			MockUserRepositoryBehavior: mockUpdateEmailBehavior,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			if currentCase.MockUserRepositoryBehavior != nil {
+				currentCase.MockUserRepositoryBehavior(s.MockUserRepository, currentCase.CurrentUser, currentCase.RepositoryResultError)
+			}
+			if currentCase.MethodResultEmail != "" {
+				s.MockSecurityEventRepository.EXPECT().
+					Create(context.Background(), gomock.AssignableToTypeOf(domain.SecurityEvent{})).
+					Return(nil).
+					Times(1)
+			}
+			result, err := s.CurrentService.ConfirmEmailChange(context.Background(), service.ConfirmEmailChangeInput{Token: currentCase.Token})
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			s.Assertions.Equal(currentCase.MethodResultEmail, result.Email)
+		})
+	}
+}
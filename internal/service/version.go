@@ -0,0 +1,35 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/service/errors"
+)
+
+const anyETag string = "*"
+
+// checkVersion compares version expected by client with current model version,
+// entity tag from `If-Match` header is preferred over version field
+func checkVersion(model domain.Model, etag string, version int) error {
+	if len(etag) != 0 {
+		for _, value := range strings.Split(etag, ",") {
+			value = strings.TrimSpace(value)
+			if value == anyETag || value == model.ETag() {
+				return nil
+			}
+		}
+
+		return errors.ErrETagMismatch
+	}
+
+	if version == 0 {
+		return errors.ErrVersionRequired
+	}
+
+	if version != model.Version {
+		return errors.ErrVersionMismatch
+	}
+
+	return nil
+}
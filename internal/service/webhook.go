@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	"github.com/aintsashqa/go-simple-blog/pkg/logger"
+	"github.com/aintsashqa/go-simple-blog/pkg/random"
+	"github.com/aintsashqa/go-simple-blog/pkg/webhook"
+	uuid "github.com/satori/go.uuid"
+	"gopkg.in/guregu/null.v4"
+)
+
+const (
+	webhookSecretSize int = 32
+
+	// Error of attempt is kept in delivery log, column holds 255 characters
+	webhookErrorMaxLength int = 255
+)
+
+// webhookPayload is sent as body of every delivery, data holds resource
+// of event
+type webhookPayload struct {
+	Event     domain.WebhookEvent `json:"event"`
+	CreatedAt time.Time           `json:"created_at"`
+	Data      interface{}         `json:"data"`
+}
+
+type WebhookService struct {
+	webhooks   repository.Webhook
+	deliveries repository.WebhookDelivery
+	provider   webhook.Provider
+	logger     logger.Logger
+	config     WebhookConfig
+}
+
+func NewWebhookService(webhooks repository.Webhook, deliveries repository.WebhookDelivery, provider webhook.Provider, logger logger.Logger, config WebhookConfig) *WebhookService {
+	return &WebhookService{webhooks: webhooks, deliveries: deliveries, provider: provider, logger: logger, config: config}
+}
+
+// Dispatch queues delivery of event for every webhook of user and every
+// global webhook subscribed to event, deliveries are sent by Deliver
+func (s *WebhookService) Dispatch(ctx context.Context, event domain.WebhookEvent, userID uuid.UUID, data interface{}) {
+	webhooks, err := s.webhooks.GetAllSubscribed(ctx, event, userID)
+	if err != nil {
+		s.logger.Errorf("service.Webhook.Dispatch error: %s", err)
+		return
+	}
+
+	if len(webhooks) == 0 {
+		return
+	}
+
+	// Email of user is private, it is not sent to receivers
+	if user, ok := data.(domain.User); ok {
+		user.Email = ""
+		data = user
+	}
+
+	payload, err := json.Marshal(webhookPayload{Event: event, CreatedAt: time.Now(), Data: data})
+	if err != nil {
+		s.logger.Errorf("service.Webhook.Dispatch error: %s", err)
+		return
+	}
+
+	for _, current := range webhooks {
+		if err := s.deliveries.Create(ctx, domain.NewWebhookDelivery(current.ID, event, string(payload))); err != nil {
+			s.logger.Errorf("service.Webhook.Dispatch error: %s", err)
+		}
+	}
+}
+
+// Create returns secret of webhook, payloads are signed with it
+func (s *WebhookService) Create(ctx context.Context, input CreateWebhookInput) (domain.Webhook, error) {
+	secret, err := random.Token(webhookSecretSize)
+	if err != nil {
+		return domain.Webhook{}, err
+	}
+
+	current := domain.Webhook{
+		ID:        uuid.NewV4(),
+		UserID:    input.UserID,
+		URL:       input.URL,
+		Secret:    secret,
+		Events:    input.Events,
+		Global:    input.Global,
+		CreatedAt: time.Now(),
+	}
+
+	if err := current.Validate(); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	if err := s.webhooks.Create(ctx, current); err != nil {
+		return domain.Webhook{}, err
+	}
+
+	return current, nil
+}
+
+func (s *WebhookService) GetAll(ctx context.Context, userID uuid.UUID) ([]domain.Webhook, error) {
+	return s.webhooks.GetAllWithUserID(ctx, userID)
+}
+
+func (s *WebhookService) Delete(ctx context.Context, input DeleteWebhookInput) error {
+	return s.webhooks.Delete(ctx, input.ID, input.UserID)
+}
+
+// Deliveries returns delivery log of webhook, webhook of another user
+// is not found
+func (s *WebhookService) Deliveries(ctx context.Context, opt PaginateWebhookDeliveryOptions) (WebhookDeliveryPagination, error) {
+	if _, err := s.webhooks.FindWithUserID(ctx, opt.WebhookID, opt.UserID); err != nil {
+		return WebhookDeliveryPagination{}, err
+	}
+
+	deliveries, err := s.deliveries.GetAllWithWebhookID(ctx, opt.WebhookID, pageOffset(opt.CurrentPage, opt.DeliveriesPerPage), opt.DeliveriesPerPage)
+	if err != nil {
+		return WebhookDeliveryPagination{}, err
+	}
+
+	count, err := s.deliveries.CountWithWebhookID(ctx, opt.WebhookID)
+	if err != nil {
+		return WebhookDeliveryPagination{}, err
+	}
+
+	previousPage, nextPage := pages(opt.CurrentPage, opt.DeliveriesPerPage, count)
+
+	return WebhookDeliveryPagination{
+		Deliveries:        deliveries,
+		DeliveriesCount:   count,
+		PreviousPage:      previousPage,
+		CurrentPage:       opt.CurrentPage,
+		NextPage:          nextPage,
+		DeliveriesPerPage: opt.DeliveriesPerPage,
+	}, nil
+}
+
+// Redeliver queues new delivery with payload of previous one, previous
+// delivery is kept in log as is
+func (s *WebhookService) Redeliver(ctx context.Context, input RedeliverWebhookInput) (domain.WebhookDelivery, error) {
+	current, err := s.webhooks.FindWithUserID(ctx, input.WebhookID, input.UserID)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	previous, err := s.deliveries.FindWithWebhookID(ctx, input.ID, current.ID)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	delivery := domain.NewWebhookDelivery(current.ID, previous.Event, previous.Payload)
+	if err := s.deliveries.Create(ctx, delivery); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	return delivery, nil
+}
+
+// Deliver attempts due deliveries and returns count of delivered ones.
+// Failed attempt is retried with exponential backoff until attempts are
+// exhausted
+func (s *WebhookService) Deliver(ctx context.Context) (int, error) {
+	now := time.Now()
+	deliveries, err := s.deliveries.GetAllDue(ctx, now, s.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		claimed, err := s.deliveries.Claim(ctx, delivery, now.Add(s.config.Lease))
+		if err != nil {
+			return delivered, err
+		}
+
+		// Delivery is attempted by another instance
+		if !claimed {
+			continue
+		}
+
+		s.attempt(ctx, &delivery)
+		if err := s.deliveries.Update(ctx, delivery); err != nil {
+			return delivered, err
+		}
+
+		if delivery.Status == domain.DeliveredWebhookDelivery {
+			delivered++
+		}
+	}
+
+	return delivered, nil
+}
+
+func (s *WebhookService) attempt(ctx context.Context, delivery *domain.WebhookDelivery) {
+	delivery.Attempts++
+	delivery.UpdatedAt = time.Now()
+
+	current, err := s.webhooks.Find(ctx, delivery.WebhookID)
+	if err != nil {
+		s.fail(delivery, 0, err.Error())
+		return
+	}
+
+	response, err := s.provider.Send(ctx, webhook.Message{
+		URL:        current.URL,
+		Secret:     current.Secret,
+		Event:      string(delivery.Event),
+		DeliveryID: delivery.ID.String(),
+		Payload:    []byte(delivery.Payload),
+	})
+
+	switch {
+
+	case err != nil:
+		s.fail(delivery, response.StatusCode, err.Error())
+
+	case !response.IsSuccess():
+		s.fail(delivery, response.StatusCode, fmt.Sprintf("Receiver responded with status %d", response.StatusCode))
+
+	default:
+		delivery.Status = domain.DeliveredWebhookDelivery
+		delivery.StatusCode = response.StatusCode
+		delivery.Error = ""
+		delivery.NextAttemptAt = null.Time{}
+	}
+}
+
+// fail schedules next attempt of delivery, delivery is failed for good
+// when attempts are exhausted
+func (s *WebhookService) fail(delivery *domain.WebhookDelivery, statusCode int, reason string) {
+	if len(reason) > webhookErrorMaxLength {
+		reason = reason[:webhookErrorMaxLength]
+	}
+
+	delivery.StatusCode = statusCode
+	delivery.Error = reason
+
+	if delivery.Attempts >= s.config.MaxAttempts {
+		delivery.Status = domain.FailedWebhookDelivery
+		delivery.NextAttemptAt = null.Time{}
+		return
+	}
+
+	delay := webhook.Backoff(delivery.Attempts, s.config.BackoffBase, s.config.BackoffMax)
+	delivery.NextAttemptAt = null.NewTime(delivery.UpdatedAt.Add(delay), true)
+}
@@ -0,0 +1,347 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/internal/domain"
+	repoerrors "github.com/aintsashqa/go-simple-blog/internal/repository/errors"
+	mock_repository "github.com/aintsashqa/go-simple-blog/internal/repository/mocks"
+	"github.com/aintsashqa/go-simple-blog/internal/service"
+	mock_logger "github.com/aintsashqa/go-simple-blog/pkg/logger/mocks"
+	"github.com/aintsashqa/go-simple-blog/pkg/webhook/client"
+	"github.com/aintsashqa/go-simple-blog/pkg/webhook/webhooktest"
+	"github.com/golang/mock/gomock"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookServiceSuite struct {
+	suite.Suite
+	*require.Assertions
+
+	Controller *gomock.Controller
+
+	MockWebhookRepository         *mock_repository.MockWebhook
+	MockWebhookDeliveryRepository *mock_repository.MockWebhookDelivery
+	MockLogger                    *mock_logger.MockLogger
+
+	Config service.WebhookConfig
+}
+
+func TestWebhookServiceSuite(t *testing.T) {
+	suite.Run(t, new(WebhookServiceSuite))
+}
+
+func (s *WebhookServiceSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.Controller = gomock.NewController(s.T())
+	s.MockWebhookRepository = mock_repository.NewMockWebhook(s.Controller)
+	s.MockWebhookDeliveryRepository = mock_repository.NewMockWebhookDelivery(s.Controller)
+	s.MockLogger = mock_logger.NewMockLogger(s.Controller)
+	s.Config = service.WebhookConfig{
+		MaxAttempts: 2,
+		BackoffBase: time.Minute,
+		BackoffMax:  time.Hour,
+		Lease:       time.Minute,
+		BatchSize:   10,
+	}
+}
+
+func (s *WebhookServiceSuite) TearDownTest() {
+	s.Controller.Finish()
+}
+
+// newService sends deliveries to receiver, so whole pipeline is tested
+// with real signed requests, receiver listens on loopback which default
+// client rejects
+func (s *WebhookServiceSuite) newService() *service.WebhookService {
+	return service.NewWebhookService(
+		s.MockWebhookRepository,
+		s.MockWebhookDeliveryRepository,
+		client.NewWebhookProvider(client.Config{HTTPClient: &http.Client{Timeout: time.Second}}),
+		s.MockLogger,
+		s.Config,
+	)
+}
+
+func (s *WebhookServiceSuite) TestDispatchMethod() {
+	userID := uuid.NewV4()
+	user := domain.User{Model: domain.Model{ID: userID}, Email: "user@example.com", Username: "username"}
+	webhooks := []domain.Webhook{{ID: uuid.NewV4()}, {ID: uuid.NewV4(), Global: true}}
+
+	s.MockWebhookRepository.EXPECT().
+		GetAllSubscribed(context.Background(), domain.UserUpdatedWebhookEvent, userID).
+		Return(webhooks, nil).
+		Times(1)
+	for _, current := range webhooks {
+		s.MockWebhookDeliveryRepository.EXPECT().
+			Create(context.Background(), gomock.AssignableToTypeOf(domain.WebhookDelivery{})).
+			DoAndReturn(func(webhookID uuid.UUID) func(context.Context, domain.WebhookDelivery) error {
+				return func(_ context.Context, delivery domain.WebhookDelivery) error {
+					s.Assertions.Equal(webhookID, delivery.WebhookID)
+					s.Assertions.Equal(domain.PendingWebhookDelivery, delivery.Status)
+					s.Assertions.True(delivery.IsDue(time.Now()))
+					s.Assertions.Contains(delivery.Payload, `"event":"user.updated"`)
+					s.Assertions.Contains(delivery.Payload, `"username":"username"`)
+					s.Assertions.NotContains(delivery.Payload, user.Email)
+					return nil
+				}
+			}(current.ID)).
+			Times(1)
+	}
+
+	s.newService().Dispatch(context.Background(), domain.UserUpdatedWebhookEvent, userID, user)
+
+	// Nothing is queued without subscriptions
+	s.MockWebhookRepository.EXPECT().
+		GetAllSubscribed(context.Background(), domain.PostCreatedWebhookEvent, userID).
+		Return([]domain.Webhook{}, nil).
+		Times(1)
+
+	s.newService().Dispatch(context.Background(), domain.PostCreatedWebhookEvent, userID, domain.Post{})
+}
+
+func (s *WebhookServiceSuite) TestCreateMethod() {
+	methodCases := []struct {
+		Name              string
+		ServiceInput      service.CreateWebhookInput
+		MethodResultError error
+	}{
+		{
+			Name:         "Success",
+			ServiceInput: service.CreateWebhookInput{UserID: uuid.NewV4(), URL: "https://example.com/hook", Events: domain.WebhookEvents{domain.PostPublishedWebhookEvent}},
+		},
+		{
+			Name:              "InvalidURL",
+			ServiceInput:      service.CreateWebhookInput{UserID: uuid.NewV4(), URL: "ftp://example.com/hook", Events: domain.WebhookEvents{domain.PostPublishedWebhookEvent}},
+			MethodResultError: domain.ErrWebhookURLInvalidValue,
+		},
+		{
+			Name:              "PrivateURL",
+			ServiceInput:      service.CreateWebhookInput{UserID: uuid.NewV4(), URL: "http://169.254.169.254/latest/meta-data", Events: domain.WebhookEvents{domain.PostPublishedWebhookEvent}},
+			MethodResultError: domain.ErrWebhookURLInvalidValue,
+		},
+		{
+			Name:              "LocalhostURL",
+			ServiceInput:      service.CreateWebhookInput{UserID: uuid.NewV4(), URL: "http://localhost:8080/hook", Events: domain.WebhookEvents{domain.PostPublishedWebhookEvent}},
+			MethodResultError: domain.ErrWebhookURLInvalidValue,
+		},
+		{
+			Name:              "EventsEmpty",
+			ServiceInput:      service.CreateWebhookInput{UserID: uuid.NewV4(), URL: "https://example.com/hook"},
+			MethodResultError: domain.ErrWebhookEventsEmptyValue,
+		},
+		{
+			Name:              "EventsInvalid",
+			ServiceInput:      service.CreateWebhookInput{UserID: uuid.NewV4(), URL: "https://example.com/hook", Events: domain.WebhookEvents{"post.updated"}},
+			MethodResultError: domain.ErrWebhookEventsInvalidValue,
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			if currentCase.MethodResultError == nil {
+				s.MockWebhookRepository.EXPECT().
+					Create(context.Background(), gomock.AssignableToTypeOf(domain.Webhook{})).
+					Return(nil).
+					Times(1)
+			}
+			result, err := s.newService().Create(context.Background(), currentCase.ServiceInput)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			if err == nil {
+				s.Assertions.Equal(currentCase.ServiceInput.UserID, result.UserID)
+				s.Assertions.NotEmpty(result.Secret)
+			}
+		})
+	}
+}
+
+func (s *WebhookServiceSuite) TestRedeliverMethod() {
+	type MockBehavior func(s *WebhookServiceSuite, input service.RedeliverWebhookInput)
+
+	mockFindBehavior := func(returns error) MockBehavior {
+		return func(s *WebhookServiceSuite, input service.RedeliverWebhookInput) {
+			s.MockWebhookRepository.EXPECT().
+				FindWithUserID(context.Background(), input.WebhookID, input.UserID).
+				Return(domain.Webhook{ID: input.WebhookID, UserID: input.UserID}, returns).
+				Times(1)
+		}
+	}
+
+	mockRedeliverBehavior := func(s *WebhookServiceSuite, input service.RedeliverWebhookInput) {
+		mockFindBehavior(nil)(s, input)
+		previous := domain.NewWebhookDelivery(input.WebhookID, domain.PostDeletedWebhookEvent, `{"event":"post.deleted"}`)
+		previous.Status = domain.FailedWebhookDelivery
+		s.MockWebhookDeliveryRepository.EXPECT().
+			FindWithWebhookID(context.Background(), input.ID, input.WebhookID).
+			Return(previous, nil).
+			Times(1)
+		s.MockWebhookDeliveryRepository.EXPECT().
+			Create(context.Background(), gomock.AssignableToTypeOf(domain.WebhookDelivery{})).
+			Return(nil).
+			Times(1)
+	}
+
+	methodCases := []struct {
+		Name              string
+		MethodResultError error
+		MockBehavior      MockBehavior
+	}{
+		{
+			Name:         "Success",
+			MockBehavior: mockRedeliverBehavior,
+		},
+		{
+			Name:              "WebhookNotFound",
+			MethodResultError: repoerrors.ErrWebhookNotFound,
+			MockBehavior:      mockFindBehavior(repoerrors.ErrWebhookNotFound),
+		},
+	}
+
+	for _, currentCase := range methodCases {
+		s.Suite.Run(currentCase.Name, func() {
+			input := service.RedeliverWebhookInput{ID: uuid.NewV4(), WebhookID: uuid.NewV4(), UserID: uuid.NewV4()}
+			currentCase.MockBehavior(s, input)
+			delivery, err := s.newService().Redeliver(context.Background(), input)
+			s.Assertions.Equal(currentCase.MethodResultError, err)
+			if err == nil {
+				s.Assertions.NotEqual(input.ID, delivery.ID)
+				s.Assertions.Equal(domain.PendingWebhookDelivery, delivery.Status)
+				s.Assertions.Equal(`{"event":"post.deleted"}`, delivery.Payload)
+			}
+		})
+	}
+}
+
+func (s *WebhookServiceSuite) TestDeliverMethod() {
+	secret := "webhook-secret"
+	receiver := webhooktest.NewReceiver(secret, http.StatusInternalServerError, http.StatusNoContent)
+	defer receiver.Close()
+
+	current := domain.Webhook{ID: uuid.NewV4(), URL: receiver.URL, Secret: secret}
+	delivery := domain.NewWebhookDelivery(current.ID, domain.PostCreatedWebhookEvent, `{"event":"post.created"}`)
+
+	// deliver runs single iteration of worker and returns updated delivery
+	deliver := func(delivery domain.WebhookDelivery) (int, domain.WebhookDelivery) {
+		var updated domain.WebhookDelivery
+		s.MockWebhookDeliveryRepository.EXPECT().
+			GetAllDue(context.Background(), gomock.Any(), s.Config.BatchSize).
+			Return([]domain.WebhookDelivery{delivery}, nil).
+			Times(1)
+		s.MockWebhookDeliveryRepository.EXPECT().
+			Claim(context.Background(), delivery, gomock.Any()).
+			Return(true, nil).
+			Times(1)
+		s.MockWebhookRepository.EXPECT().
+			Find(context.Background(), current.ID).
+			Return(current, nil).
+			Times(1)
+		s.MockWebhookDeliveryRepository.EXPECT().
+			Update(context.Background(), gomock.AssignableToTypeOf(domain.WebhookDelivery{})).
+			DoAndReturn(func(_ context.Context, delivery domain.WebhookDelivery) error {
+				updated = delivery
+				return nil
+			}).
+			Times(1)
+
+		delivered, err := s.newService().Deliver(context.Background())
+		s.Assertions.NoError(err)
+		return delivered, updated
+	}
+
+	// First attempt fails and is retried after backoff
+	delivered, delivery := deliver(delivery)
+	s.Assertions.Equal(0, delivered)
+	s.Assertions.Equal(domain.PendingWebhookDelivery, delivery.Status)
+	s.Assertions.Equal(1, delivery.Attempts)
+	s.Assertions.Equal(http.StatusInternalServerError, delivery.StatusCode)
+	s.Assertions.NotEmpty(delivery.Error)
+	s.Assertions.False(delivery.IsDue(time.Now()))
+	s.Assertions.WithinDuration(delivery.UpdatedAt.Add(s.Config.BackoffBase), delivery.NextAttemptAt.Time, time.Second)
+
+	// Second attempt succeeds
+	delivery.NextAttemptAt.Time = time.Now()
+	delivered, delivery = deliver(delivery)
+	s.Assertions.Equal(1, delivered)
+	s.Assertions.Equal(domain.DeliveredWebhookDelivery, delivery.Status)
+	s.Assertions.Equal(2, delivery.Attempts)
+	s.Assertions.Equal(http.StatusNoContent, delivery.StatusCode)
+	s.Assertions.Empty(delivery.Error)
+	s.Assertions.False(delivery.NextAttemptAt.Valid)
+
+	requests := receiver.Requests()
+	s.Assertions.Len(requests, 2)
+	for _, request := range requests {
+		s.Assertions.True(request.Valid)
+		s.Assertions.Equal(string(domain.PostCreatedWebhookEvent), request.Event)
+		s.Assertions.Equal(delivery.ID.String(), request.DeliveryID)
+		s.Assertions.Equal(`{"event":"post.created"}`, string(request.Payload))
+	}
+}
+
+func (s *WebhookServiceSuite) TestDeliverMethodExhausted() {
+	receiver := webhooktest.NewReceiver("webhook-secret", http.StatusBadGateway)
+	defer receiver.Close()
+
+	current := domain.Webhook{ID: uuid.NewV4(), URL: receiver.URL, Secret: "webhook-secret"}
+	delivery := domain.NewWebhookDelivery(current.ID, domain.PostDeletedWebhookEvent, "{}")
+	delivery.Attempts = s.Config.MaxAttempts - 1
+
+	s.MockWebhookDeliveryRepository.EXPECT().
+		GetAllDue(context.Background(), gomock.Any(), s.Config.BatchSize).
+		Return([]domain.WebhookDelivery{delivery}, nil).
+		Times(1)
+	s.MockWebhookDeliveryRepository.EXPECT().
+		Claim(context.Background(), delivery, gomock.Any()).
+		Return(true, nil).
+		Times(1)
+	s.MockWebhookRepository.EXPECT().
+		Find(context.Background(), current.ID).
+		Return(current, nil).
+		Times(1)
+	s.MockWebhookDeliveryRepository.EXPECT().
+		Update(context.Background(), gomock.AssignableToTypeOf(domain.WebhookDelivery{})).
+		DoAndReturn(func(_ context.Context, delivery domain.WebhookDelivery) error {
+			s.Assertions.Equal(domain.FailedWebhookDelivery, delivery.Status)
+			s.Assertions.Equal(http.StatusBadGateway, delivery.StatusCode)
+			s.Assertions.False(delivery.NextAttemptAt.Valid)
+			return nil
+		}).
+		Times(1)
+
+	delivered, err := s.newService().Deliver(context.Background())
+	s.Assertions.NoError(err)
+	s.Assertions.Equal(0, delivered)
+}
+
+func (s *WebhookServiceSuite) TestDeliverMethodNotClaimed() {
+	delivery := domain.NewWebhookDelivery(uuid.NewV4(), domain.PostDeletedWebhookEvent, "{}")
+
+	s.MockWebhookDeliveryRepository.EXPECT().
+		GetAllDue(context.Background(), gomock.Any(), s.Config.BatchSize).
+		Return([]domain.WebhookDelivery{delivery}, nil).
+		Times(1)
+	s.MockWebhookDeliveryRepository.EXPECT().
+		Claim(context.Background(), delivery, gomock.Any()).
+		Return(false, nil).
+		Times(1)
+
+	delivered, err := s.newService().Deliver(context.Background())
+	s.Assertions.NoError(err)
+	s.Assertions.Equal(0, delivered)
+
+	// Failure to read deliveries stops worker
+	repositoryResultError := errors.New("RepositoryResultError")
+	s.MockWebhookDeliveryRepository.EXPECT().
+		GetAllDue(context.Background(), gomock.Any(), s.Config.BatchSize).
+		Return(nil, repositoryResultError).
+		Times(1)
+
+	_, err = s.newService().Deliver(context.Background())
+	s.Assertions.Equal(repositoryResultError, err)
+}
@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/cache"
+)
+
+const (
+	SignInFailuresCacheKey string = "sign-in-failures-cache-key-%s"
+	SignInLockCacheKey     string = "sign-in-lock-cache-key-%s"
+)
+
+type SignInAttemptCache struct {
+	provider cache.CachePrivoder
+}
+
+func NewSignInAttemptCache(provider cache.CachePrivoder) *SignInAttemptCache {
+	return &SignInAttemptCache{provider: provider}
+}
+
+func (c *SignInAttemptCache) Fail(ctx context.Context, key string, window time.Duration) (int64, error) {
+	return c.provider.Increment(ctx, fmt.Sprintf(SignInFailuresCacheKey, key), window)
+}
+
+func (c *SignInAttemptCache) Lock(ctx context.Context, key string, until time.Time) error {
+	value := []byte(strconv.FormatInt(until.Unix(), 10))
+	return c.provider.SetWithExpiration(ctx, fmt.Sprintf(SignInLockCacheKey, key), value, time.Until(until))
+}
+
+func (c *SignInAttemptCache) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	value, err := c.provider.Get(ctx, fmt.Sprintf(SignInLockCacheKey, key))
+	if err == cache.ErrKeyNotFound {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	until, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(until, 0), nil
+}
+
+func (c *SignInAttemptCache) Reset(ctx context.Context, key string) error {
+	if err := c.provider.Delete(ctx, fmt.Sprintf(SignInFailuresCacheKey, key)); err != nil {
+		return err
+	}
+	return c.provider.Delete(ctx, fmt.Sprintf(SignInLockCacheKey, key))
+}
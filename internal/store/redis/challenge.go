@@ -0,0 +1,51 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/cache"
+)
+
+const (
+	MFAChallengeCacheKey     string = "mfa-challenge-cache-key-%s"
+	UsedMFAChallengeCacheKey string = "used-mfa-challenge-cache-key-%s"
+)
+
+type MFAChallengeCache struct {
+	provider cache.CachePrivoder
+}
+
+func NewMFAChallengeCache(provider cache.CachePrivoder) *MFAChallengeCache {
+	return &MFAChallengeCache{provider: provider}
+}
+
+func (c *MFAChallengeCache) Add(ctx context.Context, nonce string, exp time.Duration) error {
+	return c.provider.SetWithExpiration(ctx, fmt.Sprintf(MFAChallengeCacheKey, nonce), []byte{1}, exp)
+}
+
+func (c *MFAChallengeCache) Contains(ctx context.Context, nonce string) (bool, error) {
+	_, err := c.provider.Get(ctx, fmt.Sprintf(MFAChallengeCacheKey, nonce))
+	if err == cache.ErrKeyNotFound {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Use spends nonce, counter is incremented atomically, so only one of
+// concurrent verifications gets the first value
+func (c *MFAChallengeCache) Use(ctx context.Context, nonce string, exp time.Duration) (bool, error) {
+	contains, err := c.Contains(ctx, nonce)
+	if err != nil || !contains {
+		return false, err
+	}
+
+	count, err := c.provider.Increment(ctx, fmt.Sprintf(UsedMFAChallengeCacheKey, nonce), exp)
+	if err != nil || count != 1 {
+		return false, err
+	}
+
+	return true, c.provider.Delete(ctx, fmt.Sprintf(MFAChallengeCacheKey, nonce))
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/aintsashqa/go-simple-blog/internal/domain"
 	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/errors"
 	"github.com/aintsashqa/go-simple-blog/internal/serializer"
 	"github.com/aintsashqa/go-simple-blog/pkg/cache"
 	uuid "github.com/satori/go.uuid"
@@ -13,6 +14,7 @@ import (
 
 const (
 	PostCacheKey           = "post-cache-key-%s"
+	PostValidatorCacheKey  = "post-validator-cache-key-%s"
 	PostCollectionCacheKey = "post-collection-cache-key-%d-%d-%s"
 )
 
@@ -20,10 +22,55 @@ type PostCache struct {
 	repo       repository.Post
 	provider   cache.CachePrivoder
 	serializer serializer.PostSerializer
+	validators serializer.ValidatorSerializer
 }
 
-func NewPostCache(repo repository.Post, provider cache.CachePrivoder, serializer serializer.PostSerializer) *PostCache {
-	return &PostCache{repo: repo, provider: provider, serializer: serializer}
+func NewPostCache(repo repository.Post, provider cache.CachePrivoder, serializer serializer.PostSerializer, validators serializer.ValidatorSerializer) *PostCache {
+	return &PostCache{repo: repo, provider: provider, serializer: serializer, validators: validators}
+}
+
+// store saves post together with its validator, so conditional requests
+// could be answered without deserializing whole post
+func (c *PostCache) store(ctx context.Context, post domain.Post) error {
+	value, err := c.serializer.Serialize(post)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf(PostCacheKey, post.ID)
+	if err := c.provider.Set(ctx, key, value); err != nil {
+		return err
+	}
+
+	value, err = c.validators.Serialize(post.Validator())
+	if err != nil {
+		return err
+	}
+
+	key = fmt.Sprintf(PostValidatorCacheKey, post.ID)
+	return c.provider.Set(ctx, key, value)
+}
+
+func (c *PostCache) evict(ctx context.Context, id uuid.UUID) error {
+	return evictPosts(ctx, c.provider, []uuid.UUID{id})
+}
+
+// evictPosts is shared with user cache, posts of user must not be served
+// from cache after user is deleted
+func evictPosts(ctx context.Context, provider cache.CachePrivoder, ids []uuid.UUID) error {
+	for _, id := range ids {
+		key := fmt.Sprintf(PostCacheKey, id)
+		if err := provider.Delete(ctx, key); err != nil {
+			return err
+		}
+
+		key = fmt.Sprintf(PostValidatorCacheKey, id)
+		if err := provider.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 func (c *PostCache) Find(ctx context.Context, id uuid.UUID) (domain.Post, error) {
@@ -38,13 +85,29 @@ func (c *PostCache) Find(ctx context.Context, id uuid.UUID) (domain.Post, error)
 		return domain.Post{}, err
 	}
 
-	value, err := c.serializer.Serialize(post)
+	err = c.store(ctx, post)
+	return post, err
+}
+
+func (c *PostCache) FindValidator(ctx context.Context, id uuid.UUID) (domain.Validator, error) {
+	key := fmt.Sprintf(PostValidatorCacheKey, id)
+
+	if value, err := c.provider.Get(ctx, key); err == nil {
+		return c.validators.Deserialize(value)
+	}
+
+	validator, err := c.repo.FindValidator(ctx, id)
 	if err != nil {
-		return domain.Post{}, err
+		return domain.Validator{}, err
+	}
+
+	value, err := c.validators.Serialize(validator)
+	if err != nil {
+		return domain.Validator{}, err
 	}
 
 	err = c.provider.Set(ctx, key, value)
-	return post, err
+	return validator, err
 }
 
 func (c *PostCache) FindWithPrimaryAndUserID(ctx context.Context, postID uuid.UUID, userID uuid.UUID) (domain.Post, error) {
@@ -59,15 +122,14 @@ func (c *PostCache) FindWithPrimaryAndUserID(ctx context.Context, postID uuid.UU
 		return domain.Post{}, err
 	}
 
-	value, err := c.serializer.Serialize(post)
-	if err != nil {
-		return domain.Post{}, err
-	}
-
-	err = c.provider.Set(ctx, key, value)
+	err = c.store(ctx, post)
 	return post, err
 }
 
+func (c *PostCache) GetAllWithPrimariesAndUserID(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) ([]domain.Post, error) {
+	return c.repo.GetAllWithPrimariesAndUserID(ctx, ids, userID)
+}
+
 func (c *PostCache) GetAllPublished(ctx context.Context, offset int, count int) ([]domain.Post, error) {
 	return c.repo.GetAllPublished(ctx, offset, count)
 }
@@ -80,6 +142,23 @@ func (c *PostCache) GetAllWithUserID(ctx context.Context, id uuid.UUID, offset i
 	return c.repo.GetAllWithUserID(ctx, id, offset, count)
 }
 
+// GetAllFeed is not cached, feed is personal and changes with every follow
+func (c *PostCache) GetAllFeed(ctx context.Context, id uuid.UUID, cursor domain.FeedCursor, count int) ([]domain.Post, error) {
+	return c.repo.GetAllFeed(ctx, id, cursor, count)
+}
+
+func (c *PostCache) FindWithDeleted(ctx context.Context, id uuid.UUID) (domain.Post, error) {
+	return c.repo.FindWithDeleted(ctx, id)
+}
+
+func (c *PostCache) GetAllDeleted(ctx context.Context, offset, count int) ([]domain.Post, error) {
+	return c.repo.GetAllDeleted(ctx, offset, count)
+}
+
+func (c *PostCache) AllDeletedCount(ctx context.Context) (int, error) {
+	return c.repo.AllDeletedCount(ctx)
+}
+
 func (c *PostCache) AllPublishedCount(ctx context.Context) (int, error) {
 	return c.repo.AllPublishedCount(ctx)
 }
@@ -92,34 +171,32 @@ func (c *PostCache) TotalCountWithUserID(ctx context.Context, id uuid.UUID) (int
 	return c.repo.TotalCountWithUserID(ctx, id)
 }
 
+func (c *PostCache) GetAllIDsWithUserID(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	return c.repo.GetAllIDsWithUserID(ctx, id)
+}
+
 func (c *PostCache) Create(ctx context.Context, post domain.Post) error {
 	err := c.repo.Create(ctx, post)
 	if err != nil {
 		return err
 	}
 
-	value, err := c.serializer.Serialize(post)
-	if err != nil {
-		return err
-	}
-
-	key := fmt.Sprintf(PostCacheKey, post.ID)
-	return c.provider.Set(ctx, key, value)
+	return c.store(ctx, post)
 }
 
 func (c *PostCache) Update(ctx context.Context, post domain.Post) error {
 	err := c.repo.Update(ctx, post)
-	if err != nil {
-		return err
+	if err == errors.ErrPostVersionConflict {
+		// Cached post is outdated
+		if err := c.evict(ctx, post.ID); err != nil {
+			return err
+		}
 	}
-
-	value, err := c.serializer.Serialize(post)
 	if err != nil {
 		return err
 	}
 
-	key := fmt.Sprintf(PostCacheKey, post.ID)
-	return c.provider.Set(ctx, key, value)
+	return c.store(ctx, post)
 }
 
 func (c *PostCache) Publish(ctx context.Context, post domain.Post) error {
@@ -128,21 +205,51 @@ func (c *PostCache) Publish(ctx context.Context, post domain.Post) error {
 		return err
 	}
 
-	value, err := c.serializer.Serialize(post)
-	if err != nil {
-		return err
+	return c.store(ctx, post)
+}
+
+func (c *PostCache) Unpublish(ctx context.Context, post domain.Post) (bool, error) {
+	if err := c.evict(ctx, post.ID); err != nil {
+		return false, err
 	}
 
-	key := fmt.Sprintf(PostCacheKey, post.ID)
-	return c.provider.Set(ctx, key, value)
+	return c.repo.Unpublish(ctx, post)
 }
 
 func (c *PostCache) SoftDelete(ctx context.Context, post domain.Post) error {
-	key := fmt.Sprintf(PostCacheKey, post.ID)
-
-	if err := c.provider.Delete(ctx, key); err != nil {
+	if err := c.evict(ctx, post.ID); err != nil {
 		return err
 	}
 
 	return c.repo.SoftDelete(ctx, post)
 }
+
+func (c *PostCache) SaveMany(ctx context.Context, posts []domain.Post) error {
+	err := c.repo.SaveMany(ctx, posts)
+	if err != nil && err != errors.ErrPostVersionConflict {
+		return err
+	}
+
+	// Cached posts are outdated on conflict as well
+	for _, post := range posts {
+		if err := c.evict(ctx, post.ID); err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+// TransferAll evicts transferred posts, cached posts still hold previous author
+func (c *PostCache) TransferAll(ctx context.Context, fromUserID uuid.UUID, toUserID uuid.UUID) error {
+	ids, err := c.repo.GetAllIDsWithUserID(ctx, fromUserID)
+	if err != nil {
+		return err
+	}
+
+	if err := c.repo.TransferAll(ctx, fromUserID, toUserID); err != nil {
+		return err
+	}
+
+	return evictPosts(ctx, c.provider, ids)
+}
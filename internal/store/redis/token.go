@@ -0,0 +1,66 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/cache"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	TokenDenylistCacheKey     string = "token-denylist-cache-key-%s"
+	UserTokenDenylistCacheKey string = "user-token-denylist-cache-key-%s"
+)
+
+type TokenDenylistCache struct {
+	provider cache.CachePrivoder
+}
+
+func NewTokenDenylistCache(provider cache.CachePrivoder) *TokenDenylistCache {
+	return &TokenDenylistCache{provider: provider}
+}
+
+func (c *TokenDenylistCache) Add(ctx context.Context, id string, exp time.Duration) error {
+	key := fmt.Sprintf(TokenDenylistCacheKey, id)
+	return c.provider.SetWithExpiration(ctx, key, []byte{1}, exp)
+}
+
+func (c *TokenDenylistCache) Contains(ctx context.Context, id string) (bool, error) {
+	key := fmt.Sprintf(TokenDenylistCacheKey, id)
+
+	_, err := c.provider.Get(ctx, key)
+	if err == cache.ErrKeyNotFound {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// RevokeBefore stores revocation time of user tokens, it must be kept
+// at least for lifetime of access token
+func (c *TokenDenylistCache) RevokeBefore(ctx context.Context, userID uuid.UUID, before time.Time, exp time.Duration) error {
+	key := fmt.Sprintf(UserTokenDenylistCacheKey, userID)
+	return c.provider.SetWithExpiration(ctx, key, []byte(strconv.FormatInt(before.Unix(), 10)), exp)
+}
+
+func (c *TokenDenylistCache) RevokedBefore(ctx context.Context, userID uuid.UUID) (time.Time, error) {
+	key := fmt.Sprintf(UserTokenDenylistCacheKey, userID)
+
+	value, err := c.provider.Get(ctx, key)
+	if err == cache.ErrKeyNotFound {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	before, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(before, 0), nil
+}
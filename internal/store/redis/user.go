@@ -3,70 +3,136 @@ package redis
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/aintsashqa/go-simple-blog/internal/domain"
 	"github.com/aintsashqa/go-simple-blog/internal/repository"
+	"github.com/aintsashqa/go-simple-blog/internal/repository/errors"
 	"github.com/aintsashqa/go-simple-blog/internal/serializer"
 	"github.com/aintsashqa/go-simple-blog/pkg/cache"
 	uuid "github.com/satori/go.uuid"
 )
 
 const (
-	UserCacheKey string = "user-cache-key-%s"
+	UserCacheKey          string = "user-cache-key-%s"
+	UserSelfCacheKey      string = "user-self-cache-key-%s"
+	UserValidatorCacheKey string = "user-validator-cache-key-%s"
+	UserUsernameCacheKey  string = "user-username-cache-key-%s"
 )
 
 type UserCache struct {
 	repo       repository.User
+	posts      repository.Post
 	provider   cache.CachePrivoder
 	serializer serializer.UserSerializer
+	validators serializer.ValidatorSerializer
 }
 
-func NewUserCache(repo repository.User, provider cache.CachePrivoder, serializer serializer.UserSerializer) *UserCache {
-	return &UserCache{repo: repo, provider: provider, serializer: serializer}
+func NewUserCache(repo repository.User, posts repository.Post, provider cache.CachePrivoder, serializer serializer.UserSerializer, validators serializer.ValidatorSerializer) *UserCache {
+	return &UserCache{repo: repo, posts: posts, provider: provider, serializer: serializer, validators: validators}
 }
 
-func (c *UserCache) Create(ctx context.Context, user domain.User) error {
-	err := c.repo.Create(ctx, user)
+// store saves user together with its validator, so conditional requests
+// could be answered without deserializing whole user
+func (c *UserCache) store(ctx context.Context, user domain.User) error {
+	value, err := c.serializer.Serialize(user)
 	if err != nil {
 		return err
 	}
 
-	value, err := c.serializer.Serialize(user)
+	key := fmt.Sprintf(UserCacheKey, user.ID)
+	if err := c.provider.Set(ctx, key, value); err != nil {
+		return err
+	}
+
+	value, err = c.validators.Serialize(user.Validator())
 	if err != nil {
 		return err
 	}
 
-	key := fmt.Sprintf(UserCacheKey, user.ID)
+	key = fmt.Sprintf(UserValidatorCacheKey, user.ID)
 	return c.provider.Set(ctx, key, value)
 }
 
+func (c *UserCache) evict(ctx context.Context, id uuid.UUID) error {
+	key := fmt.Sprintf(UserCacheKey, id)
+	if err := c.provider.Delete(ctx, key); err != nil {
+		return err
+	}
+
+	key = fmt.Sprintf(UserSelfCacheKey, id)
+	if err := c.provider.Delete(ctx, key); err != nil {
+		return err
+	}
+
+	key = fmt.Sprintf(UserValidatorCacheKey, id)
+	return c.provider.Delete(ctx, key)
+}
+
+func (c *UserCache) Create(ctx context.Context, user domain.User) error {
+	err := c.repo.Create(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return c.store(ctx, user)
+}
+
 func (c *UserCache) GetByEmail(ctx context.Context, email string) (domain.User, error) {
 	return c.repo.GetByEmail(ctx, email)
 }
 
-func (c *UserCache) Find(ctx context.Context, id uuid.UUID) (domain.User, error) {
-	key := fmt.Sprintf(UserCacheKey, id)
+// GetByUsername caches only username to id mapping, so renamed user
+// does not require evicting every previous username
+func (c *UserCache) GetByUsername(ctx context.Context, username string) (domain.User, error) {
+	key := fmt.Sprintf(UserUsernameCacheKey, strings.ToLower(username))
 
 	if value, err := c.provider.Get(ctx, key); err == nil {
-		return c.serializer.Deserialize(value)
+		if id, err := uuid.FromString(string(value)); err == nil {
+			user, err := c.Find(ctx, id)
+			if err == nil && strings.EqualFold(user.Username, username) {
+				return user, nil
+			}
+		}
+
+		// Mapping is outdated, user was renamed or deleted
+		if err := c.provider.Delete(ctx, key); err != nil {
+			return domain.User{}, err
+		}
 	}
 
-	user, err := c.repo.Find(ctx, id)
+	user, err := c.repo.GetByUsername(ctx, username)
 	if err != nil {
 		return domain.User{}, err
 	}
 
-	value, err := c.serializer.Serialize(user)
+	if err := c.provider.Set(ctx, key, []byte(user.ID.String())); err != nil {
+		return domain.User{}, err
+	}
+
+	err = c.store(ctx, user)
+	return user, err
+}
+
+func (c *UserCache) Find(ctx context.Context, id uuid.UUID) (domain.User, error) {
+	key := fmt.Sprintf(UserCacheKey, id)
+
+	if value, err := c.provider.Get(ctx, key); err == nil {
+		return c.serializer.Deserialize(value)
+	}
+
+	user, err := c.repo.Find(ctx, id)
 	if err != nil {
 		return domain.User{}, err
 	}
 
-	err = c.provider.Set(ctx, key, value)
+	err = c.store(ctx, user)
 	return user, err
 }
 
+// Self is cached separately, because public user does not contain private fields
 func (c *UserCache) Self(ctx context.Context, id uuid.UUID) (domain.User, error) {
-	key := fmt.Sprintf(UserCacheKey, id)
+	key := fmt.Sprintf(UserSelfCacheKey, id)
 
 	if value, err := c.provider.Get(ctx, key); err == nil {
 		return c.serializer.Deserialize(value)
@@ -86,17 +152,146 @@ func (c *UserCache) Self(ctx context.Context, id uuid.UUID) (domain.User, error)
 	return user, err
 }
 
+func (c *UserCache) FindValidator(ctx context.Context, id uuid.UUID) (domain.Validator, error) {
+	key := fmt.Sprintf(UserValidatorCacheKey, id)
+
+	if value, err := c.provider.Get(ctx, key); err == nil {
+		return c.validators.Deserialize(value)
+	}
+
+	validator, err := c.repo.FindValidator(ctx, id)
+	if err != nil {
+		return domain.Validator{}, err
+	}
+
+	value, err := c.validators.Serialize(validator)
+	if err != nil {
+		return domain.Validator{}, err
+	}
+
+	err = c.provider.Set(ctx, key, value)
+	return validator, err
+}
+
 func (c *UserCache) Update(ctx context.Context, user domain.User) error {
 	err := c.repo.Update(ctx, user)
+	if err == nil || err == errors.ErrUserVersionConflict {
+		// Cached user is outdated, self user is cached separately
+		if err := c.evict(ctx, user.ID); err != nil {
+			return err
+		}
+	}
 	if err != nil {
 		return err
 	}
 
-	value, err := c.serializer.Serialize(user)
+	return c.store(ctx, user)
+}
+
+func (c *UserCache) VerifyEmail(ctx context.Context, user domain.User) error {
+	if err := c.repo.VerifyEmail(ctx, user); err != nil {
+		return err
+	}
+
+	return c.evict(ctx, user.ID)
+}
+
+func (c *UserCache) UpdateRoles(ctx context.Context, user domain.User) error {
+	if err := c.repo.UpdateRoles(ctx, user); err != nil {
+		return err
+	}
+
+	return c.evict(ctx, user.ID)
+}
+
+func (c *UserCache) UpdateSuspension(ctx context.Context, user domain.User) error {
+	if err := c.repo.UpdateSuspension(ctx, user); err != nil {
+		return err
+	}
+
+	return c.evict(ctx, user.ID)
+}
+
+func (c *UserCache) Search(ctx context.Context, value string, includeDeleted bool, offset, count int) ([]domain.User, error) {
+	return c.repo.Search(ctx, value, includeDeleted, offset, count)
+}
+
+func (c *UserCache) SearchCount(ctx context.Context, value string, includeDeleted bool) (int, error) {
+	return c.repo.SearchCount(ctx, value, includeDeleted)
+}
+
+func (c *UserCache) CountWithRole(ctx context.Context, role domain.Role) (int, error) {
+	return c.repo.CountWithRole(ctx, role)
+}
+
+func (c *UserCache) UpdatePassword(ctx context.Context, user domain.User) error {
+	if err := c.repo.UpdatePassword(ctx, user); err != nil {
+		return err
+	}
+
+	return c.evict(ctx, user.ID)
+}
+
+func (c *UserCache) RehashPassword(ctx context.Context, user domain.User, previous string) error {
+	if err := c.repo.RehashPassword(ctx, user, previous); err != nil {
+		return err
+	}
+
+	return c.evict(ctx, user.ID)
+}
+
+func (c *UserCache) UpdateEmail(ctx context.Context, user domain.User) error {
+	if err := c.repo.UpdateEmail(ctx, user); err != nil {
+		return err
+	}
+
+	return c.evict(ctx, user.ID)
+}
+
+func (c *UserCache) GetDeletedByEmail(ctx context.Context, email string) (domain.User, error) {
+	return c.repo.GetDeletedByEmail(ctx, email)
+}
+
+func (c *UserCache) SoftDelete(ctx context.Context, user domain.User, deletion domain.AccountDeletion) error {
+	if err := c.repo.SoftDelete(ctx, user, deletion); err != nil {
+		return err
+	}
+
+	// Posts of deleted user are hidden
+	ids, err := c.posts.GetAllIDsWithUserID(ctx, user.ID)
 	if err != nil {
 		return err
 	}
 
-	key := fmt.Sprintf(UserCacheKey, user.ID)
-	return c.provider.Set(ctx, key, value)
+	if err := evictPosts(ctx, c.provider, ids); err != nil {
+		return err
+	}
+
+	return c.evict(ctx, user.ID)
+}
+
+func (c *UserCache) Restore(ctx context.Context, user domain.User) error {
+	if err := c.repo.Restore(ctx, user); err != nil {
+		return err
+	}
+
+	return c.evict(ctx, user.ID)
+}
+
+func (c *UserCache) Purge(ctx context.Context, id uuid.UUID) error {
+	// Posts are removed together with user, so ids are selected first
+	ids, err := c.posts.GetAllIDsWithUserID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := c.repo.Purge(ctx, id); err != nil {
+		return err
+	}
+
+	if err := evictPosts(ctx, c.provider, ids); err != nil {
+		return err
+	}
+
+	return c.evict(ctx, id)
 }
@@ -8,14 +8,50 @@ import (
 )
 
 type CacheStore struct {
-	User repository.User
-	Post repository.Post
+	User                repository.User
+	Post                repository.Post
+	RefreshToken        repository.RefreshToken
+	Session             repository.Session
+	PasswordReset       repository.PasswordReset
+	SecurityEvent       repository.SecurityEvent
+	AccountDeletion     repository.AccountDeletion
+	Follow              repository.Follow
+	AuditLog            repository.AuditLog
+	PersonalAccessToken repository.PersonalAccessToken
+	UserIdentity        repository.UserIdentity
+	TOTPFactor          repository.TOTPFactor
+	RecoveryCode        repository.RecoveryCode
+	MFAPolicy           repository.MFAPolicy
+	Notification        repository.Notification
+	Webhook             repository.Webhook
+	WebhookDelivery     repository.WebhookDelivery
+	TokenDenylist       repository.TokenDenylist
+	SignInAttempt       repository.SignInAttempt
+	MFAChallenge        repository.MFAChallenge
 }
 
 func NewCacheStore(repos *repository.Repository, cache cache.CachePrivoder, serializer *serializer.Serializer) *CacheStore {
 	return &CacheStore{
-		User: redis.NewUserCache(repos.User, cache, serializer.User),
-		Post: redis.NewPostCache(repos.Post, cache, serializer.Post),
+		User:                redis.NewUserCache(repos.User, repos.Post, cache, serializer.User, serializer.Validator),
+		Post:                redis.NewPostCache(repos.Post, cache, serializer.Post, serializer.Validator),
+		RefreshToken:        repos.RefreshToken,
+		Session:             repos.Session,
+		PasswordReset:       repos.PasswordReset,
+		SecurityEvent:       repos.SecurityEvent,
+		AccountDeletion:     repos.AccountDeletion,
+		Follow:              repos.Follow,
+		AuditLog:            repos.AuditLog,
+		PersonalAccessToken: repos.PersonalAccessToken,
+		UserIdentity:        repos.UserIdentity,
+		TOTPFactor:          repos.TOTPFactor,
+		RecoveryCode:        repos.RecoveryCode,
+		MFAPolicy:           repos.MFAPolicy,
+		Notification:        repos.Notification,
+		Webhook:             repos.Webhook,
+		WebhookDelivery:     repos.WebhookDelivery,
+		TokenDenylist:       redis.NewTokenDenylistCache(cache),
+		SignInAttempt:       redis.NewSignInAttemptCache(cache),
+		MFAChallenge:        redis.NewMFAChallengeCache(cache),
 	}
 }
 
@@ -26,3 +62,75 @@ func (s *CacheStore) UserProvider() repository.User {
 func (s *CacheStore) PostProvider() repository.Post {
 	return s.Post
 }
+
+func (s *CacheStore) RefreshTokenProvider() repository.RefreshToken {
+	return s.RefreshToken
+}
+
+func (s *CacheStore) SessionProvider() repository.Session {
+	return s.Session
+}
+
+func (s *CacheStore) PasswordResetProvider() repository.PasswordReset {
+	return s.PasswordReset
+}
+
+func (s *CacheStore) SecurityEventProvider() repository.SecurityEvent {
+	return s.SecurityEvent
+}
+
+func (s *CacheStore) AccountDeletionProvider() repository.AccountDeletion {
+	return s.AccountDeletion
+}
+
+func (s *CacheStore) FollowProvider() repository.Follow {
+	return s.Follow
+}
+
+func (s *CacheStore) AuditLogProvider() repository.AuditLog {
+	return s.AuditLog
+}
+
+func (s *CacheStore) PersonalAccessTokenProvider() repository.PersonalAccessToken {
+	return s.PersonalAccessToken
+}
+
+func (s *CacheStore) UserIdentityProvider() repository.UserIdentity {
+	return s.UserIdentity
+}
+
+func (s *CacheStore) TOTPFactorProvider() repository.TOTPFactor {
+	return s.TOTPFactor
+}
+
+func (s *CacheStore) RecoveryCodeProvider() repository.RecoveryCode {
+	return s.RecoveryCode
+}
+
+func (s *CacheStore) MFAPolicyProvider() repository.MFAPolicy {
+	return s.MFAPolicy
+}
+
+func (s *CacheStore) NotificationProvider() repository.Notification {
+	return s.Notification
+}
+
+func (s *CacheStore) WebhookProvider() repository.Webhook {
+	return s.Webhook
+}
+
+func (s *CacheStore) WebhookDeliveryProvider() repository.WebhookDelivery {
+	return s.WebhookDelivery
+}
+
+func (s *CacheStore) TokenDenylistProvider() repository.TokenDenylist {
+	return s.TokenDenylist
+}
+
+func (s *CacheStore) SignInAttemptProvider() repository.SignInAttempt {
+	return s.SignInAttempt
+}
+
+func (s *CacheStore) MFAChallengeProvider() repository.MFAChallenge {
+	return s.MFAChallenge
+}
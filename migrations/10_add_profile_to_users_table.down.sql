@@ -0,0 +1,6 @@
+alter table `users` drop column `social_links`;
+alter table `users` drop column `website`;
+alter table `users` drop column `location`;
+alter table `users` drop column `avatar_url`;
+alter table `users` drop column `bio`;
+alter table `users` drop column `display_name`;
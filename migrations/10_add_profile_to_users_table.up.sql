@@ -0,0 +1,6 @@
+alter table `users` add column `display_name` varchar(64) not null default '';
+alter table `users` add column `bio` varchar(2000) not null default '';
+alter table `users` add column `avatar_url` varchar(255) not null default '';
+alter table `users` add column `location` varchar(128) not null default '';
+alter table `users` add column `website` varchar(255) not null default '';
+alter table `users` add column `social_links` varchar(4096) not null default '[]';
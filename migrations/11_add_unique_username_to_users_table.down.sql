@@ -0,0 +1 @@
+alter table `users` drop index `users_username_unique`;
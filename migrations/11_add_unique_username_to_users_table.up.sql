@@ -0,0 +1,10 @@
+update `users` as `u`
+join (
+    select `id` from (
+        select `duplicate`.`id` from `users` as `duplicate`
+        join `users` as `original` on `original`.`username` = `duplicate`.`username` and `original`.`id` < `duplicate`.`id`
+    ) as `duplicates`
+) as `d` on `d`.`id` = `u`.`id`
+set `u`.`username` = concat(left(`u`.`username`, 17), '_', left(replace(`u`.`id`, '-', ''), 12));
+
+alter table `users` add unique index `users_username_unique` (`username`);
@@ -0,0 +1,3 @@
+alter table `posts` drop index `posts_user_id_published_at_index`;
+
+drop table if exists `follows`;
@@ -0,0 +1,9 @@
+create table if not exists `follows` (
+    `follower_id` varchar(36) not null references `users` (`id`) on delete cascade,
+    `followee_id` varchar(36) not null references `users` (`id`) on delete cascade,
+    `created_at` timestamp null default null,
+    primary key (`follower_id`, `followee_id`),
+    index `follows_followee_id_created_at_index` (`followee_id`, `created_at`)
+);
+
+alter table `posts` add index `posts_user_id_published_at_index` (`user_id`, `published_at`);
@@ -0,0 +1,3 @@
+drop table if exists `audit_logs`;
+
+alter table `users` drop column `suspended_at`;
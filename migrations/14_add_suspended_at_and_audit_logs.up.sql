@@ -0,0 +1,12 @@
+alter table `users` add column `suspended_at` timestamp null default null;
+
+create table if not exists `audit_logs` (
+    `id` varchar(36) not null primary key,
+    `actor_id` varchar(36) not null,
+    `action` varchar(64) not null,
+    `target_type` varchar(32) not null,
+    `target_id` varchar(36) not null,
+    `created_at` timestamp null default null,
+    index `audit_logs_target_index` (`target_type`, `target_id`),
+    index `audit_logs_created_at_index` (`created_at`)
+);
@@ -0,0 +1,11 @@
+create table if not exists `personal_access_tokens` (
+    `id` varchar(36) not null primary key,
+    `user_id` varchar(36) not null references `users` (`id`) on delete cascade,
+    `name` varchar(100) not null,
+    `prefix` varchar(16) not null,
+    `token_hash` varchar(64) not null unique,
+    `scopes` varchar(255) not null default '',
+    `created_at` timestamp null default null,
+    `last_used_at` timestamp null default null,
+    index `personal_access_tokens_user_id_index` (`user_id`)
+);
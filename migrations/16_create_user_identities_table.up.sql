@@ -0,0 +1,9 @@
+create table if not exists `user_identities` (
+    `provider` varchar(64) not null,
+    `subject` varchar(255) not null,
+    `user_id` varchar(36) not null references `users` (`id`) on delete cascade,
+    `email` varchar(255) not null default '',
+    `created_at` timestamp null default null,
+    primary key (`provider`, `subject`),
+    index `user_identities_user_id_index` (`user_id`)
+);
@@ -0,0 +1,3 @@
+drop table if exists `mfa_required_roles`;
+drop table if exists `recovery_codes`;
+drop table if exists `totp_factors`;
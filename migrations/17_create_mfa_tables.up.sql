@@ -0,0 +1,21 @@
+create table if not exists `totp_factors` (
+    `user_id` varchar(36) not null primary key references `users` (`id`) on delete cascade,
+    `secret` varchar(64) not null,
+    `last_counter` bigint not null default 0,
+    `created_at` timestamp null default null,
+    `confirmed_at` timestamp null default null
+);
+
+create table if not exists `recovery_codes` (
+    `id` varchar(36) not null primary key,
+    `user_id` varchar(36) not null references `users` (`id`) on delete cascade,
+    `code_hash` varchar(255) not null,
+    `created_at` timestamp null default null,
+    `used_at` timestamp null default null,
+    index `recovery_codes_user_id_index` (`user_id`)
+);
+
+create table if not exists `mfa_required_roles` (
+    `role` varchar(16) not null primary key,
+    `created_at` timestamp null default null
+);
@@ -0,0 +1,10 @@
+create table if not exists `sessions` (
+    `id` varchar(36) not null primary key,
+    `user_id` varchar(36) not null references `users` (`id`) on delete cascade,
+    `user_agent` varchar(255) not null default '',
+    `ip` varchar(45) not null default '',
+    `created_at` timestamp null default null,
+    `last_seen_at` timestamp null default null,
+    `revoked_at` timestamp null default null,
+    index `sessions_user_id_index` (`user_id`)
+);
@@ -0,0 +1,12 @@
+create table if not exists `notifications` (
+    `id` varchar(36) not null primary key,
+    `user_id` varchar(36) not null references `users` (`id`) on delete cascade,
+    `type` varchar(64) not null,
+    `actor_id` varchar(36) null default null references `users` (`id`) on delete cascade,
+    `post_id` varchar(36) null default null references `posts` (`id`) on delete cascade,
+    `event` varchar(64) not null default '',
+    `created_at` timestamp null default null,
+    `read_at` timestamp null default null,
+    index `notifications_user_id_created_at_index` (`user_id`, `created_at`),
+    index `notifications_user_id_read_at_index` (`user_id`, `read_at`)
+);
@@ -0,0 +1,27 @@
+create table if not exists `webhooks` (
+    `id` varchar(36) not null primary key,
+    `user_id` varchar(36) not null references `users` (`id`) on delete cascade,
+    `url` varchar(255) not null,
+    `secret` varchar(64) not null,
+    `events` varchar(255) not null default '',
+    `global` boolean not null default false,
+    `created_at` timestamp null default null,
+    index `webhooks_user_id_index` (`user_id`),
+    index `webhooks_global_index` (`global`)
+);
+
+create table if not exists `webhook_deliveries` (
+    `id` varchar(36) not null primary key,
+    `webhook_id` varchar(36) not null references `webhooks` (`id`) on delete cascade,
+    `event` varchar(64) not null,
+    `payload` mediumtext not null,
+    `status` varchar(16) not null default 'pending',
+    `attempts` int not null default 0,
+    `status_code` int not null default 0,
+    `error` varchar(255) not null default '',
+    `next_attempt_at` timestamp null default null,
+    `created_at` timestamp null default null,
+    `updated_at` timestamp null default null,
+    index `webhook_deliveries_webhook_id_created_at_index` (`webhook_id`, `created_at`),
+    index `webhook_deliveries_status_next_attempt_at_index` (`status`, `next_attempt_at`)
+);
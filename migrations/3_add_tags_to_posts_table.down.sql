@@ -0,0 +1 @@
+alter table `posts` drop column `tags`;
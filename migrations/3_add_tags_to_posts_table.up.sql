@@ -0,0 +1 @@
+alter table `posts` add column `tags` varchar(1024) not null default '' after `content`;
@@ -0,0 +1,2 @@
+alter table `users` drop column `version`;
+alter table `posts` drop column `version`;
@@ -0,0 +1,2 @@
+alter table `users` add column `version` int unsigned not null default 1;
+alter table `posts` add column `version` int unsigned not null default 1;
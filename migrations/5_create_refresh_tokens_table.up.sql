@@ -0,0 +1,10 @@
+create table if not exists `refresh_tokens` (
+    `id` varchar(36) not null primary key,
+    `user_id` varchar(36) not null references `users` (`id`) on delete cascade,
+    `family_id` varchar(36) not null,
+    `token_hash` varchar(64) not null unique,
+    `created_at` timestamp null default null,
+    `expires_at` timestamp null default null,
+    `revoked_at` timestamp null default null,
+    index `refresh_tokens_family_id_index` (`family_id`)
+);
@@ -0,0 +1 @@
+alter table `users` add column `email_verified_at` timestamp null default null after `encrypted_password`;
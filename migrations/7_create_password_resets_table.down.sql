@@ -0,0 +1 @@
+drop table if exists `password_resets`;
@@ -0,0 +1,8 @@
+create table if not exists `password_resets` (
+    `id` varchar(36) not null primary key,
+    `user_id` varchar(36) not null references `users` (`id`) on delete cascade,
+    `token_hash` varchar(64) not null unique,
+    `created_at` timestamp null default null,
+    `expires_at` timestamp null default null,
+    `used_at` timestamp null default null
+);
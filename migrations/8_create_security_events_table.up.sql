@@ -0,0 +1,7 @@
+create table if not exists `security_events` (
+    `id` varchar(36) not null primary key,
+    `user_id` varchar(36) not null references `users` (`id`) on delete cascade,
+    `type` varchar(64) not null,
+    `created_at` timestamp null default null,
+    index `security_events_user_id_created_at_index` (`user_id`, `created_at`)
+);
@@ -0,0 +1,2 @@
+delete from `users` where `id` = '00000000-0000-4000-8000-000000000000';
+drop table if exists `account_deletions`;
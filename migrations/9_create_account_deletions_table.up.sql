@@ -0,0 +1,11 @@
+create table if not exists `account_deletions` (
+    `user_id` varchar(36) not null primary key references `users` (`id`) on delete cascade,
+    `posts_action` varchar(16) not null,
+    `transfer_to` varchar(36) null default null,
+    `requested_at` timestamp null default null,
+    `purge_at` timestamp null default null,
+    index `account_deletions_purge_at_index` (`purge_at`)
+);
+
+insert into `users` (`id`, `email`, `username`, `encrypted_password`, `created_at`, `updated_at`, `email_verified_at`)
+values ('00000000-0000-4000-8000-000000000000', 'ghost@localhost', 'ghost', '', now(), now(), null);
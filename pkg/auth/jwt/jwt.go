@@ -9,37 +9,124 @@ import (
 	uuid "github.com/satori/go.uuid"
 )
 
+var (
+	ErrUnknownKey      error = errors.New("Token is signed with unknown key")
+	ErrInvalidIssuer   error = errors.New("Token is issued by other issuer")
+	ErrInvalidAudience error = errors.New("Token is issued for other audience")
+)
+
+// tokenClaims extends standard claims with session and roles of user
+type tokenClaims struct {
+	jwt.StandardClaims
+	SessionID string   `json:"sid,omitempty"`
+	Roles     []string `json:"roles,omitempty"`
+}
+
+// Config holds key signing new tokens and keys still verifying tokens
+// signed before rotation. Issuer and audience are checked when not empty
+type Config struct {
+	Issuer           string
+	Audience         string
+	SigningKey       Key
+	VerificationKeys []Key
+}
+
 type JWTAuthorizationProvider struct {
-	signingKey string
+	issuer     string
+	audience   string
+	signingKey Key
+	keys       []Key
 }
 
-func NewJWTAuthorizationProvider(signingKey string) *JWTAuthorizationProvider {
-	return &JWTAuthorizationProvider{signingKey: signingKey}
+func NewJWTAuthorizationProvider(config Config) (*JWTAuthorizationProvider, error) {
+	if config.SigningKey.signing == nil {
+		return nil, ErrVerificationOnly
+	}
+
+	return &JWTAuthorizationProvider{
+		issuer:     config.Issuer,
+		audience:   config.Audience,
+		signingKey: config.SigningKey,
+		keys:       append([]Key{config.SigningKey}, config.VerificationKeys...),
+	}, nil
 }
 
 func (p *JWTAuthorizationProvider) NewToken(params auth.TokenParams) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{
-		Subject:   params.UserID.String(),
-		ExpiresAt: time.Now().Add(params.ExpiresAt).Unix(),
+	now := time.Now()
+	token := jwt.NewWithClaims(p.signingKey.method, tokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        params.TokenID,
+			Subject:   params.UserID.String(),
+			Issuer:    p.issuer,
+			Audience:  p.audience,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(params.ExpiresAt).Unix(),
+		},
+		SessionID: params.SessionID,
+		Roles:     params.Roles,
 	})
-	return token.SignedString([]byte(p.signingKey))
+
+	if len(p.signingKey.ID) != 0 {
+		token.Header["kid"] = p.signingKey.ID
+	}
+
+	return token.SignedString(p.signingKey.signing)
 }
 
-func (p *JWTAuthorizationProvider) Parse(value string) (uuid.UUID, error) {
-	token, err := jwt.Parse(value, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-		return []byte(p.signingKey), nil
-	})
+func (p *JWTAuthorizationProvider) Parse(value string) (auth.Claims, error) {
+	token, err := jwt.ParseWithClaims(value, &tokenClaims{}, p.key)
 	if err != nil {
-		return uuid.Nil, err
+		return auth.Claims{}, err
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
+	claims, ok := token.Claims.(*tokenClaims)
 	if !ok {
-		return uuid.Nil, errors.New("error get user claims from token")
+		return auth.Claims{}, errors.New("error get user claims from token")
+	}
+
+	if len(p.issuer) != 0 && !claims.VerifyIssuer(p.issuer, true) {
+		return auth.Claims{}, ErrInvalidIssuer
+	}
+
+	if len(p.audience) != 0 && !claims.VerifyAudience(p.audience, true) {
+		return auth.Claims{}, ErrInvalidAudience
 	}
 
-	return uuid.FromStringOrNil(claims["sub"].(string)), nil
+	return auth.Claims{
+		UserID:    uuid.FromStringOrNil(claims.Subject),
+		TokenID:   claims.Id,
+		SessionID: claims.SessionID,
+		Roles:     claims.Roles,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// key finds verification key by `kid` header, algorithm of token must match
+// algorithm of key, so public key could not be used as HMAC secret
+func (p *JWTAuthorizationProvider) key(token *jwt.Token) (interface{}, error) {
+	id, _ := token.Header["kid"].(string)
+	for _, key := range p.keys {
+		if key.ID != id {
+			continue
+		}
+
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.verifying, nil
+	}
+
+	return nil, ErrUnknownKey
+}
+
+// PublicKeys returns asymmetric keys only, symmetric keys must stay secret
+func (p *JWTAuthorizationProvider) PublicKeys() []auth.PublicKey {
+	keys := make([]auth.PublicKey, 0, len(p.keys))
+	for _, key := range p.keys {
+		if publicKey := key.PublicKey(); publicKey != nil {
+			keys = append(keys, auth.PublicKey{ID: key.ID, Algorithm: key.Algorithm(), Key: publicKey})
+		}
+	}
+	return keys
 }
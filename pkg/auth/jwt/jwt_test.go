@@ -0,0 +1,126 @@
+package jwt_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/auth"
+	"github.com/aintsashqa/go-simple-blog/pkg/auth/jwt"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func encodePEM(blockType string, bytes []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: bytes})
+}
+
+func newEd25519Keys(t *testing.T, id string) (jwt.Key, jwt.Key) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	privateBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	signingKey, err := jwt.ParseKey(id, encodePEM("PRIVATE KEY", privateBytes))
+	require.NoError(t, err)
+
+	publicBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	verificationKey, err := jwt.ParseKey(id, encodePEM("PUBLIC KEY", publicBytes))
+	require.NoError(t, err)
+
+	return signingKey, verificationKey
+}
+
+func newRSAKey(t *testing.T, id string) jwt.Key {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	key, err := jwt.ParseKey(id, encodePEM("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(privateKey)))
+	require.NoError(t, err)
+	return key
+}
+
+func newProvider(t *testing.T, config jwt.Config) *jwt.JWTAuthorizationProvider {
+	provider, err := jwt.NewJWTAuthorizationProvider(config)
+	require.NoError(t, err)
+	return provider
+}
+
+func TestNewTokenAndParse(t *testing.T) {
+	ed25519Key, _ := newEd25519Keys(t, "ed25519-key")
+	params := auth.TokenParams{UserID: uuid.NewV4(), TokenID: "token-id", SessionID: "session-id", Roles: []string{"author"}, ExpiresAt: time.Minute}
+
+	for name, key := range map[string]jwt.Key{
+		"HS256": jwt.NewHMACKey("", "signing-key"),
+		"RS256": newRSAKey(t, "rsa-key"),
+		"EdDSA": ed25519Key,
+	} {
+		t.Run(name, func(t *testing.T) {
+			provider := newProvider(t, jwt.Config{Issuer: "issuer", Audience: "audience", SigningKey: key})
+
+			token, err := provider.NewToken(params)
+			require.NoError(t, err)
+
+			claims, err := provider.Parse(token)
+			require.NoError(t, err)
+			require.Equal(t, params.UserID, claims.UserID)
+			require.Equal(t, params.TokenID, claims.TokenID)
+			require.Equal(t, params.SessionID, claims.SessionID)
+			require.Equal(t, params.Roles, claims.Roles)
+
+			_, err = newProvider(t, jwt.Config{Issuer: "issuer", Audience: "other", SigningKey: key}).Parse(token)
+			require.Equal(t, jwt.ErrInvalidAudience, err)
+
+			_, err = newProvider(t, jwt.Config{Issuer: "other", Audience: "audience", SigningKey: key}).Parse(token)
+			require.Equal(t, jwt.ErrInvalidIssuer, err)
+		})
+	}
+}
+
+func TestRotation(t *testing.T) {
+	previousKey, previousPublicKey := newEd25519Keys(t, "previous-key")
+	currentKey, currentPublicKey := newEd25519Keys(t, "current-key")
+	params := auth.TokenParams{UserID: uuid.NewV4(), TokenID: "token-id", ExpiresAt: time.Minute}
+
+	previous := newProvider(t, jwt.Config{SigningKey: previousKey})
+	token, err := previous.NewToken(params)
+	require.NoError(t, err)
+
+	current := newProvider(t, jwt.Config{SigningKey: currentKey, VerificationKeys: []jwt.Key{previousPublicKey}})
+	claims, err := current.Parse(token)
+	require.NoError(t, err)
+	require.Equal(t, params.UserID, claims.UserID)
+
+	// Token of removed key is not accepted anymore
+	_, err = newProvider(t, jwt.Config{SigningKey: currentKey}).Parse(token)
+	require.Error(t, err)
+
+	keys := current.PublicKeys()
+	require.Len(t, keys, 2)
+	require.Equal(t, "current-key", keys[0].ID)
+	require.Equal(t, "EdDSA", keys[0].Algorithm)
+	require.Equal(t, currentPublicKey.PublicKey(), keys[0].Key)
+	require.Equal(t, "previous-key", keys[1].ID)
+
+	_, err = jwt.NewJWTAuthorizationProvider(jwt.Config{SigningKey: currentPublicKey})
+	require.Equal(t, jwt.ErrVerificationOnly, err)
+}
+
+func TestParseRejectsOtherAlgorithm(t *testing.T) {
+	rsaKey := newRSAKey(t, "rsa-key")
+	params := auth.TokenParams{UserID: uuid.NewV4(), TokenID: "token-id", ExpiresAt: time.Minute}
+
+	// Token is signed with HMAC and claims id of RSA key
+	token, err := newProvider(t, jwt.Config{SigningKey: jwt.NewHMACKey("rsa-key", "signing-key")}).NewToken(params)
+	require.NoError(t, err)
+
+	_, err = newProvider(t, jwt.Config{SigningKey: rsaKey}).Parse(token)
+	require.Error(t, err)
+
+	require.Empty(t, newProvider(t, jwt.Config{SigningKey: jwt.NewHMACKey("", "signing-key")}).PublicKeys())
+}
@@ -0,0 +1,102 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var (
+	ErrInvalidKey        error = errors.New("Key is not valid PEM encoded RSA or Ed25519 key")
+	ErrVerificationOnly  error = errors.New("Key could not sign tokens, it is public key")
+	ErrUnexpectedKeyType error = errors.New("Key type is not supported")
+)
+
+// Key signs or verifies tokens with `kid` header equal to its id.
+// Public keys could only verify tokens, they are used during rotation
+type Key struct {
+	ID        string
+	method    jwt.SigningMethod
+	signing   interface{}
+	verifying interface{}
+}
+
+// NewHMACKey returns symmetric HS256 key, it is not published in key set
+func NewHMACKey(id, secret string) Key {
+	return Key{ID: id, method: jwt.SigningMethodHS256, signing: []byte(secret), verifying: []byte(secret)}
+}
+
+// LoadKey reads PEM encoded private or public key from file
+func LoadKey(id, filename string) (Key, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return Key{}, err
+	}
+
+	return ParseKey(id, data)
+}
+
+// ParseKey accepts PKCS #8 and PKCS #1 private keys and PKIX public keys,
+// signing method is chosen by type of key
+func ParseKey(id string, data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return Key{}, ErrInvalidKey
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return Key{}, ErrInvalidKey
+		}
+		return newKey(id, privateKey)
+	case "RSA PRIVATE KEY":
+		privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return Key{}, ErrInvalidKey
+		}
+		return newKey(id, privateKey)
+	case "PUBLIC KEY":
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return Key{}, ErrInvalidKey
+		}
+		return newKey(id, publicKey)
+	}
+
+	return Key{}, ErrInvalidKey
+}
+
+func newKey(id string, value interface{}) (Key, error) {
+	switch key := value.(type) {
+	case *rsa.PrivateKey:
+		return Key{ID: id, method: jwt.SigningMethodRS256, signing: key, verifying: &key.PublicKey}, nil
+	case *rsa.PublicKey:
+		return Key{ID: id, method: jwt.SigningMethodRS256, verifying: key}, nil
+	case ed25519.PrivateKey:
+		return Key{ID: id, method: SigningMethodEdDSA, signing: key, verifying: key.Public()}, nil
+	case ed25519.PublicKey:
+		return Key{ID: id, method: SigningMethodEdDSA, verifying: key}, nil
+	}
+
+	return Key{}, ErrUnexpectedKeyType
+}
+
+func (k Key) Algorithm() string {
+	return k.method.Alg()
+}
+
+// PublicKey returns nil for symmetric keys
+func (k Key) PublicKey() crypto.PublicKey {
+	if _, symmetric := k.verifying.([]byte); symmetric {
+		return nil
+	}
+	return k.verifying
+}
@@ -2,6 +2,7 @@
 package auth
 
 import (
+	"crypto"
 	"time"
 
 	uuid "github.com/satori/go.uuid"
@@ -9,10 +10,31 @@ import (
 
 type TokenParams struct {
 	UserID    uuid.UUID
+	TokenID   string
+	SessionID string
+	Roles     []string
 	ExpiresAt time.Duration
 }
 
+// Claims describes verified token payload
+type Claims struct {
+	UserID    uuid.UUID
+	TokenID   string
+	SessionID string
+	Roles     []string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// PublicKey verifies tokens signed with key of same id
+type PublicKey struct {
+	ID        string
+	Algorithm string
+	Key       crypto.PublicKey
+}
+
 type AuthorizationProvider interface {
 	NewToken(TokenParams) (string, error)
-	Parse(string) (uuid.UUID, error)
+	Parse(string) (Claims, error)
+	PublicKeys() []PublicKey
 }
@@ -2,10 +2,16 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"time"
 )
 
+var ErrKeyNotFound error = errors.New("Key not found in cache")
+
 type CachePrivoder interface {
 	Set(context.Context, string, []byte) error
+	SetWithExpiration(context.Context, string, []byte, time.Duration) error
 	Get(context.Context, string) ([]byte, error)
+	Increment(context.Context, string, time.Duration) (int64, error)
 	Delete(context.Context, string) error
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/aintsashqa/go-simple-blog/pkg/cache"
 	"github.com/go-redis/redis/v8"
 )
 
@@ -25,8 +26,28 @@ func (p *RedisProvider) Set(ctx context.Context, key string, value []byte) error
 	return p.client.Set(ctx, key, value, p.exp).Err()
 }
 
+func (p *RedisProvider) SetWithExpiration(ctx context.Context, key string, value []byte, exp time.Duration) error {
+	return p.client.Set(ctx, key, value, exp).Err()
+}
+
 func (p *RedisProvider) Get(ctx context.Context, key string) ([]byte, error) {
-	return p.client.Get(ctx, key).Bytes()
+	value, err := p.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, cache.ErrKeyNotFound
+	}
+	return value, err
+}
+
+// Increment adds one to counter and prolongs its expiration in same transaction
+func (p *RedisProvider) Increment(ctx context.Context, key string, exp time.Duration) (int64, error) {
+	pipe := p.client.TxPipeline()
+	value := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, exp)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return value.Val(), nil
 }
 
 func (p *RedisProvider) Delete(ctx context.Context, key string) error {
@@ -18,3 +18,11 @@ func (c *Config) Dsn() string {
 		c.Username, c.Password, c.Host, c.Port, c.DBName, c.Charset,
 	)
 }
+
+// MigrationDsn allows many statements in single query, migrations are run
+// on separate connection, so it is not allowed for queries of application
+func (c *Config) MigrationDsn() string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=true&multiStatements=true",
+		c.Username, c.Password, c.Host, c.Port, c.DBName, c.Charset,
+	)
+}
@@ -1,18 +1,29 @@
 package mysql
 
 import (
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/aintsashqa/go-simple-blog/pkg/database"
+	driver "github.com/go-sql-driver/mysql"
 	migrate "github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/mysql"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jmoiron/sqlx"
 )
 
+const duplicateEntryErrorNumber uint16 = 1062
+
 func NewMySQL(cfg Config) (*sqlx.DB, error) {
 	return sqlx.Connect("mysql", cfg.Dsn())
 }
 
-func Migrate(connection *sqlx.DB) error {
+// Migrate runs migrations on its own connection, which is closed when
+// migrations are done
+func Migrate(cfg Config) error {
+	connection, err := sqlx.Connect("mysql", cfg.MigrationDsn())
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
 	driver, err := mysql.WithInstance(connection.DB, &mysql.Config{})
 	if err != nil {
 		return err
@@ -34,3 +45,11 @@ func Migrate(connection *sqlx.DB) error {
 
 	return nil
 }
+
+// mapError replaces driver errors which are handled by repositories
+func mapError(err error) error {
+	if driverErr, ok := err.(*driver.MySQLError); ok && driverErr.Number == duplicateEntryErrorNumber {
+		return database.ErrDuplicateEntry
+	}
+	return err
+}
@@ -12,12 +12,12 @@ type MySQLProvider struct {
 }
 
 func NewMySQLProvider(cfg Config) (*MySQLProvider, error) {
-	db, err := NewMySQL(cfg)
-	if err != nil {
+	if err := Migrate(cfg); err != nil {
 		return nil, err
 	}
 
-	if err := Migrate(db); err != nil {
+	db, err := NewMySQL(cfg)
+	if err != nil {
 		return nil, err
 	}
 
@@ -26,7 +26,17 @@ func NewMySQLProvider(cfg Config) (*MySQLProvider, error) {
 
 func (p *MySQLProvider) Exec(ctx context.Context, query string, args ...interface{}) error {
 	_, err := p.db.ExecContext(ctx, query, args...)
-	return err
+	return mapError(err)
+}
+
+// ExecAffected returns count of rows changed by query
+func (p *MySQLProvider) ExecAffected(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	result, err := p.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return result.RowsAffected()
 }
 
 func (p *MySQLProvider) Get(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
@@ -12,7 +12,17 @@ type MySQLTx struct {
 
 func (t *MySQLTx) Exec(ctx context.Context, query string, args ...interface{}) error {
 	_, err := t.tx.ExecContext(ctx, query, args...)
-	return err
+	return mapError(err)
+}
+
+// ExecAffected returns count of rows changed by query
+func (t *MySQLTx) ExecAffected(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	result, err := t.tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, mapError(err)
+	}
+
+	return result.RowsAffected()
 }
 
 func (t *MySQLTx) Get(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
@@ -3,10 +3,15 @@ package database
 
 import (
 	"context"
+	"errors"
 )
 
+// ErrDuplicateEntry is returned by providers when unique constraint is violated
+var ErrDuplicateEntry error = errors.New("Duplicate entry")
+
 type DatabaseInterface interface {
 	Exec(context.Context, string, ...interface{}) error
+	ExecAffected(context.Context, string, ...interface{}) (int64, error)
 	Get(context.Context, interface{}, string, ...interface{}) error
 	Select(context.Context, interface{}, string, ...interface{}) error
 	QueryRow(context.Context, interface{}, string, ...interface{}) error
@@ -0,0 +1,120 @@
+package argon2
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/hash"
+	"golang.org/x/crypto/argon2"
+)
+
+const Algorithm string = "argon2id"
+
+// Config holds parameters of argon2id, memory is measured in KiB
+type Config struct {
+	Time       uint32
+	Memory     uint32
+	Threads    uint8
+	KeyLength  uint32
+	SaltLength uint32
+}
+
+// DefaultConfig follows recommendation of RFC 9106 for memory constrained environments
+var DefaultConfig Config = Config{
+	Time:       3,
+	Memory:     64 * 1024,
+	Threads:    4,
+	KeyLength:  32,
+	SaltLength: 16,
+}
+
+type Argon2Provider struct {
+	config Config
+}
+
+func NewArgon2Provider(config Config) *Argon2Provider {
+	return &Argon2Provider{config: config}
+}
+
+// Make encodes hash in PHC string format, so parameters are stored
+// together with hash and could be raised later
+func (p *Argon2Provider) Make(input string) string {
+	salt := make([]byte, p.config.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return ""
+	}
+
+	key := argon2.IDKey([]byte(input), salt, p.config.Time, p.config.Memory, p.config.Threads, p.config.KeyLength)
+
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		Algorithm, argon2.Version,
+		p.config.Memory, p.config.Time, p.config.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+func (p *Argon2Provider) Compare(hashedInput, input string) error {
+	config, salt, key, err := decode(hashedInput)
+	if err != nil {
+		return err
+	}
+
+	other := argon2.IDKey([]byte(input), salt, config.Time, config.Memory, config.Threads, config.KeyLength)
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return hash.ErrMismatchedHash
+	}
+
+	return nil
+}
+
+func (p *Argon2Provider) NeedsRehash(hashedInput string) bool {
+	config, salt, _, err := decode(hashedInput)
+	if err != nil {
+		return true
+	}
+
+	config.SaltLength = uint32(len(salt))
+	return config != p.config
+}
+
+func decode(hashedInput string) (Config, []byte, []byte, error) {
+	pieces := strings.Split(hashedInput, "$")
+	if len(pieces) != 6 || len(pieces[0]) != 0 {
+		return Config{}, nil, nil, hash.ErrMalformedHash
+	}
+
+	if pieces[1] != Algorithm {
+		return Config{}, nil, nil, hash.ErrUnsupportedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(pieces[2], "v=%d", &version); err != nil {
+		return Config{}, nil, nil, hash.ErrMalformedHash
+	}
+
+	if version != argon2.Version {
+		return Config{}, nil, nil, hash.ErrUnsupportedHash
+	}
+
+	var config Config
+	if _, err := fmt.Sscanf(pieces[3], "m=%d,t=%d,p=%d", &config.Memory, &config.Time, &config.Threads); err != nil {
+		return Config{}, nil, nil, hash.ErrMalformedHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(pieces[4])
+	if err != nil {
+		return Config{}, nil, nil, hash.ErrMalformedHash
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(pieces[5])
+	if err != nil || len(key) == 0 {
+		return Config{}, nil, nil, hash.ErrMalformedHash
+	}
+
+	config.KeyLength = uint32(len(key))
+	return config, salt, key, nil
+}
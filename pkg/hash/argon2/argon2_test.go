@@ -0,0 +1,40 @@
+package argon2_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/hash"
+	"github.com/aintsashqa/go-simple-blog/pkg/hash/argon2"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfig keeps tests fast, parameters are not meant for production
+var testConfig argon2.Config = argon2.Config{Time: 1, Memory: 1024, Threads: 1, KeyLength: 32, SaltLength: 16}
+
+func TestMakeAndCompare(t *testing.T) {
+	provider := argon2.NewArgon2Provider(testConfig)
+	value := provider.Make("secret")
+
+	require.True(t, strings.HasPrefix(value, "$argon2id$v=19$m=1024,t=1,p=1$"))
+	require.Equal(t, argon2.Algorithm, hash.Algorithm(value))
+	require.NotEqual(t, value, provider.Make("secret"))
+
+	require.NoError(t, provider.Compare(value, "secret"))
+	require.Equal(t, hash.ErrMismatchedHash, provider.Compare(value, "other"))
+	require.Equal(t, hash.ErrMalformedHash, provider.Compare("$argon2id$v=19$m=1024", "secret"))
+	require.Equal(t, hash.ErrUnsupportedHash, provider.Compare("$argon2i$v=19$m=1024,t=1,p=1$c2FsdA$a2V5", "secret"))
+}
+
+func TestNeedsRehash(t *testing.T) {
+	provider := argon2.NewArgon2Provider(testConfig)
+	value := provider.Make("secret")
+	require.False(t, provider.NeedsRehash(value))
+
+	raised := testConfig
+	raised.Time = 2
+	require.True(t, argon2.NewArgon2Provider(raised).NeedsRehash(value))
+	require.NoError(t, argon2.NewArgon2Provider(raised).Compare(value, "secret"))
+
+	require.True(t, provider.NeedsRehash("$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"))
+}
@@ -1,20 +1,34 @@
 package bcrypt
 
 import (
+	"github.com/aintsashqa/go-simple-blog/pkg/hash"
 	"golang.org/x/crypto/bcrypt"
 )
 
-type BcryptProvider struct{}
+const DefaultCost int = bcrypt.DefaultCost
 
-func NewBcryptProvider() *BcryptProvider {
-	return new(BcryptProvider)
+type BcryptProvider struct {
+	cost int
+}
+
+func NewBcryptProvider(cost int) *BcryptProvider {
+	return &BcryptProvider{cost: cost}
 }
 
 func (p *BcryptProvider) Make(input string) string {
-	b, _ := bcrypt.GenerateFromPassword([]byte(input), bcrypt.DefaultCost)
+	b, _ := bcrypt.GenerateFromPassword([]byte(input), p.cost)
 	return string(b)
 }
 
 func (p *BcryptProvider) Compare(hashedInput, input string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedInput), []byte(input))
+	err := bcrypt.CompareHashAndPassword([]byte(hashedInput), []byte(input))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return hash.ErrMismatchedHash
+	}
+	return err
+}
+
+func (p *BcryptProvider) NeedsRehash(hashedInput string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedInput))
+	return err != nil || cost != p.cost
 }
@@ -0,0 +1,37 @@
+package multi
+
+import (
+	"github.com/aintsashqa/go-simple-blog/pkg/hash"
+)
+
+// MultiProvider makes hashes with current algorithm and compares hashes
+// of any known algorithm, so algorithm could be switched without resetting
+// passwords of users
+type MultiProvider struct {
+	current    hash.HashProvider
+	algorithms map[string]hash.HashProvider
+}
+
+// NewMultiProvider accepts providers by identifiers of algorithm found
+// in their hashes, see hash.Algorithm
+func NewMultiProvider(current hash.HashProvider, algorithms map[string]hash.HashProvider) *MultiProvider {
+	return &MultiProvider{current: current, algorithms: algorithms}
+}
+
+func (p *MultiProvider) Make(input string) string {
+	return p.current.Make(input)
+}
+
+func (p *MultiProvider) Compare(hashedInput, input string) error {
+	provider, found := p.algorithms[hash.Algorithm(hashedInput)]
+	if !found {
+		return hash.ErrUnsupportedHash
+	}
+
+	return provider.Compare(hashedInput, input)
+}
+
+// NeedsRehash reports hashes of other algorithms as outdated too
+func (p *MultiProvider) NeedsRehash(hashedInput string) bool {
+	return p.current.NeedsRehash(hashedInput)
+}
@@ -0,0 +1,32 @@
+package multi_test
+
+import (
+	"testing"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/hash"
+	"github.com/aintsashqa/go-simple-blog/pkg/hash/argon2"
+	"github.com/aintsashqa/go-simple-blog/pkg/hash/bcrypt"
+	"github.com/aintsashqa/go-simple-blog/pkg/hash/multi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareDetectsAlgorithm(t *testing.T) {
+	bcryptProvider := bcrypt.NewBcryptProvider(4)
+	argon2Provider := argon2.NewArgon2Provider(argon2.Config{Time: 1, Memory: 1024, Threads: 1, KeyLength: 32, SaltLength: 16})
+	provider := multi.NewMultiProvider(argon2Provider, map[string]hash.HashProvider{
+		"2a":             bcryptProvider,
+		argon2.Algorithm: argon2Provider,
+	})
+
+	outdated := bcryptProvider.Make("secret")
+	require.NoError(t, provider.Compare(outdated, "secret"))
+	require.Equal(t, hash.ErrMismatchedHash, provider.Compare(outdated, "other"))
+	require.True(t, provider.NeedsRehash(outdated))
+
+	current := provider.Make("secret")
+	require.Equal(t, argon2.Algorithm, hash.Algorithm(current))
+	require.NoError(t, provider.Compare(current, "secret"))
+	require.False(t, provider.NeedsRehash(current))
+
+	require.Equal(t, hash.ErrUnsupportedHash, provider.Compare("plain", "plain"))
+}
@@ -1,7 +1,31 @@
 //go:generate mockgen -source=provider.go -destination=mocks/mock.go
 package hash
 
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrMismatchedHash  error = errors.New("Hash does not match input")
+	ErrUnsupportedHash error = errors.New("Hash algorithm is not supported")
+	ErrMalformedHash   error = errors.New("Hash is malformed")
+)
+
 type HashProvider interface {
 	Make(string) string
 	Compare(string, string) error
+	// NeedsRehash reports whether hash is made by other algorithm
+	// or with other parameters than current ones
+	NeedsRehash(string) bool
+}
+
+// Algorithm returns identifier of algorithm of hash encoded in PHC string
+// or modular crypt format, for example `argon2id` or `2a`
+func Algorithm(hashed string) string {
+	pieces := strings.SplitN(hashed, "$", 3)
+	if len(pieces) != 3 || len(pieces[0]) != 0 {
+		return ""
+	}
+	return pieces[1]
 }
@@ -0,0 +1,33 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/mailer"
+	uuid "github.com/satori/go.uuid"
+)
+
+// OutboxMailerProvider writes every message into directory instead of
+// delivering it, so it could be used for development and tests
+type OutboxMailerProvider struct {
+	directory string
+}
+
+func NewOutboxMailerProvider(directory string) (*OutboxMailerProvider, error) {
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, err
+	}
+
+	return &OutboxMailerProvider{directory: directory}, nil
+}
+
+func (p *OutboxMailerProvider) Send(ctx context.Context, message mailer.Message) error {
+	filename := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), uuid.NewV4())
+	content := fmt.Sprintf("To: %s\r\nSubject: %s\r\n\r\n%s", message.To, message.Subject, message.Body)
+	return ioutil.WriteFile(filepath.Join(p.directory, filename), []byte(content), 0644)
+}
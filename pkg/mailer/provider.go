@@ -0,0 +1,16 @@
+//go:generate mockgen -source=provider.go -destination=mocks/mock.go
+package mailer
+
+import (
+	"context"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type MailerProvider interface {
+	Send(context.Context, Message) error
+}
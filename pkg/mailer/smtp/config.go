@@ -0,0 +1,19 @@
+package smtp
+
+import (
+	"fmt"
+)
+
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+func (c *Config) Addr() string {
+	return fmt.Sprintf("%s:%d",
+		c.Host, c.Port,
+	)
+}
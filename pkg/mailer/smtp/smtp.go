@@ -0,0 +1,36 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/smtp"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/mailer"
+)
+
+type SMTPMailerProvider struct {
+	cfg  Config
+	auth smtp.Auth
+}
+
+func NewSMTPMailerProvider(cfg Config) *SMTPMailerProvider {
+	var auth smtp.Auth
+	if len(cfg.Username) != 0 {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &SMTPMailerProvider{cfg: cfg, auth: auth}
+}
+
+func (p *SMTPMailerProvider) Send(ctx context.Context, message mailer.Message) error {
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "From: %s\r\n", p.cfg.From)
+	fmt.Fprintf(&body, "To: %s\r\n", message.To)
+	fmt.Fprintf(&body, "Subject: %s\r\n", message.Subject)
+	fmt.Fprint(&body, "MIME-Version: 1.0\r\n")
+	fmt.Fprint(&body, "Content-Type: text/plain; charset=\"utf-8\"\r\n\r\n")
+	body.WriteString(message.Body)
+
+	return smtp.SendMail(p.cfg.Addr(), p.auth, p.cfg.From, []string{message.To}, body.Bytes())
+}
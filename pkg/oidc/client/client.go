@@ -0,0 +1,313 @@
+package client
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/oidc"
+	"github.com/dgrijalva/jwt-go"
+)
+
+const discoveryPath string = "/.well-known/openid-configuration"
+
+type Config struct {
+	Name         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	HTTPClient   *http.Client
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	KeyID     string `json:"kid"`
+	KeyType   string `json:"kty"`
+	Algorithm string `json:"alg"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+}
+
+// audience is either single string or array of strings
+type audience []string
+
+func (a *audience) UnmarshalJSON(value []byte) error {
+	var single string
+	if err := json.Unmarshal(value, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(value, &many); err != nil {
+		return err
+	}
+	*a = audience(many)
+	return nil
+}
+
+func (a audience) Contains(value string) bool {
+	for _, current := range a {
+		if current == value {
+			return true
+		}
+	}
+	return false
+}
+
+type idTokenClaims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      audience `json:"aud"`
+	ExpiresAt     int64    `json:"exp"`
+	IssuedAt      int64    `json:"iat"`
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+}
+
+func (c *idTokenClaims) Valid() error {
+	if c.ExpiresAt == 0 || time.Now().Unix() > c.ExpiresAt {
+		return errors.New("token is expired")
+	}
+	return nil
+}
+
+// OIDCProvider implements authorization code flow with PKCE, discovery
+// document and signing keys are fetched on first use
+type OIDCProvider struct {
+	config Config
+	client *http.Client
+
+	mutex     sync.Mutex
+	discovery *discovery
+	keys      map[string]*rsa.PublicKey
+}
+
+func NewOIDCProvider(config Config) *OIDCProvider {
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &OIDCProvider{config: config, client: client}
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.config.Name
+}
+
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, request oidc.AuthRequest) (string, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", request.State)
+	query.Set("nonce", request.Nonce)
+	query.Set("code_challenge", oidc.CodeChallenge(request.Verifier))
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange redeems authorization code and verifies returned id token
+func (p *OIDCProvider) Exchange(ctx context.Context, code string, request oidc.AuthRequest) (oidc.IDToken, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return oidc.IDToken{}, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("client_secret", p.config.ClientSecret)
+	form.Set("code_verifier", request.Verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return oidc.IDToken{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return oidc.IDToken{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return oidc.IDToken{}, oidc.ErrExchangeFailed
+	}
+
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil || token.IDToken == "" {
+		return oidc.IDToken{}, oidc.ErrExchangeFailed
+	}
+
+	return p.verify(ctx, discovery, token.IDToken, request.Nonce)
+}
+
+func (p *OIDCProvider) verify(ctx context.Context, discovery *discovery, value string, nonce string) (oidc.IDToken, error) {
+	claims := idTokenClaims{}
+	_, err := jwt.ParseWithClaims(value, &claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		keyID, _ := token.Header["kid"].(string)
+		return p.key(ctx, discovery, keyID)
+	})
+	if err != nil {
+		if validation, ok := err.(*jwt.ValidationError); ok && validation.Inner == oidc.ErrSigningKeyUnknown {
+			return oidc.IDToken{}, oidc.ErrSigningKeyUnknown
+		}
+		return oidc.IDToken{}, oidc.ErrIDTokenInvalid
+	}
+
+	if claims.Issuer != discovery.Issuer || !claims.Audience.Contains(p.config.ClientID) || claims.Subject == "" {
+		return oidc.IDToken{}, oidc.ErrIDTokenInvalid
+	}
+
+	if claims.Nonce != nonce {
+		return oidc.IDToken{}, oidc.ErrIDTokenNonce
+	}
+
+	return oidc.IDToken{
+		Issuer:        claims.Issuer,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+	}, nil
+}
+
+func (p *OIDCProvider) discover(ctx context.Context) (*discovery, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var document discovery
+	if err := p.get(ctx, strings.TrimSuffix(p.config.IssuerURL, "/")+discoveryPath, &document); err != nil {
+		return nil, err
+	}
+
+	// Issuer must be same as configured one, otherwise tokens of another
+	// provider could be accepted
+	if document.Issuer != p.config.IssuerURL || document.AuthorizationEndpoint == "" || document.TokenEndpoint == "" || document.JWKSURI == "" {
+		return nil, oidc.ErrDiscoveryFailed
+	}
+
+	p.discovery = &document
+	return p.discovery, nil
+}
+
+// key returns signing key with id, keys are fetched again when key is
+// unknown, because provider could rotate keys
+func (p *OIDCProvider) key(ctx context.Context, discovery *discovery, keyID string) (*rsa.PublicKey, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if key, ok := p.keys[keyID]; ok {
+		return key, nil
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.get(ctx, discovery.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+
+	p.keys = map[string]*rsa.PublicKey{}
+	for _, current := range set.Keys {
+		if current.KeyType != "RSA" {
+			continue
+		}
+		key, err := publicKey(current)
+		if err != nil {
+			continue
+		}
+		p.keys[current.KeyID] = key
+	}
+
+	if key, ok := p.keys[keyID]; ok {
+		return key, nil
+	}
+	return nil, oidc.ErrSigningKeyUnknown
+}
+
+func (p *OIDCProvider) get(ctx context.Context, target string, value interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: unexpected status %d of %s", oidc.ErrDiscoveryFailed, resp.StatusCode, target)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(value)
+}
+
+func publicKey(key jsonWebKey) (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(key.Modulus)
+	if err != nil {
+		return nil, err
+	}
+
+	exponent, err := base64.RawURLEncoding.DecodeString(key.Exponent)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(modulus),
+		E: int(new(big.Int).SetBytes(exponent).Int64()),
+	}, nil
+}
@@ -0,0 +1,93 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/oidc"
+	"github.com/aintsashqa/go-simple-blog/pkg/oidc/client"
+	"github.com/aintsashqa/go-simple-blog/pkg/oidc/oidctest"
+	"github.com/stretchr/testify/require"
+)
+
+// authorize follows login at mock provider and returns code of callback
+func authorize(t *testing.T, provider *client.OIDCProvider, request oidc.AuthRequest) string {
+	target, err := provider.AuthCodeURL(context.Background(), request)
+	require.NoError(t, err)
+
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := noRedirect.Get(target)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, request.State, location.Query().Get("state"))
+
+	return location.Query().Get("code")
+}
+
+func newProvider(server *oidctest.Server) *client.OIDCProvider {
+	return client.NewOIDCProvider(client.Config{
+		Name:         "oidctest",
+		IssuerURL:    server.URL,
+		ClientID:     oidctest.ClientID,
+		ClientSecret: oidctest.ClientSecret,
+		RedirectURL:  "http://localhost/callback",
+	})
+}
+
+func TestExchange(t *testing.T) {
+	server := oidctest.NewServer(oidctest.User{Subject: "subject", Email: "user@example.com", EmailVerified: true})
+	defer server.Close()
+
+	provider := newProvider(server)
+	request := oidc.AuthRequest{State: "state", Nonce: "nonce", Verifier: "verifier-with-enough-entropy"}
+
+	token, err := provider.Exchange(context.Background(), authorize(t, provider, request), request)
+	require.NoError(t, err)
+	require.Equal(t, oidc.IDToken{Issuer: server.URL, Subject: "subject", Email: "user@example.com", EmailVerified: true}, token)
+
+	// Code could be exchanged once
+	_, err = provider.Exchange(context.Background(), "unknown-code", request)
+	require.Equal(t, oidc.ErrExchangeFailed, err)
+}
+
+func TestExchangeVerifierMismatch(t *testing.T) {
+	server := oidctest.NewServer(oidctest.User{Subject: "subject"})
+	defer server.Close()
+
+	provider := newProvider(server)
+	request := oidc.AuthRequest{State: "state", Nonce: "nonce", Verifier: "verifier-with-enough-entropy"}
+	code := authorize(t, provider, request)
+
+	request.Verifier = "another-verifier"
+	_, err := provider.Exchange(context.Background(), code, request)
+	require.Equal(t, oidc.ErrExchangeFailed, err)
+}
+
+func TestExchangeNonceMismatch(t *testing.T) {
+	server := oidctest.NewServer(oidctest.User{Subject: "subject"})
+	server.Nonce = "replayed-nonce"
+	defer server.Close()
+
+	provider := newProvider(server)
+	request := oidc.AuthRequest{State: "state", Nonce: "nonce", Verifier: "verifier-with-enough-entropy"}
+
+	_, err := provider.Exchange(context.Background(), authorize(t, provider, request), request)
+	require.Equal(t, oidc.ErrIDTokenNonce, err)
+}
+
+func TestDiscoveryIssuerMismatch(t *testing.T) {
+	server := oidctest.NewServer(oidctest.User{Subject: "subject"})
+	defer server.Close()
+
+	provider := client.NewOIDCProvider(client.Config{IssuerURL: server.URL + "/", ClientID: oidctest.ClientID})
+	_, err := provider.AuthCodeURL(context.Background(), oidc.AuthRequest{})
+	require.Equal(t, oidc.ErrDiscoveryFailed, err)
+}
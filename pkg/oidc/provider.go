@@ -0,0 +1,46 @@
+//go:generate mockgen -source=provider.go -destination=mocks/mock.go
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+var (
+	ErrDiscoveryFailed   error = errors.New("OpenID provider discovery failed")
+	ErrExchangeFailed    error = errors.New("Authorization code exchange failed")
+	ErrIDTokenInvalid    error = errors.New("ID token is invalid")
+	ErrIDTokenNonce      error = errors.New("ID token nonce does not match")
+	ErrSigningKeyUnknown error = errors.New("ID token is signed with unknown key")
+)
+
+// AuthRequest holds values which must be kept by client until callback,
+// verifier is never sent to authorization endpoint
+type AuthRequest struct {
+	State    string
+	Nonce    string
+	Verifier string
+}
+
+// IDToken describes verified claims of id token
+type IDToken struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+type Provider interface {
+	// Name identifies provider of external identities
+	Name() string
+	AuthCodeURL(context.Context, AuthRequest) (string, error)
+	Exchange(context.Context, string, AuthRequest) (IDToken, error)
+}
+
+// CodeChallenge returns S256 challenge of PKCE verifier
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
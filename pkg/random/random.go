@@ -0,0 +1,15 @@
+package random
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Token returns hex encoded string of cryptographically secure random bytes
+func Token(size int) (string, error) {
+	value := make([]byte, size)
+	if _, err := rand.Read(value); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(value), nil
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/ratelimit"
+)
+
+// sweepInterval is how often counters of finished windows are forgotten
+const sweepInterval time.Duration = time.Minute
+
+type counter struct {
+	window   int64
+	period   time.Duration
+	previous int64
+	current  int64
+}
+
+// MemoryLimiterProvider counts requests of single instance only, so it is
+// meant as fallback when shared limiter is unavailable
+type MemoryLimiterProvider struct {
+	mu       sync.Mutex
+	counters map[string]*counter
+	swept    time.Time
+}
+
+func NewMemoryLimiterProvider() *MemoryLimiterProvider {
+	return &MemoryLimiterProvider{
+		counters: make(map[string]*counter),
+		swept:    time.Now(),
+	}
+}
+
+func (p *MemoryLimiterProvider) Allow(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	now := time.Now()
+	window, elapsed := ratelimit.Window(now, limit.Period)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sweep(now)
+
+	c, found := p.counters[key]
+	if !found || c.period != limit.Period {
+		c = &counter{window: window, period: limit.Period}
+		p.counters[key] = c
+	}
+
+	switch window - c.window {
+	case 0:
+	case 1:
+		c.window, c.previous, c.current = window, c.current, 0
+	default:
+		c.window, c.previous, c.current = window, 0, 0
+	}
+
+	allowed := ratelimit.Estimate(limit, c.previous, c.current, elapsed) < float64(limit.Requests)
+	if allowed {
+		c.current++
+	}
+
+	return ratelimit.Evaluate(limit, allowed, c.previous, c.current, elapsed), nil
+}
+
+func (p *MemoryLimiterProvider) sweep(now time.Time) {
+	if now.Sub(p.swept) < sweepInterval {
+		return
+	}
+
+	for key, c := range p.counters {
+		if window, _ := ratelimit.Window(now, c.period); window-c.window > 1 {
+			delete(p.counters, key)
+		}
+	}
+	p.swept = now
+}
@@ -0,0 +1,35 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/ratelimit"
+	"github.com/aintsashqa/go-simple-blog/pkg/ratelimit/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllow(t *testing.T) {
+	provider := memory.NewMemoryLimiterProvider()
+	limit := ratelimit.Limit{Requests: 3, Period: time.Hour}
+
+	for remaining := int64(2); remaining >= 0; remaining-- {
+		result, err := provider.Allow(context.Background(), "ip:127.0.0.1", limit)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, int64(3), result.Limit)
+		require.Equal(t, remaining, result.Remaining)
+		require.Zero(t, result.RetryAfter)
+	}
+
+	result, err := provider.Allow(context.Background(), "ip:127.0.0.1", limit)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Zero(t, result.Remaining)
+	require.Equal(t, result.ResetAfter, result.RetryAfter)
+
+	result, err = provider.Allow(context.Background(), "ip:127.0.0.2", limit)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+}
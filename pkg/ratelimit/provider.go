@@ -0,0 +1,74 @@
+//go:generate mockgen -source=provider.go -destination=mocks/mock.go
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+type Limit struct {
+	Requests int64
+	Period   time.Duration
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int64
+	Remaining  int64
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+type LimiterProvider interface {
+	Allow(context.Context, string, Limit) (Result, error)
+}
+
+// Window returns index of fixed window and time elapsed since it is started
+func Window(now time.Time, period time.Duration) (int64, time.Duration) {
+	nanos := now.UnixNano()
+	return nanos / int64(period), time.Duration(nanos % int64(period))
+}
+
+// Estimate approximates count of requests within sliding window, requests
+// of previous window are weighted by its part still covered by sliding window
+func Estimate(limit Limit, previous, current int64, elapsed time.Duration) float64 {
+	weight := float64(limit.Period-elapsed) / float64(limit.Period)
+	return float64(previous)*weight + float64(current)
+}
+
+// Evaluate builds result of sliding window, counters must already
+// include current request when it is allowed
+func Evaluate(limit Limit, allowed bool, previous, current int64, elapsed time.Duration) Result {
+	result := Result{
+		Allowed:    allowed,
+		Limit:      limit.Requests,
+		ResetAfter: limit.Period - elapsed,
+	}
+
+	estimate := Estimate(limit, previous, current, elapsed)
+	if remaining := limit.Requests - int64(math.Ceil(estimate)); remaining > 0 {
+		result.Remaining = remaining
+	}
+
+	if allowed {
+		return result
+	}
+
+	// Client has to wait until weight of previous window drops enough,
+	// when current window is full it has to wait for next window
+	period := float64(limit.Period)
+	if current < limit.Requests {
+		until := period - float64(limit.Requests-current)*period/float64(previous)
+		result.RetryAfter = time.Duration(until) - elapsed
+	} else {
+		until := period - float64(limit.Requests)*period/float64(current)
+		result.RetryAfter = limit.Period - elapsed + time.Duration(until)
+	}
+
+	if result.RetryAfter < 0 {
+		result.RetryAfter = 0
+	}
+
+	return result
+}
@@ -0,0 +1,30 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/ratelimit"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	limit := ratelimit.Limit{Requests: 10, Period: time.Minute}
+
+	// Half of previous window is still covered, so 5 of its 10 requests count
+	result := ratelimit.Evaluate(limit, true, 10, 3, 30*time.Second)
+	require.True(t, result.Allowed)
+	require.Equal(t, int64(2), result.Remaining)
+	require.Equal(t, 30*time.Second, result.ResetAfter)
+
+	// Weight of previous window has to drop to 0.4 for one more request
+	result = ratelimit.Evaluate(limit, false, 10, 6, 30*time.Second)
+	require.False(t, result.Allowed)
+	require.Zero(t, result.Remaining)
+	require.Equal(t, 6*time.Second, result.RetryAfter)
+
+	// Full current window becomes previous one, its weight has to drop to 0.5
+	result = ratelimit.Evaluate(limit, false, 0, 20, 45*time.Second)
+	require.False(t, result.Allowed)
+	require.Equal(t, 45*time.Second, result.RetryAfter)
+}
@@ -0,0 +1,75 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/ratelimit"
+	"github.com/go-redis/redis/v8"
+)
+
+const keyPrefix string = "ratelimit:"
+
+// slidingWindowScript checks and counts request atomically, so concurrent
+// requests of instances sharing redis could not exceed limit together
+var slidingWindowScript = redis.NewScript(`
+local previous = tonumber(redis.call("GET", KEYS[1]) or "0")
+local current = tonumber(redis.call("GET", KEYS[2]) or "0")
+local limit = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local elapsed = tonumber(ARGV[3])
+
+if previous * (period - elapsed) / period + current >= limit then
+	return {0, previous, current}
+end
+
+current = redis.call("INCR", KEYS[2])
+redis.call("PEXPIRE", KEYS[2], period * 2)
+return {1, previous, current}
+`)
+
+type RedisLimiterProvider struct {
+	client *redis.Client
+}
+
+func NewRedisLimiterProvider(client *redis.Client) *RedisLimiterProvider {
+	return &RedisLimiterProvider{client: client}
+}
+
+func (p *RedisLimiterProvider) Allow(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	window, elapsed := ratelimit.Window(time.Now(), limit.Period)
+	keys := []string{
+		fmt.Sprintf("%s%s:%d", keyPrefix, key, window-1),
+		fmt.Sprintf("%s%s:%d", keyPrefix, key, window),
+	}
+
+	reply, err := slidingWindowScript.Run(ctx, p.client, keys,
+		limit.Requests,
+		limit.Period.Milliseconds(),
+		elapsed.Milliseconds(),
+	).Result()
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+
+	values, casted := reply.([]interface{})
+	if !casted {
+		return ratelimit.Result{}, fmt.Errorf("unexpected sliding window result: %v", reply)
+	}
+
+	counters := make([]int64, 0, len(values))
+	for _, value := range values {
+		counter, casted := value.(int64)
+		if !casted {
+			return ratelimit.Result{}, fmt.Errorf("unexpected sliding window result: %v", values)
+		}
+		counters = append(counters, counter)
+	}
+
+	if len(counters) != 3 {
+		return ratelimit.Result{}, fmt.Errorf("unexpected sliding window result: %v", values)
+	}
+
+	return ratelimit.Evaluate(limit, counters[0] == 1, counters[1], counters[2], elapsed), nil
+}
@@ -0,0 +1,53 @@
+package hmac
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/signature"
+)
+
+const separator string = "."
+
+type HMACSignatureProvider struct {
+	key []byte
+}
+
+func NewHMACSignatureProvider(key string) *HMACSignatureProvider {
+	return &HMACSignatureProvider{key: []byte(key)}
+}
+
+func (p *HMACSignatureProvider) mac(payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func (p *HMACSignatureProvider) Sign(payload []byte) string {
+	return base64.RawURLEncoding.EncodeToString(payload) + separator + base64.RawURLEncoding.EncodeToString(p.mac(payload))
+}
+
+func (p *HMACSignatureProvider) Verify(value string) ([]byte, error) {
+	pieces := strings.Split(value, separator)
+	if len(pieces) != 2 {
+		return nil, signature.ErrInvalidSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(pieces[0])
+	if err != nil {
+		return nil, signature.ErrInvalidSignature
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(pieces[1])
+	if err != nil {
+		return nil, signature.ErrInvalidSignature
+	}
+
+	if !hmac.Equal(mac, p.mac(payload)) {
+		return nil, signature.ErrInvalidSignature
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,27 @@
+package hmac_test
+
+import (
+	"testing"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/signature"
+	"github.com/aintsashqa/go-simple-blog/pkg/signature/hmac"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	provider := hmac.NewHMACSignatureProvider("signing-key")
+	value := provider.Sign([]byte("payload"))
+
+	payload, err := provider.Verify(value)
+	require.NoError(t, err)
+	require.Equal(t, []byte("payload"), payload)
+
+	_, err = hmac.NewHMACSignatureProvider("other-key").Verify(value)
+	require.Equal(t, signature.ErrInvalidSignature, err)
+
+	_, err = provider.Verify("cGF5bG9hZA." + value[len(value)-4:])
+	require.Equal(t, signature.ErrInvalidSignature, err)
+
+	_, err = provider.Verify("payload")
+	require.Equal(t, signature.ErrInvalidSignature, err)
+}
@@ -0,0 +1,15 @@
+//go:generate mockgen -source=provider.go -destination=mocks/mock.go
+package signature
+
+import (
+	"errors"
+)
+
+var ErrInvalidSignature error = errors.New("Invalid signature")
+
+// SignatureProvider signs payload, so it could be passed to client and
+// returned back without modifications
+type SignatureProvider interface {
+	Sign([]byte) string
+	Verify(string) ([]byte, error)
+}
@@ -0,0 +1,37 @@
+package signature
+
+import (
+	"bytes"
+)
+
+const purposeSeparator string = ":"
+
+// purposeProvider binds signed value to purpose, so value signed for one
+// purpose could not be verified for another with the same key
+type purposeProvider struct {
+	provider SignatureProvider
+	prefix   []byte
+}
+
+func WithPurpose(provider SignatureProvider, purpose string) SignatureProvider {
+	return &purposeProvider{provider: provider, prefix: []byte(purpose + purposeSeparator)}
+}
+
+func (p *purposeProvider) Sign(payload []byte) string {
+	value := make([]byte, 0, len(p.prefix)+len(payload))
+	value = append(value, p.prefix...)
+	return p.provider.Sign(append(value, payload...))
+}
+
+func (p *purposeProvider) Verify(value string) ([]byte, error) {
+	payload, err := p.provider.Verify(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(payload, p.prefix) {
+		return nil, ErrInvalidSignature
+	}
+
+	return payload[len(p.prefix):], nil
+}
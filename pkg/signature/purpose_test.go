@@ -0,0 +1,26 @@
+package signature_test
+
+import (
+	"testing"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/signature"
+	"github.com/aintsashqa/go-simple-blog/pkg/signature/hmac"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPurpose(t *testing.T) {
+	provider := hmac.NewHMACSignatureProvider("signing-key")
+	verification := signature.WithPurpose(provider, "email-verification")
+	emailChange := signature.WithPurpose(provider, "email-change")
+
+	value := verification.Sign([]byte("payload"))
+	payload, err := verification.Verify(value)
+	require.NoError(t, err)
+	require.Equal(t, []byte("payload"), payload)
+
+	_, err = emailChange.Verify(value)
+	require.Equal(t, signature.ErrInvalidSignature, err)
+
+	_, err = verification.Verify(provider.Sign([]byte("payload")))
+	require.Equal(t, signature.ErrInvalidSignature, err)
+}
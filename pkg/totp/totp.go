@@ -0,0 +1,113 @@
+// Package totp implements time based one time passwords of RFC 6238 with
+// parameters supported by common authenticator apps: SHA1, 6 digits and
+// 30 seconds period
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	Digits int           = 6
+	Period time.Duration = 30 * time.Second
+
+	// Skew is count of periods before and after current one, codes of
+	// them are accepted because clocks of devices drift
+	Skew int64 = 1
+
+	secretSize int = 20
+)
+
+var ErrInvalidSecret error = errors.New("TOTP secret must be base32 encoded")
+
+var encoding *base32.Encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns base32 encoded random secret
+func GenerateSecret() (string, error) {
+	value := make([]byte, secretSize)
+	if _, err := rand.Read(value); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(value), nil
+}
+
+// URI returns otpauth uri, which is usually shown to user as QR code
+func URI(issuer string, account string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(int(Period.Seconds())))
+
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// Counter returns number of period at time
+func Counter(at time.Time) int64 {
+	return at.Unix() / int64(Period.Seconds())
+}
+
+// Code returns code of period at time
+func Code(secret string, at time.Time) (string, error) {
+	key, err := decode(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, Counter(at), Digits), nil
+}
+
+// Validate returns counter of period which code belongs to, so caller could
+// reject code which was already used
+func Validate(secret string, code string, at time.Time) (int64, bool) {
+	key, err := decode(secret)
+	if err != nil || len(code) != Digits {
+		return 0, false
+	}
+
+	current := Counter(at)
+	for counter := current - Skew; counter <= current+Skew; counter++ {
+		if subtle.ConstantTimeCompare([]byte(hotp(key, counter, Digits)), []byte(code)) == 1 {
+			return counter, true
+		}
+	}
+	return 0, false
+}
+
+func decode(secret string) ([]byte, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
+
+// hotp is HMAC based one time password of RFC 4226
+func hotp(key []byte, counter int64, digits int) string {
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < digits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%modulo)
+}
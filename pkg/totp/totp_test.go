@@ -0,0 +1,60 @@
+package totp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/totp"
+	"github.com/stretchr/testify/require"
+)
+
+// rfcSecret is ASCII "12345678901234567890" of RFC 6238 test vectors
+const rfcSecret string = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode(t *testing.T) {
+	// Expected codes are last 6 digits of RFC 6238 SHA1 test vectors
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+
+	for unix, expected := range vectors {
+		code, err := totp.Code(rfcSecret, time.Unix(unix, 0))
+		require.NoError(t, err)
+		require.Equal(t, expected, code)
+	}
+
+	_, err := totp.Code("not base32!", time.Now())
+	require.Equal(t, totp.ErrInvalidSecret, err)
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+
+	now := time.Now()
+	code, err := totp.Code(secret, now.Add(-totp.Period))
+	require.NoError(t, err)
+
+	counter, ok := totp.Validate(secret, code, now)
+	require.True(t, ok)
+	require.Equal(t, totp.Counter(now)-1, counter)
+
+	_, ok = totp.Validate(secret, code, now.Add(2*totp.Period))
+	require.False(t, ok)
+
+	_, ok = totp.Validate(secret, "12345", now)
+	require.False(t, ok)
+}
+
+func TestURI(t *testing.T) {
+	uri := totp.URI("Simple Blog", "user@example.com", rfcSecret)
+
+	require.True(t, strings.HasPrefix(uri, "otpauth://totp/Simple%20Blog:user@example.com?"))
+	require.Contains(t, uri, "secret="+rfcSecret)
+	require.Contains(t, uri, "issuer=Simple+Blog")
+}
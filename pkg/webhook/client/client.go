@@ -0,0 +1,97 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/webhook"
+)
+
+const userAgent string = "go-simple-blog-webhook"
+
+// maxResponseSize limits body of receiver which is read, so connection
+// could be reused without reading large responses
+const maxResponseSize int64 = 4096
+
+var ErrPrivateAddress error = errors.New("Webhook receiver address is not public")
+
+// Config with HTTPClient replaces default client, addresses of receivers
+// are not checked then
+type Config struct {
+	Timeout    time.Duration
+	HTTPClient *http.Client
+}
+
+type WebhookProvider struct {
+	client *http.Client
+}
+
+func NewWebhookProvider(config Config) *WebhookProvider {
+	client := config.HTTPClient
+	if client == nil {
+		timeout := config.Timeout
+		if timeout == 0 {
+			timeout = 10 * time.Second
+		}
+
+		// Resolved address is checked right before connecting, so host could
+		// not pass validation with public address and resolve to private one later
+		dialer := &net.Dialer{Timeout: timeout, Control: control}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = nil
+		transport.DialContext = dialer.DialContext
+
+		// Redirects are not followed, receiver must respond itself
+		client = &http.Client{Timeout: timeout, Transport: transport, CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}}
+	}
+
+	return &WebhookProvider{client: client}
+}
+
+// control rejects connections to loopback, private, link-local and
+// other non-public addresses
+func control(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !webhook.IsPublicIP(net.ParseIP(host)) {
+		return ErrPrivateAddress
+	}
+	return nil
+}
+
+// Send posts signed payload, response with any status code is returned
+// without error, error means receiver could not be reached
+func (p *WebhookProvider) Send(ctx context.Context, message webhook.Message) (webhook.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, message.URL, bytes.NewReader(message.Payload))
+	if err != nil {
+		return webhook.Response{}, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", userAgent)
+	request.Header.Set(webhook.EventHeader, message.Event)
+	request.Header.Set(webhook.DeliveryHeader, message.DeliveryID)
+	request.Header.Set(webhook.SignatureHeader, webhook.Sign(message.Secret, time.Now(), message.Payload))
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return webhook.Response{}, err
+	}
+	defer response.Body.Close()
+
+	io.Copy(ioutil.Discard, io.LimitReader(response.Body, maxResponseSize))
+
+	return webhook.Response{StatusCode: response.StatusCode}, nil
+}
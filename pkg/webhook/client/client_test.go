@@ -0,0 +1,56 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/webhook"
+	"github.com/aintsashqa/go-simple-blog/pkg/webhook/client"
+	"github.com/aintsashqa/go-simple-blog/pkg/webhook/webhooktest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSend(t *testing.T) {
+	receiver := webhooktest.NewReceiver("secret", http.StatusInternalServerError, http.StatusOK)
+	defer receiver.Close()
+
+	provider := client.NewWebhookProvider(client.Config{HTTPClient: receiver.Client()})
+	message := webhook.Message{URL: receiver.URL, Secret: "secret", Event: "post.created", DeliveryID: "delivery-id", Payload: []byte(`{}`)}
+
+	response, err := provider.Send(context.Background(), message)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusInternalServerError, response.StatusCode)
+	require.False(t, response.IsSuccess())
+
+	response, err = provider.Send(context.Background(), message)
+	require.NoError(t, err)
+	require.True(t, response.IsSuccess())
+
+	requests := receiver.Requests()
+	require.Len(t, requests, 2)
+	require.Equal(t, "post.created", requests[1].Event)
+	require.Equal(t, "delivery-id", requests[1].DeliveryID)
+	require.Equal(t, []byte(`{}`), requests[1].Payload)
+	require.True(t, requests[1].Valid)
+}
+
+func TestSendUnreachable(t *testing.T) {
+	receiver := webhooktest.NewReceiver("secret")
+	receiver.Close()
+
+	response, err := client.NewWebhookProvider(client.Config{HTTPClient: receiver.Client()}).Send(context.Background(), webhook.Message{URL: receiver.URL, Payload: []byte(`{}`)})
+	require.Error(t, err)
+	require.Zero(t, response.StatusCode)
+}
+
+func TestSendPrivateAddress(t *testing.T) {
+	receiver := webhooktest.NewReceiver("secret")
+	defer receiver.Close()
+
+	response, err := client.NewWebhookProvider(client.Config{}).Send(context.Background(), webhook.Message{URL: receiver.URL, Payload: []byte(`{}`)})
+	require.True(t, errors.Is(err, client.ErrPrivateAddress))
+	require.Zero(t, response.StatusCode)
+	require.Empty(t, receiver.Requests())
+}
@@ -0,0 +1,120 @@
+//go:generate mockgen -source=provider.go -destination=mocks/mock.go
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader string = "X-Webhook-Signature"
+	EventHeader     string = "X-Webhook-Event"
+	DeliveryHeader  string = "X-Webhook-Delivery"
+
+	signatureVersion string = "v1"
+)
+
+var (
+	ErrInvalidSignature error = errors.New("Webhook signature is not valid")
+	ErrExpiredSignature error = errors.New("Webhook signature is expired")
+)
+
+// Message is signed with secret of subscription, receiver verifies it
+// with same secret
+type Message struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID string
+	Payload    []byte
+}
+
+// Response holds status code of receiver, status code is zero when
+// request could not be sent
+type Response struct {
+	StatusCode int
+}
+
+type Provider interface {
+	Send(context.Context, Message) (Response, error)
+}
+
+func (r Response) IsSuccess() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+func mac(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sign returns value of signature header, timestamp is signed along with
+// payload, so captured request could not be replayed later
+func Sign(secret string, timestamp time.Time, payload []byte) string {
+	unix := timestamp.Unix()
+	return fmt.Sprintf("t=%d,%s=%s", unix, signatureVersion, mac(secret, unix, payload))
+}
+
+// Verify checks signature header of received payload, signatures older
+// than tolerance are rejected
+func Verify(secret string, header string, payload []byte, tolerance time.Duration) error {
+	var timestamp int64
+	var signature string
+	for _, piece := range strings.Split(header, ",") {
+		pair := strings.SplitN(piece, "=", 2)
+		if len(pair) != 2 {
+			return ErrInvalidSignature
+		}
+
+		switch pair[0] {
+		case "t":
+			value, err := strconv.ParseInt(pair[1], 10, 64)
+			if err != nil {
+				return ErrInvalidSignature
+			}
+			timestamp = value
+		case signatureVersion:
+			signature = pair[1]
+		}
+	}
+
+	if timestamp == 0 || len(signature) == 0 {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(mac(secret, timestamp, payload))) {
+		return ErrInvalidSignature
+	}
+
+	if tolerance > 0 && time.Since(time.Unix(timestamp, 0)) > tolerance {
+		return ErrExpiredSignature
+	}
+
+	return nil
+}
+
+// Backoff returns delay before next attempt, delay doubles after every
+// failed attempt and never exceeds max
+func Backoff(attempt int, base time.Duration, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+
+	if delay > max {
+		return max
+	}
+	return delay
+}
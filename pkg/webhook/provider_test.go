@@ -0,0 +1,62 @@
+package webhook_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/webhook"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	payload := []byte(`{"event":"post.created"}`)
+	header := webhook.Sign("secret", time.Now(), payload)
+
+	require.NoError(t, webhook.Verify("secret", header, payload, time.Minute))
+	require.Equal(t, webhook.ErrInvalidSignature, webhook.Verify("other", header, payload, time.Minute))
+	require.Equal(t, webhook.ErrInvalidSignature, webhook.Verify("secret", header, []byte(`{}`), time.Minute))
+	require.Equal(t, webhook.ErrInvalidSignature, webhook.Verify("secret", "malformed", payload, time.Minute))
+
+	expired := webhook.Sign("secret", time.Now().Add(-time.Hour), payload)
+	require.Equal(t, webhook.ErrExpiredSignature, webhook.Verify("secret", expired, payload, time.Minute))
+	require.NoError(t, webhook.Verify("secret", expired, payload, 0))
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt, expected := range map[int]time.Duration{
+		1: time.Minute,
+		2: 2 * time.Minute,
+		3: 4 * time.Minute,
+		4: 8 * time.Minute,
+		5: 10 * time.Minute,
+		9: 10 * time.Minute,
+	} {
+		require.Equal(t, expected, webhook.Backoff(attempt, time.Minute, 10*time.Minute), attempt)
+	}
+}
+
+func TestIsPublicHost(t *testing.T) {
+	for host, expected := range map[string]bool{
+		"example.com":      true,
+		"93.184.216.34":    true,
+		"2606:4700::1111":  true,
+		"localhost":        false,
+		"api.localhost.":   false,
+		"127.0.0.1":        false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"0.0.0.0":          false,
+		"::1":              false,
+		"fd00::1":          false,
+		"fe80::1":          false,
+		"::ffff:127.0.0.1": false,
+	} {
+		require.Equal(t, expected, webhook.IsPublicHost(host), host)
+	}
+
+	require.False(t, webhook.IsPublicIP(nil))
+	require.True(t, webhook.IsPublicIP(net.ParseIP("8.8.8.8")))
+}
@@ -0,0 +1,75 @@
+// Package webhooktest runs in-process webhook receiver, so deliveries
+// could be tested without real integration
+package webhooktest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/aintsashqa/go-simple-blog/pkg/webhook"
+)
+
+// Request is delivery received by receiver, valid reports whether
+// signature matched secret of receiver
+type Request struct {
+	Event      string
+	DeliveryID string
+	Payload    []byte
+	Valid      bool
+}
+
+type Receiver struct {
+	*httptest.Server
+
+	Secret string
+
+	mutex    sync.Mutex
+	statuses []int
+	requests []Request
+}
+
+// NewReceiver responds with statuses in order, last status is repeated,
+// receiver responds with 204 when statuses are empty
+func NewReceiver(secret string, statuses ...int) *Receiver {
+	receiver := &Receiver{Secret: secret, statuses: statuses}
+	receiver.Server = httptest.NewServer(http.HandlerFunc(receiver.receive))
+	return receiver
+}
+
+func (r *Receiver) receive(w http.ResponseWriter, req *http.Request) {
+	payload, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.requests = append(r.requests, Request{
+		Event:      req.Header.Get(webhook.EventHeader),
+		DeliveryID: req.Header.Get(webhook.DeliveryHeader),
+		Payload:    payload,
+		Valid:      webhook.Verify(r.Secret, req.Header.Get(webhook.SignatureHeader), payload, time.Minute) == nil,
+	})
+
+	status := http.StatusNoContent
+	if len(r.statuses) != 0 {
+		status = r.statuses[0]
+		if len(r.statuses) > 1 {
+			r.statuses = r.statuses[1:]
+		}
+	}
+
+	w.WriteHeader(status)
+}
+
+func (r *Receiver) Requests() []Request {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return append([]Request{}, r.requests...)
+}
@@ -2,6 +2,8 @@ package seeds
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/aintsashqa/go-simple-blog/internal/domain"
 	"github.com/aintsashqa/go-simple-blog/pkg/database"
@@ -9,8 +11,11 @@ import (
 	"github.com/jaswdr/faker"
 )
 
+// usernameReplacer keeps fake usernames valid, index suffix keeps them unique
+var usernameReplacer *strings.Replacer = strings.NewReplacer(".", "_", "-", "_")
+
 func UserSeed(ctx context.Context, faker faker.Faker, tx database.DatabaseInterface) error {
-	hasher := bcrypt.NewBcryptProvider()
+	hasher := bcrypt.NewBcryptProvider(bcrypt.DefaultCost)
 	trancate := "truncate table users"
 	query := "insert into users (id, email, username, encrypted_password, created_at, updated_at, deleted_at) values (?, ?, ?, ?, ?, ?, ?)"
 
@@ -18,10 +23,22 @@ func UserSeed(ctx context.Context, faker faker.Faker, tx database.DatabaseInterf
 		return err
 	}
 
+	// Follows reference truncated users
+	if err := tx.Exec(ctx, "truncate table follows"); err != nil {
+		return err
+	}
+
+	// Ghost user is created by migration and removed by truncate
+	ghost := domain.User{Email: "ghost@localhost", Username: "ghost"}
+	ghost.Init()
+	if err := tx.Exec(ctx, query, domain.GhostUserID, ghost.Email, ghost.Username, ghost.Password, ghost.CreatedAt, ghost.UpdatedAt, ghost.DeletedAt); err != nil {
+		return err
+	}
+
 	for i := 0; i < 10; i++ {
 		temp := domain.User{
 			Email:    faker.Internet().Email(),
-			Username: faker.Internet().User(),
+			Username: fmt.Sprintf("%s_%d", usernameReplacer.Replace(faker.Internet().User()), i),
 			Password: hasher.Make("secret"),
 		}
 		temp.Init()